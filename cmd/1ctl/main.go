@@ -4,20 +4,30 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
+	"1ctl/internal/api"
 	"1ctl/internal/commands"
 	"1ctl/internal/config"
 	satuskyctx "1ctl/internal/context"
+	"1ctl/internal/selfupdate"
+	"1ctl/internal/transport"
 	"1ctl/internal/utils"
 	"1ctl/internal/version"
 
 	"github.com/urfave/cli/v3"
+	"golang.org/x/term"
 )
 
 // Make run function accessible to tests
 func run() error {
 	cmd := createCommand()
-	return cmd.Run(context.Background(), os.Args)
+	err := cmd.Run(context.Background(), os.Args)
+	// Write the --trace-file even when the command failed; that is when it is needed
+	if traceErr := api.FinishTrace(); traceErr != nil && err == nil {
+		err = traceErr
+	}
+	return err
 }
 
 // Make createCommand function accessible to tests
@@ -62,9 +72,45 @@ Tokens: https://cloud.satusky.com/<org-id>/token`,
 			&cli.StringFlag{
 				Name:    "output",
 				Aliases: []string{"o"},
-				Usage:   "Output format: table or json",
+				Usage:   "Output format: table, json, or ndjson (one JSON object per line)",
 				Value:   "table",
 			},
+			&cli.BoolFlag{
+				Name:  "reveal",
+				Usage: "Show secret values, tokens and passwords in output instead of masking them",
+			},
+			&cli.BoolFlag{
+				Name:    "debug",
+				Usage:   "Log every API request and response to stderr, with credentials and secret values masked",
+				Sources: cli.EnvVars(utils.DebugEnv),
+			},
+			&cli.StringFlag{
+				Name:    "trace-file",
+				Usage:   "Record API requests to a HAR file to attach to support cases (e.g. --trace-file out.har)",
+				Sources: cli.EnvVars(api.TraceFileEnv),
+			},
+			&cli.IntFlag{
+				Name:    "max-retries",
+				Usage:   "Retries for API requests that fail with a network error, 429 or 5xx gateway error (0 disables)",
+				Value:   api.DefaultRetryPolicy().MaxRetries,
+				Sources: cli.EnvVars(api.MaxRetriesEnv),
+			},
+			&cli.DurationFlag{
+				Name:    "retry-max-delay",
+				Usage:   "Longest wait between API request retries, including waits asked for by Retry-After",
+				Value:   api.DefaultRetryPolicy().MaxDelay,
+				Sources: cli.EnvVars(api.RetryMaxDelayEnv),
+			},
+			&cli.BoolFlag{
+				Name:    "no-cache",
+				Usage:   "Fetch zones, clusters, pricing, organizations and machine labels from the API instead of the local cache",
+				Sources: cli.EnvVars(api.NoCacheEnv),
+			},
+			&cli.StringFlag{
+				Name:    "ca-file",
+				Usage:   "PEM file of extra root CAs to trust, for networks that intercept TLS (added to the profile's ca_file)",
+				Sources: cli.EnvVars(transport.CABundleEnv),
+			},
 		},
 		Commands: []*cli.Command{
 			// 1ctl init → Core workflow
@@ -73,6 +119,7 @@ Tokens: https://cloud.satusky.com/<org-id>/token`,
 			cat(commands.DeployCommand(), "Core workflow"),
 			cat(commands.AppCommand(), "Applications"),
 			cat(commands.LogsCommand(), "Core workflow"),
+			cat(commands.RunCommand(), "Core workflow"),
 			cat(commands.DoctorCommand(), "Core workflow"),
 			// Applications
 			cat(commands.DomainsCommand(), "Applications"),
@@ -98,6 +145,10 @@ Tokens: https://cloud.satusky.com/<org-id>/token`,
 			cat(commands.AuditCommand(), "Billing & operations"),
 			cat(commands.NotificationsCommand(), "Billing & operations"),
 			cat(commands.CompletionCommand(), "Billing & operations"),
+			cat(commands.APICommand(), "Billing & operations"),
+			cat(commands.VersionCommand(), "Billing & operations"),
+			cat(commands.UpdateCommand(), "Billing & operations"),
+			cat(commands.CacheCommand(), "Billing & operations"),
 			// Internal (hidden)
 			commands.ServiceCommand(),
 			commands.IngressCommand(),
@@ -105,10 +156,18 @@ Tokens: https://cloud.satusky.com/<org-id>/token`,
 		},
 		Before: func(ctx context.Context, cmd *cli.Command) (context.Context, error) {
 			// Apply --profile flag: sets profile for this process invocation only (not persisted)
-			if profile := cmd.String("profile"); profile != "" {
+			profile := cmd.String("profile")
+			if profile != "" {
 				satuskyctx.SetProfileOverride(profile)
+				if os.Getenv("SATUSKY_PROFILE") == profile {
+					satuskyctx.SetProfileSource("SATUSKY_PROFILE")
+				}
 			}
 
+			// Apply a profile pinned by satusky.toml or .satusky/profile in
+			// this directory tree, unless one was chosen explicitly
+			config.ApplyPin(profile)
+
 			// Apply --api-url flag: highest-priority URL override, set via env var so GetConfig picks it up
 			if apiURL := cmd.String("api-url"); apiURL != "" {
 				if err := os.Setenv("SATUSKY_API_URL", apiURL); err != nil {
@@ -121,6 +180,44 @@ Tokens: https://cloud.satusky.com/<org-id>/token`,
 				utils.SetOutputFormat(format)
 			}
 
+			// Apply --reveal flag: secret values are masked in all output unless asked for
+			utils.SetReveal(cmd.Bool("reveal"))
+
+			// Apply --debug and --trace-file: API traffic is logged and recorded from here on
+			if cmd.IsSet("debug") {
+				utils.SetDebug(cmd.Bool("debug"))
+			}
+			if path := cmd.String("trace-file"); path != "" {
+				api.StartTrace(path)
+			}
+
+			// Apply --max-retries and --retry-max-delay to every API request
+			if err := api.SetRetryPolicy(api.RetryPolicy{
+				MaxRetries: cmd.Int("max-retries"),
+				BaseDelay:  api.DefaultRetryPolicy().BaseDelay,
+				MaxDelay:   cmd.Duration("retry-max-delay"),
+			}); err != nil {
+				return ctx, err
+			}
+
+			// Apply --no-cache: slow-changing lookups are cached under the profile unless asked not to
+			api.SetCacheEnabled(!cmd.Bool("no-cache"))
+
+			// Apply --ca-file and the profile's TLS files; proxies come from
+			// HTTPS_PROXY, HTTP_PROXY and NO_PROXY
+			tlsFiles := satuskyctx.GetTLSFiles()
+			if err := transport.Configure(transport.Config{
+				CAFiles:    []string{tlsFiles.CAFile, cmd.String("ca-file")},
+				ClientCert: tlsFiles.ClientCert,
+				ClientKey:  tlsFiles.ClientKey,
+			}); err != nil {
+				// Still let "profile tls" fix a profile with broken files
+				if cmd.Args().First() != "profile" {
+					return ctx, err
+				}
+				utils.PrintWarning("%s", err.Error())
+			}
+
 			// Get the command or first argument
 			cmdName := cmd.Args().First()
 
@@ -130,6 +227,10 @@ Tokens: https://cloud.satusky.com/<org-id>/token`,
 				cmdName == "org" ||
 				cmdName == "init" ||
 				cmdName == "completion" ||
+				cmdName == "version" ||
+				cmdName == "cache" ||
+				cmdName == "update" ||
+				cmdName == "secret" || // checks login for its own subcommands
 				cmdName == "help" ||
 				cmd.Bool("help") ||
 				cmd.Bool("h") ||
@@ -160,19 +261,70 @@ Tokens: https://cloud.satusky.com/<org-id>/token`,
 				if cmdName == "volume" {
 					msg += "\nPersistent volumes are managed with '1ctl volumes'."
 				}
-				return ctx, utils.NewError(msg, nil)
+				return ctx, utils.NewKindError(utils.ErrUsage, msg, nil)
 			}
 
 			// Only validate environment for existing commands
 			return ctx, config.ValidateEnvironment()
 		},
+		After: func(ctx context.Context, cmd *cli.Command) error {
+			showUpdateNotice(cmd)
+			return nil
+		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
-			return utils.NewError("No command specified, use --help for usage", nil)
+			return utils.NewKindError(utils.ErrUsage, "No command specified, use --help for usage", nil)
 		},
 	}
+	markUsageErrors(cmd)
 	return cmd
 }
 
+// markUsageErrors makes bad flags and arguments to cmd and its subcommands
+// usage errors, so they exit with the usage exit code.
+func markUsageErrors(cmd *cli.Command) {
+	if cmd.OnUsageError == nil {
+		cmd.OnUsageError = func(ctx context.Context, cmd *cli.Command, err error, isSubcommand bool) error {
+			// Bad flags stop the command before Before applies --output.
+			// Help is for people; scripts asking for JSON get the error alone
+			utils.SetOutputFormat(cmd.String("output"))
+			if !utils.IsJSONOutput() {
+				if isSubcommand {
+					_ = cli.ShowSubcommandHelp(cmd) //nolint:errcheck
+				} else {
+					_ = cli.ShowRootCommandHelp(cmd) //nolint:errcheck
+				}
+			}
+			return utils.NewKindError(utils.ErrUsage, err.Error(), nil)
+		}
+	}
+	for _, sub := range cmd.Commands {
+		markUsageErrors(sub)
+	}
+}
+
+// showUpdateNotice mentions a newer release, checking at most once a day.
+// It stays quiet for development builds, in CI, for scripts reading JSON
+// and when stderr is not a terminal.
+func showUpdateNotice(cmd *cli.Command) {
+	name := cmd.Args().First()
+	if !version.IsRelease(version.Version) ||
+		os.Getenv(selfupdate.NoticeEnv) != "" ||
+		os.Getenv("CI") != "" ||
+		utils.IsJSONOutput() ||
+		!term.IsTerminal(int(os.Stderr.Fd())) || // #nosec G115 -- a file descriptor
+		name == "update" || name == "completion" ||
+		containsString(os.Args, "--generate-shell-completion") {
+		return
+	}
+	u, err := selfupdate.New()
+	if err != nil {
+		return
+	}
+	if notice := u.Notice(selfupdate.NoticeFile(), version.Version, time.Now()); notice != "" {
+		utils.NewPrinter(os.Stderr).Info("%s", notice)
+	}
+}
+
 // cat sets the command category and returns the command for chaining.
 func cat(cmd *cli.Command, category string) *cli.Command {
 	cmd.Category = category
@@ -191,6 +343,6 @@ func containsString(values []string, needle string) bool {
 func main() {
 	if err := run(); err != nil {
 		_ = utils.HandleError(err) //nolint:errcheck
-		os.Exit(1)
+		os.Exit(utils.ExitCode(err))
 	}
 }
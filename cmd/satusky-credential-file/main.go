@@ -0,0 +1,105 @@
+// Command satusky-credential-file is the reference 1ctl credential helper.
+// It keeps tokens in a single JSON file readable only by the user, keyed by
+// API URL, profile and token kind. Real helpers replace the file with a
+// call to their secret store; see package credhelper for the protocol.
+//
+// Use it with:
+//
+//	1ctl auth login --credential-helper satusky-credential-file --token <token>
+//
+// The file is SATUSKY_CREDENTIAL_FILE, or ~/.satusky/helper-credentials.json.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"1ctl/internal/credhelper"
+)
+
+type fileHelper struct {
+	path string
+}
+
+func (f fileHelper) key(req credhelper.Request) string {
+	return req.ServerURL + "|" + req.Profile + "|" + req.Kind
+}
+
+func (f fileHelper) load() (map[string]string, error) {
+	tokens := map[string]string{}
+	data, err := os.ReadFile(f.path) // #nosec G304 -- path chosen by the user
+	if os.IsNotExist(err) {
+		return tokens, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return nil, fmt.Errorf("%s: %w", f.path, err)
+	}
+	return tokens, nil
+}
+
+func (f fileHelper) save(tokens map[string]string) error {
+	data, err := json.MarshalIndent(tokens, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(f.path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(f.path, data, 0600)
+}
+
+func (f fileHelper) Get(req credhelper.Request) (string, error) {
+	tokens, err := f.load()
+	if err != nil {
+		return "", err
+	}
+	return tokens[f.key(req)], nil
+}
+
+func (f fileHelper) Store(req credhelper.Request) error {
+	tokens, err := f.load()
+	if err != nil {
+		return err
+	}
+	tokens[f.key(req)] = req.Token
+	return f.save(tokens)
+}
+
+func (f fileHelper) Erase(req credhelper.Request) error {
+	tokens, err := f.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := tokens[f.key(req)]; !ok {
+		return nil
+	}
+	delete(tokens, f.key(req))
+	return f.save(tokens)
+}
+
+func helperPath() (string, error) {
+	if path := os.Getenv("SATUSKY_CREDENTIAL_FILE"); path != "" {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".satusky", "helper-credentials.json"), nil
+}
+
+func main() {
+	path, err := helperPath()
+	if err == nil {
+		err = credhelper.Serve(os.Args[1:], os.Stdin, os.Stdout, fileHelper{path: path})
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,924 @@
+// Package apitest runs an in-memory fake of the SatuSky CLI API for tests.
+//
+// The fake serves the deployment, service, ingress, environment, secret,
+// volume and build endpoints over httptest, keeping what is created in
+// memory, so whole command flows can run against an *api.Client without a
+// backend:
+//
+//	srv := apitest.NewServer(t)
+//	resp, err := deploy.Deploy(deploy.DeploymentOptions{API: srv.Client(), ...})
+//
+// Builds complete as soon as they are submitted and volumes bind at once.
+package apitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"1ctl/internal/api"
+
+	"github.com/google/uuid"
+)
+
+// Defaults for the credentials and organization the fake expects.
+const (
+	DefaultToken     = "apitest-token"
+	DefaultUserEmail = "dev@example.com"
+	DefaultNamespace = "acme"
+)
+
+// Server is a fake SatuSky API. Its methods are safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	// Token is the API key requests must carry.
+	Token string
+	// UserEmail and Namespace are set on the clients returned by Client.
+	UserEmail string
+	Namespace string
+
+	mu           sync.Mutex
+	routes       []route
+	requests     []string
+	failures     map[string]failure
+	deployments  map[string]*api.Deployment
+	versions     map[string][]api.DeploymentVersion
+	services     map[string]*api.Service
+	ingresses    map[string]*api.Ingress
+	environments map[string]*api.Environment
+	secrets      map[string]*api.Secret
+	volumes      map[string]*api.Volume
+	builds       map[string]*api.BuildStatusResponse
+	domains      int
+}
+
+type failure struct {
+	status  int
+	message string
+}
+
+// NewServer starts a fake API that is closed when the test ends.
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+	s := &Server{
+		Token:        DefaultToken,
+		UserEmail:    DefaultUserEmail,
+		Namespace:    DefaultNamespace,
+		failures:     make(map[string]failure),
+		deployments:  make(map[string]*api.Deployment),
+		versions:     make(map[string][]api.DeploymentVersion),
+		services:     make(map[string]*api.Service),
+		ingresses:    make(map[string]*api.Ingress),
+		environments: make(map[string]*api.Environment),
+		secrets:      make(map[string]*api.Secret),
+		volumes:      make(map[string]*api.Volume),
+		builds:       make(map[string]*api.BuildStatusResponse),
+	}
+	s.registerRoutes()
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Client returns a client that talks to the fake as its user and
+// organization.
+func (s *Server) Client() *api.Client {
+	return &api.Client{
+		BaseURL:    s.URL + "/v1/cli",
+		Token:      api.StaticToken(s.Token),
+		UserEmail:  s.UserEmail,
+		Namespace:  s.Namespace,
+		HTTPClient: s.Server.Client(),
+	}
+}
+
+// Fail makes requests for method and path, relative to /v1/cli, fail with
+// status and message until the test ends.
+func (s *Server) Fail(method, path string, status int, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[method+" "+path] = failure{status: status, message: message}
+}
+
+// Requests returns the requests served so far as "METHOD /path", relative
+// to /v1/cli, in the order they arrived.
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+// AddDeployment stores d as if it had been deployed and returns it with its
+// ID, status and first version filled in.
+func (s *Server) AddDeployment(d api.Deployment) api.Deployment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.upsertDeploymentLocked(d)
+}
+
+// Deployment returns the stored deployment with id.
+func (s *Server) Deployment(id string) (api.Deployment, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.deployments[id]
+	if !ok {
+		return api.Deployment{}, false
+	}
+	return *d, true
+}
+
+// Deployments returns every stored deployment, ordered by app label.
+func (s *Server) Deployments() []api.Deployment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedValues(s.deployments, func(d api.Deployment) string { return d.Namespace + "/" + d.AppLabel })
+}
+
+// Services returns every stored service, ordered by name.
+func (s *Server) Services() []api.Service {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedValues(s.services, func(v api.Service) string { return v.Namespace + "/" + v.ServiceName })
+}
+
+// Ingresses returns every stored ingress, ordered by app label.
+func (s *Server) Ingresses() []api.Ingress {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedValues(s.ingresses, func(v api.Ingress) string { return v.Namespace + "/" + v.AppLabel })
+}
+
+// Environments returns every stored environment, ordered by app label.
+func (s *Server) Environments() []api.Environment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedValues(s.environments, func(v api.Environment) string { return v.Namespace + "/" + v.AppLabel })
+}
+
+// Secrets returns every stored secret, ordered by app label.
+func (s *Server) Secrets() []api.Secret {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedValues(s.secrets, func(v api.Secret) string { return v.Namespace + "/" + v.AppLabel })
+}
+
+// Volumes returns every stored volume, ordered by name.
+func (s *Server) Volumes() []api.Volume {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedValues(s.volumes, func(v api.Volume) string { return v.Namespace + "/" + v.VolumeName })
+}
+
+// Builds returns every submitted build, ordered by ID.
+func (s *Server) Builds() []api.BuildStatusResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedValues(s.builds, func(v api.BuildStatusResponse) string { return v.BuildID })
+}
+
+func sortedValues[T any](m map[string]*T, key func(T) string) []T {
+	out := make([]T, 0, len(m))
+	for _, v := range m {
+		out = append(out, *v)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return key(out[i]) < key(out[j]) })
+	return out
+}
+
+// --- Routing ------------------------------------------------------------
+
+// route matches a method and a path whose {name} segments match any single
+// segment, available to the handler through r.PathValue.
+type route struct {
+	method   string
+	segments []string
+	handle   func(http.ResponseWriter, *http.Request)
+}
+
+func (s *Server) handle(method, pattern string, h func(http.ResponseWriter, *http.Request)) {
+	s.routes = append(s.routes, route{method: method, segments: strings.Split(strings.Trim(pattern, "/"), "/"), handle: h})
+}
+
+func (rt route) match(r *http.Request, segments []string) bool {
+	if rt.method != r.Method || len(rt.segments) != len(segments) {
+		return false
+	}
+	for i, seg := range rt.segments {
+		if strings.HasPrefix(seg, "{") {
+			continue
+		}
+		if seg != segments[i] {
+			return false
+		}
+	}
+	for i, seg := range rt.segments {
+		if strings.HasPrefix(seg, "{") {
+			r.SetPathValue(strings.Trim(seg, "{}"), segments[i])
+		}
+	}
+	return true
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	path, ok := strings.CutPrefix(r.URL.Path, "/v1/cli")
+	if !ok {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, r.Method+" "+path)
+	fail, failing := s.failures[r.Method+" "+path]
+	s.mu.Unlock()
+
+	if r.Header.Get("x-satusky-api-key") != s.Token {
+		writeError(w, http.StatusUnauthorized, "invalid API key")
+		return
+	}
+	if failing {
+		writeError(w, fail.status, fail.message)
+		return
+	}
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for _, rt := range s.routes {
+		if rt.match(r, segments) {
+			rt.handle(w, r)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, fmt.Sprintf("no route for %s %s", r.Method, path))
+}
+
+func (s *Server) registerRoutes() {
+	s.handle(http.MethodPost, "/deployments/upsert/{namespace}/{app}", s.upsertDeployment)
+	s.handle(http.MethodGet, "/deployments/id/{id}", s.getDeployment)
+	s.handle(http.MethodGet, "/deployments/namespace/{namespace}", s.listDeployments)
+	s.handle(http.MethodGet, "/deployments/namespace/{namespace}/app/{app}", s.getDeploymentByApp)
+	s.handle(http.MethodGet, "/deployments/status/{id}", s.deploymentStatus)
+	s.handle(http.MethodPost, "/deployments/delete/{id}", s.deleteDeployment)
+	s.handle(http.MethodPost, "/deployments/{id}/restart", s.restartDeployment)
+	s.handle(http.MethodGet, "/deployments/{id}/versions", s.listVersions)
+	s.handle(http.MethodPost, "/deployments/{id}/rollback/{version}", s.rollbackDeployment)
+
+	s.handle(http.MethodPost, "/services/upsert/{namespace}/{name}", s.upsertService)
+	s.handle(http.MethodGet, "/services/namespace/{namespace}", s.listServices)
+	s.handle(http.MethodPost, "/services/delete/{id}", s.deleteService)
+
+	s.handle(http.MethodPost, "/ingresses/upsert/{namespace}/{app}", s.upsertIngress)
+	s.handle(http.MethodGet, "/ingresses/namespace/{namespace}", s.listIngresses)
+	s.handle(http.MethodGet, "/ingresses/deploymentId/{id}", s.getIngressByDeployment)
+	s.handle(http.MethodGet, "/ingresses/domainName/{domain}", s.getIngressByDomain)
+	s.handle(http.MethodGet, "/ingresses/domainNameGenerator", s.generateDomainName)
+	s.handle(http.MethodPost, "/ingresses/delete/{id}", s.deleteIngress)
+
+	s.handle(http.MethodPost, "/environments/upsert", s.upsertEnvironment)
+	s.handle(http.MethodGet, "/environments/namespace/{namespace}", s.listEnvironments)
+	s.handle(http.MethodGet, "/environments/deploymentId/{id}", s.listEnvironmentsByDeployment)
+	s.handle(http.MethodPost, "/environments/unset/{id}", s.unsetEnvironmentKey)
+	s.handle(http.MethodPost, "/environments/delete/{id}", s.deleteEnvironment)
+
+	s.handle(http.MethodPost, "/secrets/upsert", s.upsertSecret)
+	s.handle(http.MethodGet, "/secrets/namespace/{namespace}", s.listSecrets)
+	s.handle(http.MethodGet, "/secrets/deploymentId/{id}", s.listSecretsByDeployment)
+	s.handle(http.MethodPost, "/secrets/unset/{id}", s.unsetSecretKey)
+	s.handle(http.MethodPost, "/secrets/delete/{id}", s.deleteSecret)
+
+	s.handle(http.MethodPost, "/volumes/create", s.createVolume)
+	s.handle(http.MethodGet, "/volumes/all", s.listVolumes)
+	s.handle(http.MethodGet, "/volumes/id/{id}/status", s.volumeStatus)
+	s.handle(http.MethodGet, "/volumes/deploymentId/{id}/status", s.deploymentVolumeStatuses)
+	s.handle(http.MethodPost, "/volumes/{id}/detach", s.detachVolume)
+	s.handle(http.MethodDelete, "/volumes/{id}/pvc", s.deleteVolumePVC)
+
+	s.handle(http.MethodPost, "/builds", s.submitBuild)
+	s.handle(http.MethodGet, "/builds/{id}/status", s.buildStatus)
+}
+
+// --- Responses ----------------------------------------------------------
+
+func writeData(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"error": false, "message": "ok", "data": data}) //nolint:errcheck
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"error": true, "message": message}) //nolint:errcheck
+}
+
+// decode reads a JSON request body into v, answering 400 if it cannot.
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %s", err.Error()))
+		return false
+	}
+	return true
+}
+
+// --- Deployments --------------------------------------------------------
+
+// upsertDeploymentLocked creates or replaces the deployment with d's
+// namespace and app label and records a new version of it.
+func (s *Server) upsertDeploymentLocked(d api.Deployment) *api.Deployment {
+	now := time.Now().UTC()
+	for _, existing := range s.deployments {
+		if existing.Namespace == d.Namespace && existing.AppLabel == d.AppLabel {
+			d.DeploymentID = existing.DeploymentID
+			d.CreatedAt = existing.CreatedAt
+		}
+	}
+	if d.DeploymentID == uuid.Nil {
+		d.DeploymentID = uuid.New()
+		d.CreatedAt = now
+	}
+	if d.Status == "" {
+		d.Status = api.StatusRunningK8s
+	}
+	d.UpdatedAt = now
+	id := d.DeploymentID.String()
+	s.deployments[id] = &d
+	s.recordVersionLocked(&d)
+	return &d
+}
+
+func (s *Server) recordVersionLocked(d *api.Deployment) {
+	id := d.DeploymentID.String()
+	versions := s.versions[id]
+	for i := range versions {
+		versions[i].Status = "superseded"
+	}
+	s.versions[id] = append(versions, api.DeploymentVersion{
+		VersionID:     uuid.NewString(),
+		DeploymentID:  id,
+		VersionNumber: len(versions) + 1,
+		Image:         d.Image,
+		CPU:           d.CpuRequest,
+		Memory:        d.MemoryRequest,
+		Replicas:      int(d.Replicas),
+		DeployedAt:    d.UpdatedAt,
+		Status:        "active",
+	})
+}
+
+func (s *Server) upsertDeployment(w http.ResponseWriter, r *http.Request) {
+	var d api.Deployment
+	if !decode(w, r, &d) {
+		return
+	}
+	d.Namespace, d.AppLabel = r.PathValue("namespace"), r.PathValue("app")
+	s.mu.Lock()
+	stored := s.upsertDeploymentLocked(d)
+	s.mu.Unlock()
+	writeData(w, http.StatusOK, stored.DeploymentID.String())
+}
+
+// deployment looks up the deployment named by the id path value, answering
+// 404 if there is none. The caller must hold s.mu.
+func (s *Server) deployment(w http.ResponseWriter, r *http.Request) (*api.Deployment, bool) {
+	d, ok := s.deployments[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "deployment not found")
+	}
+	return d, ok
+}
+
+func (s *Server) getDeployment(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if d, ok := s.deployment(w, r); ok {
+		writeData(w, http.StatusOK, d)
+	}
+}
+
+func (s *Server) listDeployments(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := []api.Deployment{}
+	for _, d := range sortedValues(s.deployments, func(d api.Deployment) string { return d.AppLabel }) {
+		if d.Namespace == r.PathValue("namespace") {
+			list = append(list, d)
+		}
+	}
+	writeData(w, http.StatusOK, list)
+}
+
+func (s *Server) getDeploymentByApp(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, d := range s.deployments {
+		if d.Namespace == r.PathValue("namespace") && d.AppLabel == r.PathValue("app") {
+			writeData(w, http.StatusOK, d)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "deployment not found")
+}
+
+func (s *Server) deploymentStatus(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if d, ok := s.deployment(w, r); ok {
+		writeData(w, http.StatusOK, api.DeploymentStatus{Status: d.Status, Progress: 100})
+	}
+}
+
+func (s *Server) deleteDeployment(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.deployment(w, r)
+	if !ok {
+		return
+	}
+	id := d.DeploymentID
+	result := api.DeletionResult{DeletedDeployments: []string{id.String()}, Namespace: d.Namespace, AppLabel: d.AppLabel}
+	for key, v := range s.volumes {
+		if v.DeploymentID == id {
+			result.Volumes = append(result.Volumes, api.VolumeDestroyResult{
+				VolumeID: v.VolumeID, VolumeName: v.VolumeName, ClaimName: v.ClaimName,
+				Namespace: v.Namespace, Status: "deleted", DestroyPolicy: "delete",
+			})
+			delete(s.volumes, key)
+		}
+	}
+	deleteWhere(s.services, func(v *api.Service) bool { return v.DeploymentID == id })
+	deleteWhere(s.ingresses, func(v *api.Ingress) bool { return v.DeploymentID == id })
+	deleteWhere(s.environments, func(v *api.Environment) bool { return v.DeploymentID == id })
+	deleteWhere(s.secrets, func(v *api.Secret) bool { return v.DeploymentID == id })
+	delete(s.deployments, id.String())
+	delete(s.versions, id.String())
+	writeData(w, http.StatusOK, result)
+}
+
+func deleteWhere[T any](m map[string]*T, match func(*T) bool) {
+	for key, v := range m {
+		if match(v) {
+			delete(m, key)
+		}
+	}
+}
+
+func (s *Server) restartDeployment(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if d, ok := s.deployment(w, r); ok {
+		d.UpdatedAt = time.Now().UTC()
+		writeData(w, http.StatusOK, nil)
+	}
+}
+
+func (s *Server) listVersions(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if d, ok := s.deployment(w, r); ok {
+		// Newest first, like the API.
+		stored := s.versions[d.DeploymentID.String()]
+		versions := make([]api.DeploymentVersion, 0, len(stored))
+		for i := len(stored) - 1; i >= 0; i-- {
+			versions = append(versions, stored[i])
+		}
+		writeData(w, http.StatusOK, versions)
+	}
+}
+
+func (s *Server) rollbackDeployment(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.deployment(w, r)
+	if !ok {
+		return
+	}
+	n, err := strconv.Atoi(r.PathValue("version"))
+	versions := s.versions[d.DeploymentID.String()]
+	if err != nil || n < 1 || n > len(versions) {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("version %s not found", r.PathValue("version")))
+		return
+	}
+	target := versions[n-1]
+	d.Image, d.CpuRequest, d.MemoryRequest = target.Image, target.CPU, target.Memory
+	d.Replicas = int32(target.Replicas) // #nosec G115 -- stored from an int32
+	d.UpdatedAt = time.Now().UTC()
+	s.recordVersionLocked(d)
+	writeData(w, http.StatusOK, nil)
+}
+
+// --- Services -----------------------------------------------------------
+
+func (s *Server) upsertService(w http.ResponseWriter, r *http.Request) {
+	var svc api.Service
+	if !decode(w, r, &svc) {
+		return
+	}
+	svc.Namespace, svc.ServiceName = r.PathValue("namespace"), r.PathValue("name")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now().UTC()
+	svc.ServiceID, svc.CreatedAt = uuid.New(), now
+	for _, existing := range s.services {
+		if existing.Namespace == svc.Namespace && existing.ServiceName == svc.ServiceName {
+			svc.ServiceID, svc.CreatedAt = existing.ServiceID, existing.CreatedAt
+		}
+	}
+	svc.UpdatedAt = now
+	s.services[svc.ServiceID.String()] = &svc
+	writeData(w, http.StatusOK, svc.ServiceID.String())
+}
+
+func (s *Server) listServices(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := []api.Service{}
+	for _, v := range sortedValues(s.services, func(v api.Service) string { return v.ServiceName }) {
+		if v.Namespace == r.PathValue("namespace") {
+			list = append(list, v)
+		}
+	}
+	writeData(w, http.StatusOK, list)
+}
+
+func (s *Server) deleteService(w http.ResponseWriter, r *http.Request) {
+	deleteByID(s, w, r, s.services, "service")
+}
+
+// deleteByID removes the resource named by the id path value from m.
+func deleteByID[T any](s *Server, w http.ResponseWriter, r *http.Request, m map[string]*T, kind string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := m[r.PathValue("id")]; !ok {
+		writeError(w, http.StatusNotFound, kind+" not found")
+		return
+	}
+	delete(m, r.PathValue("id"))
+	writeData(w, http.StatusOK, nil)
+}
+
+// --- Ingresses ----------------------------------------------------------
+
+func (s *Server) upsertIngress(w http.ResponseWriter, r *http.Request) {
+	var ing api.Ingress
+	if !decode(w, r, &ing) {
+		return
+	}
+	ing.Namespace, ing.AppLabel = r.PathValue("namespace"), r.PathValue("app")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now().UTC()
+	ing.IngressID, ing.CreatedAt = uuid.New(), now
+	for _, existing := range s.ingresses {
+		if existing.DeploymentID == ing.DeploymentID {
+			ing.IngressID, ing.CreatedAt = existing.IngressID, existing.CreatedAt
+		}
+	}
+	ing.UpdatedAt = now
+	s.ingresses[ing.IngressID.String()] = &ing
+	writeData(w, http.StatusOK, ing.IngressID.String())
+}
+
+func (s *Server) listIngresses(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := []api.Ingress{}
+	for _, v := range sortedValues(s.ingresses, func(v api.Ingress) string { return v.AppLabel }) {
+		if v.Namespace == r.PathValue("namespace") {
+			list = append(list, v)
+		}
+	}
+	writeData(w, http.StatusOK, list)
+}
+
+func (s *Server) getIngressByDeployment(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, v := range s.ingresses {
+		if v.DeploymentID.String() == r.PathValue("id") {
+			writeData(w, http.StatusOK, v)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "ingress not found")
+}
+
+func (s *Server) getIngressByDomain(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, v := range s.ingresses {
+		if v.DomainName == r.PathValue("domain") {
+			writeData(w, http.StatusOK, v)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "ingress not found")
+}
+
+func (s *Server) generateDomainName(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	s.domains++
+	n := s.domains
+	s.mu.Unlock()
+	writeData(w, http.StatusOK, fmt.Sprintf("fake-app-%d.satusky.com", n))
+}
+
+func (s *Server) deleteIngress(w http.ResponseWriter, r *http.Request) {
+	deleteByID(s, w, r, s.ingresses, "ingress")
+}
+
+// --- Environments and secrets -------------------------------------------
+
+// mergeKeyValues sets the keys in update on base, keeping base's order and
+// appending new keys.
+func mergeKeyValues(base, update []api.KeyValuePair) []api.KeyValuePair {
+	out := append([]api.KeyValuePair(nil), base...)
+	for _, kv := range update {
+		found := false
+		for i := range out {
+			if out[i].Key == kv.Key {
+				out[i].Value, found = kv.Value, true
+			}
+		}
+		if !found {
+			out = append(out, kv)
+		}
+	}
+	return out
+}
+
+func removeKey(kvs []api.KeyValuePair, key string) ([]api.KeyValuePair, bool) {
+	for i, kv := range kvs {
+		if kv.Key == key {
+			return append(kvs[:i:i], kvs[i+1:]...), true
+		}
+	}
+	return kvs, false
+}
+
+func (s *Server) upsertEnvironment(w http.ResponseWriter, r *http.Request) {
+	var env api.Environment
+	if !decode(w, r, &env) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now().UTC()
+	env.EnvironmentID, env.CreatedAt = uuid.New(), now
+	for _, existing := range s.environments {
+		if existing.DeploymentID == env.DeploymentID && existing.AppLabel == env.AppLabel {
+			env.EnvironmentID, env.CreatedAt = existing.EnvironmentID, existing.CreatedAt
+			env.KeyValues = mergeKeyValues(existing.KeyValues, env.KeyValues)
+		}
+	}
+	env.UpdatedAt = now
+	s.environments[env.EnvironmentID.String()] = &env
+	writeData(w, http.StatusOK, env)
+}
+
+func (s *Server) listEnvironments(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := []api.Environment{}
+	for _, v := range sortedValues(s.environments, func(v api.Environment) string { return v.AppLabel }) {
+		if v.Namespace == r.PathValue("namespace") {
+			list = append(list, v)
+		}
+	}
+	writeData(w, http.StatusOK, list)
+}
+
+func (s *Server) listEnvironmentsByDeployment(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := []api.Environment{}
+	for _, v := range sortedValues(s.environments, func(v api.Environment) string { return v.AppLabel }) {
+		if v.DeploymentID.String() == r.PathValue("id") {
+			list = append(list, v)
+		}
+	}
+	writeData(w, http.StatusOK, list)
+}
+
+func (s *Server) unsetEnvironmentKey(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Key string `json:"key"`
+	}
+	if !decode(w, r, &body) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	env, ok := s.environments[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "environment not found")
+		return
+	}
+	if env.KeyValues, ok = removeKey(env.KeyValues, body.Key); !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("key %s not found", body.Key))
+		return
+	}
+	writeData(w, http.StatusOK, nil)
+}
+
+func (s *Server) deleteEnvironment(w http.ResponseWriter, r *http.Request) {
+	deleteByID(s, w, r, s.environments, "environment")
+}
+
+func (s *Server) upsertSecret(w http.ResponseWriter, r *http.Request) {
+	var secret api.Secret
+	if !decode(w, r, &secret) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now().UTC()
+	secret.SecretID, secret.CreatedAt = uuid.New(), now
+	for _, existing := range s.secrets {
+		if existing.DeploymentID == secret.DeploymentID && existing.AppLabel == secret.AppLabel {
+			secret.SecretID, secret.CreatedAt = existing.SecretID, existing.CreatedAt
+			secret.KeyValues = mergeKeyValues(existing.KeyValues, secret.KeyValues)
+		}
+	}
+	secret.UpdatedAt = now
+	s.secrets[secret.SecretID.String()] = &secret
+	writeData(w, http.StatusOK, secret)
+}
+
+func (s *Server) listSecrets(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := []api.Secret{}
+	for _, v := range sortedValues(s.secrets, func(v api.Secret) string { return v.AppLabel }) {
+		if v.Namespace == r.PathValue("namespace") {
+			list = append(list, v)
+		}
+	}
+	writeData(w, http.StatusOK, list)
+}
+
+func (s *Server) listSecretsByDeployment(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := []api.Secret{}
+	for _, v := range sortedValues(s.secrets, func(v api.Secret) string { return v.AppLabel }) {
+		if v.DeploymentID.String() == r.PathValue("id") {
+			list = append(list, v)
+		}
+	}
+	writeData(w, http.StatusOK, list)
+}
+
+func (s *Server) unsetSecretKey(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Key string `json:"key"`
+	}
+	if !decode(w, r, &body) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	secret, ok := s.secrets[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "secret not found")
+		return
+	}
+	if secret.KeyValues, ok = removeKey(secret.KeyValues, body.Key); !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("key %s not found", body.Key))
+		return
+	}
+	writeData(w, http.StatusOK, nil)
+}
+
+func (s *Server) deleteSecret(w http.ResponseWriter, r *http.Request) {
+	deleteByID(s, w, r, s.secrets, "secret")
+}
+
+// --- Volumes ------------------------------------------------------------
+
+func (s *Server) createVolume(w http.ResponseWriter, r *http.Request) {
+	var v api.Volume
+	if !decode(w, r, &v) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now().UTC()
+	v.VolumeID, v.CreatedAt, v.UpdatedAt = uuid.New(), now, now
+	s.volumes[v.VolumeID.String()] = &v
+	writeData(w, http.StatusCreated, v)
+}
+
+// lifecycle reports v as bound, and mounted while it is attached.
+func lifecycle(v api.Volume) api.VolumeLifecycleStatus {
+	return api.VolumeLifecycleStatus{
+		Volume: v,
+		PVC: api.VolumePVCStatus{
+			Name: v.ClaimName, Namespace: v.Namespace, Exists: true, Phase: "Bound",
+			StorageClass: v.StorageClass, Capacity: v.StorageSize,
+		},
+		Mount:         api.VolumeMountStatus{Attached: v.DesiredAttached, Mounted: v.DesiredAttached, Path: v.MountPath},
+		DestroyPolicy: "delete",
+	}
+}
+
+func (s *Server) listVolumes(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeData(w, http.StatusOK, sortedValues(s.volumes, func(v api.Volume) string { return v.VolumeName }))
+}
+
+func (s *Server) volume(w http.ResponseWriter, r *http.Request) (*api.Volume, bool) {
+	v, ok := s.volumes[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "volume not found")
+	}
+	return v, ok
+}
+
+func (s *Server) volumeStatus(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if v, ok := s.volume(w, r); ok {
+		writeData(w, http.StatusOK, lifecycle(*v))
+	}
+}
+
+func (s *Server) deploymentVolumeStatuses(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := []api.VolumeLifecycleStatus{}
+	for _, v := range sortedValues(s.volumes, func(v api.Volume) string { return v.VolumeName }) {
+		if v.DeploymentID.String() == r.PathValue("id") {
+			list = append(list, lifecycle(v))
+		}
+	}
+	writeData(w, http.StatusOK, list)
+}
+
+func (s *Server) detachVolume(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if v, ok := s.volume(w, r); ok {
+		v.DesiredAttached = false
+		v.UpdatedAt = time.Now().UTC()
+		writeData(w, http.StatusOK, lifecycle(*v))
+	}
+}
+
+func (s *Server) deleteVolumePVC(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if v, ok := s.volume(w, r); ok {
+		status := lifecycle(*v)
+		status.PVC.Exists, status.PVC.Phase = false, ""
+		status.Mount = api.VolumeMountStatus{}
+		delete(s.volumes, r.PathValue("id"))
+		writeData(w, http.StatusOK, status)
+	}
+}
+
+// --- Builds -------------------------------------------------------------
+
+func (s *Server) submitBuild(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid build upload: %s", err.Error()))
+		return
+	}
+	if _, _, err := r.FormFile("context"); err != nil {
+		writeError(w, http.StatusBadRequest, "missing build context")
+		return
+	}
+	project := r.FormValue("project")
+	if project == "" {
+		writeError(w, http.StatusBadRequest, "missing project")
+		return
+	}
+	id := uuid.NewString()
+	s.mu.Lock()
+	s.builds[id] = &api.BuildStatusResponse{
+		BuildID:   id,
+		Status:    "completed",
+		ImageRef:  fmt.Sprintf("registry.satusky.com/%s/%s:%s", s.Namespace, project, id[:8]),
+		ImageArch: "amd64",
+		Logs:      fmt.Sprintf("Building %s from %s\nPushed image\n", project, r.FormValue("dockerfile")),
+	}
+	s.mu.Unlock()
+	writeData(w, http.StatusCreated, api.BuildResponse{BuildID: id, Status: "queued"})
+}
+
+func (s *Server) buildStatus(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.builds[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "build not found")
+		return
+	}
+	writeData(w, http.StatusOK, b)
+}
@@ -27,59 +27,11 @@ type AuditLog struct {
 	CreatedAt      time.Time              `json:"created_at"`
 }
 
-// auditLogListResponse represents paginated audit log response
-type auditLogListResponse struct {
-	AuditLogs []AuditLog `json:"audit_logs"`
-	Total     int        `json:"total"`
-	Limit     int        `json:"limit"`
-	Offset    int        `json:"offset"`
-}
-
-// GetAuditLogs gets audit logs for an organization
-func GetAuditLogs(orgID string, limit int, action, userID string) ([]AuditLog, error) {
-	path := fmt.Sprintf("/audit-logs/organizations/%s", orgID)
-	params := []string{}
-	if limit > 0 {
-		params = append(params, fmt.Sprintf("limit=%d", limit))
-	}
-	if action != "" {
-		params = append(params, fmt.Sprintf("action=%s", action))
-	}
-	if userID != "" {
-		params = append(params, fmt.Sprintf("user_id=%s", userID))
-	}
-	if len(params) > 0 {
-		path = path + "?"
-		for i, p := range params {
-			if i > 0 {
-				path += "&"
-			}
-			path += p
-		}
-	}
-
-	var resp apiResponse
-	err := makeRequest("GET", path, nil, &resp)
-	if err != nil {
-		return nil, err
-	}
-
-	data, err := json.Marshal(resp.Data)
-	if err != nil {
-		return nil, utils.NewError(fmt.Sprintf("failed to marshal response data: %s", err.Error()), nil)
-	}
-
-	// Try paginated response first, fall back to flat array
-	var listResp auditLogListResponse
-	if err := json.Unmarshal(data, &listResp); err == nil && listResp.AuditLogs != nil {
-		return listResp.AuditLogs, nil
-	}
-
-	var logs []AuditLog
-	if err := json.Unmarshal(data, &logs); err != nil {
-		return nil, utils.NewError(fmt.Sprintf("failed to unmarshal audit logs: %s", err.Error()), nil)
-	}
-	return logs, nil
+// ListAuditLogs fetches an organization's audit logs, filtered by action
+// and user ID when they are set, passing each page to yield.
+func ListAuditLogs(orgID, action, userID string, opts PageOptions, yield func([]AuditLog) error) error {
+	path := listPath(fmt.Sprintf("/audit-logs/organizations/%s", orgID), map[string]string{"action": action, "user_id": userID})
+	return Paginate(defaultClient, ListEndpoint{Path: path, ItemsKey: "audit_logs"}, opts, yield)
 }
 
 // GetAuditLog gets a specific audit log
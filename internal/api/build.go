@@ -1,12 +1,11 @@
 package api
 
 import (
-	"1ctl/internal/config"
-	"1ctl/internal/context"
 	"1ctl/internal/utils"
 	"bufio"
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
@@ -21,7 +20,8 @@ import (
 // Uses a longer timeout than the shared API client because build archives
 // can be large and the upload may take time on slow connections.
 var buildUploadClient = &http.Client{
-	Timeout: 5 * time.Minute,
+	Timeout:   5 * time.Minute,
+	Transport: tracingTransport{},
 }
 
 // BuildResponse is returned by POST /v1/cli/builds.
@@ -48,10 +48,10 @@ const (
 // SubmitBuild uploads the gzipped build context to the backend and returns the
 // build ID. The backend selects a cloud builder, builds the image, and pushes it
 // to the internal registry.
-func SubmitBuild(contextTarPath, projectName, dockerfilePath, builder string, buildArgs map[string]string) (string, error) {
-	token := context.GetToken()
-	if token == "" {
-		return "", utils.NewError("not authenticated. Please run '1ctl auth login' to authenticate", nil)
+func (c *Client) SubmitBuild(contextTarPath, projectName, dockerfilePath, builder string, buildArgs map[string]string) (string, error) {
+	token, err := c.authToken()
+	if err != nil {
+		return "", err
 	}
 
 	f, err := os.Open(contextTarPath) // #nosec G304 -- caller-supplied temp file from PackageContext
@@ -93,12 +93,16 @@ func SubmitBuild(contextTarPath, projectName, dockerfilePath, builder string, bu
 		return "", utils.NewError(fmt.Sprintf("failed to close multipart writer: %s", err.Error()), nil)
 	}
 
-	cfg := config.GetConfig()
-	apiURL := cfg.ApiURL + "/builds"
+	baseURL := c.baseURL()
+	apiURL := baseURL + "/builds"
 
 	// Enforce HTTPS for non-localhost API URLs to prevent token leakage
 	if !utils.IsLocalhostURL(apiURL) && !strings.HasPrefix(apiURL, "https://") {
-		return "", utils.NewError(fmt.Sprintf("refusing to send auth token over insecure connection (%s). Use HTTPS or http://localhost for local development", cfg.ApiURL), nil)
+		return "", utils.NewError(fmt.Sprintf("refusing to send auth token over insecure connection (%s). Use HTTPS or http://localhost for local development", baseURL), nil)
+	}
+
+	if err := checkBeforeRequest(apiURL); err != nil {
+		return "", err
 	}
 
 	req, err := http.NewRequest("POST", apiURL, body)
@@ -106,27 +110,32 @@ func SubmitBuild(contextTarPath, projectName, dockerfilePath, builder string, bu
 		return "", utils.NewError(fmt.Sprintf("failed to create request: %s", err.Error()), nil)
 	}
 	req.Header.Set("Content-Type", w.FormDataContentType())
+	c.setVersionHeaders(req)
 	req.Header.Set("x-satusky-api-key", token)
-	if email := context.GetEmail(); email != "" {
+	if email := c.email(); email != "" {
 		req.Header.Set("x-satusky-user-email", email)
 	}
 
-	resp, err := buildUploadClient.Do(req)
+	resp, err := c.client(buildUploadClient).Do(req)
 	if err != nil {
-		return "", utils.NewError(fmt.Sprintf("failed to submit build: %s", err.Error()), nil)
+		return "", utils.NewKindError(utils.ErrNetwork, fmt.Sprintf("failed to submit build: %s", err.Error()), nil)
 	}
 	defer func() { _ = resp.Body.Close() }() //nolint:errcheck
+	if err := checkResponse(http.MethodPost, resp); err != nil {
+		return "", err
+	}
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", utils.NewError(fmt.Sprintf("failed to read build response: %s", err.Error()), nil)
+		return "", utils.NewKindError(utils.ErrNetwork, fmt.Sprintf("failed to read build response: %s", err.Error()), nil)
 	}
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		requestID := resp.Header.Get(requestIDHeader)
 		var apiErr APIError
 		if jsonErr := json.Unmarshal(respBody, &apiErr); jsonErr == nil && apiErr.Message != "" {
-			return "", utils.NewError(fmt.Sprintf("build submission failed: %s", apiErr.Message), nil)
+			return "", utils.NewAPIError(resp.StatusCode, apiErr.Code, requestID, fmt.Sprintf("build submission failed: %s", apiErr.Message))
 		}
-		return "", utils.NewError(fmt.Sprintf("build submission failed (HTTP %d): %s", resp.StatusCode, string(respBody)), nil)
+		return "", utils.NewAPIError(resp.StatusCode, "", requestID, fmt.Sprintf("build submission failed (HTTP %d): %s", resp.StatusCode, string(respBody)))
 	}
 
 	var apiResp struct {
@@ -143,12 +152,12 @@ func SubmitBuild(contextTarPath, projectName, dockerfilePath, builder string, bu
 }
 
 // GetBuildStatus returns the current build status and any accumulated logs.
-func GetBuildStatus(buildID string) (*BuildStatusResponse, error) {
+func (c *Client) GetBuildStatus(buildID string) (*BuildStatusResponse, error) {
 	var resp struct {
 		Error bool                `json:"error"`
 		Data  BuildStatusResponse `json:"data"`
 	}
-	if err := makeRequest("GET", fmt.Sprintf("/builds/%s/status", buildID), nil, &resp); err != nil {
+	if err := c.do("GET", fmt.Sprintf("/builds/%s/status", buildID), nil, &resp); err != nil {
 		return nil, err
 	}
 	return &resp.Data, nil
@@ -160,28 +169,50 @@ type BuildResult struct {
 	ImageArch string // "amd64", "arm64", or "" if detection failed
 }
 
+// buildPollInterval is how often WaitForBuildResult polls; tests shorten it.
+var buildPollInterval = 3 * time.Second
+
+// transientPollError reports whether a failed build status poll is worth
+// repeating: the API could not be reached or answered with a status that
+// retryableStatus allows. Anything else will fail the same way again.
+func transientPollError(err error) bool {
+	if utils.KindOf(err) == utils.ErrNetwork {
+		return true
+	}
+	var cliErr *utils.CLIError
+	return errors.As(err, &cliErr) && retryableStatus(cliErr.Status)
+}
+
 // WaitForBuildResult polls the cloud-build job until completion, streaming
 // new log lines to progressWriter, and returns the BuildResult (image ref +
 // detected image architecture).
-func WaitForBuildResult(buildID string, progressWriter io.Writer) (*BuildResult, error) {
+func (c *Client) WaitForBuildResult(buildID string, progressWriter io.Writer) (*BuildResult, error) {
 	const (
-		pollInterval = 3 * time.Second
-		maxWait      = 15 * time.Minute
+		maxWait = 15 * time.Minute
+		// GetBuildStatus already retries transient failures, so this many
+		// failed polls in a row means the build cannot be followed.
+		maxPollErrors = 5
 	)
 
 	deadline := time.Now().Add(maxWait)
-	ticker := time.NewTicker(pollInterval)
+	ticker := time.NewTicker(buildPollInterval)
 	defer ticker.Stop()
 
-	var logOffset int
+	var logOffset, pollErrors int
 
 	for time.Now().Before(deadline) {
 		<-ticker.C
 
-		status, err := GetBuildStatus(buildID)
+		status, err := c.GetBuildStatus(buildID)
 		if err != nil {
+			pollErrors++
+			utils.Debugf("polling build %s failed (%d of %d): %s", buildID, pollErrors, maxPollErrors, err.Error())
+			if !transientPollError(err) || pollErrors >= maxPollErrors {
+				return nil, utils.NewError(fmt.Sprintf("lost track of cloud build %s", buildID), err)
+			}
 			continue
 		}
+		pollErrors = 0
 
 		if len(status.Logs) > logOffset {
 			newLogs := status.Logs[logOffset:]
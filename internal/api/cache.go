@@ -0,0 +1,158 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"1ctl/internal/context"
+	"1ctl/internal/utils"
+)
+
+// Slow-changing lookups such as zones, clusters and pricing are cached on
+// disk under the profile's cache directory. A cached response is used as is
+// for its endpoint's TTL, then revalidated with If-None-Match, and is used
+// when the API cannot be reached at all.
+
+// NoCacheEnv disables the response cache, like --no-cache.
+const NoCacheEnv = "SATUSKY_NO_CACHE"
+
+// How long each cached lookup is used before it is revalidated.
+const (
+	zonesCacheTTL         = time.Hour
+	clustersCacheTTL      = 10 * time.Minute
+	pricingCacheTTL       = time.Hour
+	labelKeysCacheTTL     = 10 * time.Minute
+	machineLabelsCacheTTL = 5 * time.Minute
+	orgsCacheTTL          = 5 * time.Minute
+)
+
+var cacheDisabled atomic.Bool
+
+// SetCacheEnabled turns the response cache on or off for this process.
+func SetCacheEnabled(enabled bool) {
+	cacheDisabled.Store(!enabled)
+}
+
+// cacheEntry is a cached response body, stored as
+// <cache dir>/<key>.json.
+type cacheEntry struct {
+	URL      string          `json:"url"`
+	ETag     string          `json:"etag,omitempty"`
+	StoredAt time.Time       `json:"stored_at"`
+	Body     json.RawMessage `json:"body"`
+}
+
+// getCached is do for a GET of path whose response may be cached for ttl.
+func (c *Client) getCached(path string, ttl time.Duration, response interface{}) error {
+	url := c.baseURL() + path
+	if cacheDisabled.Load() {
+		return c.doURL(http.MethodGet, url, nil, response)
+	}
+
+	file := c.cacheFile(url)
+	entry, cached := readCacheEntry(file, url)
+	if cached && time.Since(entry.StoredAt) < ttl {
+		utils.Debugf("GET %s: cached %s ago", url, time.Since(entry.StoredAt).Round(time.Second))
+		return decodeCached(entry.Body, response)
+	}
+
+	var header http.Header
+	if cached && entry.ETag != "" {
+		header = http.Header{"If-None-Match": {entry.ETag}}
+	}
+	resp, body, err := c.send(http.MethodGet, url, nil, header)
+	if err != nil {
+		if cached && utils.KindOf(err) == utils.ErrNetwork {
+			utils.Debugf("GET %s: %v; using the response cached %s ago", url, err, time.Since(entry.StoredAt).Round(time.Second))
+			return decodeCached(entry.Body, response)
+		}
+		return err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached:
+		entry.StoredAt = time.Now()
+	case resp.StatusCode >= 400:
+		return responseError(resp, body)
+	default:
+		entry = cacheEntry{URL: url, ETag: resp.Header.Get("ETag"), StoredAt: time.Now(), Body: body}
+	}
+
+	if err := decodeCached(entry.Body, response); err != nil {
+		return err
+	}
+	if err := writeCacheEntry(file, entry); err != nil {
+		utils.Debugf("failed to cache %s: %v", url, err)
+	}
+	return nil
+}
+
+// cacheFile returns the cache file for url. The key includes the user and
+// organization, whose permissions shape the response.
+func (c *Client) cacheFile(url string) string {
+	sum := sha256.Sum256([]byte(url + "\n" + c.email() + "\n" + context.GetCurrentOrgID()))
+	return filepath.Join(context.Default().CacheDir(), hex.EncodeToString(sum[:16])+".json")
+}
+
+// invalidateCache drops the cached response for path after a change to it.
+func (c *Client) invalidateCache(path string) {
+	if err := os.Remove(c.cacheFile(c.baseURL() + path)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		utils.Debugf("failed to drop cached %s: %v", path, err)
+	}
+}
+
+// ClearCache deletes the active profile's cached responses, or every
+// profile's with allProfiles, and returns the directory it cleared.
+func ClearCache(allProfiles bool) (string, error) {
+	dir := context.Default().CacheDir()
+	if allProfiles {
+		dir = filepath.Dir(dir)
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return dir, utils.NewError(fmt.Sprintf("failed to clear %s", dir), err)
+	}
+	return dir, nil
+}
+
+func readCacheEntry(file, url string) (cacheEntry, bool) {
+	data, err := os.ReadFile(file) // #nosec G304 -- path built from the config dir and a hash
+	if err != nil {
+		return cacheEntry{}, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.URL != url {
+		return cacheEntry{}, false
+	}
+	return entry, true
+}
+
+func writeCacheEntry(file string, entry cacheEntry) error {
+	if !json.Valid(entry.Body) {
+		return errors.New("response is not JSON")
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return err
+	}
+	return context.WriteFileAtomic(file, data, 0600)
+}
+
+func decodeCached(body []byte, response interface{}) error {
+	if response == nil || len(body) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(body, response); err != nil {
+		return utils.NewError(fmt.Sprintf("failed to parse response: %s", err.Error()), nil)
+	}
+	return nil
+}
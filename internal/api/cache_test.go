@@ -0,0 +1,137 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"1ctl/internal/context"
+)
+
+// ageCache makes every cached response of the active profile d older.
+func ageCache(t *testing.T, d time.Duration) {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(context.Default().CacheDir(), "*.json"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no cached responses (err %v)", err)
+	}
+	for _, file := range files {
+		var entry cacheEntry
+		data, err := os.ReadFile(file) // #nosec G304 -- test file
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(data, &entry); err != nil {
+			t.Fatal(err)
+		}
+		entry.StoredAt = entry.StoredAt.Add(-d)
+		if err := writeCacheEntry(file, entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCachedLookupRevalidatesWithETag(t *testing.T) {
+	var (
+		requests    []string
+		unreachable bool
+	)
+	useRetryTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		if unreachable {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				_ = conn.Close() //nolint:errcheck
+			}
+			return
+		}
+		requests = append(requests, r.Header.Get("If-None-Match"))
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": []ZoneOption{{Value: "my-kul-1"}}})
+	})
+	SetCacheEnabled(true)
+	t.Cleanup(func() { SetCacheEnabled(true) })
+
+	zones := func(step string) {
+		t.Helper()
+		got, err := GetAvailableZones()
+		if err != nil {
+			t.Fatalf("%s: GetAvailableZones() error = %v", step, err)
+		}
+		if len(got) != 1 || got[0].Value != "my-kul-1" {
+			t.Fatalf("%s: zones = %+v", step, got)
+		}
+	}
+
+	zones("first lookup")
+	zones("within the TTL")
+	if len(requests) != 1 {
+		t.Fatalf("requests within the TTL = %q, want 1", requests)
+	}
+
+	ageCache(t, zonesCacheTTL)
+	zones("after the TTL")
+	if len(requests) != 2 || requests[1] != `"v1"` {
+		t.Fatalf("requests = %q, want a revalidation with If-None-Match", requests)
+	}
+	zones("after a 304")
+	if len(requests) != 2 {
+		t.Fatalf("a 304 did not renew the cached response: requests = %q", requests)
+	}
+
+	ageCache(t, zonesCacheTTL)
+	unreachable = true
+	zones("with the API unreachable")
+
+	unreachable = false
+	SetCacheEnabled(false)
+	zones("with --no-cache")
+	if len(requests) != 3 || requests[2] != "" {
+		t.Errorf("requests = %q, want an unconditional request with --no-cache", requests)
+	}
+}
+
+func TestLabelChangesInvalidateCachedLabels(t *testing.T) {
+	labels := map[string]string{"satusky.com/gpu": "a100"}
+	gets := 0
+	useRetryTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch {
+			labels = map[string]string{"satusky.com/gpu": "h100"}
+			writeJSON(w, http.StatusOK, map[string]interface{}{"data": nil})
+			return
+		}
+		gets++
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]interface{}{"custom": labels}})
+	})
+	SetCacheEnabled(true)
+
+	for i := 0; i < 2; i++ {
+		if _, err := GetMachineLabels("m-1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := UpdateMachineLabels("m-1", map[string]string{"satusky.com/gpu": "h100"}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := GetMachineLabels("m-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gets != 2 || got["satusky.com/gpu"] != "h100" {
+		t.Errorf("after an update: %d GETs, labels %v; want 2 GETs and the new labels", gets, got)
+	}
+
+	dir, err := ClearCache(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("cache dir %s still exists after ClearCache", dir)
+	}
+}
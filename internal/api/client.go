@@ -20,7 +20,8 @@ import (
 // httpClient is a shared HTTP client with a reasonable timeout.
 // 30 seconds is sufficient for most API calls while preventing indefinite hangs.
 var httpClient = &http.Client{
-	Timeout: 30 * time.Second,
+	Timeout:   30 * time.Second,
+	Transport: tracingTransport{},
 }
 
 // Common response structure that matches backend
@@ -32,9 +33,9 @@ type apiResponse struct {
 }
 
 // DeleteDeployment deletes a deployment
-func DeleteDeployment(deploymentID string) (*DeletionResult, error) {
+func (c *Client) DeleteDeployment(deploymentID string) (*DeletionResult, error) {
 	var resp apiResponse
-	if err := makeRequest("POST", fmt.Sprintf("/deployments/delete/%s", deploymentID), nil, &resp); err != nil {
+	if err := c.do("POST", fmt.Sprintf("/deployments/delete/%s", deploymentID), nil, &resp); err != nil {
 		return nil, err
 	}
 
@@ -51,58 +52,58 @@ func DeleteDeployment(deploymentID string) (*DeletionResult, error) {
 }
 
 // RestartDeployment triggers a rolling restart of a deployment
-func RestartDeployment(deploymentID string) error {
-	return makeRequest("POST", fmt.Sprintf("/deployments/%s/restart", deploymentID), nil, nil)
+func (c *Client) RestartDeployment(deploymentID string) error {
+	return c.do("POST", fmt.Sprintf("/deployments/%s/restart", deploymentID), nil, nil)
 }
 
 // ListDeployments lists all deployments for the current namespace.
-// Thin wrapper around ListDeploymentsByNamespace using the active context.
-func ListDeployments() ([]Deployment, error) {
-	namespace, err := context.GetCurrentNamespaceOrError()
+// Thin wrapper around ListDeploymentsByNamespace using the client's namespace.
+func (c *Client) ListDeployments() ([]Deployment, error) {
+	namespace, err := c.namespaceOrError()
 	if err != nil {
 		return nil, err
 	}
-	return ListDeploymentsByNamespace(namespace)
+	return c.ListDeploymentsByNamespace(namespace)
 }
 
 // ListDeploymentVersions returns the release history for a deployment.
-func ListDeploymentVersions(deploymentID string) ([]DeploymentVersion, error) {
+func (c *Client) ListDeploymentVersions(deploymentID string) ([]DeploymentVersion, error) {
 	var resp struct {
 		Error   bool                `json:"error"`
 		Message string              `json:"message"`
 		Data    []DeploymentVersion `json:"data"`
 	}
-	if err := makeRequest("GET", fmt.Sprintf("/deployments/%s/versions", deploymentID), nil, &resp); err != nil {
+	if err := c.do("GET", fmt.Sprintf("/deployments/%s/versions", deploymentID), nil, &resp); err != nil {
 		return nil, err
 	}
 	return resp.Data, nil
 }
 
 // RollbackDeployment initiates a rollback to the specified version number.
-func RollbackDeployment(deploymentID string, versionNumber int) error {
-	return makeRequest("POST", fmt.Sprintf("/deployments/%s/rollback/%d", deploymentID, versionNumber), nil, nil)
+func (c *Client) RollbackDeployment(deploymentID string, versionNumber int) error {
+	return c.do("POST", fmt.Sprintf("/deployments/%s/rollback/%d", deploymentID, versionNumber), nil, nil)
 }
 
 // ListDeploymentsByNamespace lists deployments in a specific namespace
-func ListDeploymentsByNamespace(namespace string) ([]Deployment, error) {
+func (c *Client) ListDeploymentsByNamespace(namespace string) ([]Deployment, error) {
 	var response struct {
 		Error   bool         `json:"error"`
 		Message string       `json:"message"`
 		Count   int          `json:"count"`
 		Data    []Deployment `json:"data"`
 	}
-	err := makeRequest("GET", fmt.Sprintf("/deployments/namespace/%s", namespace), nil, &response)
+	err := c.do("GET", fmt.Sprintf("/deployments/namespace/%s", namespace), nil, &response)
 	return response.Data, err
 }
 
 // GetDeploymentByAppLabel looks up a deployment by its app label within a namespace.
 // This is the primary way the CLI resolves deployment IDs from satusky.toml.
-func GetDeploymentByAppLabel(namespace, appLabel string) (*Deployment, error) {
+func (c *Client) GetDeploymentByAppLabel(namespace, appLabel string) (*Deployment, error) {
 	var resp struct {
 		Error bool       `json:"error"`
 		Data  Deployment `json:"data"`
 	}
-	if err := makeRequest("GET", fmt.Sprintf("/deployments/namespace/%s/app/%s", namespace, appLabel), nil, &resp); err != nil {
+	if err := c.do("GET", fmt.Sprintf("/deployments/namespace/%s/app/%s", namespace, appLabel), nil, &resp); err != nil {
 		return nil, err
 	}
 	return &resp.Data, nil
@@ -112,12 +113,12 @@ func GetDeploymentByAppLabel(namespace, appLabel string) (*Deployment, error) {
 // Uses the typed-inline-struct pattern: the response is unmarshalled once
 // directly into the Deployment struct, avoiding the legacy apiResponse +
 // json.Marshal + json.Unmarshal double-encoding round trip.
-func GetDeployment(deploymentID string) (*Deployment, error) {
+func (c *Client) GetDeployment(deploymentID string) (*Deployment, error) {
 	var resp struct {
 		Error bool       `json:"error"`
 		Data  Deployment `json:"data"`
 	}
-	if err := makeRequest("GET", fmt.Sprintf("/deployments/id/%s", deploymentID), nil, &resp); err != nil {
+	if err := c.do("GET", fmt.Sprintf("/deployments/id/%s", deploymentID), nil, &resp); err != nil {
 		return nil, err
 	}
 	return &resp.Data, nil
@@ -125,10 +126,10 @@ func GetDeployment(deploymentID string) (*Deployment, error) {
 
 // Service methods
 
-func ListServices() ([]Service, error) {
-	namespace := context.GetCurrentNamespace()
+func (c *Client) ListServices() ([]Service, error) {
+	namespace := c.namespace()
 	var resp apiResponse
-	err := makeRequest("GET", fmt.Sprintf("/services/namespace/%s", namespace), nil, &resp)
+	err := c.do("GET", fmt.Sprintf("/services/namespace/%s", namespace), nil, &resp)
 	if err != nil {
 		return nil, err
 	}
@@ -145,13 +146,13 @@ func ListServices() ([]Service, error) {
 	return services, nil
 }
 
-func DeleteService(serviceID string) error {
+func (c *Client) DeleteService(serviceID string) error {
 	var resp apiResponse
-	return makeRequest("POST", fmt.Sprintf("/services/delete/%s", serviceID), nil, &resp)
+	return c.do("POST", fmt.Sprintf("/services/delete/%s", serviceID), nil, &resp)
 }
 
 // Secret methods
-func CreateSecret(secret Secret) (*Secret, error) {
+func (c *Client) CreateSecret(secret Secret) (*Secret, error) {
 	var resp apiResponse
 	var secretResp Secret
 	resp.Data = &secretResp
@@ -160,10 +161,11 @@ func CreateSecret(secret Secret) (*Secret, error) {
 	// a non-empty value silently routes resources to the wrong namespace when
 	// the deploy was scoped via --organization.
 	if secret.Namespace == "" {
-		secret.Namespace = context.GetCurrentNamespace()
+		secret.Namespace = c.namespace()
 	}
 
-	err := makeRequest("POST", "/secrets/upsert", secret, &resp)
+	registerSecrets(secret)
+	err := c.do("POST", "/secrets/upsert", secret, &resp)
 	if err != nil {
 		return nil, err
 	}
@@ -176,13 +178,14 @@ func CreateSecret(secret Secret) (*Secret, error) {
 	if err := json.Unmarshal(data, &secretResp); err != nil {
 		return nil, utils.NewError(fmt.Sprintf("failed to unmarshal secret response: %s", err.Error()), nil)
 	}
+	registerSecrets(secretResp)
 	return &secretResp, nil
 }
 
-func ListSecrets() ([]Secret, error) {
-	namespace := context.GetCurrentNamespace()
+func (c *Client) ListSecrets() ([]Secret, error) {
+	namespace := c.namespace()
 	var resp apiResponse
-	err := makeRequest("GET", fmt.Sprintf("/secrets/namespace/%s", namespace), nil, &resp)
+	err := c.do("GET", fmt.Sprintf("/secrets/namespace/%s", namespace), nil, &resp)
 	if err != nil {
 		return nil, err
 	}
@@ -196,18 +199,19 @@ func ListSecrets() ([]Secret, error) {
 	if err := json.Unmarshal(data, &secrets); err != nil {
 		return nil, utils.NewError(fmt.Sprintf("failed to unmarshal secrets: %s", err.Error()), nil)
 	}
+	registerSecrets(secrets...)
 	return secrets, nil
 }
 
-func DeleteSecret(secretID string) error {
+func (c *Client) DeleteSecret(secretID string) error {
 	var resp apiResponse
-	return makeRequest("POST", fmt.Sprintf("/secrets/delete/%s", secretID), nil, &resp)
+	return c.do("POST", fmt.Sprintf("/secrets/delete/%s", secretID), nil, &resp)
 }
 
 // GetSecretsByDeploymentID returns secrets for a given deployment ID.
-func GetSecretsByDeploymentID(deploymentID string) ([]Secret, error) {
+func (c *Client) GetSecretsByDeploymentID(deploymentID string) ([]Secret, error) {
 	var resp apiResponse
-	err := makeRequest("GET", fmt.Sprintf("/secrets/deploymentId/%s", deploymentID), nil, &resp)
+	err := c.do("GET", fmt.Sprintf("/secrets/deploymentId/%s", deploymentID), nil, &resp)
 	if err != nil {
 		return nil, err
 	}
@@ -221,23 +225,33 @@ func GetSecretsByDeploymentID(deploymentID string) ([]Secret, error) {
 	if err := json.Unmarshal(data, &secrets); err != nil {
 		return nil, utils.NewError(fmt.Sprintf("failed to unmarshal secrets: %s", err.Error()), nil)
 	}
+	registerSecrets(secrets...)
 	return secrets, nil
 }
 
+// registerSecrets marks secret values as sensitive so output masks them.
+func registerSecrets(secrets ...Secret) {
+	for _, s := range secrets {
+		for _, kv := range s.KeyValues {
+			utils.RegisterSecret(kv.Value)
+		}
+	}
+}
+
 // UnsetSecretKey removes a single key from a secret.
-func UnsetSecretKey(secretID, key string) error {
+func (c *Client) UnsetSecretKey(secretID, key string) error {
 	body := map[string]string{"key": key}
 	var resp struct{}
-	return makeRequest("POST", fmt.Sprintf("/secrets/unset/%s", secretID), body, &resp)
+	return c.do("POST", fmt.Sprintf("/secrets/unset/%s", secretID), body, &resp)
 }
 
 // Ingress methods
 
 // ListIngresses lists all ingresses for current namespace
-func ListIngresses() ([]Ingress, error) {
-	namespace := context.GetCurrentNamespace()
+func (c *Client) ListIngresses() ([]Ingress, error) {
+	namespace := c.namespace()
 	var resp apiResponse
-	err := makeRequest("GET", fmt.Sprintf("/ingresses/namespace/%s", namespace), nil, &resp)
+	err := c.do("GET", fmt.Sprintf("/ingresses/namespace/%s", namespace), nil, &resp)
 	if err != nil {
 		return nil, err
 	}
@@ -254,13 +268,13 @@ func ListIngresses() ([]Ingress, error) {
 	return ingresses, nil
 }
 
-func DeleteIngress(ingressID string) error {
+func (c *Client) DeleteIngress(ingressID string) error {
 	var resp apiResponse
-	return makeRequest("POST", fmt.Sprintf("/ingresses/delete/%s", ingressID), nil, &resp)
+	return c.do("POST", fmt.Sprintf("/ingresses/delete/%s", ingressID), nil, &resp)
 }
 
 // Environment methods
-func UpsertEnvironment(env Environment) (*Environment, error) {
+func (c *Client) UpsertEnvironment(env Environment) (*Environment, error) {
 	var resp apiResponse
 	var envResp Environment
 	resp.Data = &envResp
@@ -269,10 +283,10 @@ func UpsertEnvironment(env Environment) (*Environment, error) {
 	// a non-empty value silently routes resources to the wrong namespace when
 	// the deploy was scoped via --organization.
 	if env.Namespace == "" {
-		env.Namespace = context.GetCurrentNamespace()
+		env.Namespace = c.namespace()
 	}
 
-	err := makeRequest("POST", "/environments/upsert", env, &resp)
+	err := c.do("POST", "/environments/upsert", env, &resp)
 	if err != nil {
 		return nil, err
 	}
@@ -289,10 +303,10 @@ func UpsertEnvironment(env Environment) (*Environment, error) {
 }
 
 // ListEnvironments lists all environments for current namespace
-func ListEnvironments() ([]Environment, error) {
-	namespace := context.GetCurrentNamespace()
+func (c *Client) ListEnvironments() ([]Environment, error) {
+	namespace := c.namespace()
 	var resp apiResponse
-	err := makeRequest("GET", fmt.Sprintf("/environments/namespace/%s", namespace), nil, &resp)
+	err := c.do("GET", fmt.Sprintf("/environments/namespace/%s", namespace), nil, &resp)
 	if err != nil {
 		return nil, err
 	}
@@ -309,15 +323,15 @@ func ListEnvironments() ([]Environment, error) {
 	return environments, nil
 }
 
-func DeleteEnvironment(environmentID string) error {
+func (c *Client) DeleteEnvironment(environmentID string) error {
 	var resp apiResponse
-	return makeRequest("POST", fmt.Sprintf("/environments/delete/%s", environmentID), nil, &resp)
+	return c.do("POST", fmt.Sprintf("/environments/delete/%s", environmentID), nil, &resp)
 }
 
 // GetEnvironmentsByDeploymentID returns environments for a given deployment ID.
-func GetEnvironmentsByDeploymentID(deploymentID string) ([]Environment, error) {
+func (c *Client) GetEnvironmentsByDeploymentID(deploymentID string) ([]Environment, error) {
 	var resp apiResponse
-	err := makeRequest("GET", fmt.Sprintf("/environments/deploymentId/%s", deploymentID), nil, &resp)
+	err := c.do("GET", fmt.Sprintf("/environments/deploymentId/%s", deploymentID), nil, &resp)
 	if err != nil {
 		return nil, err
 	}
@@ -335,10 +349,10 @@ func GetEnvironmentsByDeploymentID(deploymentID string) ([]Environment, error) {
 }
 
 // UnsetEnvironmentKey removes a single key from an environment's ConfigMap.
-func UnsetEnvironmentKey(environmentID, key string) error {
+func (c *Client) UnsetEnvironmentKey(environmentID, key string) error {
 	body := map[string]string{"key": key}
 	var resp struct{}
-	return makeRequest("POST", fmt.Sprintf("/environments/unset/%s", environmentID), body, &resp)
+	return c.do("POST", fmt.Sprintf("/environments/unset/%s", environmentID), body, &resp)
 }
 
 // LoginCLI logs in the CLI with the API token
@@ -362,13 +376,13 @@ func LoginCLI(token string) (*TokenValidate, error) {
 
 	resp, err := httpClient.Do(request)
 	if err != nil {
-		return nil, utils.NewError(fmt.Sprintf("failed to send request: %s", err.Error()), nil)
+		return nil, utils.NewKindError(utils.ErrNetwork, fmt.Sprintf("failed to send request: %s", err.Error()), nil)
 	}
 	defer func() { _ = resp.Body.Close() }() //nolint:errcheck
 
 	if resp.StatusCode != 200 {
 		bodyBytes, _ := io.ReadAll(resp.Body) //nolint:errcheck
-		return nil, utils.NewError(fmt.Sprintf("login failed with status %d: %s", resp.StatusCode, string(bodyBytes)), nil)
+		return nil, utils.NewAPIError(resp.StatusCode, "", resp.Header.Get(requestIDHeader), fmt.Sprintf("login failed with status %d: %s", resp.StatusCode, string(bodyBytes)))
 	}
 
 	var result TokenValidate
@@ -377,72 +391,72 @@ func LoginCLI(token string) (*TokenValidate, error) {
 	}
 
 	if !result.Valid {
-		return nil, utils.NewError("invalid token", nil)
+		return nil, utils.NewKindError(utils.ErrAuth, "invalid token", nil)
 	}
 
 	return &result, nil
 }
 
 // CreateVolume creates a new volume for a deployment
-func CreateVolume(volume Volume) error {
-	return makeRequest("POST", "/volumes/create", volume, nil)
+func (c *Client) CreateVolume(volume Volume) error {
+	return c.do("POST", "/volumes/create", volume, nil)
 }
 
 // GetAllVolumes returns all volumes across all namespaces.
-func GetAllVolumes() ([]Volume, error) {
+func (c *Client) GetAllVolumes() ([]Volume, error) {
 	var resp struct {
 		Error bool     `json:"error"`
 		Data  []Volume `json:"data"`
 	}
-	if err := makeRequest("GET", "/volumes/all", nil, &resp); err != nil {
+	if err := c.do("GET", "/volumes/all", nil, &resp); err != nil {
 		return nil, err
 	}
 	return resp.Data, nil
 }
 
 // GetVolumeLifecycleStatus reports DB, PVC, and mount state for a volume.
-func GetVolumeLifecycleStatus(volumeID string) (*VolumeLifecycleStatus, error) {
+func (c *Client) GetVolumeLifecycleStatus(volumeID string) (*VolumeLifecycleStatus, error) {
 	var resp struct {
 		Error bool                  `json:"error"`
 		Data  VolumeLifecycleStatus `json:"data"`
 	}
-	if err := makeRequest("GET", fmt.Sprintf("/volumes/id/%s/status", volumeID), nil, &resp); err != nil {
+	if err := c.do("GET", fmt.Sprintf("/volumes/id/%s/status", volumeID), nil, &resp); err != nil {
 		return nil, err
 	}
 	return &resp.Data, nil
 }
 
 // GetDeploymentVolumeLifecycleStatuses reports all volume lifecycle state for a deployment.
-func GetDeploymentVolumeLifecycleStatuses(deploymentID string) ([]VolumeLifecycleStatus, error) {
+func (c *Client) GetDeploymentVolumeLifecycleStatuses(deploymentID string) ([]VolumeLifecycleStatus, error) {
 	var resp struct {
 		Error bool                    `json:"error"`
 		Data  []VolumeLifecycleStatus `json:"data"`
 	}
-	if err := makeRequest("GET", fmt.Sprintf("/volumes/deploymentId/%s/status", deploymentID), nil, &resp); err != nil {
+	if err := c.do("GET", fmt.Sprintf("/volumes/deploymentId/%s/status", deploymentID), nil, &resp); err != nil {
 		return nil, err
 	}
 	return resp.Data, nil
 }
 
 // DetachVolume removes the deployment mount reference without deleting the PVC.
-func DetachVolume(volumeID string) (*VolumeLifecycleStatus, error) {
+func (c *Client) DetachVolume(volumeID string) (*VolumeLifecycleStatus, error) {
 	var resp struct {
 		Error bool                  `json:"error"`
 		Data  VolumeLifecycleStatus `json:"data"`
 	}
-	if err := makeRequest("POST", fmt.Sprintf("/volumes/%s/detach", volumeID), nil, &resp); err != nil {
+	if err := c.do("POST", fmt.Sprintf("/volumes/%s/detach", volumeID), nil, &resp); err != nil {
 		return nil, err
 	}
 	return &resp.Data, nil
 }
 
 // DeleteVolumePVC detaches and destroys the live PVC, then removes the DB record.
-func DeleteVolumePVC(volumeID string) (*VolumeLifecycleStatus, error) {
+func (c *Client) DeleteVolumePVC(volumeID string) (*VolumeLifecycleStatus, error) {
 	var resp struct {
 		Error bool                  `json:"error"`
 		Data  VolumeLifecycleStatus `json:"data"`
 	}
-	if err := makeRequest("DELETE", fmt.Sprintf("/volumes/%s/pvc", volumeID), nil, &resp); err != nil {
+	if err := c.do("DELETE", fmt.Sprintf("/volumes/%s/pvc", volumeID), nil, &resp); err != nil {
 		return nil, err
 	}
 	return &resp.Data, nil
@@ -450,19 +464,19 @@ func DeleteVolumePVC(volumeID string) (*VolumeLifecycleStatus, error) {
 
 // GetDeploymentStatus gets the current status of a deployment.
 // Uses the typed-inline-struct pattern (see GetDeployment for rationale).
-func GetDeploymentStatus(deploymentID string) (*DeploymentStatus, error) {
+func (c *Client) GetDeploymentStatus(deploymentID string) (*DeploymentStatus, error) {
 	var resp struct {
 		Error bool             `json:"error"`
 		Data  DeploymentStatus `json:"data"`
 	}
-	if err := makeRequest("GET", fmt.Sprintf("/deployments/status/%s", deploymentID), nil, &resp); err != nil {
+	if err := c.do("GET", fmt.Sprintf("/deployments/status/%s", deploymentID), nil, &resp); err != nil {
 		return nil, err
 	}
 	return &resp.Data, nil
 }
 
 // WaitForDeployment waits for a deployment to reach a terminal state
-func WaitForDeployment(deploymentID string, timeout time.Duration) (*DeploymentStatus, error) {
+func (c *Client) WaitForDeployment(deploymentID string, timeout time.Duration) (*DeploymentStatus, error) {
 	ticker := time.NewTicker(5 * time.Second)
 	defer ticker.Stop()
 
@@ -473,7 +487,7 @@ func WaitForDeployment(deploymentID string, timeout time.Duration) (*DeploymentS
 			return nil, utils.NewError("timeout waiting for deployment", nil)
 		}
 
-		status, err := GetDeploymentStatus(deploymentID)
+		status, err := c.GetDeploymentStatus(deploymentID)
 		if err != nil {
 			return nil, err
 		}
@@ -500,88 +514,149 @@ func WaitForDeployment(deploymentID string, timeout time.Duration) (*DeploymentS
 	}
 }
 
+// AuthToken returns the active profile's token, refreshing a browser login
+// that is about to expire.
+func AuthToken() (string, error) {
+	if err := context.EnsureFreshToken(); err != nil {
+		return "", err
+	}
+	token := context.GetToken()
+	if token == "" {
+		return "", utils.NewKindError(utils.ErrAuth, "not authenticated. Please run '1ctl auth login' to authenticate", nil)
+	}
+	utils.RegisterSecret(token)
+	return token, nil
+}
+
 // makeRequest is a helper function to make HTTP requests
 func makeRequest(method, path string, body interface{}, response interface{}) error {
-	config := config.GetConfig()
-	url := fmt.Sprintf("%s%s", config.ApiURL, path)
-	return makeRequestURL(method, url, body, response)
+	return defaultClient.do(method, path, body, response)
 }
 
 func makeMainAPIRequest(method, path string, body interface{}, response interface{}) error {
-	cfg := config.GetConfig()
-	baseURL := strings.TrimSuffix(cfg.ApiURL, "/")
-	baseURL = strings.TrimSuffix(baseURL, "/cli")
-	baseURL = strings.TrimSuffix(baseURL, "/")
-	url := fmt.Sprintf("%s%s", baseURL, path)
-	return makeRequestURL(method, url, body, response)
+	return makeRequestURL(method, defaultClient.mainBaseURL()+path, body, response)
 }
 
 func makeRequestURL(method, url string, body interface{}, response interface{}) error {
-	// Enforce HTTPS for non-localhost API URLs to prevent token leakage over plaintext
-	if !utils.IsLocalhostURL(url) && !strings.HasPrefix(url, "https://") {
-		return utils.NewError(fmt.Sprintf("refusing to send auth token over insecure connection (%s). Use HTTPS or http://localhost for local development", url), nil)
-	}
+	return defaultClient.doURL(method, url, body, response)
+}
+
+// do sends a request to path under the client's API URL.
+func (c *Client) do(method, path string, body interface{}, response interface{}) error {
+	return c.doURL(method, c.baseURL()+path, body, response)
+}
 
-	var bodyReader io.Reader
+func (c *Client) doURL(method, url string, body interface{}, response interface{}) error {
+	var jsonData []byte
 	if body != nil {
-		jsonData, err := json.Marshal(body)
+		var err error
+		jsonData, err = json.Marshal(body)
 		if err != nil {
 			return utils.NewError(fmt.Sprintf("failed to marshal request body: %s", err.Error()), nil)
 		}
-		bodyReader = bytes.NewReader(jsonData)
 	}
 
-	req, err := http.NewRequest(method, url, bodyReader)
+	resp, respBody, err := c.send(method, url, jsonData, nil)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to create request: %s", err.Error()), nil)
+		return err
 	}
 
-	token := context.GetToken()
-	if token == "" {
-		return utils.NewError("not authenticated. Please run '1ctl auth login' to authenticate", nil)
+	if resp.StatusCode >= 400 {
+		return responseError(resp, respBody)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("x-satusky-api-key", token)
-	if email := context.GetEmail(); email != "" {
-		req.Header.Set("x-satusky-user-email", email)
+	if response != nil && len(respBody) > 0 {
+		if err := json.Unmarshal(respBody, response); err != nil {
+			return utils.NewError(fmt.Sprintf("failed to parse response: %s", err.Error()), nil)
+		}
 	}
 
-	resp, err := httpClient.Do(req)
-	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to make request: %s", err.Error()), nil)
+	return nil
+}
+
+// responseError returns the error for a failed API response, carrying its
+// status, the API's error code and the request ID.
+func responseError(resp *http.Response, respBody []byte) error {
+	requestID := resp.Header.Get(requestIDHeader)
+
+	// Check for resource exhausted error (422 Unprocessable Entity)
+	resourceErr, parseErr := utils.ParseResourceExhaustedFromBytes(respBody, resp.StatusCode)
+	if parseErr == nil && resourceErr != nil {
+		cliErr := utils.NewResourceExhaustedCLIError(resourceErr)
+		cliErr.RequestID = requestID
+		return cliErr
 	}
-	defer func() { _ = resp.Body.Close() }() //nolint:errcheck
 
-	respBody, err := io.ReadAll(resp.Body)
+	var apiError APIError
+	if err := json.Unmarshal(respBody, &apiError); err != nil {
+		return utils.NewAPIError(resp.StatusCode, "", requestID, fmt.Sprintf("request failed with status %d: %s", resp.StatusCode, string(respBody)))
+	}
+	if resp.StatusCode == http.StatusUpgradeRequired {
+		return utils.NewAPIError(resp.StatusCode, apiError.Code, requestID, strings.TrimSuffix(apiError.Message, ".")+". "+UpgradeHint)
+	}
+	if resp.StatusCode == 500 {
+		return utils.NewAPIError(resp.StatusCode, apiError.Code, requestID, fmt.Sprintf("%s — check backend logs for details", apiError.Message))
+	}
+	return utils.NewAPIError(resp.StatusCode, apiError.Code, requestID, apiError.Message)
+}
+
+// send sends jsonData to url with the client's credentials and header,
+// retrying under the retry policy, and returns the response and its body
+// whatever the status.
+func (c *Client) send(method, url string, jsonData []byte, header http.Header) (*http.Response, []byte, error) {
+	// Enforce HTTPS for non-localhost API URLs to prevent token leakage over plaintext
+	if !utils.IsLocalhostURL(url) && !strings.HasPrefix(url, "https://") {
+		return nil, nil, utils.NewError(fmt.Sprintf("refusing to send auth token over insecure connection (%s). Use HTTPS or http://localhost for local development", url), nil)
+	}
+
+	token, err := c.authToken()
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to read response body: %s", err.Error()), nil)
+		return nil, nil, err
 	}
+	if err := checkBeforeRequest(url); err != nil {
+		return nil, nil, err
+	}
+	email := c.email()
 
-	if resp.StatusCode >= 400 {
-		// Check for resource exhausted error (422 Unprocessable Entity)
-		resourceErr, parseErr := utils.ParseResourceExhaustedFromBytes(respBody, resp.StatusCode)
-		if parseErr == nil && resourceErr != nil {
-			return utils.NewResourceExhaustedCLIError(resourceErr)
-		}
+	// Mutating requests carry one key across all attempts so the API
+	// applies them once however often they are retried.
+	var idempotencyKey string
+	if !idempotentMethod(method) {
+		idempotencyKey = uuid.NewString()
+	}
 
-		var apiError APIError
-		if err := json.Unmarshal(respBody, &apiError); err != nil {
-			return utils.NewError(fmt.Sprintf("request failed with status %d: %s", resp.StatusCode, string(respBody)), nil)
+	resp, respBody, err := doWithRetry(c.client(httpClient), func() (*http.Request, error) {
+		var bodyReader io.Reader
+		if jsonData != nil {
+			bodyReader = bytes.NewReader(jsonData)
 		}
-		if resp.StatusCode == 500 {
-			return utils.NewError(fmt.Sprintf("%s — check backend logs for details", apiError.Message), nil)
+		req, err := http.NewRequest(method, url, bodyReader)
+		if err != nil {
+			return nil, err
 		}
-		return utils.NewError(apiError.Message, nil)
-	}
-
-	if response != nil && len(respBody) > 0 {
-		if err := json.Unmarshal(respBody, response); err != nil {
-			return utils.NewError(fmt.Sprintf("failed to parse response: %s", err.Error()), nil)
+		req.Header.Set("Content-Type", "application/json")
+		c.setVersionHeaders(req)
+		req.Header.Set("x-satusky-api-key", token)
+		if email != "" {
+			req.Header.Set("x-satusky-user-email", email)
+		}
+		if idempotencyKey != "" {
+			req.Header.Set(IdempotencyKeyHeader, idempotencyKey)
 		}
+		for name, values := range header {
+			for _, v := range values {
+				req.Header.Add(name, v)
+			}
+		}
+		return req, nil
+	})
+	if err != nil {
+		return nil, nil, err
 	}
-
-	return nil
+	if err := checkResponse(method, resp); err != nil {
+		return nil, nil, err
+	}
+	return resp, respBody, nil
 }
 
 // GetDeploymentLogs gets deployment logs
@@ -734,12 +809,12 @@ func GetUserTokens(userID string, orgID string) ([]APIToken, error) {
 }
 
 // Ingress methods
-func GetIngressByDomainName(domainName string) (*Ingress, error) {
+func (c *Client) GetIngressByDomainName(domainName string) (*Ingress, error) {
 	var resp apiResponse
 	// PathEscape so domains with characters that would otherwise be reserved
 	// (e.g. ?, #, /) don't break the request URL. The backend validates the
 	// decoded value separately.
-	err := makeRequest("GET", fmt.Sprintf("/ingresses/domainName/%s", url.PathEscape(domainName)), nil, &resp)
+	err := c.do("GET", fmt.Sprintf("/ingresses/domainName/%s", url.PathEscape(domainName)), nil, &resp)
 	if err != nil {
 		return nil, err
 	}
@@ -784,9 +859,9 @@ func DetachDomain(ingressID string, req DetachDomainRequest) error {
 }
 
 // GetIngressByDeploymentID gets existing ingress by deployment ID
-func GetIngressByDeploymentID(deploymentID string) (*Ingress, error) {
+func (c *Client) GetIngressByDeploymentID(deploymentID string) (*Ingress, error) {
 	var resp apiResponse
-	err := makeRequest("GET", fmt.Sprintf("/ingresses/deploymentId/%s", deploymentID), nil, &resp)
+	err := c.do("GET", fmt.Sprintf("/ingresses/deploymentId/%s", deploymentID), nil, &resp)
 	if err != nil {
 		return nil, err
 	}
@@ -804,7 +879,7 @@ func GetIngressByDeploymentID(deploymentID string) (*Ingress, error) {
 }
 
 // GetDomainStatus returns consolidated backend, route, DNS, TLS, and optional HTTP status.
-func GetDomainStatus(ingressID, domain string, probe bool) (*DomainStatusResponse, error) {
+func (c *Client) GetDomainStatus(ingressID, domain string, probe bool) (*DomainStatusResponse, error) {
 	query := url.Values{}
 	if domain != "" {
 		query.Set("domain", domain)
@@ -821,16 +896,16 @@ func GetDomainStatus(ingressID, domain string, probe bool) (*DomainStatusRespons
 		Error bool                 `json:"error"`
 		Data  DomainStatusResponse `json:"data"`
 	}
-	if err := makeRequest("GET", path, nil, &resp); err != nil {
+	if err := c.do("GET", path, nil, &resp); err != nil {
 		return nil, err
 	}
 	return &resp.Data, nil
 }
 
 // Environment methods
-func GetEnvironmentsByNamespace(namespace string) ([]Environment, error) {
+func (c *Client) GetEnvironmentsByNamespace(namespace string) ([]Environment, error) {
 	var resp apiResponse
-	err := makeRequest("GET", fmt.Sprintf("/environments/namespace/%s", namespace), nil, &resp)
+	err := c.do("GET", fmt.Sprintf("/environments/namespace/%s", namespace), nil, &resp)
 	if err != nil {
 		return nil, err
 	}
@@ -857,14 +932,14 @@ const MachineTagLabelPrefix = "satusky.com/"
 // GetMachineLabels returns the satusky.com/* labels for a machine. Used by
 // the deploy `--machine-tag` resolver to filter owned machines client-side
 // without a new backend endpoint.
-func GetMachineLabels(machineID string) (map[string]string, error) {
+func (c *Client) GetMachineLabels(machineID string) (map[string]string, error) {
 	var resp struct {
 		Error bool `json:"error"`
 		Data  struct {
 			Custom map[string]string `json:"custom"`
 		} `json:"data"`
 	}
-	if err := makeRequest("GET", fmt.Sprintf("/machines/%s/labels", url.PathEscape(machineID)), nil, &resp); err != nil {
+	if err := c.getCached(machineLabelsPath(machineID), machineLabelsCacheTTL, &resp); err != nil {
 		return nil, err
 	}
 	return resp.Data.Custom, nil
@@ -879,7 +954,13 @@ type updateLabelsRequest struct {
 func UpdateMachineLabels(machineID string, labels map[string]string) error {
 	var resp apiResponse
 	req := updateLabelsRequest{Labels: labels}
-	return makeRequest("PATCH", fmt.Sprintf("/machines/%s/labels", machineID), req, &resp)
+	defer defaultClient.invalidateCache("/machines/label-keys")
+	defer defaultClient.invalidateCache(machineLabelsPath(machineID))
+	return makeRequest("PATCH", machineLabelsPath(machineID), req, &resp)
+}
+
+func machineLabelsPath(machineID string) string {
+	return fmt.Sprintf("/machines/%s/labels", url.PathEscape(machineID))
 }
 
 // QueryMachinesByLabel finds machines that have ALL the specified labels.
@@ -900,7 +981,7 @@ func GetAvailableLabelKeys() ([]string, error) {
 		Error bool     `json:"error"`
 		Data  []string `json:"data"`
 	}
-	if err := makeRequest("GET", "/machines/label-keys", nil, &resp); err != nil {
+	if err := defaultClient.getCached("/machines/label-keys", labelKeysCacheTTL, &resp); err != nil {
 		return nil, err
 	}
 	return resp.Data, nil
@@ -1056,9 +1137,9 @@ func MachineHasAnyTag(labels map[string]string, tags []string) bool {
 	return false
 }
 
-func GetMachinesByOwnerID(ownerID uuid.UUID) ([]Machine, error) {
+func (c *Client) GetMachinesByOwnerID(ownerID uuid.UUID) ([]Machine, error) {
 	var resp apiResponse
-	err := makeRequest("GET", fmt.Sprintf("/machines/ownerId/%s", ownerID), nil, &resp)
+	err := c.do("GET", fmt.Sprintf("/machines/ownerId/%s", ownerID), nil, &resp)
 	if err != nil {
 		return nil, err
 	}
@@ -1094,9 +1175,9 @@ func GetMachineByID(machineID uuid.UUID) (*Machine, error) {
 	return &machine, nil
 }
 
-func GetMachineByName(machineName string) (*Machine, error) {
+func (c *Client) GetMachineByName(machineName string) (*Machine, error) {
 	var resp apiResponse
-	err := makeRequest("GET", fmt.Sprintf("/machines/name/%s", machineName), nil, &resp)
+	err := c.do("GET", fmt.Sprintf("/machines/name/%s", machineName), nil, &resp)
 	if err != nil {
 		return nil, err
 	}
@@ -1153,31 +1234,31 @@ func SendMachineCommand(machineID string, req SendCommandRequest) (*SendCommandR
 }
 
 // UpsertDeployment creates or updates a deployment and returns the deployment ID
-func UpsertDeployment(req Deployment, response *string) error {
+func (c *Client) UpsertDeployment(req Deployment, response *string) error {
 	var resp apiResponse
 	resp.Data = response
 
 	path := fmt.Sprintf("/deployments/upsert/%s/%s", req.Namespace, req.AppLabel)
-	return makeRequest("POST", path, req, &resp)
+	return c.do("POST", path, req, &resp)
 }
 
 // UpsertService creates or updates a service and returns the service ID
-func UpsertService(service Service, response *string) error {
+func (c *Client) UpsertService(service Service, response *string) error {
 	var resp apiResponse
 	resp.Data = response
 
 	path := fmt.Sprintf("/services/upsert/%s/%s", service.Namespace, service.ServiceName)
-	return makeRequest("POST", path, service, &resp)
+	return c.do("POST", path, service, &resp)
 }
 
 // UpsertIngress creates or updates an ingress and returns the ingress
-func UpsertIngress(ingress Ingress) (*Ingress, error) {
+func (c *Client) UpsertIngress(ingress Ingress) (*Ingress, error) {
 	var resp apiResponse
 	var ingressIDStr string
 	resp.Data = &ingressIDStr
 
 	path := fmt.Sprintf("/ingresses/upsert/%s/%s", ingress.Namespace, ingress.AppLabel)
-	err := makeRequest("POST", path, ingress, &resp)
+	err := c.do("POST", path, ingress, &resp)
 	if err != nil {
 		return nil, err
 	}
@@ -1265,7 +1346,7 @@ func GetUsageCost(usageID string) (*UsageCostResponse, error) {
 // ListPricingConfigs lists all pricing configurations
 func ListPricingConfigs() ([]PricingConfig, error) {
 	var resp apiResponse
-	if err := makeRequest("GET", "/pricing/configs", nil, &resp); err != nil {
+	if err := defaultClient.getCached("/pricing/configs", pricingCacheTTL, &resp); err != nil {
 		return nil, err
 	}
 	data, err := json.Marshal(resp.Data)
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"1ctl/internal/utils"
+)
+
+func TestClientUsesItsOwnSettings(t *testing.T) {
+	var got http.Header
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, path = r.Header.Clone(), r.URL.Path
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": []Service{{ServiceName: "web"}}})
+	}))
+	t.Cleanup(server.Close)
+
+	// No profile is involved: every setting comes from the client.
+	client := &Client{
+		BaseURL:    server.URL + "/v1/cli",
+		Token:      StaticToken("client-token"),
+		UserEmail:  "dev@example.com",
+		Namespace:  "acme",
+		HTTPClient: server.Client(),
+		UserAgent:  "deploy-bot/2",
+	}
+	services, err := client.ListServices()
+	if err != nil {
+		t.Fatalf("ListServices() error = %v", err)
+	}
+	if len(services) != 1 || services[0].ServiceName != "web" {
+		t.Errorf("services = %+v", services)
+	}
+	if path != "/v1/cli/services/namespace/acme" {
+		t.Errorf("path = %q, want the client's namespace", path)
+	}
+	for header, want := range map[string]string{
+		"X-Satusky-Api-Key":    "client-token",
+		"X-Satusky-User-Email": "dev@example.com",
+		"User-Agent":           "deploy-bot/2",
+	} {
+		if got.Get(header) != want {
+			t.Errorf("%s = %q, want %q", header, got.Get(header), want)
+		}
+	}
+}
+
+func TestFailedRequestsReturnTypedErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     interface{}
+		wantKind utils.ErrorKind
+		wantCode string
+	}{
+		{name: "not found", status: http.StatusNotFound, body: APIError{Message: "deployment not found", Code: "DEPLOYMENT_NOT_FOUND"}, wantKind: utils.ErrNotFound, wantCode: "DEPLOYMENT_NOT_FOUND"},
+		{name: "unauthorized", status: http.StatusUnauthorized, body: APIError{Message: "invalid API key"}, wantKind: utils.ErrAuth},
+		{name: "validation", status: http.StatusBadRequest, body: APIError{Message: "port must be set", Code: "INVALID_PORT"}, wantKind: utils.ErrValidation, wantCode: "INVALID_PORT"},
+		{name: "quota", status: http.StatusUnprocessableEntity, body: map[string]interface{}{"success": false, "error": map[string]interface{}{"code": "RESOURCE_EXHAUSTED", "type": "quota_cpu", "message": "CPU quota exceeded"}}, wantKind: utils.ErrQuota, wantCode: "RESOURCE_EXHAUSTED"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useRetryTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Request-Id", "req-123")
+				writeJSON(w, tt.status, tt.body)
+			})
+			err := makeRequest("GET", "/deployments/id/d-1", nil, nil)
+			if got := utils.KindOf(err); got != tt.wantKind {
+				t.Fatalf("KindOf(%v) = %q, want %q", err, got, tt.wantKind)
+			}
+			body := utils.NewErrorEnvelope(err).Error
+			if body.Status != tt.status || body.APICode != tt.wantCode || body.RequestID != "req-123" {
+				t.Errorf("envelope = %+v, want status %d, code %q and the request ID", body, tt.status, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestUnreachableAPIIsANetworkError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	client := &Client{BaseURL: server.URL + "/v1/cli", Token: StaticToken("t"), Namespace: "acme"}
+	original := GetRetryPolicy()
+	t.Cleanup(func() { _ = SetRetryPolicy(original) }) //nolint:errcheck
+	if err := SetRetryPolicy(RetryPolicy{MaxDelay: time.Second}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := client.ListServices()
+	if got := utils.ExitCode(err); got != 10 {
+		t.Errorf("ExitCode(%v) = %d, want 10", err, got)
+	}
+}
+
+func TestWaitForBuildResultStopsOnPermanentErrors(t *testing.T) {
+	original := buildPollInterval
+	buildPollInterval = time.Millisecond
+	t.Cleanup(func() { buildPollInterval = original })
+
+	tests := []struct {
+		name      string
+		status    int
+		wantKind  utils.ErrorKind
+		wantPolls int
+	}{
+		{name: "build not found", status: http.StatusNotFound, wantKind: utils.ErrNotFound, wantPolls: 1},
+		{name: "API unavailable", status: http.StatusServiceUnavailable, wantKind: utils.ErrServer, wantPolls: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			polls := 0
+			waits := useRetryTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+				polls++
+				writeJSON(w, tt.status, APIError{Message: http.StatusText(tt.status)})
+			})
+			_, err := Default().WaitForBuildResult("b-1", io.Discard)
+			if got := utils.KindOf(err); got != tt.wantKind {
+				t.Errorf("KindOf(%v) = %q, want %q", err, got, tt.wantKind)
+			}
+			if got := polls - len(*waits); got != tt.wantPolls {
+				t.Errorf("polled %d times, want %d", got, tt.wantPolls)
+			}
+		})
+	}
+}
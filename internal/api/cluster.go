@@ -31,7 +31,7 @@ type ClusterInfo struct {
 // Backend wraps responses as {"error": false, "data": [...]}.
 func GetAvailableZones() ([]ZoneOption, error) {
 	var resp apiResponse
-	if err := makeRequest("GET", "/clusters/zones", nil, &resp); err != nil {
+	if err := defaultClient.getCached("/clusters/zones", zonesCacheTTL, &resp); err != nil {
 		return nil, err
 	}
 
@@ -51,7 +51,7 @@ func GetAvailableZones() ([]ZoneOption, error) {
 // Backend wraps responses as {"error": false, "data": [...]}.
 func GetClusters() ([]ClusterInfo, error) {
 	var resp apiResponse
-	if err := makeRequest("GET", "/clusters", nil, &resp); err != nil {
+	if err := defaultClient.getCached("/clusters", clustersCacheTTL, &resp); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,204 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"1ctl/internal/utils"
+	"1ctl/internal/version"
+)
+
+// Every request tells the API which CLI sent it, and any response may tell
+// the CLI which versions the API supports and which features it has.
+const (
+	CLIVersionHeader            = "X-Satusky-CLI-Version"
+	APIVersionHeader            = "X-Satusky-API-Version"
+	MinCLIVersionHeader         = "X-Satusky-Min-CLI-Version"
+	RecommendedCLIVersionHeader = "X-Satusky-Recommended-CLI-Version"
+	FeaturesHeader              = "X-Satusky-Features"
+)
+
+// UpgradeHint tells people how to get a newer 1ctl.
+const UpgradeHint = "Upgrade with '1ctl update' ('brew upgrade satuctl' for Homebrew installs)"
+
+// Compatibility is what an API advertises about the CLI versions it
+// supports.
+type Compatibility struct {
+	APIVersion            string   `json:"api_version,omitempty"`
+	MinCLIVersion         string   `json:"min_cli_version,omitempty"`
+	RecommendedCLIVersion string   `json:"recommended_cli_version,omitempty"`
+	Features              []string `json:"features,omitempty"`
+}
+
+// CompatibilityStatus is how a CLI version fares against a Compatibility.
+type CompatibilityStatus string
+
+const (
+	Compatible         CompatibilityStatus = "compatible"
+	UpgradeRecommended CompatibilityStatus = "upgrade_recommended"
+	UpgradeRequired    CompatibilityStatus = "upgrade_required"
+	// CompatibilityUnknown is for development builds and APIs that
+	// advertise no versions.
+	CompatibilityUnknown CompatibilityStatus = "unknown"
+)
+
+// Status compares cliVersion with the versions c advertises.
+func (c Compatibility) Status(cliVersion string) CompatibilityStatus {
+	if !version.IsRelease(cliVersion) || (c.MinCLIVersion == "" && c.RecommendedCLIVersion == "") {
+		return CompatibilityUnknown
+	}
+	if cmp, ok := version.Compare(cliVersion, c.MinCLIVersion); ok && cmp < 0 {
+		return UpgradeRequired
+	}
+	if cmp, ok := version.Compare(cliVersion, c.RecommendedCLIVersion); ok && cmp < 0 {
+		return UpgradeRecommended
+	}
+	return Compatible
+}
+
+// HasFeature reports whether the API advertises the named feature.
+func (c Compatibility) HasFeature(name string) bool {
+	for _, f := range c.Features {
+		if strings.EqualFold(f, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c Compatibility) empty() bool {
+	return c.APIVersion == "" && c.MinCLIVersion == "" && c.RecommendedCLIVersion == "" && len(c.Features) == 0
+}
+
+// compatibilityFromHeader reads the Compatibility a response advertises.
+func compatibilityFromHeader(h http.Header) Compatibility {
+	c := Compatibility{
+		APIVersion:            h.Get(APIVersionHeader),
+		MinCLIVersion:         h.Get(MinCLIVersionHeader),
+		RecommendedCLIVersion: h.Get(RecommendedCLIVersionHeader),
+	}
+	for _, f := range strings.Split(h.Get(FeaturesHeader), ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			c.Features = append(c.Features, f)
+		}
+	}
+	return c
+}
+
+// compatibilityOut is where the upgrade warnings go; stderr keeps them out
+// of JSON output.
+var compatibilityOut io.Writer = os.Stderr
+
+// advertised holds what each API host has advertised in this process.
+var advertised = struct {
+	sync.Mutex
+	byHost map[string]Compatibility
+	warned bool
+}{byHost: map[string]Compatibility{}}
+
+// ServerCompatibility returns what the API at host last advertised in this
+// process, if anything.
+func ServerCompatibility(host string) (Compatibility, bool) {
+	advertised.Lock()
+	defer advertised.Unlock()
+	c, ok := advertised.byHost[host]
+	return c, ok
+}
+
+// checkBeforeRequest refuses to send a request to an API that has already
+// said it no longer supports this CLI.
+func checkBeforeRequest(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil
+	}
+	if c, ok := ServerCompatibility(u.Host); ok && c.Status(version.Version) == UpgradeRequired {
+		return upgradeRequiredError(c)
+	}
+	return nil
+}
+
+// checkResponse records what resp advertises, warns once when an upgrade
+// is recommended, and fails when the API requires a newer CLI. A change
+// the API has already applied is not reported as a failure.
+func checkResponse(method string, resp *http.Response) error {
+	c := compatibilityFromHeader(resp.Header)
+	if c.empty() {
+		return nil
+	}
+	status := c.Status(version.Version)
+	advertised.Lock()
+	advertised.byHost[resp.Request.URL.Host] = c
+	warn := status == UpgradeRecommended && !advertised.warned
+	if warn {
+		advertised.warned = true
+	}
+	advertised.Unlock()
+
+	switch status {
+	case UpgradeRequired:
+		applied := resp.StatusCode < 300 && method != http.MethodGet && method != http.MethodHead
+		if !applied {
+			return upgradeRequiredError(c)
+		}
+		utils.NewPrinter(compatibilityOut).Warning("1ctl %s is older than %s, the oldest version this API supports; this change was applied, but further requests will be refused. %s", version.Version, c.MinCLIVersion, UpgradeHint)
+	case UpgradeRecommended:
+		if warn {
+			utils.NewPrinter(compatibilityOut).Warning("1ctl %s is out of date; this API recommends %s or newer. %s", version.Version, c.RecommendedCLIVersion, UpgradeHint)
+		}
+	}
+	return nil
+}
+
+func upgradeRequiredError(c Compatibility) error {
+	return utils.NewKindError(utils.ErrUpgradeRequired, fmt.Sprintf("1ctl %s is no longer supported by this API, which needs %s or newer. %s", version.Version, c.MinCLIVersion, UpgradeHint), nil)
+}
+
+// setVersionHeaders identifies the CLI on req.
+func (c *Client) setVersionHeaders(req *http.Request) {
+	req.Header.Set("User-Agent", c.userAgent())
+	req.Header.Set(CLIVersionHeader, version.Version)
+}
+
+// CheckCompatibility asks the API which CLI versions it supports. It needs
+// no login: the API's GET /version is public, and an API without it still
+// advertises its versions in the headers of the error.
+func (c *Client) CheckCompatibility() (Compatibility, error) {
+	versionURL := strings.TrimSuffix(c.baseURL(), "/") + "/version"
+	resp, body, err := doWithRetry(c.client(httpClient), func() (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodGet, versionURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		c.setVersionHeaders(req)
+		req.Header.Set("Accept", "application/json")
+		return req, nil
+	})
+	if err != nil {
+		return Compatibility{}, err
+	}
+
+	compat := compatibilityFromHeader(resp.Header)
+	if resp.StatusCode == http.StatusOK {
+		var envelope struct {
+			Data Compatibility `json:"data"`
+		}
+		if err := json.Unmarshal(body, &envelope); err == nil && !envelope.Data.empty() {
+			compat = envelope.Data
+		}
+	} else if resp.StatusCode >= 500 {
+		return Compatibility{}, responseError(resp, body)
+	}
+	if !compat.empty() {
+		advertised.Lock()
+		advertised.byHost[resp.Request.URL.Host] = compat
+		advertised.Unlock()
+	}
+	return compat, nil
+}
@@ -0,0 +1,161 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"1ctl/internal/utils"
+	"1ctl/internal/version"
+)
+
+// useCLIVersion runs the test as CLI version v, with nothing advertised yet
+// and warnings going to the returned buffer.
+func useCLIVersion(t *testing.T, v string) *bytes.Buffer {
+	t.Helper()
+	oldVersion, oldOut := version.Version, compatibilityOut
+	var warnings bytes.Buffer
+	version.Version, compatibilityOut = v, &warnings
+	resetAdvertised := func() {
+		advertised.Lock()
+		advertised.byHost = map[string]Compatibility{}
+		advertised.warned = false
+		advertised.Unlock()
+	}
+	resetAdvertised()
+	t.Cleanup(func() {
+		version.Version, compatibilityOut = oldVersion, oldOut
+		resetAdvertised()
+	})
+	return &warnings
+}
+
+func TestCompatibilityStatus(t *testing.T) {
+	compat := Compatibility{MinCLIVersion: "v1.2.0", RecommendedCLIVersion: "v1.4.0"}
+	tests := []struct {
+		cli    string
+		compat Compatibility
+		want   CompatibilityStatus
+	}{
+		{"v1.1.9", compat, UpgradeRequired},
+		{"v1.3.0", compat, UpgradeRecommended},
+		{"v1.4.0", compat, Compatible},
+		{"v1.3.0", Compatibility{MinCLIVersion: "v1.2.0"}, Compatible},
+		{"dev", compat, CompatibilityUnknown},
+		{"v1.0.0", Compatibility{Features: []string{"ha"}}, CompatibilityUnknown},
+	}
+	for _, tt := range tests {
+		if got := tt.compat.Status(tt.cli); got != tt.want {
+			t.Errorf("%+v.Status(%q) = %q, want %q", tt.compat, tt.cli, got, tt.want)
+		}
+	}
+}
+
+func TestRequestsNegotiateCLIVersion(t *testing.T) {
+	warnings := useCLIVersion(t, "v1.3.0")
+	var requests []string
+	minVersion := "v1.0.0"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.Header.Get(CLIVersionHeader))
+		w.Header().Set(MinCLIVersionHeader, minVersion)
+		w.Header().Set(RecommendedCLIVersionHeader, "v1.5.0")
+		w.Header().Set(FeaturesHeader, "ha, preview-builds")
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": nil})
+	}))
+	defer server.Close()
+	client := &Client{BaseURL: server.URL, Token: StaticToken("t")}
+
+	// An upgrade is recommended once per process.
+	for i := 0; i < 2; i++ {
+		if _, err := client.Raw(http.MethodGet, "/apps", nil, false); err != nil {
+			t.Fatalf("Raw() error = %v", err)
+		}
+	}
+	if n := strings.Count(warnings.String(), "recommends v1.5.0"); n != 1 {
+		t.Errorf("warnings = %q, want one recommendation", warnings.String())
+	}
+	if compat, _ := ServerCompatibility(strings.TrimPrefix(server.URL, "http://")); !compat.HasFeature("preview-builds") {
+		t.Errorf("features = %v, want preview-builds", compat.Features)
+	}
+
+	// A change the API applied succeeds; the requests after it are refused
+	// without being sent.
+	minVersion = "v1.4.0"
+	if _, err := client.Raw(http.MethodPost, "/apps", []byte(`{}`), false); err != nil {
+		t.Fatalf("applied POST error = %v", err)
+	}
+	if !strings.Contains(warnings.String(), "this change was applied") {
+		t.Errorf("warnings = %q, want the applied change reported", warnings.String())
+	}
+	_, err := client.Raw(http.MethodGet, "/apps", nil, false)
+	if got := utils.KindOf(err); got != utils.ErrUpgradeRequired {
+		t.Errorf("GET after the minimum rose: error = %v, kind %q, want %q", err, got, utils.ErrUpgradeRequired)
+	}
+
+	want := []string{"GET v1.3.0", "GET v1.3.0", "POST v1.3.0"}
+	if strings.Join(requests, ",") != strings.Join(want, ",") {
+		t.Errorf("requests = %q, want %q", requests, want)
+	}
+}
+
+func TestUpgradeRequiredStatusIsTyped(t *testing.T) {
+	useCLIVersion(t, "dev")
+	useRetryTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusUpgradeRequired, map[string]interface{}{"error": true, "message": "this CLI is too old"})
+	})
+
+	err := makeRequest(http.MethodGet, "/apps", nil, &apiResponse{})
+	if got := utils.KindOf(err); got != utils.ErrUpgradeRequired {
+		t.Errorf("KindOf(%v) = %q, want %q", err, got, utils.ErrUpgradeRequired)
+	}
+	if err == nil || !strings.Contains(err.Error(), "brew upgrade") {
+		t.Errorf("error = %v, want an upgrade hint", err)
+	}
+}
+
+func TestCheckCompatibility(t *testing.T) {
+	useCLIVersion(t, "v1.0.0")
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		want    Compatibility
+	}{
+		{
+			name: "version endpoint",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/cli/version" || r.Header.Get("x-satusky-api-key") != "" {
+					t.Errorf("request = %s with key %q, want /v1/cli/version without one", r.URL.Path, r.Header.Get("x-satusky-api-key"))
+				}
+				writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]interface{}{
+					"api_version": "2026-09-01", "min_cli_version": "v0.9.0", "features": []string{"ha"},
+				}})
+			},
+			want: Compatibility{APIVersion: "2026-09-01", MinCLIVersion: "v0.9.0", Features: []string{"ha"}},
+		},
+		{
+			name: "headers of an older API",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(MinCLIVersionHeader, "v1.1.0")
+				writeJSON(w, http.StatusNotFound, map[string]interface{}{"error": true, "message": "not found"})
+			},
+			want: Compatibility{MinCLIVersion: "v1.1.0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+			client := &Client{BaseURL: server.URL + "/v1/cli"}
+
+			got, err := client.CheckCompatibility()
+			if err != nil {
+				t.Fatalf("CheckCompatibility() error = %v", err)
+			}
+			if got.APIVersion != tt.want.APIVersion || got.MinCLIVersion != tt.want.MinCLIVersion || strings.Join(got.Features, ",") != strings.Join(tt.want.Features, ",") {
+				t.Errorf("CheckCompatibility() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"1ctl/internal/utils"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -123,38 +124,14 @@ func GetCreditBalance(orgID string) (*CreditBalance, error) {
 	return &balance, nil
 }
 
-// GetCreditTransactions gets transaction history for an organization
-func GetCreditTransactions(orgID string, limit, offset int) ([]CreditTransaction, error) {
+// ListCreditTransactions fetches an organization's transaction history,
+// starting offset transactions in, passing each page to yield.
+func ListCreditTransactions(orgID string, offset int, opts PageOptions, yield func([]CreditTransaction) error) error {
 	path := fmt.Sprintf("/credits/organizations/%s/transactions", orgID)
-	if limit > 0 || offset > 0 {
-		path = fmt.Sprintf("%s?limit=%d&offset=%d", path, limit, offset)
+	if offset > 0 {
+		path = listPath(path, map[string]string{"offset": strconv.Itoa(offset)})
 	}
-
-	var resp apiResponse
-	err := makeRequest("GET", path, nil, &resp)
-	if err != nil {
-		return nil, err
-	}
-
-	data, err := json.Marshal(resp.Data)
-	if err != nil {
-		return nil, utils.NewError(fmt.Sprintf("failed to marshal response data: %s", err.Error()), nil)
-	}
-
-	// Try paginated response first, fall back to flat array
-	var listResp struct {
-		Transactions []CreditTransaction `json:"transactions"`
-		Total        int                 `json:"total"`
-	}
-	if err := json.Unmarshal(data, &listResp); err == nil && listResp.Transactions != nil {
-		return listResp.Transactions, nil
-	}
-
-	var transactions []CreditTransaction
-	if err := json.Unmarshal(data, &transactions); err != nil {
-		return nil, utils.NewError(fmt.Sprintf("failed to unmarshal transactions: %s", err.Error()), nil)
-	}
-	return transactions, nil
+	return Paginate(defaultClient, ListEndpoint{Path: path, ItemsKey: "transactions"}, opts, yield)
 }
 
 // GetMachineUsageHistory gets machine usage history for an organization
@@ -0,0 +1,279 @@
+package api
+
+import (
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// The package-level functions below use Default(), the client for the
+// active profile.
+
+// Deployments
+
+// UpsertDeployment is a wrapper around Default().UpsertDeployment.
+func UpsertDeployment(req Deployment, response *string) error {
+	return defaultClient.UpsertDeployment(req, response)
+}
+
+// GetDeployment is a wrapper around Default().GetDeployment.
+func GetDeployment(deploymentID string) (*Deployment, error) {
+	return defaultClient.GetDeployment(deploymentID)
+}
+
+// GetDeploymentByAppLabel is a wrapper around Default().GetDeploymentByAppLabel.
+func GetDeploymentByAppLabel(namespace, appLabel string) (*Deployment, error) {
+	return defaultClient.GetDeploymentByAppLabel(namespace, appLabel)
+}
+
+// ListDeployments is a wrapper around Default().ListDeployments.
+func ListDeployments() ([]Deployment, error) {
+	return defaultClient.ListDeployments()
+}
+
+// ListDeploymentsByNamespace is a wrapper around Default().ListDeploymentsByNamespace.
+func ListDeploymentsByNamespace(namespace string) ([]Deployment, error) {
+	return defaultClient.ListDeploymentsByNamespace(namespace)
+}
+
+// ListDeploymentVersions is a wrapper around Default().ListDeploymentVersions.
+func ListDeploymentVersions(deploymentID string) ([]DeploymentVersion, error) {
+	return defaultClient.ListDeploymentVersions(deploymentID)
+}
+
+// GetDeploymentStatus is a wrapper around Default().GetDeploymentStatus.
+func GetDeploymentStatus(deploymentID string) (*DeploymentStatus, error) {
+	return defaultClient.GetDeploymentStatus(deploymentID)
+}
+
+// WaitForDeployment is a wrapper around Default().WaitForDeployment.
+func WaitForDeployment(deploymentID string, timeout time.Duration) (*DeploymentStatus, error) {
+	return defaultClient.WaitForDeployment(deploymentID, timeout)
+}
+
+// RestartDeployment is a wrapper around Default().RestartDeployment.
+func RestartDeployment(deploymentID string) error {
+	return defaultClient.RestartDeployment(deploymentID)
+}
+
+// RollbackDeployment is a wrapper around Default().RollbackDeployment.
+func RollbackDeployment(deploymentID string, versionNumber int) error {
+	return defaultClient.RollbackDeployment(deploymentID, versionNumber)
+}
+
+// DeleteDeployment is a wrapper around Default().DeleteDeployment.
+func DeleteDeployment(deploymentID string) (*DeletionResult, error) {
+	return defaultClient.DeleteDeployment(deploymentID)
+}
+
+// Services
+
+// UpsertService is a wrapper around Default().UpsertService.
+func UpsertService(service Service, response *string) error {
+	return defaultClient.UpsertService(service, response)
+}
+
+// ListServices is a wrapper around Default().ListServices.
+func ListServices() ([]Service, error) {
+	return defaultClient.ListServices()
+}
+
+// DeleteService is a wrapper around Default().DeleteService.
+func DeleteService(serviceID string) error {
+	return defaultClient.DeleteService(serviceID)
+}
+
+// Ingresses
+
+// UpsertIngress is a wrapper around Default().UpsertIngress.
+func UpsertIngress(ingress Ingress) (*Ingress, error) {
+	return defaultClient.UpsertIngress(ingress)
+}
+
+// ListIngresses is a wrapper around Default().ListIngresses.
+func ListIngresses() ([]Ingress, error) {
+	return defaultClient.ListIngresses()
+}
+
+// GetIngressByDeploymentID is a wrapper around Default().GetIngressByDeploymentID.
+func GetIngressByDeploymentID(deploymentID string) (*Ingress, error) {
+	return defaultClient.GetIngressByDeploymentID(deploymentID)
+}
+
+// GetIngressByDomainName is a wrapper around Default().GetIngressByDomainName.
+func GetIngressByDomainName(domainName string) (*Ingress, error) {
+	return defaultClient.GetIngressByDomainName(domainName)
+}
+
+// GenerateDomainName is a wrapper around Default().GenerateDomainName.
+func GenerateDomainName(projectName string) (string, error) {
+	return defaultClient.GenerateDomainName(projectName)
+}
+
+// DeleteIngress is a wrapper around Default().DeleteIngress.
+func DeleteIngress(ingressID string) error {
+	return defaultClient.DeleteIngress(ingressID)
+}
+
+// GetDomainStatus is a wrapper around Default().GetDomainStatus.
+func GetDomainStatus(ingressID, domain string, probe bool) (*DomainStatusResponse, error) {
+	return defaultClient.GetDomainStatus(ingressID, domain, probe)
+}
+
+// GetIngressDNSStatus is a wrapper around Default().GetIngressDNSStatus.
+func GetIngressDNSStatus(ingressID string) (*DNSStatusResponse, error) {
+	return defaultClient.GetIngressDNSStatus(ingressID)
+}
+
+// WaitForIngressDNSStatus is a wrapper around Default().WaitForIngressDNSStatus.
+func WaitForIngressDNSStatus(ingressID string, timeout time.Duration) (*DNSStatusResponse, error) {
+	return defaultClient.WaitForIngressDNSStatus(ingressID, timeout)
+}
+
+// Environments
+
+// UpsertEnvironment is a wrapper around Default().UpsertEnvironment.
+func UpsertEnvironment(env Environment) (*Environment, error) {
+	return defaultClient.UpsertEnvironment(env)
+}
+
+// ListEnvironments is a wrapper around Default().ListEnvironments.
+func ListEnvironments() ([]Environment, error) {
+	return defaultClient.ListEnvironments()
+}
+
+// GetEnvironmentsByNamespace is a wrapper around Default().GetEnvironmentsByNamespace.
+func GetEnvironmentsByNamespace(namespace string) ([]Environment, error) {
+	return defaultClient.GetEnvironmentsByNamespace(namespace)
+}
+
+// GetEnvironmentsByDeploymentID is a wrapper around Default().GetEnvironmentsByDeploymentID.
+func GetEnvironmentsByDeploymentID(deploymentID string) ([]Environment, error) {
+	return defaultClient.GetEnvironmentsByDeploymentID(deploymentID)
+}
+
+// UnsetEnvironmentKey is a wrapper around Default().UnsetEnvironmentKey.
+func UnsetEnvironmentKey(environmentID, key string) error {
+	return defaultClient.UnsetEnvironmentKey(environmentID, key)
+}
+
+// DeleteEnvironment is a wrapper around Default().DeleteEnvironment.
+func DeleteEnvironment(environmentID string) error {
+	return defaultClient.DeleteEnvironment(environmentID)
+}
+
+// Secrets
+
+// CreateSecret is a wrapper around Default().CreateSecret.
+func CreateSecret(secret Secret) (*Secret, error) {
+	return defaultClient.CreateSecret(secret)
+}
+
+// ListSecrets is a wrapper around Default().ListSecrets.
+func ListSecrets() ([]Secret, error) {
+	return defaultClient.ListSecrets()
+}
+
+// GetSecretsByDeploymentID is a wrapper around Default().GetSecretsByDeploymentID.
+func GetSecretsByDeploymentID(deploymentID string) ([]Secret, error) {
+	return defaultClient.GetSecretsByDeploymentID(deploymentID)
+}
+
+// UnsetSecretKey is a wrapper around Default().UnsetSecretKey.
+func UnsetSecretKey(secretID, key string) error {
+	return defaultClient.UnsetSecretKey(secretID, key)
+}
+
+// DeleteSecret is a wrapper around Default().DeleteSecret.
+func DeleteSecret(secretID string) error {
+	return defaultClient.DeleteSecret(secretID)
+}
+
+// Volumes
+
+// CreateVolume is a wrapper around Default().CreateVolume.
+func CreateVolume(volume Volume) error {
+	return defaultClient.CreateVolume(volume)
+}
+
+// GetAllVolumes is a wrapper around Default().GetAllVolumes.
+func GetAllVolumes() ([]Volume, error) {
+	return defaultClient.GetAllVolumes()
+}
+
+// GetVolumeLifecycleStatus is a wrapper around Default().GetVolumeLifecycleStatus.
+func GetVolumeLifecycleStatus(volumeID string) (*VolumeLifecycleStatus, error) {
+	return defaultClient.GetVolumeLifecycleStatus(volumeID)
+}
+
+// GetDeploymentVolumeLifecycleStatuses is a wrapper around Default().GetDeploymentVolumeLifecycleStatuses.
+func GetDeploymentVolumeLifecycleStatuses(deploymentID string) ([]VolumeLifecycleStatus, error) {
+	return defaultClient.GetDeploymentVolumeLifecycleStatuses(deploymentID)
+}
+
+// DetachVolume is a wrapper around Default().DetachVolume.
+func DetachVolume(volumeID string) (*VolumeLifecycleStatus, error) {
+	return defaultClient.DetachVolume(volumeID)
+}
+
+// DeleteVolumePVC is a wrapper around Default().DeleteVolumePVC.
+func DeleteVolumePVC(volumeID string) (*VolumeLifecycleStatus, error) {
+	return defaultClient.DeleteVolumePVC(volumeID)
+}
+
+// Builds
+
+// SubmitBuild is a wrapper around Default().SubmitBuild.
+func SubmitBuild(contextTarPath, projectName, dockerfilePath, builder string, buildArgs map[string]string) (string, error) {
+	return defaultClient.SubmitBuild(contextTarPath, projectName, dockerfilePath, builder, buildArgs)
+}
+
+// GetBuildStatus is a wrapper around Default().GetBuildStatus.
+func GetBuildStatus(buildID string) (*BuildStatusResponse, error) {
+	return defaultClient.GetBuildStatus(buildID)
+}
+
+// WaitForBuildResult is a wrapper around Default().WaitForBuildResult.
+func WaitForBuildResult(buildID string, progressWriter io.Writer) (*BuildResult, error) {
+	return defaultClient.WaitForBuildResult(buildID, progressWriter)
+}
+
+// Machines
+
+// GetMachineLabels is a wrapper around Default().GetMachineLabels.
+func GetMachineLabels(machineID string) (map[string]string, error) {
+	return defaultClient.GetMachineLabels(machineID)
+}
+
+// GetMachinesByOwnerID is a wrapper around Default().GetMachinesByOwnerID.
+func GetMachinesByOwnerID(ownerID uuid.UUID) ([]Machine, error) {
+	return defaultClient.GetMachinesByOwnerID(ownerID)
+}
+
+// GetMachineByName is a wrapper around Default().GetMachineByName.
+func GetMachineByName(machineName string) (*Machine, error) {
+	return defaultClient.GetMachineByName(machineName)
+}
+
+// Postgres
+
+// GetPostgresCluster is a wrapper around Default().GetPostgresCluster.
+func GetPostgresCluster(storageID string) (*StorageConfig, error) {
+	return defaultClient.GetPostgresCluster(storageID)
+}
+
+// GetPostgresCredentials is a wrapper around Default().GetPostgresCredentials.
+func GetPostgresCredentials(storageID string) (*PostgresCredentials, error) {
+	return defaultClient.GetPostgresCredentials(storageID)
+}
+
+// CreatePostgresUser is a wrapper around Default().CreatePostgresUser.
+func CreatePostgresUser(storageID string, req CreateDatabaseUserRequest) (*CreateDatabaseUserResponse, error) {
+	return defaultClient.CreatePostgresUser(storageID, req)
+}
+
+// DeletePostgresUser is a wrapper around Default().DeletePostgresUser.
+func DeletePostgresUser(storageID, username string) error {
+	return defaultClient.DeletePostgresUser(storageID, username)
+}
@@ -11,12 +11,12 @@ const dnsPollInterval = 3 * time.Second
 // GetIngressDNSStatus asks the backend control plane for the current DNS
 // propagation status of a specific ingress. The backend owns the authoritative
 // view, so the CLI does not guess from the workstation's resolver.
-func GetIngressDNSStatus(ingressID string) (*DNSStatusResponse, error) {
+func (c *Client) GetIngressDNSStatus(ingressID string) (*DNSStatusResponse, error) {
 	var resp struct {
 		Error bool              `json:"error"`
 		Data  DNSStatusResponse `json:"data"`
 	}
-	if err := makeRequest("GET", fmt.Sprintf("/ingresses/%s/dns-status", ingressID), nil, &resp); err != nil {
+	if err := c.do("GET", fmt.Sprintf("/ingresses/%s/dns-status", ingressID), nil, &resp); err != nil {
 		return nil, err
 	}
 	return &resp.Data, nil
@@ -24,7 +24,7 @@ func GetIngressDNSStatus(ingressID string) (*DNSStatusResponse, error) {
 
 // WaitForIngressDNSStatus polls the backend until the ingress DNS status is
 // resolved or the timeout expires.
-func WaitForIngressDNSStatus(ingressID string, timeout time.Duration) (*DNSStatusResponse, error) {
+func (c *Client) WaitForIngressDNSStatus(ingressID string, timeout time.Duration) (*DNSStatusResponse, error) {
 	deadline := time.Now().Add(timeout)
 	ticker := time.NewTicker(dnsPollInterval)
 	defer ticker.Stop()
@@ -32,7 +32,7 @@ func WaitForIngressDNSStatus(ingressID string, timeout time.Duration) (*DNSStatu
 	var last *DNSStatusResponse
 
 	for {
-		status, err := GetIngressDNSStatus(ingressID)
+		status, err := c.GetIngressDNSStatus(ingressID)
 		if err == nil && status != nil {
 			last = status
 			if status.Status == DNSStatusResolved {
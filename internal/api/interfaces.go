@@ -0,0 +1,119 @@
+package api
+
+import (
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// The API is split by domain so code can depend on, and tests fake, only
+// the calls it makes. *Client implements all of them.
+
+// DeploymentsAPI creates, reads and manages deployments.
+type DeploymentsAPI interface {
+	UpsertDeployment(req Deployment, response *string) error
+	GetDeployment(deploymentID string) (*Deployment, error)
+	GetDeploymentByAppLabel(namespace, appLabel string) (*Deployment, error)
+	ListDeployments() ([]Deployment, error)
+	ListDeploymentsByNamespace(namespace string) ([]Deployment, error)
+	ListDeploymentVersions(deploymentID string) ([]DeploymentVersion, error)
+	GetDeploymentStatus(deploymentID string) (*DeploymentStatus, error)
+	WaitForDeployment(deploymentID string, timeout time.Duration) (*DeploymentStatus, error)
+	RestartDeployment(deploymentID string) error
+	RollbackDeployment(deploymentID string, versionNumber int) error
+	DeleteDeployment(deploymentID string) (*DeletionResult, error)
+}
+
+// ServicesAPI manages the Kubernetes services in front of deployments.
+type ServicesAPI interface {
+	UpsertService(service Service, response *string) error
+	ListServices() ([]Service, error)
+	DeleteService(serviceID string) error
+}
+
+// IngressesAPI manages ingresses and the domains that route to deployments.
+type IngressesAPI interface {
+	UpsertIngress(ingress Ingress) (*Ingress, error)
+	ListIngresses() ([]Ingress, error)
+	GetIngressByDeploymentID(deploymentID string) (*Ingress, error)
+	GetIngressByDomainName(domainName string) (*Ingress, error)
+	GenerateDomainName(projectName string) (string, error)
+	DeleteIngress(ingressID string) error
+	GetDomainStatus(ingressID, domain string, probe bool) (*DomainStatusResponse, error)
+	GetIngressDNSStatus(ingressID string) (*DNSStatusResponse, error)
+	WaitForIngressDNSStatus(ingressID string, timeout time.Duration) (*DNSStatusResponse, error)
+}
+
+// EnvironmentsAPI manages deployment environment variables.
+type EnvironmentsAPI interface {
+	UpsertEnvironment(env Environment) (*Environment, error)
+	ListEnvironments() ([]Environment, error)
+	GetEnvironmentsByNamespace(namespace string) ([]Environment, error)
+	GetEnvironmentsByDeploymentID(deploymentID string) ([]Environment, error)
+	UnsetEnvironmentKey(environmentID, key string) error
+	DeleteEnvironment(environmentID string) error
+}
+
+// SecretsAPI manages deployment secrets.
+type SecretsAPI interface {
+	CreateSecret(secret Secret) (*Secret, error)
+	ListSecrets() ([]Secret, error)
+	GetSecretsByDeploymentID(deploymentID string) ([]Secret, error)
+	UnsetSecretKey(secretID, key string) error
+	DeleteSecret(secretID string) error
+}
+
+// VolumesAPI manages persistent volumes.
+type VolumesAPI interface {
+	CreateVolume(volume Volume) error
+	GetAllVolumes() ([]Volume, error)
+	GetVolumeLifecycleStatus(volumeID string) (*VolumeLifecycleStatus, error)
+	GetDeploymentVolumeLifecycleStatuses(deploymentID string) ([]VolumeLifecycleStatus, error)
+	DetachVolume(volumeID string) (*VolumeLifecycleStatus, error)
+	DeleteVolumePVC(volumeID string) (*VolumeLifecycleStatus, error)
+}
+
+// BuildsAPI runs cloud image builds.
+type BuildsAPI interface {
+	SubmitBuild(contextTarPath, projectName, dockerfilePath, builder string, buildArgs map[string]string) (string, error)
+	GetBuildStatus(buildID string) (*BuildStatusResponse, error)
+	WaitForBuildResult(buildID string, progressWriter io.Writer) (*BuildResult, error)
+}
+
+// MachinesAPI looks up the machines deployments can be placed on.
+type MachinesAPI interface {
+	GetMachineLabels(machineID string) (map[string]string, error)
+	GetMachinesByOwnerID(ownerID uuid.UUID) ([]Machine, error)
+	GetMachineByName(machineName string) (*Machine, error)
+}
+
+// PostgresAPI reads Postgres clusters and manages their database users.
+type PostgresAPI interface {
+	GetPostgresCluster(storageID string) (*StorageConfig, error)
+	GetPostgresCredentials(storageID string) (*PostgresCredentials, error)
+	CreatePostgresUser(storageID string, req CreateDatabaseUserRequest) (*CreateDatabaseUserResponse, error)
+	DeletePostgresUser(storageID, username string) error
+}
+
+// RawAPI sends arbitrary requests, for "1ctl api".
+type RawAPI interface {
+	Raw(method, path string, body []byte, mainAPI bool) (*RawResponse, error)
+}
+
+// API is the SatuSky API that the deploy orchestrator and the app commands
+// use.
+type API interface {
+	DeploymentsAPI
+	ServicesAPI
+	IngressesAPI
+	EnvironmentsAPI
+	SecretsAPI
+	VolumesAPI
+	BuildsAPI
+	MachinesAPI
+	PostgresAPI
+	RawAPI
+}
+
+var _ API = (*Client)(nil)
@@ -4,6 +4,7 @@ import (
 	"1ctl/internal/utils"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -44,45 +45,14 @@ type MarketplaceDeployResponse struct {
 	Status       string    `json:"status"`
 }
 
-// GetMarketplaceApps gets all marketplace apps
-func GetMarketplaceApps(limit, offset int, sortBy string) ([]MarketplaceApp, error) {
-	path := "/marketplace/all"
-	params := []string{}
-	if limit > 0 {
-		params = append(params, fmt.Sprintf("limit=%d", limit))
-	}
+// ListMarketplaceApps fetches the marketplace catalog sorted by sortBy,
+// starting offset apps in, passing each page to yield.
+func ListMarketplaceApps(offset int, sortBy string, opts PageOptions, yield func([]MarketplaceApp) error) error {
+	params := map[string]string{"sort": sortBy}
 	if offset > 0 {
-		params = append(params, fmt.Sprintf("offset=%d", offset))
-	}
-	if sortBy != "" {
-		params = append(params, fmt.Sprintf("sort=%s", sortBy))
-	}
-	if len(params) > 0 {
-		path = path + "?"
-		for i, p := range params {
-			if i > 0 {
-				path += "&"
-			}
-			path += p
-		}
-	}
-
-	var resp apiResponse
-	err := makeRequest("GET", path, nil, &resp)
-	if err != nil {
-		return nil, err
-	}
-
-	data, err := json.Marshal(resp.Data)
-	if err != nil {
-		return nil, utils.NewError(fmt.Sprintf("failed to marshal response data: %s", err.Error()), nil)
-	}
-
-	var apps []MarketplaceApp
-	if err := json.Unmarshal(data, &apps); err != nil {
-		return nil, utils.NewError(fmt.Sprintf("failed to unmarshal marketplace apps: %s", err.Error()), nil)
+		params["offset"] = strconv.Itoa(offset)
 	}
-	return apps, nil
+	return Paginate(defaultClient, ListEndpoint{Path: listPath("/marketplace/all", params)}, opts, yield)
 }
 
 // GetMarketplaceApp gets a specific marketplace app
@@ -117,9 +87,8 @@ func ResolveMarketplaceApp(nameOrID string) (*MarketplaceApp, error) {
 		}
 	}
 
-	// Fall back to name-based lookup in the catalog.
-	// Use a high limit to guarantee all apps are returned (backend defaults to 9).
-	apps, err := GetMarketplaceApps(100, 0, "name")
+	// Fall back to name-based lookup in the whole catalog.
+	apps, err := collect[MarketplaceApp](defaultClient, ListEndpoint{Path: listPath("/marketplace/all", map[string]string{"sort": "name"})}, PageOptions{All: true, PageSize: 100})
 	if err != nil {
 		return nil, utils.NewError(fmt.Sprintf("failed to list marketplace apps: %s", err.Error()), nil)
 	}
@@ -25,61 +25,20 @@ type Notification struct {
 	CreatedAt      time.Time              `json:"created_at"`
 }
 
-// notificationListResponse represents paginated notification response
-type notificationListResponse struct {
-	Notifications []Notification `json:"notifications"`
-	Total         int64          `json:"total"`
-	Page          int            `json:"page"`
-	PageSize      int            `json:"page_size"`
-}
-
 // UnreadCount represents unread notification count
 type UnreadCount struct {
 	Count int `json:"count"`
 }
 
-// GetNotifications gets notifications for an organization
-func GetNotifications(orgID string, unreadOnly bool, limit int) ([]Notification, error) {
-	path := fmt.Sprintf("/notifications/organizations/%s", orgID)
-	params := []string{}
+// ListNotifications fetches an organization's notifications, only unread
+// ones when unreadOnly is set, passing each page to yield.
+func ListNotifications(orgID string, unreadOnly bool, opts PageOptions, yield func([]Notification) error) error {
+	var unread string
 	if unreadOnly {
-		params = append(params, "unread=true")
-	}
-	if limit > 0 {
-		params = append(params, fmt.Sprintf("limit=%d", limit))
-	}
-	if len(params) > 0 {
-		path = path + "?"
-		for i, p := range params {
-			if i > 0 {
-				path += "&"
-			}
-			path += p
-		}
-	}
-
-	var resp apiResponse
-	err := makeRequest("GET", path, nil, &resp)
-	if err != nil {
-		return nil, err
-	}
-
-	data, err := json.Marshal(resp.Data)
-	if err != nil {
-		return nil, utils.NewError(fmt.Sprintf("failed to marshal response data: %s", err.Error()), nil)
-	}
-
-	// Try paginated response first, fall back to flat array
-	var listResp notificationListResponse
-	if err := json.Unmarshal(data, &listResp); err == nil && listResp.Notifications != nil {
-		return listResp.Notifications, nil
-	}
-
-	var notifications []Notification
-	if err := json.Unmarshal(data, &notifications); err != nil {
-		return nil, utils.NewError(fmt.Sprintf("failed to unmarshal notifications: %s", err.Error()), nil)
+		unread = "true"
 	}
-	return notifications, nil
+	path := listPath(fmt.Sprintf("/notifications/organizations/%s", orgID), map[string]string{"unread": unread})
+	return Paginate(defaultClient, ListEndpoint{Path: path, ItemsKey: "notifications", SizeParam: "page_size"}, opts, yield)
 }
 
 // GetUnreadCount gets unread notification count
@@ -0,0 +1,227 @@
+package api
+
+import (
+	"1ctl/internal/config"
+	"1ctl/internal/context"
+	"1ctl/internal/utils"
+	stdcontext "context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Browser login uses the OAuth 2.0 device authorization grant (RFC 8628):
+// the CLI asks for a device code, the user approves it in the browser, and
+// the CLI polls the token endpoint until it receives an access token and a
+// refresh token.
+
+// oauthClientID identifies 1ctl to the authorization server.
+const oauthClientID = "1ctl"
+
+// AuthURLEnv overrides the authorization server base URL.
+const AuthURLEnv = "SATUSKY_AUTH_URL"
+
+// devicePollUnit scales the polling interval. Tests shrink it.
+var devicePollUnit = time.Second
+
+// DeviceAuthorization is the device code response.
+type DeviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// OAuthToken is a token endpoint response.
+type OAuthToken struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+type oauthError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e *oauthError) Error() string {
+	if e.Description != "" {
+		return e.Description
+	}
+	return e.Code
+}
+
+func init() {
+	// The context package cannot import api, so the refresh call is
+	// registered from here.
+	context.SetTokenRefresher(func(refreshToken string) (string, string, error) {
+		tok, err := RefreshAccessToken(refreshToken)
+		if err != nil {
+			return "", "", err
+		}
+		return tok.AccessToken, tok.RefreshToken, nil
+	})
+}
+
+// authBaseURL returns the authorization server URL: SATUSKY_AUTH_URL, or
+// /oauth on the main API host.
+func authBaseURL() (string, error) {
+	base := os.Getenv(AuthURLEnv)
+	if base == "" {
+		base = strings.TrimSuffix(config.GetConfig().ApiURL, "/")
+		base = strings.TrimSuffix(base, "/cli")
+		base = strings.TrimSuffix(base, "/") + "/oauth"
+	}
+	base = strings.TrimSuffix(base, "/")
+	if !utils.IsLocalhostURL(base) && !strings.HasPrefix(base, "https://") {
+		return "", utils.NewError(fmt.Sprintf("refusing to authenticate over insecure connection (%s). Use HTTPS or http://localhost for local development", base), nil)
+	}
+	return base, nil
+}
+
+// postOAuthForm posts a form to the authorization server and decodes a
+// successful response into out. OAuth error responses are returned as
+// *oauthError.
+func postOAuthForm(path string, form url.Values, out interface{}) error {
+	base, err := authBaseURL()
+	if err != nil {
+		return err
+	}
+	form.Set("client_id", oauthClientID)
+	resp, err := httpClient.PostForm(base+path, form)
+	if err != nil {
+		return utils.NewKindError(utils.ErrNetwork, fmt.Sprintf("failed to reach authorization server: %s", err.Error()), nil)
+	}
+	defer func() { _ = resp.Body.Close() }() //nolint:errcheck
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return utils.NewError(fmt.Sprintf("failed to read response body: %s", err.Error()), nil)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var oerr oauthError
+		if json.Unmarshal(body, &oerr) == nil && oerr.Code != "" {
+			return &oerr
+		}
+		return utils.NewError(fmt.Sprintf("authorization server returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body))), nil)
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return utils.NewError(fmt.Sprintf("failed to decode response: %s", err.Error()), nil)
+	}
+	return nil
+}
+
+// StartDeviceAuthorization requests a device code and the URL where the
+// user approves it.
+func StartDeviceAuthorization() (*DeviceAuthorization, error) {
+	var da DeviceAuthorization
+	if err := postOAuthForm("/device/code", url.Values{"scope": {"cli"}}, &da); err != nil {
+		return nil, err
+	}
+	if da.DeviceCode == "" || da.VerificationURI == "" {
+		return nil, utils.NewError("authorization server returned an incomplete device code response", nil)
+	}
+	if da.Interval <= 0 {
+		da.Interval = 5
+	}
+	return &da, nil
+}
+
+// PollDeviceToken polls until the user approves or denies the device code,
+// the code expires, or ctx is cancelled.
+func PollDeviceToken(ctx stdcontext.Context, da *DeviceAuthorization) (*OAuthToken, error) {
+	interval := time.Duration(da.Interval) * devicePollUnit
+	var deadline <-chan time.Time
+	if da.ExpiresIn > 0 {
+		timer := time.NewTimer(time.Duration(da.ExpiresIn) * devicePollUnit)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-deadline:
+			return nil, utils.NewError("the login code expired before it was approved. Run '1ctl auth login --web' again", nil)
+		case <-time.After(interval):
+		}
+
+		var tok OAuthToken
+		err := postOAuthForm("/token", url.Values{
+			"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+			"device_code": {da.DeviceCode},
+		}, &tok)
+		if err == nil {
+			if tok.AccessToken == "" {
+				return nil, utils.NewError("authorization server returned no access token", nil)
+			}
+			utils.RegisterSecret(tok.AccessToken, tok.RefreshToken)
+			return &tok, nil
+		}
+
+		oerr, ok := err.(*oauthError)
+		if !ok {
+			return nil, err
+		}
+		switch oerr.Code {
+		case "authorization_pending":
+		case "slow_down":
+			interval += 5 * devicePollUnit
+		case "access_denied":
+			return nil, utils.NewError("login was denied in the browser", nil)
+		case "expired_token":
+			return nil, utils.NewError("the login code expired before it was approved. Run '1ctl auth login --web' again", nil)
+		default:
+			return nil, utils.NewError(fmt.Sprintf("login failed: %s", oerr.Error()), nil)
+		}
+	}
+}
+
+// RefreshAccessToken exchanges a refresh token for a new access token. The
+// response may rotate the refresh token.
+func RefreshAccessToken(refreshToken string) (*OAuthToken, error) {
+	var tok OAuthToken
+	err := postOAuthForm("/token", url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	}, &tok)
+	if err != nil {
+		return nil, err
+	}
+	if tok.AccessToken == "" {
+		return nil, utils.NewError("authorization server returned no access token", nil)
+	}
+	utils.RegisterSecret(tok.AccessToken, tok.RefreshToken)
+	return &tok, nil
+}
+
+// ExchangeIDToken trades a CI workload identity token for a short-lived,
+// org-scoped CLI token (RFC 8693 token exchange). The server checks the
+// token's repository and branch against the organization's trust policies.
+func ExchangeIDToken(idToken string) (*OAuthToken, error) {
+	utils.RegisterSecret(idToken)
+	var tok OAuthToken
+	err := postOAuthForm("/token", url.Values{
+		"grant_type":           {"urn:ietf:params:oauth:grant-type:token-exchange"},
+		"subject_token":        {idToken},
+		"subject_token_type":   {"urn:ietf:params:oauth:token-type:id_token"},
+		"requested_token_type": {"urn:ietf:params:oauth:token-type:access_token"},
+	}, &tok)
+	if err != nil {
+		return nil, err
+	}
+	if tok.AccessToken == "" {
+		return nil, utils.NewError("authorization server returned no access token", nil)
+	}
+	utils.RegisterSecret(tok.AccessToken)
+	return &tok, nil
+}
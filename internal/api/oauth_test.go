@@ -0,0 +1,217 @@
+package api
+
+import (
+	"1ctl/internal/context"
+	stdcontext "context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeAuthServer is a minimal RFC 8628 authorization server. The device
+// code is approved after pending polls.
+type fakeAuthServer struct {
+	t       *testing.T
+	mu      sync.Mutex
+	pending int
+	polls   int
+	deny    bool
+	// refreshes counts refresh_token grants.
+	refreshes int
+}
+
+func (f *fakeAuthServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		f.t.Fatal(err)
+	}
+	if got := r.PostForm.Get("client_id"); got != oauthClientID {
+		f.t.Errorf("client_id = %q, want %q", got, oauthClientID)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/v1/oauth/device/code":
+		writeJSON(w, http.StatusOK, DeviceAuthorization{
+			DeviceCode:      "dev-code",
+			UserCode:        "ABCD-EFGH",
+			VerificationURI: "https://cloud.example.com/device",
+			ExpiresIn:       600,
+			Interval:        1,
+		})
+	case "/v1/oauth/token":
+		switch r.PostForm.Get("grant_type") {
+		case "urn:ietf:params:oauth:grant-type:device_code":
+			f.polls++
+			switch {
+			case f.deny:
+				writeJSON(w, http.StatusBadRequest, oauthError{Code: "access_denied"})
+			case f.polls == 1:
+				writeJSON(w, http.StatusBadRequest, oauthError{Code: "slow_down"})
+			case f.polls <= f.pending:
+				writeJSON(w, http.StatusBadRequest, oauthError{Code: "authorization_pending"})
+			default:
+				writeJSON(w, http.StatusOK, OAuthToken{AccessToken: testJWT(time.Hour), RefreshToken: "refresh-1", TokenType: "Bearer"})
+			}
+		case "urn:ietf:params:oauth:grant-type:token-exchange":
+			if r.PostForm.Get("subject_token") != "trusted-id-token" || r.PostForm.Get("subject_token_type") != "urn:ietf:params:oauth:token-type:id_token" {
+				writeJSON(w, http.StatusBadRequest, oauthError{Code: "invalid_grant", Description: "no trust policy matches repository acme/other"})
+				return
+			}
+			writeJSON(w, http.StatusOK, OAuthToken{AccessToken: testJWT(time.Hour), TokenType: "Bearer"})
+		case "refresh_token":
+			f.refreshes++
+			if got := r.PostForm.Get("refresh_token"); got != "refresh-1" {
+				writeJSON(w, http.StatusBadRequest, oauthError{Code: "invalid_grant", Description: "refresh token revoked"})
+				return
+			}
+			writeJSON(w, http.StatusOK, OAuthToken{AccessToken: testJWT(time.Hour), RefreshToken: "refresh-2", TokenType: "Bearer"})
+		default:
+			writeJSON(w, http.StatusBadRequest, oauthError{Code: "unsupported_grant_type"})
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v) //nolint:errcheck
+}
+
+// testJWT returns an unsigned JWT expiring after d.
+func testJWT(d time.Duration) string {
+	enc := base64.RawURLEncoding
+	payload := fmt.Sprintf(`{"exp":%d}`, time.Now().Add(d).Unix())
+	return enc.EncodeToString([]byte(`{"alg":"none"}`)) + "." + enc.EncodeToString([]byte(payload)) + ".sig"
+}
+
+func useFakeAuthServer(t *testing.T, f *fakeAuthServer) {
+	t.Helper()
+	f.t = t
+	server := httptest.NewServer(f)
+	t.Cleanup(server.Close)
+	t.Setenv("SATUSKY_API_URL", server.URL+"/v1/cli")
+	t.Setenv(AuthURLEnv, "")
+
+	original := devicePollUnit
+	devicePollUnit = time.Millisecond
+	t.Cleanup(func() { devicePollUnit = original })
+}
+
+func TestDeviceLoginFlow(t *testing.T) {
+	f := &fakeAuthServer{pending: 3}
+	useFakeAuthServer(t, f)
+
+	da, err := StartDeviceAuthorization()
+	if err != nil {
+		t.Fatalf("StartDeviceAuthorization() error = %v", err)
+	}
+	if da.UserCode != "ABCD-EFGH" {
+		t.Errorf("UserCode = %q", da.UserCode)
+	}
+
+	tok, err := PollDeviceToken(stdcontext.Background(), da)
+	if err != nil {
+		t.Fatalf("PollDeviceToken() error = %v", err)
+	}
+	if tok.RefreshToken != "refresh-1" || tok.AccessToken == "" {
+		t.Errorf("token = %+v", tok)
+	}
+	if f.polls != 4 {
+		t.Errorf("polls = %d, want 4", f.polls)
+	}
+}
+
+func TestDeviceLoginDenied(t *testing.T) {
+	useFakeAuthServer(t, &fakeAuthServer{deny: true})
+
+	da, err := StartDeviceAuthorization()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = PollDeviceToken(stdcontext.Background(), da)
+	if err == nil || !strings.Contains(err.Error(), "denied") {
+		t.Errorf("PollDeviceToken() error = %v, want denied", err)
+	}
+}
+
+func TestRequestRefreshesExpiringToken(t *testing.T) {
+	f := &fakeAuthServer{}
+	useFakeAuthServer(t, f)
+
+	originalStore := context.Default()
+	t.Cleanup(func() { context.SetDefault(originalStore) })
+	store := context.NewTestStore(t.TempDir())
+	store.SetProfileOverride("test")
+	context.SetDefault(store)
+
+	expiring := testJWT(10 * time.Second)
+	if err := context.SaveOAuthLoginState(expiring, "refresh-1", "user", "a@b.c", "org", "Org", "ns"); err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := AuthToken()
+	if err != nil {
+		t.Fatalf("AuthToken() error = %v", err)
+	}
+	if token == expiring {
+		t.Error("AuthToken() returned the expiring token")
+	}
+	if f.refreshes != 1 {
+		t.Errorf("refreshes = %d, want 1", f.refreshes)
+	}
+	if got := context.Default().GetRefreshToken(); got != "refresh-2" {
+		t.Errorf("refresh token = %q, want rotated refresh-2", got)
+	}
+
+	// A fresh token is used as is.
+	if _, err := AuthToken(); err != nil {
+		t.Fatal(err)
+	}
+	if f.refreshes != 1 {
+		t.Errorf("refreshes = %d after a fresh token, want 1", f.refreshes)
+	}
+}
+
+func TestRefreshFailureAsksToLogInAgain(t *testing.T) {
+	useFakeAuthServer(t, &fakeAuthServer{})
+
+	originalStore := context.Default()
+	t.Cleanup(func() { context.SetDefault(originalStore) })
+	store := context.NewTestStore(t.TempDir())
+	store.SetProfileOverride("test")
+	context.SetDefault(store)
+
+	if err := context.SaveOAuthLoginState(testJWT(-time.Minute), "revoked", "user", "a@b.c", "org", "Org", "ns"); err != nil {
+		t.Fatal(err)
+	}
+	err := context.CheckTokenExpiry()
+	if err == nil || !strings.Contains(err.Error(), "auth login --web") {
+		t.Errorf("CheckTokenExpiry() error = %v, want a re-login hint", err)
+	}
+}
+
+func TestExchangeIDToken(t *testing.T) {
+	useFakeAuthServer(t, &fakeAuthServer{})
+
+	tok, err := ExchangeIDToken("trusted-id-token")
+	if err != nil {
+		t.Fatalf("ExchangeIDToken() error = %v", err)
+	}
+	if tok.AccessToken == "" || tok.RefreshToken != "" {
+		t.Errorf("token = %+v, want an access token only", tok)
+	}
+
+	_, err = ExchangeIDToken("untrusted-id-token")
+	if err == nil || !strings.Contains(err.Error(), "no trust policy") {
+		t.Errorf("ExchangeIDToken(untrusted) error = %v", err)
+	}
+}
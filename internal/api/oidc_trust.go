@@ -0,0 +1,70 @@
+package api
+
+import (
+	"1ctl/internal/utils"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OIDCTrustPolicy lets CI jobs from one repository, and optionally one
+// branch, exchange their identity token for a CLI token in an organization.
+type OIDCTrustPolicy struct {
+	ID         uuid.UUID `json:"trust_id"`
+	Provider   string    `json:"provider"`
+	Repository string    `json:"repository"`
+	Branch     string    `json:"branch,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// CreateOIDCTrustRequest represents request to create a trust policy
+type CreateOIDCTrustRequest struct {
+	Provider   string `json:"provider"`
+	Repository string `json:"repository"`
+	Branch     string `json:"branch,omitempty"`
+}
+
+// ListOIDCTrustPolicies lists the trust policies of an organization
+func ListOIDCTrustPolicies(orgID string) ([]OIDCTrustPolicy, error) {
+	var resp apiResponse
+	if err := makeRequest("GET", fmt.Sprintf("/oidc-trust/list/%s", orgID), nil, &resp); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(resp.Data)
+	if err != nil {
+		return nil, utils.NewError(fmt.Sprintf("failed to marshal response data: %s", err.Error()), nil)
+	}
+
+	var policies []OIDCTrustPolicy
+	if err := json.Unmarshal(data, &policies); err != nil {
+		return nil, utils.NewError(fmt.Sprintf("failed to unmarshal trust policies: %s", err.Error()), nil)
+	}
+	return policies, nil
+}
+
+// CreateOIDCTrustPolicy adds a trust policy to an organization
+func CreateOIDCTrustPolicy(orgID string, req CreateOIDCTrustRequest) (*OIDCTrustPolicy, error) {
+	var resp apiResponse
+	if err := makeRequest("POST", fmt.Sprintf("/oidc-trust/create/%s", orgID), req, &resp); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(resp.Data)
+	if err != nil {
+		return nil, utils.NewError(fmt.Sprintf("failed to marshal response data: %s", err.Error()), nil)
+	}
+
+	var policy OIDCTrustPolicy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return nil, utils.NewError(fmt.Sprintf("failed to unmarshal trust policy: %s", err.Error()), nil)
+	}
+	return &policy, nil
+}
+
+// DeleteOIDCTrustPolicy removes a trust policy from an organization
+func DeleteOIDCTrustPolicy(orgID, trustID string) error {
+	return makeRequest("POST", fmt.Sprintf("/oidc-trust/delete/%s/%s", orgID, trustID), nil, nil)
+}
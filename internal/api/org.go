@@ -40,7 +40,7 @@ type UpdateTeamMemberRoleRequest struct {
 // GetUserOrganizations gets all organizations for the current user
 func GetUserOrganizations() ([]Organization, error) {
 	var resp apiResponse
-	err := makeRequest("GET", "/organizations/user", nil, &resp)
+	err := defaultClient.getCached("/organizations/user", orgsCacheTTL, &resp)
 	if err != nil {
 		return nil, err
 	}
@@ -60,6 +60,7 @@ func GetUserOrganizations() ([]Organization, error) {
 // CreateOrganization creates a new organization
 func CreateOrganization(req CreateOrganizationRequest) (*Organization, error) {
 	var resp apiResponse
+	defer defaultClient.invalidateCache("/organizations/user")
 	err := makeRequest("POST", "/organizations/create", req, &resp)
 	if err != nil {
 		return nil, err
@@ -79,6 +80,7 @@ func CreateOrganization(req CreateOrganizationRequest) (*Organization, error) {
 
 // DeleteOrganization deletes an organization
 func DeleteOrganization(orgID string) error {
+	defer defaultClient.invalidateCache("/organizations/user")
 	return makeRequest("POST", fmt.Sprintf("/organizations/delete/%s", orgID), nil, nil)
 }
 
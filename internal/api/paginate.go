@@ -0,0 +1,179 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"1ctl/internal/utils"
+)
+
+// PageOptions says how much of a paged list to fetch. List commands set it
+// from their --all, --limit and --page-size flags.
+type PageOptions struct {
+	// All follows pages until the list is exhausted.
+	All bool
+	// Limit stops after this many items. Without All, 0 fetches one page.
+	Limit int
+	// PageSize is the number of items to ask for per request; 0 asks for
+	// Limit items, or lets the API choose.
+	PageSize int
+}
+
+// Validate checks the options, as given on the command line.
+func (o PageOptions) Validate() error {
+	if o.Limit < 0 {
+		return utils.NewKindError(utils.ErrUsage, fmt.Sprintf("--limit must not be negative, got %d", o.Limit), nil)
+	}
+	if o.PageSize < 0 {
+		return utils.NewKindError(utils.ErrUsage, fmt.Sprintf("--page-size must not be negative, got %d", o.PageSize), nil)
+	}
+	return nil
+}
+
+// ListEndpoint describes a paged list endpoint.
+type ListEndpoint struct {
+	// Path is the endpoint under the CLI API, with any filters in its query.
+	Path string
+	// ItemsKey is the field of the data object that holds the items; empty
+	// when data is the list itself.
+	ItemsKey string
+	// SizeParam is the query parameter for the page size: "limit" when
+	// empty, or e.g. "page_size".
+	SizeParam string
+}
+
+// Paginate fetches the items of a paged list endpoint with c, passing each
+// page to yield as it arrives, until the list is exhausted or opts.Limit
+// items have been passed. It follows page tokens, cursors, offsets and page
+// numbers as NextPage does; a list returned without paging fields is
+// followed by offset while pages come back full.
+func Paginate[T any](c *Client, e ListEndpoint, opts PageOptions, yield func(page []T) error) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	sizeParam := e.SizeParam
+	if sizeParam == "" {
+		sizeParam = "limit"
+	}
+	size := opts.PageSize
+	if size == 0 {
+		size = opts.Limit
+	}
+
+	path := e.Path
+	if size > 0 {
+		path = setQuery(path, sizeParam, strconv.Itoa(size))
+	}
+	for seen := 0; ; {
+		var body json.RawMessage
+		if err := c.do("GET", path, nil, &body); err != nil {
+			return err
+		}
+		items, err := pageItems[T](body, e.ItemsKey)
+		if err != nil {
+			return err
+		}
+		n := len(items)
+		if opts.Limit > 0 && seen+n > opts.Limit {
+			items = items[:opts.Limit-seen]
+		}
+		if len(items) > 0 {
+			if err := yield(items); err != nil {
+				return err
+			}
+		}
+		seen += len(items)
+
+		if n == 0 || (opts.Limit > 0 && seen >= opts.Limit) || (!opts.All && opts.Limit == 0) {
+			return nil
+		}
+		next, paged, ok := nextPage(path, body)
+		if !paged && sizeParam == "limit" && size > 0 && n >= size {
+			next, ok = nextOffset(path, n), true
+		}
+		if !ok || next == path {
+			return nil
+		}
+		path = next
+	}
+}
+
+// pageItems decodes the items of a page: data itself, or data[key].
+func pageItems[T any](body []byte, key string) ([]T, error) {
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, utils.NewError(fmt.Sprintf("failed to parse response: %s", err.Error()), nil)
+	}
+	data := envelope.Data
+	if key != "" && len(data) > 0 && data[0] == '{' {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return nil, utils.NewError(fmt.Sprintf("failed to parse response: %s", err.Error()), nil)
+		}
+		data = fields[key]
+	}
+	var items []T
+	if len(data) == 0 {
+		return nil, nil
+	}
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, utils.NewError(fmt.Sprintf("failed to parse list: %s", err.Error()), nil)
+	}
+	return items, nil
+}
+
+// nextOffset returns path with its offset moved past n items.
+func nextOffset(path string, n int) string {
+	u, err := url.Parse(path)
+	if err != nil {
+		return path
+	}
+	q := u.Query()
+	offset, _ := strconv.Atoi(q.Get("offset")) //nolint:errcheck // a missing offset is 0
+	q.Set("offset", strconv.Itoa(offset+n))
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// setQuery returns path with its query parameter key set to value.
+func setQuery(path, key, value string) string {
+	u, err := url.Parse(path)
+	if err != nil {
+		return path
+	}
+	q := u.Query()
+	q.Set(key, value)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// listPath returns path with the non-empty params as its query.
+func listPath(path string, params map[string]string) string {
+	q := url.Values{}
+	for key, value := range params {
+		if value != "" {
+			q.Set(key, value)
+		}
+	}
+	if len(q) == 0 {
+		return path
+	}
+	return path + "?" + q.Encode()
+}
+
+// collect returns the items Paginate fetches as one list.
+func collect[T any](c *Client, e ListEndpoint, opts PageOptions) ([]T, error) {
+	var all []T
+	err := Paginate(c, e, opts, func(page []T) error {
+		all = append(all, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return all, nil
+}
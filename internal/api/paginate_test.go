@@ -0,0 +1,127 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+type pageItem struct {
+	ID int `json:"id"`
+}
+
+// pagedServer serves n items at /items, as the "items" field of data with
+// limit, offset and total when offsets is set, or else as a bare array.
+func pagedServer(t *testing.T, n int, offsets bool) (*Client, *[]string) {
+	t.Helper()
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RawQuery)
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit")) //nolint:errcheck
+		if limit == 0 {
+			limit = 3
+		}
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset")) //nolint:errcheck
+		items := []pageItem{}
+		for i := offset; i < n && i < offset+limit; i++ {
+			items = append(items, pageItem{ID: i})
+		}
+		if !offsets {
+			writeJSON(w, http.StatusOK, map[string]interface{}{"data": items})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]interface{}{
+			"items": items, "limit": limit, "offset": offset, "total": n,
+		}})
+	}))
+	t.Cleanup(server.Close)
+	return &Client{BaseURL: server.URL + "/v1/cli", Token: StaticToken("t"), Namespace: "acme"}, &requests
+}
+
+func TestPaginate(t *testing.T) {
+	tests := []struct {
+		name         string
+		items        int
+		offsets      bool
+		opts         PageOptions
+		wantPages    []int
+		wantRequests []string
+	}{
+		{name: "one page by default", items: 7, offsets: true, wantPages: []int{3}, wantRequests: []string{""}},
+		{name: "limit sets the page size", items: 7, offsets: true, opts: PageOptions{Limit: 2}, wantPages: []int{2}, wantRequests: []string{"limit=2"}},
+		{name: "all follows offsets to total", items: 7, offsets: true, opts: PageOptions{All: true}, wantPages: []int{3, 3, 1}, wantRequests: []string{"", "limit=3&offset=3", "limit=3&offset=6"}},
+		{name: "limit across pages", items: 7, offsets: true, opts: PageOptions{Limit: 5, PageSize: 2}, wantPages: []int{2, 2, 1}, wantRequests: []string{"limit=2", "limit=2&offset=2", "limit=2&offset=4"}},
+		{name: "bare arrays by offset while full", items: 4, opts: PageOptions{All: true, PageSize: 2}, wantPages: []int{2, 2}, wantRequests: []string{"limit=2", "limit=2&offset=2", "limit=2&offset=4"}},
+		{name: "empty list", items: 0, offsets: true, opts: PageOptions{All: true}, wantRequests: []string{""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, requests := pagedServer(t, tt.items, tt.offsets)
+			var pages []int
+			next := 0
+			err := Paginate(client, ListEndpoint{Path: "/items", ItemsKey: "items"}, tt.opts, func(page []pageItem) error {
+				for _, item := range page {
+					if item.ID != next {
+						return fmt.Errorf("got item %d, want %d", item.ID, next)
+					}
+					next++
+				}
+				pages = append(pages, len(page))
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("Paginate() error = %v", err)
+			}
+			if !reflect.DeepEqual(pages, tt.wantPages) {
+				t.Errorf("pages = %v, want %v", pages, tt.wantPages)
+			}
+			if !reflect.DeepEqual(*requests, tt.wantRequests) {
+				t.Errorf("requests = %q, want %q", *requests, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestPaginateFollowsPageNumbers(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RawQuery)
+		page, _ := strconv.Atoi(r.URL.Query().Get("page")) //nolint:errcheck
+		if page == 0 {
+			page = 1
+		}
+		items := []pageItem{{ID: 2*page - 2}, {ID: 2*page - 1}}
+		if page == 3 {
+			items = items[:1]
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]interface{}{
+			"notifications": items, "page": page, "page_size": 2, "total": 5,
+		}})
+	}))
+	t.Cleanup(server.Close)
+	client := &Client{BaseURL: server.URL + "/v1/cli", Token: StaticToken("t")}
+
+	got, err := collect[pageItem](client, ListEndpoint{Path: "/n?unread=true", ItemsKey: "notifications", SizeParam: "page_size"}, PageOptions{All: true, PageSize: 2})
+	if err != nil {
+		t.Fatalf("collect() error = %v", err)
+	}
+	if len(got) != 5 || got[4].ID != 4 {
+		t.Errorf("items = %v, want 0 to 4", got)
+	}
+	want := []string{"page_size=2&unread=true", "page=2&page_size=2&unread=true", "page=3&page_size=2&unread=true"}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("requests = %q, want %q", requests, want)
+	}
+}
+
+func TestPaginateRejectsNegativeOptions(t *testing.T) {
+	for _, opts := range []PageOptions{{Limit: -1}, {PageSize: -1}} {
+		err := Paginate(&Client{}, ListEndpoint{Path: "/x"}, opts, func([]pageItem) error { return nil })
+		if err == nil {
+			t.Errorf("Paginate(%+v) succeeded", opts)
+		}
+	}
+}
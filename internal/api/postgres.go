@@ -2,6 +2,7 @@ package api
 
 import (
 	"1ctl/internal/context"
+	"1ctl/internal/utils"
 	"fmt"
 	"net/url"
 	"time"
@@ -222,12 +223,12 @@ func ListPostgresClusters(namespace string) ([]StorageConfig, error) {
 	return clusters, nil
 }
 
-func GetPostgresCluster(storageID string) (*StorageConfig, error) {
+func (c *Client) GetPostgresCluster(storageID string) (*StorageConfig, error) {
 	var resp struct {
 		Error bool          `json:"error"`
 		Data  StorageConfig `json:"data"`
 	}
-	if err := makeRequest("GET", fmt.Sprintf("/storage/id/%s", url.PathEscape(storageID)), nil, &resp); err != nil {
+	if err := c.do("GET", fmt.Sprintf("/storage/id/%s", url.PathEscape(storageID)), nil, &resp); err != nil {
 		return nil, err
 	}
 	return &resp.Data, nil
@@ -252,14 +253,16 @@ func GetPostgresStatus(storageID string) (*PostgresStatus, error) {
 	return &resp.Data, nil
 }
 
-func GetPostgresCredentials(storageID string) (*PostgresCredentials, error) {
+func (c *Client) GetPostgresCredentials(storageID string) (*PostgresCredentials, error) {
 	var resp struct {
 		Error bool                `json:"error"`
 		Data  PostgresCredentials `json:"data"`
 	}
-	if err := makeRequest("GET", fmt.Sprintf("/storage/%s/credentials", url.PathEscape(storageID)), nil, &resp); err != nil {
+	if err := c.do("GET", fmt.Sprintf("/storage/%s/credentials", url.PathEscape(storageID)), nil, &resp); err != nil {
 		return nil, err
 	}
+	creds := resp.Data
+	utils.RegisterSecret(creds.Password, creds.URI, creds.InternalURI, creds.ExternalURI)
 	return &resp.Data, nil
 }
 
@@ -274,7 +277,7 @@ func ListPostgresUsers(storageID string) ([]CNPGDatabaseUser, error) {
 	return resp.Data, nil
 }
 
-func CreatePostgresUser(storageID string, req CreateDatabaseUserRequest) (*CreateDatabaseUserResponse, error) {
+func (c *Client) CreatePostgresUser(storageID string, req CreateDatabaseUserRequest) (*CreateDatabaseUserResponse, error) {
 	var resp struct {
 		Error                bool             `json:"error"`
 		Data                 CNPGDatabaseUser `json:"data"`
@@ -283,9 +286,11 @@ func CreatePostgresUser(storageID string, req CreateDatabaseUserRequest) (*Creat
 		ReconciliationStatus string           `json:"reconciliation_status,omitempty"`
 		ReadinessMessage     string           `json:"readiness_message,omitempty"`
 	}
-	if err := makeRequest("POST", fmt.Sprintf("/storage/%s/database-users", url.PathEscape(storageID)), req, &resp); err != nil {
+	if err := c.do("POST", fmt.Sprintf("/storage/%s/database-users", url.PathEscape(storageID)), req, &resp); err != nil {
 		return nil, err
 	}
+	// The password is shown once on purpose; only keep it out of CI logs.
+	utils.MaskInCI(resp.Password)
 	return &CreateDatabaseUserResponse{
 		User:                 resp.Data,
 		Password:             resp.Password,
@@ -295,8 +300,8 @@ func CreatePostgresUser(storageID string, req CreateDatabaseUserRequest) (*Creat
 	}, nil
 }
 
-func DeletePostgresUser(storageID, username string) error {
-	return makeRequest("DELETE", fmt.Sprintf("/storage/%s/database-users/%s", url.PathEscape(storageID), url.PathEscape(username)), nil, nil)
+func (c *Client) DeletePostgresUser(storageID, username string) error {
+	return c.do("DELETE", fmt.Sprintf("/storage/%s/database-users/%s", url.PathEscape(storageID), url.PathEscape(username)), nil, nil)
 }
 
 func ListPostgresFirewallRules(storageID string) ([]CNPGFirewallRule, error) {
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// RawResponse is an API response as received, for requests the CLI does
+// not wrap (1ctl api).
+type RawResponse struct {
+	StatusCode int
+	Status     string
+	Header     http.Header
+	Body       []byte
+}
+
+// RequestID returns the API's ID for the request, quoted in support cases.
+func (r *RawResponse) RequestID() string {
+	return r.Header.Get(requestIDHeader)
+}
+
+// Raw sends body, JSON or nil, to path under the CLI API, or under the main
+// API when mainAPI is set, with the client's credentials. path may carry a
+// query string. The response is returned whatever its status.
+func (c *Client) Raw(method, path string, body []byte, mainAPI bool) (*RawResponse, error) {
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	base := c.baseURL()
+	if mainAPI {
+		base = c.mainBaseURL()
+	}
+	resp, respBody, err := c.send(strings.ToUpper(method), base+path, body, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &RawResponse{StatusCode: resp.StatusCode, Status: resp.Status, Header: resp.Header, Body: respBody}, nil
+}
+
+// pageFields are the paging fields list endpoints return, either at the
+// top level or inside the data object.
+type pageFields struct {
+	NextPageToken string `json:"next_page_token"`
+	NextCursor    string `json:"next_cursor"`
+	Total         *int   `json:"total"`
+	Limit         *int   `json:"limit"`
+	Offset        *int   `json:"offset"`
+	Page          *int   `json:"page"`
+	PageSize      *int   `json:"page_size"`
+}
+
+// NextPage returns the path of the page after body, the response to a GET
+// of path, and false on the last page or for a response that is not paged.
+// It follows next_page_token and next_cursor, then offsets and 1-based page
+// numbers, stopping at total or at a short page.
+func NextPage(path string, body []byte) (string, bool) {
+	next, _, ok := nextPage(path, body)
+	return next, ok
+}
+
+// nextPage is NextPage that also reports whether body carried any paging
+// fields, to tell the last page from a response that is not paged.
+func nextPage(path string, body []byte) (next string, paged, ok bool) {
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return "", false, false
+	}
+	var f pageFields
+	_ = json.Unmarshal(body, &f) //nolint:errcheck // an object, as checked above
+	if data, ok := envelope["data"]; ok && len(data) > 0 && data[0] == '{' {
+		_ = json.Unmarshal(data, &f) //nolint:errcheck
+	}
+
+	u, err := url.Parse(path)
+	if err != nil {
+		return "", false, false
+	}
+	q := u.Query()
+	n, counted := pageLength(envelope["data"])
+
+	paged = f.NextPageToken != "" || f.NextCursor != "" || f.Offset != nil || f.Page != nil
+	switch {
+	case f.NextPageToken != "":
+		if q.Get("page_token") == f.NextPageToken {
+			return "", paged, false
+		}
+		q.Set("page_token", f.NextPageToken)
+	case f.NextCursor != "":
+		if q.Get("cursor") == f.NextCursor {
+			return "", paged, false
+		}
+		q.Set("cursor", f.NextCursor)
+	case f.Offset != nil:
+		if !counted || n == 0 {
+			return "", paged, false
+		}
+		offset := *f.Offset + n
+		if f.Total != nil && offset >= *f.Total {
+			return "", paged, false
+		}
+		if f.Total == nil && f.Limit != nil && n < *f.Limit {
+			return "", paged, false
+		}
+		q.Set("offset", strconv.Itoa(offset))
+		if f.Limit != nil && q.Get("limit") == "" {
+			q.Set("limit", strconv.Itoa(*f.Limit))
+		}
+	case f.Page != nil:
+		if !counted || n == 0 {
+			return "", paged, false
+		}
+		if f.PageSize != nil {
+			if f.Total != nil && *f.Page**f.PageSize >= *f.Total {
+				return "", paged, false
+			}
+			if f.Total == nil && n < *f.PageSize {
+				return "", paged, false
+			}
+		}
+		q.Set("page", strconv.Itoa(*f.Page+1))
+	default:
+		return "", paged, false
+	}
+	u.RawQuery = q.Encode()
+	return u.String(), true, true
+}
+
+// pageLength counts the items in a page's data: the array itself, or the
+// only array in the data object.
+func pageLength(data json.RawMessage) (int, bool) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err == nil {
+		return len(items), true
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return 0, false
+	}
+	n, arrays := 0, 0
+	for _, v := range fields {
+		if err := json.Unmarshal(v, &items); err == nil && len(v) > 0 && v[0] == '[' {
+			n, arrays = len(items), arrays+1
+		}
+	}
+	return n, arrays == 1
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestRawUsesMainAPIAndKeepsErrorResponses(t *testing.T) {
+	var got string
+	useRetryTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		got = r.Method + " " + r.URL.RequestURI() + " " + r.Header.Get("x-satusky-api-key")
+		writeJSON(w, http.StatusNotFound, map[string]interface{}{"error": true, "message": "no such machine"})
+	})
+
+	resp, err := Default().Raw("get", "machines/m-1?x=1", nil, true)
+	if err != nil {
+		t.Fatalf("Raw() error = %v", err)
+	}
+	if want := "GET /v1/machines/m-1?x=1 test-token"; got != want {
+		t.Errorf("request = %q, want %q", got, want)
+	}
+	if resp.StatusCode != http.StatusNotFound || len(resp.Body) == 0 {
+		t.Errorf("Raw() = %d %s, want the 404 and its body", resp.StatusCode, resp.Body)
+	}
+}
+
+func TestNextPage(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		body     string
+		wantPath string
+	}{
+		{name: "offset", path: "/logs", body: `{"data":{"items":[1,2],"total":5,"limit":2,"offset":0}}`, wantPath: "/logs?limit=2&offset=2"},
+		{name: "offset at total", path: "/logs?offset=4", body: `{"data":{"items":[5],"total":5,"limit":2,"offset":4}}`},
+		{name: "short page without total", path: "/logs", body: `{"data":[1],"limit":2,"offset":0}`},
+		{name: "page numbers", path: "/x?page=1", body: `{"data":[1,2],"page":1,"page_size":2,"total":3}`, wantPath: "/x?page=2"},
+		{name: "last page number", path: "/x?page=2", body: `{"data":[3],"page":2,"page_size":2,"total":3}`},
+		{name: "page token", path: "/x", body: `{"data":[1],"next_page_token":"t2"}`, wantPath: "/x?page_token=t2"},
+		{name: "repeated token", path: "/x?page_token=t2", body: `{"data":[1],"next_page_token":"t2"}`},
+		{name: "cursor", path: "/x?a=b", body: `{"data":{"next_cursor":"c9","rows":[]}}`, wantPath: "/x?a=b&cursor=c9"},
+		{name: "not paged", path: "/x", body: `{"data":{"id":"d-1"}}`},
+		{name: "not JSON", path: "/x", body: `ok`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := NextPage(tt.path, []byte(tt.body))
+			if got != tt.wantPath || ok != (tt.wantPath != "") {
+				t.Errorf("NextPage() = %q, %v, want %q", got, ok, tt.wantPath)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"1ctl/internal/utils"
+)
+
+// Retry limits can be set with the --max-retries and --retry-max-delay
+// global flags or these environment variables.
+const (
+	MaxRetriesEnv    = "SATUSKY_MAX_RETRIES"
+	RetryMaxDelayEnv = "SATUSKY_RETRY_MAX_DELAY"
+)
+
+// IdempotencyKeyHeader carries a key the API uses to apply a mutating
+// request at most once, so the client can retry it safely.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// RetryPolicy controls how API requests are retried. Requests are retried
+// after network errors and 429, 502, 503 and 504 responses, if their method
+// is idempotent or they carry an Idempotency-Key.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt; 0
+	// disables retries.
+	MaxRetries int
+	// BaseDelay is the backoff before the first retry. It doubles with
+	// each retry, with jitter.
+	BaseDelay time.Duration
+	// MaxDelay caps a single wait, including one asked for by Retry-After.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy is used unless SetRetryPolicy is called.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxRetries: 3, BaseDelay: 500 * time.Millisecond, MaxDelay: 30 * time.Second}
+}
+
+var (
+	retryMu     sync.RWMutex
+	retryPolicy = DefaultRetryPolicy()
+
+	// retrySleep waits between attempts; tests replace it.
+	retrySleep = time.Sleep
+)
+
+// SetRetryPolicy sets the retry policy for this process.
+func SetRetryPolicy(p RetryPolicy) error {
+	if p.MaxRetries < 0 {
+		return utils.NewError(fmt.Sprintf("max retries must not be negative, got %d", p.MaxRetries), nil)
+	}
+	if p.MaxDelay <= 0 {
+		return utils.NewError(fmt.Sprintf("retry max delay must be positive, got %s", p.MaxDelay), nil)
+	}
+	if p.BaseDelay <= 0 || p.BaseDelay > p.MaxDelay {
+		p.BaseDelay = min(DefaultRetryPolicy().BaseDelay, p.MaxDelay)
+	}
+	retryMu.Lock()
+	defer retryMu.Unlock()
+	retryPolicy = p
+	return nil
+}
+
+// GetRetryPolicy returns the retry policy in effect.
+func GetRetryPolicy() RetryPolicy {
+	retryMu.RLock()
+	defer retryMu.RUnlock()
+	return retryPolicy
+}
+
+// backoff returns the wait before retry n (0-based): exponential from
+// BaseDelay, capped at MaxDelay, with the upper half jittered so clients
+// that failed together do not retry together.
+func (p RetryPolicy) backoff(n int) time.Duration {
+	d := p.MaxDelay
+	if n < 32 {
+		if exp := p.BaseDelay << n; exp > 0 && exp < p.MaxDelay {
+			d = exp
+		}
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1)) // #nosec G404 -- jitter, not security
+}
+
+// idempotentMethod reports whether repeating a request with method has the
+// same effect as sending it once.
+func idempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// retryableStatus reports whether a response status is worth retrying.
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryAfter parses a Retry-After header, given in seconds or as an HTTP
+// date.
+func retryAfter(h http.Header, now time.Time) (time.Duration, bool) {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// doWithRetry sends the request built by newRequest with client, retrying
+// it under the retry policy. newRequest is called for every attempt so the
+// body can be sent again. It returns the last response and its body, which
+// has been read and closed; a retryable status that is still failing after
+// the last retry is returned as a response, not an error.
+func doWithRetry(client *http.Client, newRequest func() (*http.Request, error)) (*http.Response, []byte, error) {
+	policy := GetRetryPolicy()
+	for attempt := 0; ; attempt++ {
+		req, err := newRequest()
+		if err != nil {
+			return nil, nil, utils.NewError(fmt.Sprintf("failed to create request: %s", err.Error()), nil)
+		}
+
+		var (
+			body   []byte
+			reason string
+			wait   time.Duration
+		)
+		resp, err := client.Do(req)
+		if err != nil {
+			err = utils.NewKindError(utils.ErrNetwork, fmt.Sprintf("failed to make request: %s", err.Error()), nil)
+		} else {
+			body, err = io.ReadAll(resp.Body)
+			_ = resp.Body.Close() //nolint:errcheck
+			if err != nil {
+				err = utils.NewKindError(utils.ErrNetwork, fmt.Sprintf("failed to read response body: %s", err.Error()), nil)
+			}
+		}
+		switch {
+		case err != nil:
+			reason = err.Error()
+		case retryableStatus(resp.StatusCode):
+			reason = resp.Status
+			if d, ok := retryAfter(resp.Header, time.Now()); ok {
+				wait = d
+			}
+		default:
+			return resp, body, nil
+		}
+
+		canRetry := idempotentMethod(req.Method) || req.Header.Get(IdempotencyKeyHeader) != ""
+		if !canRetry || attempt >= policy.MaxRetries {
+			if err != nil {
+				return nil, nil, err
+			}
+			return resp, body, nil
+		}
+
+		if wait == 0 {
+			wait = policy.backoff(attempt)
+		}
+		wait = min(wait, policy.MaxDelay)
+		utils.Debugf("%s %s: %s; retrying in %s (retry %d of %d)", req.Method, req.URL.Redacted(), reason, wait.Round(time.Millisecond), attempt+1, policy.MaxRetries)
+		retrySleep(wait)
+	}
+}
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"1ctl/internal/context"
+)
+
+// useRetryTestAPI points API requests at handler with a logged-in test
+// profile and records the waits between retries instead of sleeping.
+func useRetryTestAPI(t *testing.T, handler http.HandlerFunc) *[]time.Duration {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	t.Setenv("SATUSKY_API_URL", server.URL+"/v1/cli")
+
+	originalStore := context.Default()
+	t.Cleanup(func() { context.SetDefault(originalStore) })
+	store := context.NewTestStore(t.TempDir())
+	store.SetProfileOverride("test")
+	context.SetDefault(store)
+	if err := context.SetToken("test-token"); err != nil {
+		t.Fatal(err)
+	}
+
+	originalPolicy, originalSleep := GetRetryPolicy(), retrySleep
+	t.Cleanup(func() {
+		_ = SetRetryPolicy(originalPolicy) //nolint:errcheck
+		retrySleep = originalSleep
+	})
+	var (
+		mu    sync.Mutex
+		waits []time.Duration
+	)
+	retrySleep = func(d time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		waits = append(waits, d)
+	}
+	return &waits
+}
+
+func TestRetryHonorsRetryAfterAndReusesIdempotencyKey(t *testing.T) {
+	var keys []string
+	waits := useRetryTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(IdempotencyKeyHeader))
+		switch len(keys) {
+		case 1:
+			w.Header().Set("Retry-After", "7")
+			writeJSON(w, http.StatusTooManyRequests, map[string]interface{}{"message": "slow down"})
+		case 2:
+			writeJSON(w, http.StatusServiceUnavailable, map[string]interface{}{"message": "unavailable"})
+		default:
+			writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]string{"id": "sec-1"}})
+		}
+	})
+
+	if err := makeRequest(http.MethodPost, "/secrets/create", map[string]string{"name": "db"}, nil); err != nil {
+		t.Fatalf("makeRequest() error = %v", err)
+	}
+	if len(keys) != 3 {
+		t.Fatalf("attempts = %d, want 3", len(keys))
+	}
+	if keys[0] == "" || keys[1] != keys[0] || keys[2] != keys[0] {
+		t.Errorf("idempotency keys = %v, want one key reused on every attempt", keys)
+	}
+	if len(*waits) != 2 || (*waits)[0] != 7*time.Second {
+		t.Errorf("waits = %v, want Retry-After's 7s first", *waits)
+	}
+	// The second retry backs off from twice the base delay, half jittered.
+	if base, d := GetRetryPolicy().BaseDelay, (*waits)[1]; d < base || d > 2*base {
+		t.Errorf("backoff = %s, want between %s and %s", d, base, 2*base)
+	}
+}
+
+func TestRetryGivesUpAfterMaxRetries(t *testing.T) {
+	attempts := 0
+	waits := useRetryTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Retry-After", "3600")
+		writeJSON(w, http.StatusBadGateway, map[string]interface{}{"message": "bad gateway"})
+	})
+	if err := SetRetryPolicy(RetryPolicy{MaxRetries: 2, MaxDelay: 5 * time.Second}); err != nil {
+		t.Fatal(err)
+	}
+
+	err := makeRequest(http.MethodGet, "/deployments", nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "bad gateway") {
+		t.Errorf("makeRequest() error = %v, want the last response's message", err)
+	}
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+	for _, d := range *waits {
+		if d != 5*time.Second {
+			t.Errorf("wait = %s, want Retry-After capped at 5s", d)
+		}
+	}
+}
+
+func TestNoRetryForClientErrors(t *testing.T) {
+	attempts := 0
+	useRetryTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if r.Header.Get(IdempotencyKeyHeader) != "" {
+			t.Errorf("GET sent an idempotency key")
+		}
+		writeJSON(w, http.StatusNotFound, map[string]interface{}{"message": "not found"})
+	})
+
+	if err := makeRequest(http.MethodGet, "/deployments/missing", nil, nil); err == nil {
+		t.Fatal("makeRequest() should fail")
+	}
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1", attempts)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"", 0, false},
+		{"120", 2 * time.Minute, true},
+		{"-1", 0, false},
+		{now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second, true},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+		{"soon", 0, false},
+	}
+	for _, tt := range tests {
+		h := http.Header{}
+		if tt.value != "" {
+			h.Set("Retry-After", tt.value)
+		}
+		got, ok := retryAfter(h, now)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("retryAfter(%q) = %s, %v, want %s, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
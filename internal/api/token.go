@@ -75,6 +75,8 @@ func CreateCLIToken(userID, orgID string, req CreateTokenRequest) (*CLIToken, er
 	if err := json.Unmarshal(data, &token); err != nil {
 		return nil, utils.NewError(fmt.Sprintf("failed to unmarshal token: %s", err.Error()), nil)
 	}
+	// The token is shown once on purpose; only keep it out of CI logs.
+	utils.MaskInCI(token.Token)
 	return &token, nil
 }
 
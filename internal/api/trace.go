@@ -0,0 +1,438 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"1ctl/internal/transport"
+	"1ctl/internal/utils"
+	"1ctl/internal/version"
+
+	"github.com/gorilla/websocket"
+)
+
+// TraceFileEnv names a HAR file to record API traffic to, like --trace-file.
+const TraceFileEnv = "SATUSKY_TRACE_FILE"
+
+const (
+	// debugBodyLimit truncates bodies in debug output.
+	debugBodyLimit = 2 << 10
+	// harBodyLimit truncates bodies in the trace file.
+	harBodyLimit = 256 << 10
+	// requestIDHeader is the API's ID for a request, quoted in support cases.
+	requestIDHeader = "X-Request-Id"
+)
+
+// sensitiveHeaders are never written to debug output or trace files.
+var sensitiveHeaders = map[string]bool{
+	"X-Satusky-Api-Key":   true,
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+}
+
+// sensitiveFields are JSON and form fields whose values are masked in
+// traced bodies, in addition to every value registered with the redactor.
+var sensitiveFields = map[string]bool{
+	"token":         true,
+	"access_token":  true,
+	"refresh_token": true,
+	"id_token":      true,
+	"subject_token": true,
+	"device_code":   true,
+	"password":      true,
+	"secret":        true,
+	"client_secret": true,
+	"private_key":   true,
+	"api_key":       true,
+	"value":         true,
+}
+
+var (
+	traceMu      sync.Mutex
+	tracePath    string
+	traceEntries []harEntry
+)
+
+// StartTrace records every API request from now on. FinishTrace writes
+// them to path as a HAR 1.2 file.
+func StartTrace(path string) {
+	traceMu.Lock()
+	defer traceMu.Unlock()
+	tracePath, traceEntries = path, nil
+}
+
+// FinishTrace writes the requests recorded since StartTrace to the trace
+// file and stops recording. It does nothing if no trace was started.
+func FinishTrace() error {
+	traceMu.Lock()
+	path, entries := tracePath, traceEntries
+	tracePath, traceEntries = "", nil
+	traceMu.Unlock()
+	if path == "" {
+		return nil
+	}
+	if entries == nil {
+		entries = []harEntry{}
+	}
+
+	har := harFile{Log: harLog{
+		Version: "1.2",
+		Creator: harCreator{Name: "1ctl", Version: version.Version},
+		Entries: entries,
+	}}
+	data, err := json.MarshalIndent(har, "", "  ")
+	if err != nil {
+		return utils.NewError(fmt.Sprintf("failed to encode trace: %s", err.Error()), nil)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return utils.NewError(fmt.Sprintf("failed to write trace file: %s", err.Error()), nil)
+	}
+	utils.Debugf("wrote a trace of %d entries to %s", len(entries), path)
+	return nil
+}
+
+func tracing() (debug, har bool) {
+	traceMu.Lock()
+	defer traceMu.Unlock()
+	return utils.IsDebug(), tracePath != ""
+}
+
+// tracingTransport logs requests in debug mode and records them for the
+// trace file. It passes requests straight through when neither is on.
+type tracingTransport struct {
+	base http.RoundTripper
+}
+
+func (t tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = transport.Shared()
+	}
+	if debug, har := tracing(); !debug && !har {
+		return base.RoundTrip(req)
+	}
+
+	var reqBody []byte
+	if req.Body != nil && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			reqBody, _ = io.ReadAll(body) //nolint:errcheck
+			_ = body.Close()              //nolint:errcheck
+		}
+	}
+
+	start := time.Now()
+	resp, err := base.RoundTrip(req)
+	var respBody []byte
+	if err == nil {
+		respBody, err = io.ReadAll(resp.Body)
+		_ = resp.Body.Close() //nolint:errcheck
+		resp.Body = io.NopCloser(bytes.NewReader(respBody))
+		if err != nil {
+			resp = nil
+		}
+	}
+	recordExchange(req, reqBody, resp, respBody, start, err)
+	return resp, err
+}
+
+// DialWebSocket opens a WebSocket to wsURL. The handshake is traced like any
+// other API request and carries the CLI's version.
+func DialWebSocket(wsURL string, header http.Header) (*websocket.Conn, error) {
+	header = header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set("User-Agent", Default().userAgent())
+	header.Set(CLIVersionHeader, version.Version)
+	start := time.Now()
+	conn, resp, err := transport.WebSocketDialer().Dial(wsURL, header)
+	if debug, har := tracing(); debug || har {
+		var respBody []byte
+		if resp != nil && resp.Body != nil {
+			respBody, _ = io.ReadAll(resp.Body) //nolint:errcheck
+		}
+		req := &http.Request{Method: http.MethodGet, Header: header, Proto: "HTTP/1.1"}
+		if u, parseErr := url.Parse(wsURL); parseErr == nil {
+			req.URL = u
+		}
+		recordExchange(req, nil, resp, respBody, start, err)
+	}
+	return conn, err
+}
+
+// recordExchange logs a request and its response, or the error that
+// prevented one, and adds it to the trace.
+func recordExchange(req *http.Request, reqBody []byte, resp *http.Response, respBody []byte, start time.Time, err error) {
+	elapsed := time.Since(start)
+	debug, har := tracing()
+	target := "<invalid URL>"
+	if req.URL != nil {
+		target = req.URL.Redacted()
+	}
+	reqText := redactBody(req.Header.Get("Content-Type"), reqBody)
+
+	if debug {
+		utils.Debugf("→ %s %s", req.Method, target)
+		if reqText != "" {
+			utils.Debugf("  %s", truncateBody(reqText, debugBodyLimit))
+		}
+		switch {
+		case err != nil && resp == nil:
+			utils.Debugf("← %s after %s", err.Error(), elapsed.Round(time.Millisecond))
+		default:
+			line := fmt.Sprintf("← %s in %s", resp.Status, elapsed.Round(time.Millisecond))
+			if id := resp.Header.Get(requestIDHeader); id != "" {
+				line += ", request ID " + id
+			}
+			utils.Debugf("%s", line)
+			if text := redactBody(resp.Header.Get("Content-Type"), respBody); text != "" {
+				utils.Debugf("  %s", truncateBody(text, debugBodyLimit))
+			}
+		}
+	}
+	if !har {
+		return
+	}
+
+	entry := harEntry{
+		StartedDateTime: start.UTC().Format(time.RFC3339Nano),
+		Time:            float64(elapsed.Microseconds()) / 1000,
+		Request: harRequest{
+			Method:      req.Method,
+			URL:         target,
+			HTTPVersion: protoOrDefault(req.Proto),
+			Cookies:     []harNameValue{},
+			Headers:     harHeaders(req.Header),
+			QueryString: []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    len(reqBody),
+		},
+		Response: harResponse{
+			HTTPVersion: "HTTP/1.1",
+			Cookies:     []harNameValue{},
+			Headers:     []harNameValue{},
+			Content:     harContent{MimeType: "x-unknown"},
+			HeadersSize: -1,
+			BodySize:    -1,
+		},
+		Cache:   struct{}{},
+		Timings: harTimings{Send: 0, Wait: float64(elapsed.Microseconds()) / 1000, Receive: 0},
+	}
+	if req.URL != nil {
+		for name, values := range req.URL.Query() {
+			for _, v := range values {
+				entry.Request.QueryString = append(entry.Request.QueryString, harNameValue{Name: name, Value: v})
+			}
+		}
+	}
+	if reqText != "" {
+		entry.Request.PostData = &harPostData{
+			MimeType: req.Header.Get("Content-Type"),
+			Text:     truncateBody(reqText, harBodyLimit),
+		}
+	}
+	if resp != nil {
+		mimeType := resp.Header.Get("Content-Type")
+		if mimeType == "" {
+			mimeType = "x-unknown"
+		}
+		entry.Response.Status = resp.StatusCode
+		entry.Response.StatusText = strings.TrimSpace(strings.TrimPrefix(resp.Status, fmt.Sprint(resp.StatusCode)))
+		entry.Response.HTTPVersion = protoOrDefault(resp.Proto)
+		entry.Response.Headers = harHeaders(resp.Header)
+		entry.Response.BodySize = len(respBody)
+		entry.Response.Content = harContent{
+			Size:     len(respBody),
+			MimeType: mimeType,
+			Text:     truncateBody(redactBody(resp.Header.Get("Content-Type"), respBody), harBodyLimit),
+		}
+	}
+	if err != nil {
+		entry.Comment = err.Error()
+	}
+
+	traceMu.Lock()
+	defer traceMu.Unlock()
+	if tracePath != "" {
+		traceEntries = append(traceEntries, entry)
+	}
+}
+
+func protoOrDefault(proto string) string {
+	if proto == "" {
+		return "HTTP/1.1"
+	}
+	return proto
+}
+
+// harHeaders lists h sorted by name, with credentials masked.
+func harHeaders(h http.Header) []harNameValue {
+	out := []harNameValue{}
+	for name, values := range h {
+		for _, v := range values {
+			if sensitiveHeaders[http.CanonicalHeaderKey(name)] {
+				v = utils.RedactedValue
+			}
+			out = append(out, harNameValue{Name: name, Value: v})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// redactBody renders a body for tracing: JSON and form bodies with
+// sensitive fields masked, other text as is, and binary bodies as a size.
+// Registered secret values are masked in all of them.
+func redactBody(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType) //nolint:errcheck
+	var text string
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		var v interface{}
+		if err := json.Unmarshal(body, &v); err != nil {
+			text = string(body)
+			break
+		}
+		masked, err := json.Marshal(redactJSON(v))
+		if err != nil {
+			text = string(body)
+			break
+		}
+		text = string(masked)
+	case mediaType == "application/x-www-form-urlencoded":
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			text = string(body)
+			break
+		}
+		for name := range values {
+			if sensitiveFields[strings.ToLower(name)] {
+				values[name] = []string{utils.RedactedValue}
+			}
+		}
+		text = values.Encode()
+	case strings.HasPrefix(mediaType, "text/") || (mediaType == "" && utf8.Valid(body)):
+		text = string(body)
+	default:
+		return fmt.Sprintf("[%d bytes of %s omitted]", len(body), mediaType)
+	}
+	return utils.Redact(text)
+}
+
+func redactJSON(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if _, isString := value.(string); isString && sensitiveFields[strings.ToLower(key)] {
+				v[key] = utils.RedactedValue
+				continue
+			}
+			v[key] = redactJSON(value)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redactJSON(v[i])
+		}
+	}
+	return v
+}
+
+// truncateBody cuts s to at most limit bytes on a rune boundary.
+func truncateBody(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return fmt.Sprintf("%s… (%d more bytes)", s[:cut], len(s)-cut)
+}
+
+// HAR 1.2, as described at http://www.softwareishard.com/blog/har-12-spec/.
+type harFile struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	Comment         string      `json:"comment,omitempty"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"1ctl/internal/utils"
+)
+
+// useDebugOutput turns on debug output for the test and returns what is
+// logged.
+func useDebugOutput(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	wasDebug := utils.IsDebug()
+	utils.SetDebug(true)
+	utils.SetDebugOutput(&buf)
+	t.Cleanup(func() {
+		utils.SetDebug(wasDebug)
+		utils.SetDebugOutput(os.Stderr)
+	})
+	return &buf
+}
+
+func TestTraceRedactsCredentialsAndSecrets(t *testing.T) {
+	useRetryTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(requestIDHeader, "req-42")
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"data": map[string]string{"secret_id": "sec-1"}})
+	})
+	debug := useDebugOutput(t)
+	path := filepath.Join(t.TempDir(), "out.har")
+	StartTrace(path)
+	t.Cleanup(func() { _ = FinishTrace() }) //nolint:errcheck
+
+	body := map[string]interface{}{
+		"namespace":  "acme",
+		"key_values": []map[string]string{{"key": "DB_PASSWORD", "value": "hunter2-hunter2"}},
+	}
+	if err := makeRequest(http.MethodPost, "/secrets/create", body, nil); err != nil {
+		t.Fatalf("makeRequest() error = %v", err)
+	}
+	if err := FinishTrace(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, leak := range []string{"test-token", "hunter2-hunter2"} {
+		if strings.Contains(debug.String(), leak) {
+			t.Errorf("debug output contains %q:\n%s", leak, debug)
+		}
+	}
+	if !strings.Contains(debug.String(), "201 Created") || !strings.Contains(debug.String(), "request ID req-42") {
+		t.Errorf("debug output lacks the status and request ID:\n%s", debug)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, leak := range []string{"test-token", "hunter2-hunter2"} {
+		if strings.Contains(string(data), leak) {
+			t.Errorf("trace file contains %q", leak)
+		}
+	}
+	var har harFile
+	if err := json.Unmarshal(data, &har); err != nil {
+		t.Fatalf("trace file is not JSON: %v", err)
+	}
+	if har.Log.Version != "1.2" || len(har.Log.Entries) != 1 {
+		t.Fatalf("log = %+v, want one HAR 1.2 entry", har.Log)
+	}
+	entry := har.Log.Entries[0]
+	if entry.Request.Method != http.MethodPost || !strings.HasSuffix(entry.Request.URL, "/v1/cli/secrets/create") {
+		t.Errorf("request = %s %s", entry.Request.Method, entry.Request.URL)
+	}
+	if entry.Response.Status != http.StatusCreated || !strings.Contains(entry.Response.Content.Text, "sec-1") {
+		t.Errorf("response = %+v", entry.Response)
+	}
+	if entry.Request.PostData == nil || !strings.Contains(entry.Request.PostData.Text, "DB_PASSWORD") {
+		t.Errorf("postData = %+v, want the body with only values masked", entry.Request.PostData)
+	}
+	for _, h := range entry.Request.Headers {
+		if strings.EqualFold(h.Name, "x-satusky-api-key") && h.Value != utils.RedactedValue {
+			t.Errorf("api key header = %q, want it masked", h.Value)
+		}
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	tests := []struct {
+		contentType string
+		body        string
+		want        string
+	}{
+		{"application/json", `{"refresh_token":"r-123","org":"acme"}`, `{"org":"acme","refresh_token":"********"}`},
+		{"application/x-www-form-urlencoded", "grant_type=refresh_token&refresh_token=r-123", "grant_type=refresh_token&refresh_token=%2A%2A%2A%2A%2A%2A%2A%2A"},
+		{"text/plain; charset=utf-8", "not found", "not found"},
+		{"application/gzip", "\x1f\x8b\x08", "[3 bytes of application/gzip omitted]"},
+	}
+	for _, tt := range tests {
+		if got := redactBody(tt.contentType, []byte(tt.body)); got != tt.want {
+			t.Errorf("redactBody(%q, %q) = %q, want %q", tt.contentType, tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestTruncateBody(t *testing.T) {
+	if got := truncateBody("héllo", 2); got != "h… (5 more bytes)" {
+		t.Errorf("truncateBody() = %q", got)
+	}
+	if got := truncateBody("short", 10); got != "short" {
+		t.Errorf("truncateBody() = %q", got)
+	}
+}
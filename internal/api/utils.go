@@ -91,12 +91,12 @@ func SafeInt32(n int) (int32, error) {
 // GenerateDomainName calls the backend's domain generator endpoint and returns
 // the auto-assigned domain name (adjective+animal-suffix.satusky.com).
 // projectName is unused — the backend owns domain assignment.
-func GenerateDomainName(_ string) (string, error) {
+func (c *Client) GenerateDomainName(_ string) (string, error) {
 	var resp struct {
 		Error bool   `json:"error"`
 		Data  string `json:"data"`
 	}
-	if err := makeRequest("GET", "/ingresses/domainNameGenerator", nil, &resp); err != nil {
+	if err := c.do("GET", "/ingresses/domainNameGenerator", nil, &resp); err != nil {
 		return "", fmt.Errorf("failed to get auto-assigned domain: %w", err)
 	}
 	if resp.Data == "" {
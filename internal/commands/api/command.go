@@ -0,0 +1,110 @@
+// Package api defines the "1ctl api" command, which sends raw requests to
+// endpoints the CLI does not wrap yet.
+package api
+
+import (
+	"context"
+
+	"github.com/urfave/cli/v3"
+)
+
+// --- Flag name constants ------------------------------------------------
+
+const (
+	flagField    = "field"
+	flagRawField = "raw-field"
+	flagInput    = "input"
+	flagMainAPI  = "main-api"
+	flagJQ       = "jq"
+	flagPaginate = "paginate"
+)
+
+// --- Input structs ------------------------------------------------------
+
+type requestInput struct {
+	Method    string
+	Path      string
+	Fields    []string
+	RawFields []string
+	Input     string
+	MainAPI   bool
+	JQ        string
+	Paginate  bool
+}
+
+// --- Command tree -------------------------------------------------------
+
+// Command returns the "1ctl api" command.
+func Command() *cli.Command {
+	var in requestInput
+	return &cli.Command{
+		Name:      "api",
+		Usage:     "Send an authenticated request to the SatuSky API",
+		ArgsUsage: "[method] <path>",
+		Description: `Sends a request with the active profile's API URL and credentials and
+prints the JSON response. Use it for endpoints the CLI does not wrap yet.
+
+The path is relative to the CLI API (e.g. /deployments/status/<id>), or to
+the main API with --main-api. The method defaults to GET, or POST when
+fields or --input are given.
+
+Fields are sent as a JSON object, or as query parameters for GET:
+  -f key=value   adds a string
+  -F key=value   adds true, false, null or an integer as such; @file reads
+                 the value from a file, @- from stdin
+
+Examples:
+  1ctl api GET /deployments/status/<id>
+  1ctl api POST /machines/<id>/command -f type=CMD_REBOOT
+  1ctl api GET /deployments/namespace/acme --jq '.data[].app_label'
+  1ctl api GET /audit-logs/organizations/<org-id> --paginate`,
+		// Field values may contain commas.
+		DisableSliceFlagSeparator: true,
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:        flagField,
+				Aliases:     []string{"F"},
+				Usage:       "Add a typed field (key=value); true, false, null and integers keep their type, @file reads a file",
+				Destination: &in.Fields,
+			},
+			&cli.StringSliceFlag{
+				Name:        flagRawField,
+				Aliases:     []string{"f"},
+				Usage:       "Add a string field (key=value)",
+				Destination: &in.RawFields,
+			},
+			&cli.StringFlag{
+				Name:        flagInput,
+				Usage:       "Send the JSON in a file as the request body (- for stdin); fields become query parameters",
+				Destination: &in.Input,
+			},
+			&cli.BoolFlag{
+				Name:        flagMainAPI,
+				Usage:       "Send the request to the main API instead of the CLI API",
+				Destination: &in.MainAPI,
+			},
+			&cli.StringFlag{
+				Name:        flagJQ,
+				Aliases:     []string{"q"},
+				Usage:       "Print only the values at a path such as .data[].id or .data.items[0].name",
+				Destination: &in.JQ,
+			},
+			&cli.BoolFlag{
+				Name:        flagPaginate,
+				Usage:       "Fetch every page of a paged GET response, printing each in turn",
+				Destination: &in.Paginate,
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			switch cmd.Args().Len() {
+			case 1:
+				in.Path = cmd.Args().First()
+			case 2:
+				in.Method, in.Path = cmd.Args().Get(0), cmd.Args().Get(1)
+			default:
+				return cli.ShowSubcommandHelp(cmd)
+			}
+			return handleRequest(ctx, in)
+		},
+	}
+}
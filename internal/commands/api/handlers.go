@@ -0,0 +1,249 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	apipkg "1ctl/internal/api"
+	"1ctl/internal/utils"
+)
+
+func handleRequest(ctx context.Context, in requestInput) error {
+	method, path, body, err := buildRequest(in, os.Stdin)
+	if err != nil {
+		return err
+	}
+	var sel *query
+	if in.JQ != "" {
+		if sel, err = parseQuery(in.JQ); err != nil {
+			return err
+		}
+	}
+	if in.Paginate && method != http.MethodGet {
+		return utils.NewError("--paginate works only with GET requests", nil)
+	}
+
+	client := apipkg.FromContext(ctx)
+	for {
+		resp, err := client.Raw(method, path, body, in.MainAPI)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode >= 400 {
+			// The error body is printed as is for scripts to inspect.
+			if err := printBody(os.Stdout, resp.Body, nil); err != nil {
+				return err
+			}
+			return apiError(resp)
+		}
+		if err := printBody(os.Stdout, resp.Body, sel); err != nil {
+			return err
+		}
+		if !in.Paginate {
+			return nil
+		}
+		next, ok := apipkg.NextPage(path, resp.Body)
+		if !ok {
+			return nil
+		}
+		path = next
+	}
+}
+
+// buildRequest works out the method, path and body of the request from the
+// arguments, fields and --input, reading @- and --input - from stdin.
+func buildRequest(in requestInput, stdin io.Reader) (method, path string, body []byte, err error) {
+	path = in.Path
+	if u, err := url.Parse(path); err != nil || u.IsAbs() || u.Host != "" {
+		return "", "", nil, utils.NewError(fmt.Sprintf("path %q must be relative to the API URL, e.g. /deployments/status/<id>", path), nil)
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	fields, err := parseFields(in.Fields, in.RawFields, stdin)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	method = strings.ToUpper(in.Method)
+	if method == "" {
+		method = http.MethodGet
+		if len(fields) > 0 || in.Input != "" {
+			method = http.MethodPost
+		}
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return "", "", nil, utils.NewError(fmt.Sprintf("unsupported method %q", in.Method), nil)
+	}
+
+	if in.Input != "" {
+		if body, err = readInput(in.Input, stdin); err != nil {
+			return "", "", nil, err
+		}
+		if !json.Valid(body) {
+			return "", "", nil, utils.NewError(fmt.Sprintf("%s is not valid JSON", in.Input), nil)
+		}
+	}
+
+	if len(fields) == 0 {
+		return method, path, body, nil
+	}
+	if method == http.MethodGet || in.Input != "" {
+		return method, addQuery(path, fields), body, nil
+	}
+	if body, err = json.Marshal(fields); err != nil {
+		return "", "", nil, utils.NewError("failed to encode fields", err)
+	}
+	return method, path, body, nil
+}
+
+// parseFields turns -F and -f key=value pairs into request fields.
+func parseFields(typed, raw []string, stdin io.Reader) (map[string]interface{}, error) {
+	fields := make(map[string]interface{}, len(typed)+len(raw))
+	for _, f := range raw {
+		key, value, ok := strings.Cut(f, "=")
+		if !ok || key == "" {
+			return nil, utils.NewError(fmt.Sprintf("field %q must be key=value", f), nil)
+		}
+		fields[key] = value
+	}
+	for _, f := range typed {
+		key, value, ok := strings.Cut(f, "=")
+		if !ok || key == "" {
+			return nil, utils.NewError(fmt.Sprintf("field %q must be key=value", f), nil)
+		}
+		v, err := typedValue(value, stdin)
+		if err != nil {
+			return nil, err
+		}
+		fields[key] = v
+	}
+	return fields, nil
+}
+
+// typedValue interprets a -F value: literals keep their JSON type and @file
+// reads the value from a file.
+func typedValue(value string, stdin io.Reader) (interface{}, error) {
+	switch value {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return n, nil
+	}
+	if name, ok := strings.CutPrefix(value, "@"); ok {
+		data, err := readInput(name, stdin)
+		if err != nil {
+			return nil, err
+		}
+		return string(data), nil
+	}
+	return value, nil
+}
+
+func readInput(name string, stdin io.Reader) ([]byte, error) {
+	if name == "-" {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, utils.NewError("failed to read stdin", err)
+		}
+		return data, nil
+	}
+	data, err := os.ReadFile(name) // #nosec G304 -- file named by the user
+	if err != nil {
+		return nil, utils.NewError(fmt.Sprintf("failed to read %s: %s", name, err.Error()), nil)
+	}
+	return data, nil
+}
+
+// addQuery adds fields to path's query string.
+func addQuery(path string, fields map[string]interface{}) string {
+	u, err := url.Parse(path)
+	if err != nil {
+		return path
+	}
+	q := u.Query()
+	for key, value := range fields {
+		switch v := value.(type) {
+		case nil:
+			q.Set(key, "")
+		case string:
+			q.Set(key, v)
+		default:
+			q.Set(key, fmt.Sprint(v))
+		}
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// printBody writes a response body: JSON indented, or the values q selects
+// from it, strings unquoted; anything else as is.
+func printBody(w io.Writer, body []byte, q *query) error {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	if !json.Valid(body) {
+		_, err := fmt.Fprintln(w, utils.Redact(strings.TrimRight(string(body), "\n")))
+		return err
+	}
+	if q == nil {
+		var out bytes.Buffer
+		if err := json.Indent(&out, body, "", "  "); err != nil {
+			return err
+		}
+		_, err := fmt.Fprintln(w, utils.Redact(out.String()))
+		return err
+	}
+
+	var doc interface{}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return utils.NewError("failed to parse response", err)
+	}
+	values, err := q.eval(doc)
+	if err != nil {
+		return err
+	}
+	for _, v := range values {
+		line, ok := v.(string)
+		if !ok {
+			b, err := json.MarshalIndent(v, "", "  ")
+			if err != nil {
+				return err
+			}
+			line = string(b)
+		}
+		if _, err := fmt.Fprintln(w, utils.Redact(line)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// apiError returns the error for a failed request: the API's message, or
+// the HTTP status text, with its status, code and request ID.
+func apiError(resp *apipkg.RawResponse) error {
+	message := http.StatusText(resp.StatusCode)
+	var apiErr apipkg.APIError
+	if err := json.Unmarshal(resp.Body, &apiErr); err == nil && apiErr.Message != "" {
+		message = apiErr.Message
+	}
+	return utils.NewAPIError(resp.StatusCode, apiErr.Code, resp.RequestID(), fmt.Sprintf("%s (HTTP %d)", message, resp.StatusCode))
+}
@@ -0,0 +1,184 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	satuskyctx "1ctl/internal/context"
+)
+
+// useTestAPI points the active profile at handler and logs it in.
+func useTestAPI(t *testing.T, handler http.HandlerFunc) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	t.Setenv("SATUSKY_API_URL", server.URL+"/v1/cli")
+
+	original := satuskyctx.Default()
+	t.Cleanup(func() { satuskyctx.SetDefault(original) })
+	store := satuskyctx.NewTestStore(t.TempDir())
+	store.SetProfileOverride("test")
+	satuskyctx.SetDefault(store)
+	if err := satuskyctx.SetToken("test-token"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAPICommandSendsFieldsWithProfileCredentials(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		got    []string
+		bodies []string
+	)
+	useTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body) //nolint:errcheck
+		mu.Lock()
+		got = append(got, r.Method+" "+r.URL.RequestURI()+" "+r.Header.Get("x-satusky-api-key"))
+		bodies = append(bodies, string(body))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"error":false,"data":{"status":"queued"}}`)) //nolint:errcheck
+	})
+
+	// Flags after the path, and a value with a comma.
+	args := []string{"api", "POST", "/machines/m-1/command", "-f", "type=CMD_REBOOT,now", "-F", "force=true", "--main-api"}
+	if err := Command().Run(context.Background(), args); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if want := []string{"POST /v1/machines/m-1/command test-token"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("requests = %v, want %v", got, want)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal([]byte(bodies[0]), &body); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]interface{}{"type": "CMD_REBOOT,now", "force": true}; !reflect.DeepEqual(body, want) {
+		t.Errorf("body = %v, want %v", body, want)
+	}
+}
+
+func TestAPICommandPaginates(t *testing.T) {
+	var (
+		mu  sync.Mutex
+		got []string
+	)
+	useTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		got = append(got, r.URL.RequestURI())
+		mu.Unlock()
+		page := map[string]string{
+			"":         `{"data":{"audit_logs":[{"id":"a"},{"id":"b"}],"total":3,"limit":2,"offset":0}}`,
+			"offset=2": `{"data":{"audit_logs":[{"id":"c"}],"total":3,"limit":2,"offset":2}}`,
+		}[strings.ReplaceAll(r.URL.RawQuery, "limit=2&", "")]
+		_, _ = w.Write([]byte(page)) //nolint:errcheck
+	})
+
+	if err := handleRequest(context.Background(), requestInput{Path: "/audit-logs/organizations/o-1", Paginate: true, JQ: ".data.audit_logs[].id"}); err != nil {
+		t.Fatalf("handleRequest() error = %v", err)
+	}
+	want := []string{"/v1/cli/audit-logs/organizations/o-1", "/v1/cli/audit-logs/organizations/o-1?limit=2&offset=2"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("requests = %v, want %v", got, want)
+	}
+}
+
+func TestAPICommandFailsOnErrorStatus(t *testing.T) {
+	useTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":true,"message":"deployment not found"}`)) //nolint:errcheck
+	})
+	err := handleRequest(context.Background(), requestInput{Path: "/deployments/id/nope"})
+	if err == nil || err.Error() != "deployment not found (HTTP 404)" {
+		t.Errorf("handleRequest() error = %v", err)
+	}
+}
+
+func TestBuildRequest(t *testing.T) {
+	input := filepath.Join(t.TempDir(), "body.json")
+	if err := os.WriteFile(input, []byte(`{"replicas":2}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		in         requestInput
+		wantMethod string
+		wantPath   string
+		wantBody   string
+		wantErr    string
+	}{
+		{name: "GET by default", in: requestInput{Path: "deployments/status/d-1"}, wantMethod: "GET", wantPath: "/deployments/status/d-1"},
+		{name: "GET fields go to the query", in: requestInput{Method: "GET", Path: "/audit-logs?x=1", RawFields: []string{"action=login"}}, wantMethod: "GET", wantPath: "/audit-logs?action=login&x=1"},
+		{name: "fields make a POST", in: requestInput{Path: "/m", Fields: []string{"n=3", "on=false", "note=null"}}, wantMethod: "POST", wantPath: "/m", wantBody: `{"n":3,"note":null,"on":false}`},
+		{name: "raw fields stay strings", in: requestInput{Method: "patch", Path: "/m", RawFields: []string{"n=3"}}, wantMethod: "PATCH", wantPath: "/m", wantBody: `{"n":"3"}`},
+		{name: "input is the body", in: requestInput{Method: "PUT", Path: "/d", Input: input, RawFields: []string{"dry_run=true"}}, wantMethod: "PUT", wantPath: "/d?dry_run=true", wantBody: `{"replicas":2}`},
+		{name: "@- reads stdin", in: requestInput{Path: "/m", Fields: []string{"script=@-"}}, wantMethod: "POST", wantPath: "/m", wantBody: `{"script":"echo hi\n"}`},
+		{name: "absolute URL", in: requestInput{Path: "https://evil.example.com/x"}, wantErr: "must be relative"},
+		{name: "bad field", in: requestInput{Path: "/m", RawFields: []string{"novalue"}}, wantErr: "must be key=value"},
+		{name: "bad method", in: requestInput{Method: "BREW", Path: "/m"}, wantErr: "unsupported method"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method, path, body, err := buildRequest(tt.in, strings.NewReader("echo hi\n"))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("buildRequest() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if method != tt.wantMethod || path != tt.wantPath || string(body) != tt.wantBody {
+				t.Errorf("buildRequest() = %s %s %s, want %s %s %s", method, path, body, tt.wantMethod, tt.wantPath, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestQuery(t *testing.T) {
+	body := []byte(`{"data":[{"id":"d-1","replicas":2,"tags":{"b":"2","a":"1"}},{"id":"d-2","replicas":1}]}`)
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{".data[].id", "d-1\nd-2\n"},
+		{".data[-1].replicas", "1\n"},
+		{".data[0].tags[]", "1\n2\n"},
+		{".data[5].id", "null\n"},
+		{".data[1]", "{\n  \"id\": \"d-2\",\n  \"replicas\": 1\n}\n"},
+	}
+	for _, tt := range tests {
+		q, err := parseQuery(tt.expr)
+		if err != nil {
+			t.Fatalf("parseQuery(%q) error = %v", tt.expr, err)
+		}
+		var out bytes.Buffer
+		if err := printBody(&out, body, q); err != nil {
+			t.Fatalf("%s: %v", tt.expr, err)
+		}
+		if out.String() != tt.want {
+			t.Errorf("%s printed %q, want %q", tt.expr, out.String(), tt.want)
+		}
+	}
+
+	for _, bad := range []string{"data", ".data[x]", ".data["} {
+		if _, err := parseQuery(bad); err == nil {
+			t.Errorf("parseQuery(%q) succeeded", bad)
+		}
+	}
+	q, _ := parseQuery(".data.id") //nolint:errcheck
+	if err := printBody(io.Discard, body, q); err == nil || !strings.Contains(err.Error(), "cannot apply .id to an array") {
+		t.Errorf("field of an array error = %v", err)
+	}
+}
@@ -0,0 +1,159 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"1ctl/internal/utils"
+)
+
+// query is a jq-style path: "." followed by .field, [n] and [] steps,
+// e.g. .data[].app_label or .data.items[-1].name.
+type query struct {
+	expr  string
+	steps []step
+}
+
+type step struct {
+	field   string
+	index   int
+	kind    stepKind
+	display string
+}
+
+type stepKind int
+
+const (
+	stepField stepKind = iota
+	stepIndex
+	stepIterate
+)
+
+func parseQuery(expr string) (*query, error) {
+	q := &query{expr: expr}
+	rest := strings.TrimSpace(expr)
+	if !strings.HasPrefix(rest, ".") {
+		return nil, utils.NewError(fmt.Sprintf("--jq %q must start with '.'", expr), nil)
+	}
+	if rest == "." {
+		return q, nil
+	}
+	for rest != "" {
+		switch {
+		case strings.HasPrefix(rest, "["):
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, utils.NewError(fmt.Sprintf("--jq %q: missing ']'", expr), nil)
+			}
+			inner := rest[1:end]
+			if inner == "" {
+				q.steps = append(q.steps, step{kind: stepIterate, display: "[]"})
+			} else {
+				n, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, utils.NewError(fmt.Sprintf("--jq %q: %q is not an array index", expr, inner), nil)
+				}
+				q.steps = append(q.steps, step{kind: stepIndex, index: n, display: rest[:end+1]})
+			}
+			rest = rest[end+1:]
+		case strings.HasPrefix(rest, "."):
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			name := rest[:end]
+			if name == "" {
+				if strings.HasPrefix(rest, "[") {
+					continue // .[0] is the same as [0]
+				}
+				return nil, utils.NewError(fmt.Sprintf("--jq %q: empty field name", expr), nil)
+			}
+			q.steps = append(q.steps, step{kind: stepField, field: name, display: "." + name})
+			rest = rest[end:]
+		default:
+			return nil, utils.NewError(fmt.Sprintf("--jq %q: unexpected %q", expr, rest), nil)
+		}
+	}
+	return q, nil
+}
+
+// eval returns the values the query selects from doc. Like jq, a missing
+// field or index selects null.
+func (q *query) eval(doc interface{}) ([]interface{}, error) {
+	values := []interface{}{doc}
+	for _, s := range q.steps {
+		var next []interface{}
+		for _, v := range values {
+			switch s.kind {
+			case stepField:
+				switch t := v.(type) {
+				case map[string]interface{}:
+					next = append(next, t[s.field])
+				case nil:
+					next = append(next, nil)
+				default:
+					return nil, q.typeError(s, v)
+				}
+			case stepIndex:
+				switch t := v.(type) {
+				case []interface{}:
+					i := s.index
+					if i < 0 {
+						i += len(t)
+					}
+					if i < 0 || i >= len(t) {
+						next = append(next, nil)
+					} else {
+						next = append(next, t[i])
+					}
+				case nil:
+					next = append(next, nil)
+				default:
+					return nil, q.typeError(s, v)
+				}
+			case stepIterate:
+				switch t := v.(type) {
+				case []interface{}:
+					next = append(next, t...)
+				case map[string]interface{}:
+					for _, key := range sortedKeys(t) {
+						next = append(next, t[key])
+					}
+				default:
+					return nil, q.typeError(s, v)
+				}
+			}
+		}
+		values = next
+	}
+	return values, nil
+}
+
+func (q *query) typeError(s step, v interface{}) error {
+	var kind string
+	switch v.(type) {
+	case []interface{}:
+		kind = "an array"
+	case map[string]interface{}:
+		kind = "an object"
+	case string:
+		kind = "a string"
+	case bool:
+		kind = "a boolean"
+	default:
+		kind = "a number"
+	}
+	return utils.NewError(fmt.Sprintf("--jq %q: cannot apply %s to %s", q.expr, s.display, kind), nil)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
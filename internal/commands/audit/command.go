@@ -10,9 +10,11 @@ import (
 // --- Flag name constants ------------------------------------------------
 
 const (
-	flagLimit  = "limit"
-	flagAction = "action"
-	flagUser   = "user"
+	flagLimit    = "limit"
+	flagAll      = "all"
+	flagPageSize = "page-size"
+	flagAction   = "action"
+	flagUser     = "user"
 )
 
 // --- Flag constructors --------------------------------------------------
@@ -30,9 +32,11 @@ func optionalString(name, usage string, dest *string, validate func(string) erro
 // --- Input structs ------------------------------------------------------
 
 type auditListInput struct {
-	Limit  int
-	Action string
-	User   string
+	Limit    int
+	All      bool
+	PageSize int
+	Action   string
+	User     string
 }
 
 type auditGetInput struct {
@@ -61,14 +65,27 @@ func auditListCommand() *cli.Command {
 		Flags: []cli.Flag{
 			&cli.IntFlag{
 				Name:        flagLimit,
-				Usage:       "Number of logs to show",
+				Usage:       "Number of logs to show, fetching more pages as needed",
 				Destination: &in.Limit,
 				Value:       20,
 			},
+			&cli.BoolFlag{
+				Name:        flagAll,
+				Usage:       "Show every log, fetching all pages (up to --limit if also given)",
+				Destination: &in.All,
+			},
+			&cli.IntFlag{
+				Name:        flagPageSize,
+				Usage:       "Number of logs to fetch per request (default: --limit, or the API's page size)",
+				Destination: &in.PageSize,
+			},
 			optionalString(flagAction, "Filter by action type", &in.Action, nil),
 			optionalString(flagUser, "Filter by user ID", &in.User, nil),
 		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
+			if in.All && !cmd.IsSet(flagLimit) {
+				in.Limit = 0
+			}
 			return handleAuditList(ctx, in)
 		},
 	}
@@ -15,16 +15,19 @@ func handleAuditList(ctx context.Context, in auditListInput) error {
 		return utils.NewError("organization ID not found. Please run '1ctl auth login' first", nil)
 	}
 
-	logs, err := api.GetAuditLogs(orgID, in.Limit, in.Action, in.User)
-	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to get audit logs: %s", err.Error()), nil)
+	stream := utils.NewListStream("No audit logs found", printAuditLogs)
+	opts := api.PageOptions{All: in.All, Limit: in.Limit, PageSize: in.PageSize}
+	if err := api.ListAuditLogs(orgID, in.Action, in.User, opts, stream.Add); err != nil {
+		return utils.NewError("failed to get audit logs", err)
 	}
+	stream.Close()
+	return nil
+}
 
-	if utils.PrintListOrJSON(logs, "No audit logs found") {
-		return nil
+func printAuditLogs(logs []api.AuditLog, first bool) {
+	if first {
+		utils.PrintHeader("Audit Logs")
 	}
-
-	utils.PrintHeader("Audit Logs")
 	for _, log := range logs {
 		utils.PrintStatusLine("ID", log.ID.String())
 		utils.PrintStatusLine("Action", log.Action)
@@ -39,7 +42,6 @@ func handleAuditList(ctx context.Context, in auditListInput) error {
 		utils.PrintStatusLine("Time", utils.FormatTimeAgo(log.CreatedAt))
 		utils.PrintDivider()
 	}
-	return nil
 }
 
 func handleAuditGet(ctx context.Context, in auditGetInput) error {
@@ -50,7 +52,7 @@ func handleAuditGet(ctx context.Context, in auditGetInput) error {
 
 	log, err := api.GetAuditLog(orgID, in.ID)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to get audit log: %s", err.Error()), nil)
+		return utils.NewError("failed to get audit log", err)
 	}
 
 	if utils.TryPrintJSON(log) {
@@ -4,17 +4,35 @@ package auth
 import (
 	"context"
 
+	"1ctl/internal/oidc"
+
 	"github.com/urfave/cli/v3"
 )
 
 // --- Flag name constants ------------------------------------------------
 
-const flagToken = "token"
+const (
+	flagToken            = "token"
+	flagCredentialStore  = "credential-store"
+	flagCredentialHelper = "credential-helper"
+	flagWeb              = "web"
+	flagNoBrowser        = "no-browser"
+	flagOIDC             = "oidc"
+	flagIDTokenFile      = "id-token-file"
+	flagAudience         = "audience"
+)
 
 // --- Input structs ------------------------------------------------------
 
 type authLoginInput struct {
-	Token string
+	Token            string
+	CredentialStore  string
+	CredentialHelper string
+	Web              bool
+	NoBrowser        bool
+	OIDC             bool
+	IDTokenFile      string
+	Audience         string
 }
 
 // --- Command tree -------------------------------------------------------
@@ -38,8 +56,32 @@ func authLoginCommand() *cli.Command {
 		Name:  "login",
 		Usage: "Authenticate with Satusky",
 		Description: `Authenticate using one of these methods:
-   1. CLI flag:     1ctl auth login --token=<your-token>
-   2. Environment:  export SATUSKY_API_KEY=<your-token> && 1ctl auth login`,
+   1. Browser:      1ctl auth login --web
+   2. CLI flag:     1ctl auth login --token=<your-token>
+   3. Environment:  export SATUSKY_API_KEY=<your-token> && 1ctl auth login
+
+--web opens the browser to approve a one-time code and stores a short-lived
+token with a refresh token; 1ctl refreshes the token before it expires.
+--no-browser prints the URL instead, for use over SSH.
+
+--oidc is for CI: it exchanges the job's identity token (GitHub Actions,
+GitLab CI, or --id-token-file) for a short-lived token without a stored
+API key. On GitHub Actions the token is kept in the runner's temporary
+directory, which is emptied after the job; elsewhere it is only kept in
+memory. It is never saved in the profile. The repository must be trusted first with
+'1ctl token oidc-trust add --repo <org/repo>'.
+
+The token is saved in the OS keyring when one is available, otherwise in a
+file encrypted with a passphrase (prompted for, or SATUSKY_CREDENTIAL_PASSPHRASE).
+Use --credential-store to choose: keyring, file, or plaintext to keep it
+unencrypted in the profile file. A token saved unencrypted by an older 1ctl
+is moved into the keyring, or into the file when SATUSKY_CREDENTIAL_PASSPHRASE
+is set, the first time it is read; otherwise logging in again moves it.
+
+--credential-helper hands the token to an external program (for example a
+1Password or Vault integration) that speaks the get/store/erase protocol,
+and records it in the profile. Without --token the helper is asked for the
+token. SATUSKY_CREDENTIAL_HELPER applies a helper to every command.`,
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:        flagToken,
@@ -47,6 +89,44 @@ func authLoginCommand() *cli.Command {
 				Sources:     cli.EnvVars("SATUSKY_API_KEY"),
 				Destination: &in.Token,
 			},
+			&cli.BoolFlag{
+				Name:        flagWeb,
+				Usage:       "Log in through the browser instead of with an API token",
+				Destination: &in.Web,
+			},
+			&cli.BoolFlag{
+				Name:        flagNoBrowser,
+				Usage:       "With --web, print the login URL instead of opening a browser",
+				Destination: &in.NoBrowser,
+			},
+			&cli.BoolFlag{
+				Name:        flagOIDC,
+				Usage:       "Log in from CI with the job's OIDC identity token",
+				Destination: &in.OIDC,
+			},
+			&cli.StringFlag{
+				Name:        flagIDTokenFile,
+				Usage:       "With --oidc, read the identity token from this file instead of detecting the CI provider",
+				Destination: &in.IDTokenFile,
+			},
+			&cli.StringFlag{
+				Name:        flagAudience,
+				Usage:       "With --oidc, the audience to request the identity token for",
+				Value:       oidc.DefaultAudience,
+				Destination: &in.Audience,
+			},
+			&cli.StringFlag{
+				Name:        flagCredentialStore,
+				Usage:       "Where to store the token: keyring, file, ephemeral or plaintext (default: keyring if available, else file)",
+				Sources:     cli.EnvVars("SATUSKY_CREDENTIAL_STORE"),
+				Destination: &in.CredentialStore,
+			},
+			&cli.StringFlag{
+				Name:        flagCredentialHelper,
+				Usage:       "Credential helper program that supplies and stores the token",
+				Sources:     cli.EnvVars("SATUSKY_CREDENTIAL_HELPER"),
+				Destination: &in.CredentialHelper,
+			},
 		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
 			return handleLogin(ctx, in)
@@ -6,25 +6,55 @@ import (
 	"time"
 
 	"1ctl/internal/api"
+	"1ctl/internal/config"
 	satuskyctx "1ctl/internal/context"
+	"1ctl/internal/oidc"
 	"1ctl/internal/utils"
 )
 
 func handleLogin(ctx context.Context, in authLoginInput) error {
+	if in.Web && in.OIDC {
+		return utils.NewError("--web and --oidc cannot be used together", nil)
+	}
+	if in.CredentialHelper != "" {
+		if in.CredentialStore != "" || in.OIDC {
+			return utils.NewError("--credential-helper cannot be combined with --credential-store or --oidc", nil)
+		}
+		satuskyctx.SetCredentialHelper(in.CredentialHelper)
+	}
+	if in.Web {
+		return handleWebLogin(ctx, in)
+	}
+	if in.OIDC {
+		return handleOIDCLogin(ctx, in)
+	}
+	if in.NoBrowser {
+		return utils.NewError("--no-browser requires --web", nil)
+	}
+	if in.IDTokenFile != "" {
+		return utils.NewError("--id-token-file requires --oidc", nil)
+	}
+
 	// Try to get token from flag first, then environment variable
 	token := in.Token
 	if token == "" {
 		// check in context.json
 		token = satuskyctx.GetToken()
 		if token == "" {
-			return utils.NewError("token is required. Use --token flag or set SATUSKY_API_KEY environment variable", nil)
+			return utils.NewError("token is required. Use --token flag, set SATUSKY_API_KEY environment variable, or log in with --web", nil)
+		}
+	}
+
+	if in.CredentialStore != "" {
+		if err := satuskyctx.SetCredentialStore(in.CredentialStore); err != nil {
+			return err
 		}
 	}
 
 	// Validate token with API first, before writing anything to disk
 	result, err := api.LoginCLI(token)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to login: %s", err.Error()), nil)
+		return utils.NewError("failed to login", err)
 	}
 
 	// Fail-fast: a token unassociated with an organization is unusable.
@@ -33,16 +63,120 @@ func handleLogin(ctx context.Context, in authLoginInput) error {
 	}
 
 	if err := satuskyctx.SaveLoginState(token, result.UserID, result.UserEmail, result.OrganizationID, result.OrganizationName, result.Namespace); err != nil {
-		return utils.NewError(fmt.Sprintf("failed to store login state: %s", err.Error()), nil)
+		return utils.NewError("failed to store login state", err)
+	}
+
+	printLoggedIn(result.UserEmail)
+	return nil
+}
+
+// handleWebLogin runs the device code flow: show a code, let the user
+// approve it in the browser, then poll for the token.
+func handleWebLogin(ctx context.Context, in authLoginInput) error {
+	if in.Token != "" {
+		return utils.NewError("--web and --token cannot be used together", nil)
+	}
+	if in.CredentialStore != "" {
+		if err := satuskyctx.SetCredentialStore(in.CredentialStore); err != nil {
+			return err
+		}
+	}
+
+	da, err := api.StartDeviceAuthorization()
+	if err != nil {
+		return utils.NewError("failed to start browser login", err)
+	}
+
+	openURL := da.VerificationURIComplete
+	if openURL == "" {
+		openURL = da.VerificationURI
+	}
+	utils.PrintInfo("Your one-time code is: %s", da.UserCode)
+	if in.NoBrowser {
+		utils.PrintInfo("Open %s in a browser and enter the code to log in", da.VerificationURI)
+	} else if err := utils.OpenBrowser(openURL); err != nil {
+		utils.PrintWarning("Could not open a browser: %s", err.Error())
+		utils.PrintInfo("Open %s in a browser and enter the code to log in", da.VerificationURI)
+	} else {
+		utils.PrintInfo("Opened %s in your browser. Confirm the code there to log in", da.VerificationURI)
+	}
+	utils.PrintInfo("Waiting for approval...")
+
+	tok, err := api.PollDeviceToken(ctx, da)
+	if err != nil {
+		return err
+	}
+
+	result, err := api.LoginCLI(tok.AccessToken)
+	if err != nil {
+		return utils.NewError("failed to login", err)
+	}
+	if result.OrganizationID == "" || result.Namespace == "" {
+		return utils.NewError("your account is not a member of any organization — create or join one at https://cloud.satusky.com first", nil)
+	}
+
+	if err := satuskyctx.SaveOAuthLoginState(tok.AccessToken, tok.RefreshToken, result.UserID, result.UserEmail, result.OrganizationID, result.OrganizationName, result.Namespace); err != nil {
+		return utils.NewError("failed to store login state", err)
 	}
 
-	utils.PrintSuccess("Logged in successfully to SatuSky 1ctl as %s!\n", result.UserEmail)
+	printLoggedIn(result.UserEmail)
 	return nil
 }
 
+// handleOIDCLogin exchanges the CI job's identity token for a short-lived
+// CLI token, kept in the ephemeral credential store.
+func handleOIDCLogin(ctx context.Context, in authLoginInput) error {
+	if in.Token != "" {
+		return utils.NewError("--oidc and --token cannot be used together", nil)
+	}
+	if in.CredentialStore != "" && in.CredentialStore != satuskyctx.CredentialStoreEphemeral {
+		return utils.NewError("--oidc tokens are short-lived and only kept in the ephemeral credential store", nil)
+	}
+	if err := satuskyctx.SetCredentialStore(satuskyctx.CredentialStoreEphemeral); err != nil {
+		return err
+	}
+
+	idToken, provider, err := oidc.Token(in.IDTokenFile, in.Audience)
+	if err != nil {
+		return utils.NewError(err.Error(), nil)
+	}
+	tok, err := api.ExchangeIDToken(idToken)
+	if err != nil {
+		return utils.NewError(fmt.Sprintf("failed to exchange %s identity token: %s. Check that the repository is trusted with '1ctl token oidc-trust list'", provider, err.Error()), nil)
+	}
+
+	result, err := api.LoginCLI(tok.AccessToken)
+	if err != nil {
+		return utils.NewError("failed to login", err)
+	}
+	if result.OrganizationID == "" || result.Namespace == "" {
+		return utils.NewError("the exchanged token is not associated with an organization", nil)
+	}
+
+	if err := satuskyctx.SaveLoginState(tok.AccessToken, result.UserID, result.UserEmail, result.OrganizationID, result.OrganizationName, result.Namespace); err != nil {
+		return utils.NewError("failed to store login state", err)
+	}
+
+	printLoggedIn(result.UserEmail)
+	utils.PrintStatusLine("Identity from", provider)
+	return nil
+}
+
+func printLoggedIn(email string) {
+	utils.PrintSuccess("Logged in successfully to SatuSky 1ctl as %s!\n", email)
+	utils.PrintStatusLine("Token stored in", satuskyctx.GetCredentialStore())
+	if satuskyctx.GetCredentialStore() == satuskyctx.CredentialStorePlaintext {
+		utils.PrintWarning("The token is saved unencrypted in the profile file")
+	}
+}
+
 func handleLogout(ctx context.Context) error {
 	if err := satuskyctx.ClearAuthState(); err != nil {
-		return utils.NewError(fmt.Sprintf("failed to clear auth state: %s", err.Error()), nil)
+		return utils.NewError("failed to clear auth state", err)
+	}
+	// Cached organizations and machines belong to the account logged out of
+	if _, err := api.ClearCache(false); err != nil {
+		utils.PrintWarning("%s", err.Error())
 	}
 
 	utils.PrintSuccess("Successfully logged out")
@@ -52,13 +186,13 @@ func handleLogout(ctx context.Context) error {
 func handleAuthStatus(ctx context.Context) error {
 	token := satuskyctx.GetToken()
 	if token == "" {
-		return utils.NewError("not authenticated. Please run '1ctl auth login' to authenticate", nil)
+		return utils.NewKindError(utils.ErrAuth, "not authenticated. Please run '1ctl auth login' to authenticate", nil)
 	}
 
 	// Validate token with API
 	result, err := api.LoginCLI(token)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to check token status: %s", err.Error()), nil)
+		return utils.NewError("failed to check token status", err)
 	}
 
 	if !result.IsActive {
@@ -77,5 +211,9 @@ func handleAuthStatus(ctx context.Context) error {
 	utils.PrintStatusLine("Organization ID", result.OrganizationID)
 	utils.PrintStatusLine("Namespace", result.Namespace)
 	utils.PrintStatusLine("Token expires", fmt.Sprintf("in %.0f days", daysUntilExpiry))
+	if satuskyctx.GetCredentialStore() == "" {
+		utils.PrintWarning("The token is saved unencrypted in the profile file by an older 1ctl — run '1ctl auth login' to move it to the credential store")
+	}
+	config.PrintContext(config.DescribeContext())
 	return nil
 }
@@ -0,0 +1,56 @@
+// Package cache defines the "1ctl cache" command tree.
+package cache
+
+import (
+	"context"
+
+	"github.com/urfave/cli/v3"
+)
+
+// --- Flag name constants ------------------------------------------------
+
+const (
+	flagAllProfiles = "all-profiles"
+)
+
+// --- Input structs ------------------------------------------------------
+
+type cacheClearInput struct {
+	AllProfiles bool
+}
+
+// --- Command tree -------------------------------------------------------
+
+// Command returns the root cache command tree.
+func Command() *cli.Command {
+	return &cli.Command{
+		Name:  "cache",
+		Usage: "Manage the local cache of API lookups",
+		Description: `Zones, clusters, pricing, organizations and machine labels change rarely,
+so 1ctl keeps them under the profile's cache directory, revalidates them
+with the API after a few minutes and uses them when the API is
+unreachable. Pass --no-cache (or SATUSKY_NO_CACHE=1) to skip the cache for
+one command.`,
+		Commands: []*cli.Command{
+			cacheClearCommand(),
+		},
+	}
+}
+
+func cacheClearCommand() *cli.Command {
+	var in cacheClearInput
+	return &cli.Command{
+		Name:  "clear",
+		Usage: "Delete the active profile's cached API responses",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:        flagAllProfiles,
+				Usage:       "Delete the cached responses of every profile",
+				Destination: &in.AllProfiles,
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return handleCacheClear(ctx, in)
+		},
+	}
+}
@@ -0,0 +1,17 @@
+package cache
+
+import (
+	"context"
+
+	"1ctl/internal/api"
+	"1ctl/internal/utils"
+)
+
+func handleCacheClear(ctx context.Context, in cacheClearInput) error {
+	dir, err := api.ClearCache(in.AllProfiles)
+	if err != nil {
+		return err
+	}
+	utils.PrintSuccess("Cleared cached API responses in %s", dir)
+	return nil
+}
@@ -15,7 +15,7 @@ import (
 func handleListZones(ctx context.Context) error {
 	zones, err := api.GetAvailableZones()
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to list zones: %s", err.Error()), nil)
+		return utils.NewError("failed to list zones", err)
 	}
 
 	if utils.PrintListOrJSON(zones, "No zones available") {
@@ -24,18 +24,18 @@ func handleListZones(ctx context.Context) error {
 
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
 	if _, err := fmt.Fprintln(w, "ZONE\tLABEL\tCLUSTER"); err != nil {
-		return utils.NewError(fmt.Sprintf("failed to write zone table header: %s", err.Error()), nil)
+		return utils.NewError("failed to write zone table header", err)
 	}
 	if _, err := fmt.Fprintln(w, "----\t-----\t-------"); err != nil {
-		return utils.NewError(fmt.Sprintf("failed to write zone table header: %s", err.Error()), nil)
+		return utils.NewError("failed to write zone table header", err)
 	}
 	for _, z := range zones {
 		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\n", z.Value, z.Label, z.ClusterID); err != nil {
-			return utils.NewError(fmt.Sprintf("failed to write zone row: %s", err.Error()), nil)
+			return utils.NewError("failed to write zone row", err)
 		}
 	}
 	if err := w.Flush(); err != nil {
-		return utils.NewError(fmt.Sprintf("failed to flush zone table: %s", err.Error()), nil)
+		return utils.NewError("failed to flush zone table", err)
 	}
 
 	return nil
@@ -44,7 +44,7 @@ func handleListZones(ctx context.Context) error {
 func handleListClusters(ctx context.Context) error {
 	clusters, err := api.GetClusters()
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to list clusters: %s", err.Error()), nil)
+		return utils.NewError("failed to list clusters", err)
 	}
 
 	if utils.PrintListOrJSON(clusters, "No clusters available") {
@@ -53,10 +53,10 @@ func handleListClusters(ctx context.Context) error {
 
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
 	if _, err := fmt.Fprintln(w, "ID\tNAME\tZONE\tENDPOINT\tHEALTHY\tDEFAULT\tPRIORITY"); err != nil {
-		return utils.NewError(fmt.Sprintf("failed to write cluster table header: %s", err.Error()), nil)
+		return utils.NewError("failed to write cluster table header", err)
 	}
 	if _, err := fmt.Fprintln(w, "--\t----\t----\t--------\t-------\t-------\t--------"); err != nil {
-		return utils.NewError(fmt.Sprintf("failed to write cluster table header: %s", err.Error()), nil)
+		return utils.NewError("failed to write cluster table header", err)
 	}
 	for _, c := range clusters {
 		healthStr := "✓"
@@ -69,11 +69,11 @@ func handleListClusters(ctx context.Context) error {
 		}
 		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%d\n",
 			c.ID, c.Name, c.Zone, c.Endpoint, healthStr, defaultStr, c.Priority); err != nil {
-			return utils.NewError(fmt.Sprintf("failed to write cluster row: %s", err.Error()), nil)
+			return utils.NewError("failed to write cluster row", err)
 		}
 	}
 	if err := w.Flush(); err != nil {
-		return utils.NewError(fmt.Sprintf("failed to flush cluster table: %s", err.Error()), nil)
+		return utils.NewError("failed to flush cluster table", err)
 	}
 
 	return nil
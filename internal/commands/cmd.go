@@ -8,7 +8,9 @@
 package commands
 
 import (
+	apicmd "1ctl/internal/commands/api"
 	"1ctl/internal/commands/audit"
+	"1ctl/internal/commands/cache"
 	"1ctl/internal/commands/auth"
 	"1ctl/internal/commands/cluster"
 	"1ctl/internal/commands/completion"
@@ -29,10 +31,13 @@ import (
 	"1ctl/internal/commands/org"
 	"1ctl/internal/commands/pricing"
 	"1ctl/internal/commands/profile"
+	"1ctl/internal/commands/run"
 	"1ctl/internal/commands/secret"
 	"1ctl/internal/commands/service"
 	"1ctl/internal/commands/token"
+	"1ctl/internal/commands/update"
 	"1ctl/internal/commands/user"
+	versioncmd "1ctl/internal/commands/version"
 	"1ctl/internal/commands/volumes"
 
 	"github.com/urfave/cli/v3"
@@ -50,6 +55,9 @@ func InitCommand() *cli.Command { return initcmd.Command() }
 // LaunchCommand returns the "1ctl launch" command tree.
 func LaunchCommand() *cli.Command { return launch.Command() }
 
+// RunCommand returns the "1ctl run" command.
+func RunCommand() *cli.Command { return run.Command() }
+
 // CompletionCommand returns the "1ctl completion" command tree.
 func CompletionCommand() *cli.Command { return completion.Command() }
 
@@ -74,6 +82,18 @@ func TokenCommand() *cli.Command { return token.Command() }
 // MarketplaceCommand returns the "1ctl marketplace" command tree.
 func MarketplaceCommand() *cli.Command { return marketplace.Command() }
 
+// APICommand returns the "1ctl api" command.
+func APICommand() *cli.Command { return apicmd.Command() }
+
+// CacheCommand returns the "1ctl cache" command tree.
+func CacheCommand() *cli.Command { return cache.Command() }
+
+// UpdateCommand returns the "1ctl update" command.
+func UpdateCommand() *cli.Command { return update.Command() }
+
+// VersionCommand returns the "1ctl version" command.
+func VersionCommand() *cli.Command { return versioncmd.Command() }
+
 // AuditCommand returns the "1ctl audit" command tree.
 func AuditCommand() *cli.Command { return audit.Command() }
 
@@ -184,7 +184,7 @@ func handleCompletionInstall(ctx context.Context, rootWriter io.Writer) (err err
 	shell := os.Getenv("SHELL")
 	home, err := os.UserHomeDir()
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to resolve home directory: %s", err.Error()), nil)
+		return utils.NewError("failed to resolve home directory", err)
 	}
 
 	type shellInfo struct {
@@ -11,16 +11,20 @@ import (
 // --- Flag name constants ------------------------------------------------
 
 const (
-	flagLimit  = "limit"
-	flagOffset = "offset"
-	flagDays   = "days"
+	flagLimit    = "limit"
+	flagOffset   = "offset"
+	flagAll      = "all"
+	flagPageSize = "page-size"
+	flagDays     = "days"
 )
 
 // --- Input structs ------------------------------------------------------
 
 type creditsTransactionsInput struct {
-	Limit  int
-	Offset int
+	Limit    int
+	Offset   int
+	All      bool
+	PageSize int
 }
 
 type creditsUsageInput struct {
@@ -70,10 +74,19 @@ func creditsTransactionsCommand() *cli.Command {
 		Name:  "transactions",
 		Usage: "Show transaction history",
 		Flags: []cli.Flag{
-			optionalInt(flagLimit, "Number of transactions to show", &in.Limit, 10),
-			optionalInt(flagOffset, "Offset for pagination", &in.Offset, 0),
+			optionalInt(flagLimit, "Number of transactions to show, fetching more pages as needed", &in.Limit, 10),
+			optionalInt(flagOffset, "Number of transactions to skip", &in.Offset, 0),
+			&cli.BoolFlag{
+				Name:        flagAll,
+				Usage:       "Show every transaction, fetching all pages (up to --limit if also given)",
+				Destination: &in.All,
+			},
+			optionalInt(flagPageSize, "Number of transactions to fetch per request (default: --limit, or the API's page size)", &in.PageSize, 0),
 		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
+			if in.All && !cmd.IsSet(flagLimit) {
+				in.Limit = 0
+			}
 			return handleCreditsTransactions(ctx, in)
 		},
 	}
@@ -19,7 +19,7 @@ func handleCreditsBalance(ctx context.Context) error {
 
 	balance, err := api.GetCreditBalance(orgID)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to get credit balance: %s", err.Error()), nil)
+		return utils.NewError("failed to get credit balance", err)
 	}
 
 	if utils.TryPrintJSON(balance) {
@@ -73,16 +73,19 @@ func handleCreditsTransactions(ctx context.Context, in creditsTransactionsInput)
 		return utils.NewError("organization ID not found. Please run '1ctl auth login' first", nil)
 	}
 
-	transactions, err := api.GetCreditTransactions(orgID, in.Limit, in.Offset)
-	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to get transactions: %s", err.Error()), nil)
+	stream := utils.NewListStream("No transactions found", printTransactions)
+	opts := api.PageOptions{All: in.All, Limit: in.Limit, PageSize: in.PageSize}
+	if err := api.ListCreditTransactions(orgID, in.Offset, opts, stream.Add); err != nil {
+		return utils.NewError("failed to get transactions", err)
 	}
+	stream.Close()
+	return nil
+}
 
-	if utils.PrintListOrJSON(transactions, "No transactions found") {
-		return nil
+func printTransactions(transactions []api.CreditTransaction, first bool) {
+	if first {
+		utils.PrintHeader("Transaction History")
 	}
-
-	utils.PrintHeader("Transaction History")
 	for _, tx := range transactions {
 		amountStr := fmt.Sprintf("$%.2f", tx.Amount)
 		if tx.Amount > 0 {
@@ -95,7 +98,6 @@ func handleCreditsTransactions(ctx context.Context, in creditsTransactionsInput)
 		utils.PrintStatusLine("Date", utils.FormatTimeAgo(tx.CreatedAt))
 		utils.PrintDivider()
 	}
-	return nil
 }
 
 func handleCreditsUsage(ctx context.Context, in creditsUsageInput) error {
@@ -106,7 +108,7 @@ func handleCreditsUsage(ctx context.Context, in creditsUsageInput) error {
 
 	usages, err := api.GetMachineUsageHistory(orgID, in.Days)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to get machine usage: %s", err.Error()), nil)
+		return utils.NewError("failed to get machine usage", err)
 	}
 
 	if utils.PrintListOrJSON(usages, "No machine usage found for the last 7 days") {
@@ -27,19 +27,23 @@ Examples:
    1ctl app status my-app
    1ctl app delete my-app
    1ctl app restart my-app
+   1ctl app apply-config my-app
    1ctl app releases my-app
    1ctl app open my-app
-   1ctl app scale my-app 3`,
+   1ctl app scale my-app 3
+   1ctl app drift --all`,
 		Commands: []*cli.Command{
 			appListCommand(),
 			appGetCommand(),
 			appStatusCommand(),
 			appDestroyCommand(),
 			appRestartCommand(),
+			appApplyConfigCommand(),
 			appReleasesCommand(),
 			appRollbackCommand(),
 			appOpenCommand(),
 			appScaleCommand(),
+			appDriftCommand(),
 		},
 	}
 }
@@ -156,6 +160,37 @@ func appRestartCommand() *cli.Command {
 	}
 }
 
+func appApplyConfigCommand() *cli.Command {
+	var in DeployRefInput
+	return &cli.Command{
+		Name:      "apply-config",
+		Usage:     "Restart once to apply staged environment and secret changes",
+		ArgsUsage: "<app-name>",
+		Description: `Apply environment and secret changes made with --no-restart in a single
+rolling restart.
+
+Examples:
+   1ctl secret create DB_PASSWORD=s3cret --app api --no-restart
+   1ctl env create --env LOG_LEVEL=debug --app api --no-restart
+   1ctl app apply-config api`,
+		Flags: []cli.Flag{
+			optionalString(flagDeploymentID, "Deployment ID (alternative to positional arg)", &in.DeploymentID),
+			optionalString(flagConfig, "Config name or path", &in.Config),
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			if cmd.Args().Len() >= 1 {
+				arg := cmd.Args().First()
+				if looksLikeUUID(arg) {
+					in.DeploymentID = arg
+				} else {
+					in.App = arg
+				}
+			}
+			return handleApplyConfig(ctx, in)
+		},
+	}
+}
+
 func appReleasesCommand() *cli.Command {
 	var in DeployRefInput
 	return &cli.Command{
@@ -257,3 +292,27 @@ func appScaleCommand() *cli.Command {
 		},
 	}
 }
+
+func appDriftCommand() *cli.Command {
+	var in DriftInput
+	return &cli.Command{
+		Name:  "drift",
+		Usage: "Report live configuration that differs from satusky.toml",
+		Description: `Compare satusky.toml against the live deployment, ingress, environment and
+volumes, and report fields changed outside the repo (e.g. by "1ctl app scale"
+or "1ctl env create"). Exits non-zero when any app has drifted, so it can run
+as a scheduled CI job.
+
+Examples:
+   1ctl app drift
+   1ctl app drift --config staging
+   1ctl app drift --all -o json`,
+		Flags: []cli.Flag{
+			optionalString(flagConfig, "Config name or path (default: nearest satusky.toml)", &in.Config),
+			optionalBool(flagAll, "Check every satusky.toml in the repository", &in.All),
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return handleDrift(ctx, in)
+		},
+	}
+}
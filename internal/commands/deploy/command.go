@@ -64,6 +64,9 @@ const (
 	flagYes                 = "yes"
 	flagVersion             = "version"
 	flagWatch               = "watch"
+	flagAll                 = "all"
+	flagChangedSince        = "changed-since"
+	flagConcurrency         = "concurrency"
 
 )
 
@@ -117,6 +120,8 @@ type DeployInput struct {
 	RollingMaxSurge      string
 	RollingMaxUnavail    string
 	Config               string
+	ChangedSince         string
+	Concurrency          int
 }
 
 // GetDeploymentInput holds flags for the "get" subcommand.
@@ -245,6 +250,12 @@ Examples:
    1ctl deploy --name api --port 8080 --memory 512Mi
    1ctl deploy --image ghcr.io/acme/api:v1 --port 8080
    1ctl deploy --machine-tag production --port 8080
+   1ctl deploy --changed-since origin/main
+
+With --changed-since, every satusky.toml below the repository root is a
+service whose build context is its directory. Only services with files
+changed since the given git ref (and not excluded by .dockerignore) are
+deployed, up to --concurrency at a time.
 
 To manage a deployed application, use "1ctl app".`,
 		Flags: deployFlags(&in),
@@ -269,6 +280,9 @@ func deployFlags(in *DeployInput) []cli.Flag {
 		optionalString(flagDomain, "Custom domain (default: *.satusky.com)", &in.Domain),
 		optionalStringSlice(flagEnv, "Environment variables (format: KEY=VALUE)", &in.Env),
 		optionalString(flagConfig, "Config name or path (e.g. staging, satusky.staging.toml)", &in.Config),
+		// ── Monorepo ──
+		optionalString(flagChangedSince, "Deploy every service in the repository with changes since this git ref", &in.ChangedSince),
+		optionalIntVal(flagConcurrency, "Maximum services deployed at once with --changed-since", 4, &in.Concurrency),
 		// ── Resources ──
 		optionalStringVal(flagCPURequest, "Guaranteed CPU reservation per replica (e.g. '250m')", "250m", &in.CPURequest),
 		optionalStringVal(flagCPULimit, "Maximum burst CPU per replica (e.g. '1')", "1", &in.CPULimit),
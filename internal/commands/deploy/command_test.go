@@ -33,6 +33,13 @@ func TestDeployCommand_Subcommands(t *testing.T) {
 	}
 }
 
+func TestDefaultDeployInput(t *testing.T) {
+	in := defaultDeployInput()
+	if in.Port != 8080 || in.CPURequest != "250m" || in.Strategy != "rolling" || !in.BackupEnabled {
+		t.Errorf("defaultDeployInput() did not pick up flag defaults: %+v", in)
+	}
+}
+
 func walkCommands(cmd *cli.Command, fn func(*cli.Command)) {
 	fn(cmd)
 	for _, sub := range cmd.Commands {
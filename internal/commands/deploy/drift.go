@@ -0,0 +1,212 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"1ctl/internal/api"
+	"1ctl/internal/config"
+	satuskyctx "1ctl/internal/context"
+	deploypkg "1ctl/internal/deploy"
+	"1ctl/internal/utils"
+
+	"github.com/urfave/cli/v3"
+)
+
+// Drift report statuses.
+const (
+	driftInSync      = "in-sync"
+	driftDrifted     = "drifted"
+	driftNotDeployed = "not-deployed"
+	driftError       = "error"
+)
+
+// DriftInput holds flags for the "app drift" subcommand.
+type DriftInput struct {
+	Config string
+	All    bool
+}
+
+// driftReport is the per-config result of a drift check.
+type driftReport struct {
+	App          string             `json:"app"`
+	Config       string             `json:"config"`
+	DeploymentID string             `json:"deployment_id,omitempty"`
+	Status       string             `json:"status"`
+	Error        string             `json:"error,omitempty"`
+	Changes      []deploypkg.Change `json:"changes"`
+}
+
+func handleDrift(ctx context.Context, in DriftInput) error {
+	if err := satuskyctx.CheckTokenExpiry(); err != nil {
+		return err
+	}
+
+	configs, root, err := driftConfigs(in)
+	if err != nil {
+		return err
+	}
+
+	reports := make([]driftReport, 0, len(configs))
+	for _, cfg := range configs {
+		report := checkDrift(api.FromContext(ctx), cfg)
+		report.Config = relPath(root, cfg.Path)
+		reports = append(reports, report)
+	}
+
+	drifted := 0
+	for _, r := range reports {
+		if r.Status != driftInSync {
+			drifted++
+		}
+	}
+
+	if !utils.TryPrintJSON(reports) {
+		printDriftReports(reports)
+	}
+	if drifted > 0 {
+		return utils.NewError(fmt.Sprintf("drift detected in %d of %d app(s)", drifted, len(reports)), nil)
+	}
+	return nil
+}
+
+// driftConfigs returns the configs to check and the directory their paths
+// are reported relative to.
+func driftConfigs(in DriftInput) ([]*config.ProjectConfig, string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, "", utils.NewError("failed to get working directory", err)
+	}
+
+	if !in.All {
+		cfg, err := config.FindConfig(in.Config)
+		if err != nil {
+			return nil, "", utils.NewError("failed to load config", err)
+		}
+		if cfg == nil {
+			return nil, "", utils.NewError("no satusky.toml found — run in a project directory, pass --config, or use --all", nil)
+		}
+		return []*config.ProjectConfig{cfg}, wd, nil
+	}
+
+	root := config.FindRepoRoot(wd)
+	paths, err := config.DiscoverConfigs(root)
+	if err != nil {
+		return nil, "", utils.NewError(fmt.Sprintf("failed to scan %s for satusky.toml: %s", root, err.Error()), nil)
+	}
+	if len(paths) == 0 {
+		return nil, "", utils.NewError(fmt.Sprintf("no satusky.toml found below %s", root), nil)
+	}
+	configs := make([]*config.ProjectConfig, 0, len(paths))
+	for _, path := range paths {
+		cfg, err := config.LoadConfig(path)
+		if err != nil {
+			return nil, "", utils.NewError(fmt.Sprintf("failed to load %s: %s", path, err.Error()), nil)
+		}
+		configs = append(configs, cfg)
+	}
+	return configs, root, nil
+}
+
+// checkDrift compares one satusky.toml against the live deployment it
+// names. Errors are folded into the report so one broken app does not
+// hide the results for the rest of an --all run.
+func checkDrift(client api.API, cfg *config.ProjectConfig) driftReport {
+	report := driftReport{App: cfg.App.Name, Config: cfg.Path, Changes: []deploypkg.Change{}}
+	fail := func(format string, a ...interface{}) driftReport {
+		report.Status = driftError
+		report.Error = fmt.Sprintf(format, a...)
+		return report
+	}
+
+	if cfg.App.Name == "" {
+		return fail("satusky.toml is missing [app] name")
+	}
+
+	merged := mergeConfig(defaultDeployInput(), cfg)
+	if merged.Organization == "" {
+		return fail("no organization is selected — run '1ctl auth login' or set [app] organization")
+	}
+
+	dep, err := client.GetDeploymentByAppLabel(merged.Organization, cfg.App.Name)
+	if utils.KindOf(err) == utils.ErrNotFound {
+		report.Status = driftNotDeployed
+		report.Error = fmt.Sprintf("app %q not found in organization %s", cfg.App.Name, merged.Organization)
+		return report
+	}
+	if err != nil {
+		return fail("failed to look up app %q: %s", cfg.App.Name, err.Error())
+	}
+	report.DeploymentID = dep.DeploymentID.String()
+
+	live, err := deploypkg.FetchLiveState(client, report.DeploymentID)
+	if err != nil {
+		return fail("failed to fetch live state: %s", err.Error())
+	}
+
+	// The image is produced by the build, not declared in the repo, so the
+	// live image stands in for it. This also skips Dockerfile resolution,
+	// which would otherwise be relative to the current directory.
+	merged.Image = live.Deployment.Image
+	opts, err := prepareDeploymentOptions(client, merged, cfg)
+	if err != nil {
+		return fail("%s", err.Error())
+	}
+	desired, err := deploypkg.DesiredStateFromOptions(opts, merged.Image)
+	if err != nil {
+		return fail("%s", err.Error())
+	}
+
+	report.Changes = deploypkg.Compare(desired, *live)
+	report.Status = driftInSync
+	if len(report.Changes) > 0 {
+		report.Status = driftDrifted
+	}
+	return report
+}
+
+// defaultDeployInput returns a DeployInput holding the deploy flag defaults,
+// exactly as "1ctl deploy" with no flags would see it. Parsing an empty
+// command line keeps this in lockstep with deployFlags.
+func defaultDeployInput() DeployInput {
+	var in DeployInput
+	cmd := &cli.Command{
+		Name:   "deploy",
+		Flags:  deployFlags(&in),
+		Action: func(context.Context, *cli.Command) error { return nil },
+	}
+	_ = cmd.Run(context.Background(), []string{"deploy"}) //nolint:errcheck
+	return in
+}
+
+func printDriftReports(reports []driftReport) {
+	headers := []string{"APP", "RESOURCE", "FIELD", "REPO", "LIVE"}
+	var rows [][]string
+	for _, r := range reports {
+		for _, c := range r.Changes {
+			rows = append(rows, []string{r.App, c.Resource, c.Field, c.Desired, c.Live})
+		}
+	}
+	if len(rows) > 0 {
+		utils.PrintTable(headers, rows)
+		fmt.Println()
+	}
+
+	for _, r := range reports {
+		label := r.App
+		if label == "" {
+			label = r.Config
+		}
+		switch r.Status {
+		case driftInSync:
+			utils.PrintSuccess("%s (%s): in sync", label, r.Config)
+		case driftDrifted:
+			utils.PrintWarning("%s (%s): %d field(s) changed outside the repo", label, r.Config, len(r.Changes))
+		case driftNotDeployed:
+			utils.PrintWarning("%s (%s): not deployed — %s", label, r.Config, r.Error)
+		default:
+			utils.PrintError("%s (%s): %s", label, r.Config, r.Error)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package deploy
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -22,10 +24,16 @@ func handleDeploy(ctx context.Context, in DeployInput) error {
 	if err := satuskyctx.CheckTokenExpiry(); err != nil {
 		return err
 	}
+	if err := config.CheckPinnedOrganization(); err != nil {
+		return err
+	}
+	if in.ChangedSince != "" {
+		return handleChangedDeploy(ctx, in)
+	}
 
 	cfg, err := config.FindConfig(in.Config)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to load config: %s", err.Error()), nil)
+		return utils.NewError("failed to load config", err)
 	}
 
 	merged := mergeConfig(in, cfg)
@@ -35,12 +43,12 @@ func handleDeploy(ctx context.Context, in DeployInput) error {
 	}
 
 	if err := validateInputs(merged); err != nil {
-		return utils.NewError(fmt.Sprintf("validation failed: %s", err.Error()), nil)
+		return utils.NewError("validation failed", err)
 	}
 
-	opts, err := prepareDeploymentOptions(merged, cfg)
+	opts, err := prepareDeploymentOptions(api.FromContext(ctx), merged, cfg)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("deployment preparation failed: %s", err.Error()), nil)
+		return utils.NewError("deployment preparation failed", err)
 	}
 
 	resp, err := deploypkg.Deploy(opts)
@@ -48,15 +56,15 @@ func handleDeploy(ctx context.Context, in DeployInput) error {
 		if _, ok := err.(*utils.ResourceExhaustedCLIError); ok {
 			return err
 		}
-		return utils.NewError(fmt.Sprintf("deployment failed: %s", err.Error()), nil)
+		return utils.NewError("deployment failed", err)
 	}
 
 	ingressID := ""
 	if resp != nil && resp.IngressID != uuid.Nil {
 		ingressID = resp.IngressID.String()
 	}
-	publicURL := deploypkg.WaitForPublicURL(ingressID, resp.Domain)
-	return deploypkg.ReportDeployResult(resp.AppLabel, resp.DeploymentID.String(), resp.Domain, publicURL, merged.HealthPath, merged.StrictSmoke)
+	publicURL := deploypkg.WaitForPublicURL(opts.API, os.Stdout, ingressID, resp.Domain)
+	return deploypkg.ReportDeployResult(os.Stdout, resp.AppLabel, resp.DeploymentID.String(), resp.Domain, publicURL, merged.HealthPath, merged.StrictSmoke)
 }
 
 type mergedInput struct {
@@ -66,6 +74,9 @@ type mergedInput struct {
 	AppName      string
 	Organization string
 	UserSetFlags map[string]bool
+	// ContextDir is the build context the Dockerfile is resolved against.
+	// Empty means the current directory.
+	ContextDir string
 }
 
 func mergeConfig(in DeployInput, cfg *config.ProjectConfig) mergedInput {
@@ -174,6 +185,13 @@ func mergeConfig(in DeployInput, cfg *config.ProjectConfig) mergedInput {
 	return m
 }
 
+func (m mergedInput) contextDir() string {
+	if m.ContextDir == "" {
+		return "."
+	}
+	return m.ContextDir
+}
+
 func applyIf(dst *string, src string) {
 	if src != "" && *dst == "" {
 		*dst = src
@@ -192,8 +210,8 @@ func shouldShowDeployHelp(m mergedInput, cfg *config.ProjectConfig) bool {
 
 func validateInputs(m mergedInput) error {
 	if m.Image == "" {
-		if err := validator.ValidateDockerfile(m.Dockerfile); err != nil {
-			_, findErr := validator.FindDockerfile(".")
+		if err := validator.ValidateDockerfile(filepath.Join(m.contextDir(), m.Dockerfile)); err != nil {
+			_, findErr := validator.FindDockerfile(m.contextDir())
 			if findErr != nil {
 				return utils.NewError("no valid Dockerfile found: please ensure a Dockerfile exists in your project", err)
 			}
@@ -272,15 +290,17 @@ func validateInputs(m mergedInput) error {
 	return nil
 }
 
-func prepareDeploymentOptions(m mergedInput, cfg *config.ProjectConfig) (deploypkg.DeploymentOptions, error) {
+func prepareDeploymentOptions(client api.API, m mergedInput, cfg *config.ProjectConfig) (deploypkg.DeploymentOptions, error) {
 	dockerfilePath := m.Dockerfile
 	if m.Image == "" && dockerfilePath != "" {
-		if err := validator.ValidateDockerfile(dockerfilePath); err != nil {
-			found, findErr := validator.FindDockerfile(".")
+		if err := validator.ValidateDockerfile(filepath.Join(m.contextDir(), dockerfilePath)); err != nil {
+			found, findErr := validator.FindDockerfile(m.contextDir())
 			if findErr != nil {
 				return deploypkg.DeploymentOptions{}, utils.NewError("no valid Dockerfile found", err)
 			}
-			dockerfilePath = found
+			if dockerfilePath, err = filepath.Rel(m.contextDir(), found); err != nil {
+				return deploypkg.DeploymentOptions{}, utils.NewError("no valid Dockerfile found", err)
+			}
 		}
 	}
 	if m.Image != "" {
@@ -299,6 +319,8 @@ func prepareDeploymentOptions(m mergedInput, cfg *config.ProjectConfig) (deployp
 		DockerfilePath: dockerfilePath,
 		PrebuiltImage:  m.Image,
 		FastBuild:      m.Fast,
+		ContextDir:     m.ContextDir,
+		API:            client,
 	}
 
 	opts.Name = m.AppName
@@ -314,9 +336,9 @@ func prepareDeploymentOptions(m mergedInput, cfg *config.ProjectConfig) (deployp
 	if len(m.Machine) > 0 {
 		hostnameSet := make(map[string]bool)
 		for _, machineName := range m.Machine {
-			machine, err := api.GetMachineByName(machineName)
+			machine, err := client.GetMachineByName(machineName)
 			if err != nil {
-				return deploypkg.DeploymentOptions{}, utils.NewError(fmt.Sprintf("failed to get machine by name: %s", err.Error()), nil)
+				return deploypkg.DeploymentOptions{}, utils.NewError("failed to get machine by name", err)
 			}
 			if !machine.Monetized && machine.OwnerID.String() != satuskyctx.GetUserID() {
 				return deploypkg.DeploymentOptions{}, utils.NewError(fmt.Sprintf("machine %s is not owned by you", machineName), nil)
@@ -346,7 +368,7 @@ func prepareDeploymentOptions(m mergedInput, cfg *config.ProjectConfig) (deployp
 	}
 
 	if m.MachineTag != "" && len(m.Machine) == 0 {
-		hostnames, err := resolveMachineTagExpr(m.MachineTag)
+		hostnames, err := resolveMachineTagExpr(client, m.MachineTag)
 		if err != nil {
 			return deploypkg.DeploymentOptions{}, err
 		}
@@ -534,18 +556,18 @@ func defaultInt32(v, fallback int32) int32 {
 	return v
 }
 
-func resolveMachineTagExpr(expr string) ([]string, error) {
+func resolveMachineTagExpr(client api.MachinesAPI, expr string) ([]string, error) {
 	userID := satuskyctx.GetUserID()
 	if userID == "" {
-		return nil, utils.NewError("not authenticated — run '1ctl auth login' first", nil)
+		return nil, utils.NewKindError(utils.ErrAuth, "not authenticated — run '1ctl auth login' first", nil)
 	}
 	userUUID, err := api.ParseUUID(userID)
 	if err != nil {
-		return nil, utils.NewError(fmt.Sprintf("invalid user ID in context: %s", err.Error()), nil)
+		return nil, utils.NewError("invalid user ID in context", err)
 	}
-	machines, err := api.GetMachinesByOwnerID(userUUID)
+	machines, err := client.GetMachinesByOwnerID(userUUID)
 	if err != nil {
-		return nil, utils.NewError(fmt.Sprintf("failed to list owned machines: %s", err.Error()), nil)
+		return nil, utils.NewError("failed to list owned machines", err)
 	}
 	if len(machines) == 0 {
 		return nil, utils.NewError("no owned machines found — register a machine before using --machine-tag", nil)
@@ -556,7 +578,7 @@ func resolveMachineTagExpr(expr string) ([]string, error) {
 		if m.Status != "online" {
 			continue
 		}
-		labels, err := api.GetMachineLabels(m.MachineID)
+		labels, err := client.GetMachineLabels(m.MachineID)
 		if err != nil {
 			utils.PrintWarning("Could not read labels for machine %s: %s", m.MachineID, err.Error())
 			continue
@@ -581,13 +603,14 @@ func resolveMachineTagExpr(expr string) ([]string, error) {
 // --- List / Get ---------------------------------------------------------
 
 func handleListDeployments(ctx context.Context) error {
+	client := api.FromContext(ctx)
 	namespace, err := satuskyctx.GetCurrentNamespaceOrError()
 	if err != nil {
 		return err
 	}
-	deployments, err := api.ListDeploymentsByNamespace(namespace)
+	deployments, err := client.ListDeploymentsByNamespace(namespace)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to list deployments: %s", err.Error()), nil)
+		return utils.NewError("failed to list deployments", err)
 	}
 
 	if utils.PrintListOrJSON(deployments, "No deployments found") {
@@ -614,16 +637,17 @@ func handleListDeployments(ctx context.Context) error {
 }
 
 func handleGetDeployment(ctx context.Context, in GetDeploymentInput) error {
-	deploymentID, err := deploypkg.ResolveDeploymentID(in.DeploymentID, in.App, in.Config)
+	client := api.FromContext(ctx)
+	deploymentID, err := deploypkg.ResolveDeploymentID(client, in.DeploymentID, in.App, in.Config)
 	if err != nil {
 		return err
 	}
-	deployment, err := api.GetDeployment(deploymentID)
+	deployment, err := client.GetDeployment(deploymentID)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to get deployment: %s", err.Error()), nil)
+		return utils.NewError("failed to get deployment", err)
 	}
 
-	if ingress, iErr := api.GetIngressByDeploymentID(deploymentID); iErr == nil && ingress != nil && ingress.DomainName != "" {
+	if ingress, iErr := client.GetIngressByDeploymentID(deploymentID); iErr == nil && ingress != nil && ingress.DomainName != "" {
 		deployment.Domain = "https://" + ingress.DomainName
 	}
 
@@ -660,15 +684,16 @@ func handleGetDeployment(ctx context.Context, in GetDeploymentInput) error {
 // --- Status -------------------------------------------------------------
 
 func handleDeploymentStatus(ctx context.Context, in StatusInput) error {
-	deploymentID, err := deploypkg.ResolveDeploymentID(in.DeploymentID, in.App, in.Config)
+	client := api.FromContext(ctx)
+	deploymentID, err := deploypkg.ResolveDeploymentID(client, in.DeploymentID, in.App, in.Config)
 	if err != nil {
 		return err
 	}
 
 	if in.Watch {
-		status, err := api.WaitForDeployment(deploymentID, 5*time.Minute)
+		status, err := client.WaitForDeployment(deploymentID, 5*time.Minute)
 		if err != nil {
-			return utils.NewError(fmt.Sprintf("failed to watch deployment: %s", err.Error()), nil)
+			return utils.NewError("failed to watch deployment", err)
 		}
 		utils.PrintStatusLine("Final status", status.Status)
 		if status.Message != "" {
@@ -677,22 +702,22 @@ func handleDeploymentStatus(ctx context.Context, in StatusInput) error {
 		return nil
 	}
 
-	status, err := api.GetDeploymentStatus(deploymentID)
+	status, err := client.GetDeploymentStatus(deploymentID)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to get deployment status: %s", err.Error()), nil)
+		return utils.NewError("failed to get deployment status", err)
 	}
 
-	deployment, err := api.GetDeployment(deploymentID)
+	deployment, err := client.GetDeployment(deploymentID)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to get deployment details: %s", err.Error()), nil)
+		return utils.NewError("failed to get deployment details", err)
 	}
 
 	var ingress *api.Ingress
 	var domainStatus *api.DomainStatusResponse
-	if ing, ingErr := api.GetIngressByDeploymentID(deploymentID); ingErr == nil {
+	if ing, ingErr := client.GetIngressByDeploymentID(deploymentID); ingErr == nil {
 		ingress = ing
 		if ing.DomainName != "" {
-			if ds, dsErr := api.GetDomainStatus(ing.IngressID.String(), ing.DomainName, false); dsErr == nil {
+			if ds, dsErr := client.GetDomainStatus(ing.IngressID.String(), ing.DomainName, false); dsErr == nil {
 				domainStatus = ds
 			}
 		}
@@ -744,12 +769,16 @@ func handleDeploymentStatus(ctx context.Context, in StatusInput) error {
 // --- Destroy ------------------------------------------------------------
 
 func handleDestroyDeployment(ctx context.Context, in DestroyInput) error {
-	deploymentID, err := deploypkg.ResolveDeploymentID(in.DeploymentID, in.App, in.Config)
+	client := api.FromContext(ctx)
+	if err := config.CheckPinnedOrganization(); err != nil {
+		return err
+	}
+	deploymentID, err := deploypkg.ResolveDeploymentID(client, in.DeploymentID, in.App, in.Config)
 	if err != nil {
 		return err
 	}
 
-	preview, pErr := previewDeletion(deploymentID)
+	preview, pErr := previewDeletion(client, deploymentID)
 	if pErr == nil {
 		fmt.Println(strings.Join(preview, "\n"))
 		fmt.Println()
@@ -763,7 +792,7 @@ func handleDestroyDeployment(ctx context.Context, in DestroyInput) error {
 	}
 
 	utils.PrintInfo("Destroying deployment %s...", deploymentID)
-	statuses, volErr := api.GetDeploymentVolumeLifecycleStatuses(deploymentID)
+	statuses, volErr := client.GetDeploymentVolumeLifecycleStatuses(deploymentID)
 	if volErr != nil {
 		utils.PrintWarning("Could not list volumes for destruction: %s", volErr.Error())
 	} else if len(statuses) > 0 {
@@ -772,16 +801,16 @@ func handleDestroyDeployment(ctx context.Context, in DestroyInput) error {
 		} else {
 			for _, v := range statuses {
 				utils.PrintInfo("Destroying volume %s (PVC: %s)...", v.Volume.VolumeName, v.PVC.Name)
-				if _, delErr := api.DeleteVolumePVC(v.Volume.VolumeID.String()); delErr != nil {
+				if _, delErr := client.DeleteVolumePVC(v.Volume.VolumeID.String()); delErr != nil {
 					utils.PrintWarning("Failed to destroy volume %s: %s", v.Volume.VolumeName, delErr.Error())
 				}
 			}
 		}
 	}
 
-	result, err := api.DeleteDeployment(deploymentID)
+	result, err := client.DeleteDeployment(deploymentID)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to delete deployment: %s", err.Error()), nil)
+		return utils.NewError("failed to delete deployment", err)
 	}
 
 	if utils.TryPrintJSON(result) {
@@ -791,9 +820,9 @@ func handleDestroyDeployment(ctx context.Context, in DestroyInput) error {
 	return nil
 }
 
-func previewDeletion(deploymentID string) ([]string, error) {
+func previewDeletion(client api.API, deploymentID string) ([]string, error) {
 	var lines []string
-	dep, err := api.GetDeployment(deploymentID)
+	dep, err := client.GetDeployment(deploymentID)
 	if err != nil {
 		return nil, err
 	}
@@ -801,7 +830,7 @@ func previewDeletion(deploymentID string) ([]string, error) {
 	lines = append(lines, "")
 	lines = append(lines, "Resources that will be deleted:")
 
-	ing, err := api.GetIngressByDeploymentID(deploymentID)
+	ing, err := client.GetIngressByDeploymentID(deploymentID)
 	if err == nil && ing != nil {
 		domainDisplay := ing.DomainName
 		if domainDisplay == "" {
@@ -810,7 +839,7 @@ func previewDeletion(deploymentID string) ([]string, error) {
 		lines = append(lines, fmt.Sprintf("  • Ingress  — %s", domainDisplay))
 	}
 
-	volumes, err := api.GetDeploymentVolumeLifecycleStatuses(deploymentID)
+	volumes, err := client.GetDeploymentVolumeLifecycleStatuses(deploymentID)
 	if err == nil {
 		for _, v := range volumes {
 			policy := v.DestroyPolicy
@@ -821,7 +850,7 @@ func previewDeletion(deploymentID string) ([]string, error) {
 		}
 	}
 
-	services, err := api.ListServices()
+	services, err := client.ListServices()
 	if err == nil {
 		for _, s := range services {
 			if s.DeploymentID.String() == deploymentID {
@@ -877,27 +906,61 @@ func printDeletionResult(deploymentID string, result *api.DeletionResult) {
 // --- Restart / Releases / Rollback / Open / Scale -----------------------
 
 func handleRestartDeployment(ctx context.Context, in DeployRefInput) error {
-	deploymentID, err := deploypkg.ResolveDeploymentID(in.DeploymentID, in.App, in.Config)
+	client := api.FromContext(ctx)
+	if err := config.CheckPinnedOrganization(); err != nil {
+		return err
+	}
+	deploymentID, err := deploypkg.ResolveDeploymentID(client, in.DeploymentID, in.App, in.Config)
 	if err != nil {
 		return err
 	}
 	utils.PrintInfo("Initiating rolling restart for deployment %s...", deploymentID)
-	if err := api.RestartDeployment(deploymentID); err != nil {
-		return utils.NewError(fmt.Sprintf("failed to restart: %s", err.Error()), nil)
+	if err := client.RestartDeployment(deploymentID); err != nil {
+		return utils.NewError("failed to restart", err)
 	}
 	utils.PrintSuccess("Rolling restart initiated.")
 	utils.PrintInfo("Use '1ctl deploy status --deployment-id %s' to monitor progress.", deploymentID)
 	return nil
 }
 
+// handleApplyConfig restarts a deployment so environment and secret changes
+// staged with --no-restart take effect together.
+func handleApplyConfig(ctx context.Context, in DeployRefInput) error {
+	client := api.FromContext(ctx)
+	if err := config.CheckPinnedOrganization(); err != nil {
+		return err
+	}
+	deploymentID, err := deploypkg.ResolveDeploymentID(client, in.DeploymentID, in.App, in.Config)
+	if err != nil {
+		return err
+	}
+
+	envKeys, secretKeys := 0, 0
+	if envs, err := client.GetEnvironmentsByDeploymentID(deploymentID); err == nil && len(envs) > 0 {
+		envKeys = len(envs[0].KeyValues)
+	}
+	if secrets, err := client.GetSecretsByDeploymentID(deploymentID); err == nil && len(secrets) > 0 {
+		secretKeys = len(secrets[0].KeyValues)
+	}
+
+	utils.PrintInfo("Applying %d environment variable(s) and %d secret(s) to deployment %s...", envKeys, secretKeys, deploymentID)
+	if err := client.RestartDeployment(deploymentID); err != nil {
+		return utils.NewError("failed to restart", err)
+	}
+	utils.PrintSuccess("Rolling restart initiated — configuration will be live shortly.")
+	utils.PrintInfo("Use '1ctl app status %s' to monitor progress.", deploymentID)
+	return nil
+}
+
 func handleListReleases(ctx context.Context, in DeployRefInput) error {
-	deploymentID, err := deploypkg.ResolveDeploymentID(in.DeploymentID, in.App, in.Config)
+	client := api.FromContext(ctx)
+	deploymentID, err := deploypkg.ResolveDeploymentID(client, in.DeploymentID, in.App, in.Config)
 	if err != nil {
 		return err
 	}
-	versions, err := api.ListDeploymentVersions(deploymentID)
+	versions, err := client.ListDeploymentVersions(deploymentID)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to list releases: %s", err.Error()), nil)
+		return utils.NewError("failed to list releases", err)
 	}
 	if utils.PrintListOrJSON(versions, "No releases found") {
 		return nil
@@ -917,15 +980,19 @@ func handleListReleases(ctx context.Context, in DeployRefInput) error {
 }
 
 func handleRollback(ctx context.Context, in RollbackInput) error {
-	deploymentID, err := deploypkg.ResolveDeploymentID(in.DeploymentID, in.App, in.Config)
+	client := api.FromContext(ctx)
+	if err := config.CheckPinnedOrganization(); err != nil {
+		return err
+	}
+	deploymentID, err := deploypkg.ResolveDeploymentID(client, in.DeploymentID, in.App, in.Config)
 	if err != nil {
 		return err
 	}
 	version := in.Version
 	if version == 0 {
-		versions, err := api.ListDeploymentVersions(deploymentID)
+		versions, err := client.ListDeploymentVersions(deploymentID)
 		if err != nil {
-			return utils.NewError(fmt.Sprintf("failed to fetch releases: %s", err.Error()), nil)
+			return utils.NewError("failed to fetch releases", err)
 		}
 		if len(versions) < 2 {
 			return utils.NewError("no previous release to roll back to", nil)
@@ -938,8 +1005,8 @@ func handleRollback(ctx context.Context, in RollbackInput) error {
 		return nil
 	}
 
-	if err := api.RollbackDeployment(deploymentID, version); err != nil {
-		return utils.NewError(fmt.Sprintf("rollback failed: %s", err.Error()), nil)
+	if err := client.RollbackDeployment(deploymentID, version); err != nil {
+		return utils.NewError("rollback failed", err)
 	}
 	utils.PrintSuccess("Rollback to version %d initiated", version)
 	utils.PrintInfo("Use '1ctl deploy status --deployment-id %s' to monitor progress.", deploymentID)
@@ -947,17 +1014,18 @@ func handleRollback(ctx context.Context, in RollbackInput) error {
 }
 
 func handleOpenDeployment(ctx context.Context, in DeployRefInput) error {
-	deploymentID, err := deploypkg.ResolveDeploymentID(in.DeploymentID, in.App, in.Config)
+	client := api.FromContext(ctx)
+	deploymentID, err := deploypkg.ResolveDeploymentID(client, in.DeploymentID, in.App, in.Config)
 	if err != nil {
 		return err
 	}
-	ing, err := api.GetIngressByDeploymentID(deploymentID)
+	ing, err := client.GetIngressByDeploymentID(deploymentID)
 	if err != nil || ing == nil || ing.DomainName == "" {
 		return utils.NewError(fmt.Sprintf("no domain attached to deployment %s — use '1ctl domains add' first", deploymentID), nil)
 	}
 	url := "https://" + ing.DomainName
 	utils.PrintInfo("Opening %s", url)
-	if err := deploypkg.OpenBrowser(url); err != nil {
+	if err := utils.OpenBrowser(url); err != nil {
 		utils.PrintWarning("Could not open browser: %s", err.Error())
 		utils.PrintInfo("URL: %s", url)
 	}
@@ -965,7 +1033,11 @@ func handleOpenDeployment(ctx context.Context, in DeployRefInput) error {
 }
 
 func handleScaleDeployment(ctx context.Context, in ScaleInput) error {
-	deploymentID, err := deploypkg.ResolveDeploymentID(in.DeploymentID, in.App, in.Config)
+	client := api.FromContext(ctx)
+	if err := config.CheckPinnedOrganization(); err != nil {
+		return err
+	}
+	deploymentID, err := deploypkg.ResolveDeploymentID(client, in.DeploymentID, in.App, in.Config)
 	if err != nil {
 		return err
 	}
@@ -977,9 +1049,9 @@ func handleScaleDeployment(ctx context.Context, in ScaleInput) error {
 		return utils.NewError("--replicas must be >= 1", nil)
 	}
 
-	current, err := api.GetDeployment(deploymentID)
+	current, err := client.GetDeployment(deploymentID)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to fetch deployment: %s", err.Error()), nil)
+		return utils.NewError("failed to fetch deployment", err)
 	}
 	if current.HPAConfig != nil && current.HPAConfig.Enabled {
 		return utils.NewError(fmt.Sprintf("deployment %s is managed by HPA — adjust --hpa-min-replicas / --hpa-max-replicas instead", deploymentID), nil)
@@ -994,8 +1066,8 @@ func handleScaleDeployment(ctx context.Context, in ScaleInput) error {
 	current.Replicas = replicas
 
 	var resp string
-	if err := api.UpsertDeployment(*current, &resp); err != nil {
-		return utils.NewError(fmt.Sprintf("failed to scale deployment: %s", err.Error()), nil)
+	if err := client.UpsertDeployment(*current, &resp); err != nil {
+		return utils.NewError("failed to scale deployment", err)
 	}
 	utils.PrintSuccess("Scaled deployment %s to %d replicas", deploymentID, replicas)
 	return nil
@@ -0,0 +1,68 @@
+package deploy
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"1ctl/internal/api"
+	"1ctl/internal/api/apitest"
+	"1ctl/internal/utils"
+)
+
+func TestAppLifecycleAgainstFakeAPI(t *testing.T) {
+	srv := apitest.NewServer(t)
+	ctx := api.WithClient(context.Background(), srv.Client())
+
+	srv.AddDeployment(api.Deployment{Namespace: "acme", AppLabel: "web", Image: "web:1", Replicas: 1})
+	dep := srv.AddDeployment(api.Deployment{Namespace: "acme", AppLabel: "web", Image: "web:2", Replicas: 1})
+	id := dep.DeploymentID.String()
+	if err := srv.Client().CreateVolume(api.Volume{DeploymentID: dep.DeploymentID, VolumeName: "web-volume", ClaimName: "web-claim"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without --version, rollback goes to the release before the current one.
+	if err := handleRollback(ctx, RollbackInput{DeploymentID: id, Yes: true}); err != nil {
+		t.Fatalf("rollback: %v", err)
+	}
+	if got, _ := srv.Deployment(id); got.Image != "web:1" {
+		t.Errorf("image after rollback = %q, want web:1", got.Image)
+	}
+
+	if err := handleScaleDeployment(ctx, ScaleInput{DeploymentID: id, Replicas: 3}); err != nil {
+		t.Fatalf("scale: %v", err)
+	}
+	if got, _ := srv.Deployment(id); got.Replicas != 3 || got.Image != "web:1" {
+		t.Errorf("deployment after scale = %+v, want 3 replicas of web:1", got)
+	}
+
+	if err := handleDestroyDeployment(ctx, DestroyInput{DeploymentID: id, Yes: true}); err != nil {
+		t.Fatalf("destroy: %v", err)
+	}
+	if _, ok := srv.Deployment(id); ok {
+		t.Error("deployment still exists after destroy")
+	}
+	if vols := srv.Volumes(); len(vols) != 0 {
+		t.Errorf("volumes after destroy = %+v", vols)
+	}
+}
+
+func TestRestartReportsAPIError(t *testing.T) {
+	srv := apitest.NewServer(t)
+	ctx := api.WithClient(context.Background(), srv.Client())
+	dep := srv.AddDeployment(api.Deployment{Namespace: "acme", AppLabel: "web", Image: "web:1"})
+	path := "/deployments/" + dep.DeploymentID.String() + "/restart"
+	srv.Fail(http.MethodPost, path, http.StatusConflict, "a rollout is already in progress")
+
+	err := handleRestartDeployment(ctx, DeployRefInput{DeploymentID: dep.DeploymentID.String()})
+	if err == nil || !strings.Contains(err.Error(), "a rollout is already in progress") {
+		t.Errorf("handleRestartDeployment() error = %v, want the API message", err)
+	}
+	if got := utils.KindOf(err); got != utils.ErrConflict {
+		t.Errorf("KindOf(%v) = %q, want %q", err, got, utils.ErrConflict)
+	}
+	if reqs := srv.Requests(); len(reqs) != 1 || reqs[0] != "POST "+path {
+		t.Errorf("requests = %v", reqs)
+	}
+}
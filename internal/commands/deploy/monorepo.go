@@ -0,0 +1,250 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"1ctl/internal/api"
+	"1ctl/internal/config"
+	deploypkg "1ctl/internal/deploy"
+	"1ctl/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+// Service deploy statuses reported by --changed-since.
+const (
+	serviceDeployed = "deployed"
+	serviceFailed   = "failed"
+)
+
+// serviceResult is the per-service outcome of a --changed-since deploy.
+type serviceResult struct {
+	App          string `json:"app"`
+	Config       string `json:"config"`
+	DeploymentID string `json:"deployment_id,omitempty"`
+	Domain       string `json:"domain,omitempty"`
+	Status       string `json:"status"`
+	Error        string `json:"error,omitempty"`
+}
+
+// handleChangedDeploy deploys every service in the repository whose build
+// context changed since in.ChangedSince. Options are prepared one service at
+// a time so validation errors are reported in a stable order; the builds and
+// rollouts then run with at most in.Concurrency in flight.
+func handleChangedDeploy(ctx context.Context, in DeployInput) error {
+	if in.Config != "" || in.Name != "" {
+		return utils.NewError("--changed-since deploys every changed service; it cannot be combined with --config or --name", nil)
+	}
+	if in.Concurrency < 1 {
+		return utils.NewError("--concurrency must be at least 1", nil)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return utils.NewError("failed to get working directory", err)
+	}
+	root := config.FindRepoRoot(wd)
+
+	configs, err := config.DiscoverConfigs(root)
+	if err != nil {
+		return utils.NewError(fmt.Sprintf("failed to scan %s for satusky.toml: %s", root, err.Error()), nil)
+	}
+	if len(configs) == 0 {
+		return utils.NewError(fmt.Sprintf("no satusky.toml found below %s", root), nil)
+	}
+	changed, err := deploypkg.ChangedFiles(root, in.ChangedSince)
+	if err != nil {
+		return utils.NewError(err.Error(), nil)
+	}
+	affected, err := deploypkg.AffectedConfigs(root, configs, changed)
+	if err != nil {
+		return utils.NewError(err.Error(), nil)
+	}
+	if len(affected) == 0 {
+		if !utils.TryPrintJSON([]serviceResult{}) {
+			utils.PrintInfo("No services changed since %s (%d checked)", in.ChangedSince, len(configs))
+		}
+		return nil
+	}
+
+	// With -o json, stdout carries only the summary; progress goes to stderr.
+	var progress io.Writer = os.Stdout
+	if utils.IsJSONOutput() {
+		progress = os.Stderr
+	}
+	out := utils.NewPrinter(progress)
+	out.Info("%d of %d service(s) changed since %s", len(affected), len(configs), in.ChangedSince)
+
+	results := make([]serviceResult, len(affected))
+	var ready []int
+	opts := make([]deploypkg.DeploymentOptions, len(affected))
+	for i, path := range affected {
+		results[i] = serviceResult{Config: relPath(root, path)}
+		o, app, err := prepareServiceDeploy(api.FromContext(ctx), in, path)
+		results[i].App = app
+		if err != nil {
+			results[i].Status = serviceFailed
+			results[i].Error = err.Error()
+			continue
+		}
+		out.Info("  %s (%s)", app, results[i].Config)
+		opts[i] = o
+		ready = append(ready, i)
+	}
+
+	var (
+		wg  sync.WaitGroup
+		mu  sync.Mutex
+		sem = make(chan struct{}, in.Concurrency)
+	)
+	for _, i := range ready {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			o := opts[i]
+			w := &prefixWriter{mu: &mu, out: progress, prefix: fmt.Sprintf("[%s] ", o.Name)}
+			o.Output = w
+			results[i] = deployService(o, results[i])
+			w.Flush()
+		}(i)
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, r := range results {
+		if r.Status != serviceDeployed {
+			failed++
+		}
+	}
+	if !utils.TryPrintJSON(results) {
+		printServiceResults(results)
+	}
+	if failed > 0 {
+		return utils.NewError(fmt.Sprintf("%d of %d service deploy(s) failed", failed, len(results)), nil)
+	}
+	return nil
+}
+
+// prepareServiceDeploy loads one service's satusky.toml and builds its
+// deployment options, with the service directory as the build context.
+func prepareServiceDeploy(client api.API, in DeployInput, configPath string) (deploypkg.DeploymentOptions, string, error) {
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return deploypkg.DeploymentOptions{}, "", fmt.Errorf("failed to load config: %w", err)
+	}
+	merged := mergeConfig(in, cfg)
+	merged.ContextDir = filepath.Dir(configPath)
+	if merged.AppName == "" {
+		return deploypkg.DeploymentOptions{}, "", fmt.Errorf("satusky.toml is missing [app] name")
+	}
+	if shouldShowDeployHelp(merged, cfg) {
+		return deploypkg.DeploymentOptions{}, merged.AppName, fmt.Errorf("insufficient configuration — set CPU/memory in satusky.toml")
+	}
+	if err := validateInputs(merged); err != nil {
+		return deploypkg.DeploymentOptions{}, merged.AppName, fmt.Errorf("validation failed: %w", err)
+	}
+	opts, err := prepareDeploymentOptions(client, merged, cfg)
+	if err != nil {
+		return deploypkg.DeploymentOptions{}, merged.AppName, fmt.Errorf("deployment preparation failed: %w", err)
+	}
+	return opts, merged.AppName, nil
+}
+
+// deployService runs the same deploy and result check as a single-service
+// deploy, writing its progress to opts.Output, and records the outcome in
+// result.
+func deployService(opts deploypkg.DeploymentOptions, result serviceResult) serviceResult {
+	resp, err := deploypkg.Deploy(opts)
+	if err != nil {
+		result.Status = serviceFailed
+		result.Error = err.Error()
+		return result
+	}
+	result.DeploymentID = resp.DeploymentID.String()
+	result.Domain = resp.Domain
+
+	ingressID := ""
+	if resp.IngressID != uuid.Nil {
+		ingressID = resp.IngressID.String()
+	}
+	publicURL := deploypkg.WaitForPublicURL(opts.API, opts.Output, ingressID, resp.Domain)
+	if err := deploypkg.ReportDeployResult(opts.Output, resp.AppLabel, result.DeploymentID, resp.Domain, publicURL, opts.SmokePath, opts.StrictSmoke); err != nil {
+		result.Status = serviceFailed
+		result.Error = err.Error()
+		return result
+	}
+	result.Status = serviceDeployed
+	return result
+}
+
+func printServiceResults(results []serviceResult) {
+	fmt.Println()
+	rows := make([][]string, 0, len(results))
+	for _, r := range results {
+		detail := r.Error
+		if r.Status == serviceDeployed && r.Domain != "" {
+			detail = "https://" + r.Domain
+		}
+		rows = append(rows, []string{r.App, r.Config, r.Status, r.DeploymentID, detail})
+	}
+	utils.PrintTable([]string{"APP", "CONFIG", "STATUS", "DEPLOYMENT ID", "DETAIL"}, rows)
+}
+
+func relPath(root, path string) string {
+	if rel, err := filepath.Rel(root, path); err == nil {
+		return rel
+	}
+	return path
+}
+
+// prefixWriter tags each complete line with prefix so output from
+// concurrent services stays readable. Lines from all writers sharing mu are
+// written whole, never interleaved mid-line. A line redrawn with carriage
+// returns, like a progress spinner, is written as its final state.
+type prefixWriter struct {
+	mu     *sync.Mutex
+	out    io.Writer
+	prefix string
+	buf    []byte
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		err := w.writeLine(w.buf[:i])
+		w.buf = w.buf[i+1:]
+		if err != nil {
+			return 0, err
+		}
+	}
+}
+
+// Flush writes a final line that has no newline.
+func (w *prefixWriter) Flush() {
+	if len(w.buf) > 0 {
+		_ = w.writeLine(w.buf) //nolint:errcheck
+		w.buf = nil
+	}
+}
+
+func (w *prefixWriter) writeLine(line []byte) error {
+	if i := bytes.LastIndexByte(line, '\r'); i >= 0 {
+		line = line[i+1:]
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, err := fmt.Fprintf(w.out, "%s%s\n", w.prefix, line)
+	return err
+}
@@ -0,0 +1,25 @@
+package deploy
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestPrefixWriter(t *testing.T) {
+	var (
+		out bytes.Buffer
+		mu  sync.Mutex
+	)
+	w := &prefixWriter{mu: &mu, out: &out, prefix: "[web] "}
+	fmt.Fprint(w, "Packaging build context...\nStep 1/5: Building ⠋")
+	fmt.Fprint(w, "\rStep 1/5: Building ✓\nBuild ")
+	fmt.Fprint(w, "queued")
+	w.Flush()
+
+	want := "[web] Packaging build context...\n[web] Step 1/5: Building ✓\n[web] Build queued\n"
+	if out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+}
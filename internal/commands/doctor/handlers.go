@@ -19,6 +19,7 @@ type doctorReport struct {
 	UserEmail    string                   `json:"user_email"`
 	Organization string                   `json:"organization"`
 	Namespace    string                   `json:"namespace"`
+	Context      config.EffectiveContext  `json:"context"`
 	Zones        int                      `json:"zones"`
 	Clusters     int                      `json:"clusters"`
 	Deployments  []doctorDeploymentReport `json:"deployments"`
@@ -39,7 +40,7 @@ type doctorDeploymentReport struct {
 func handleDoctor(ctx context.Context, in doctorInput) error {
 	user, err := api.GetCurrentUser()
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("auth/backend check failed: %s", err.Error()), nil)
+		return utils.NewError("auth/backend check failed", err)
 	}
 
 	namespace := satuskyctx.GetCurrentNamespace()
@@ -55,6 +56,10 @@ func handleDoctor(ctx context.Context, in doctorInput) error {
 		UserEmail:    user.Email,
 		Organization: user.Organization,
 		Namespace:    namespace,
+		Context:      config.DescribeContext(),
+	}
+	if report.Context.Mismatch {
+		report.Issues = append(report.Issues, fmt.Sprintf("organization: %s pins %q but profile %q uses %q", report.Context.PinPath, report.Context.PinnedOrganization, report.Context.Profile, namespace))
 	}
 
 	if zones, err := api.GetAvailableZones(); err != nil {
@@ -69,7 +74,7 @@ func handleDoctor(ctx context.Context, in doctorInput) error {
 		report.Clusters = len(clusters)
 	}
 
-	targets, smokePath, targetedMode, err := resolveDoctorTargets(in)
+	targets, smokePath, targetedMode, err := resolveDoctorTargets(api.FromContext(ctx), in)
 	if err != nil {
 		return err
 	}
@@ -124,6 +129,7 @@ func handleDoctor(ctx context.Context, in doctorInput) error {
 	utils.PrintStatusLine("User", report.UserEmail)
 	utils.PrintStatusLine("Organization", report.Organization)
 	utils.PrintStatusLine("Namespace", report.Namespace)
+	config.PrintContext(report.Context)
 	utils.PrintStatusLine("Zones", fmt.Sprintf("%d available", report.Zones))
 	utils.PrintStatusLine("Clusters", fmt.Sprintf("%d available", report.Clusters))
 
@@ -172,15 +178,15 @@ func handleDoctor(ctx context.Context, in doctorInput) error {
 
 // --- Target resolution --------------------------------------------------
 
-func resolveDoctorTargets(in doctorInput) ([]api.Deployment, string, bool, error) {
+func resolveDoctorTargets(client api.DeploymentsAPI, in doctorInput) ([]api.Deployment, string, bool, error) {
 	healthPath := strings.TrimSpace(in.HealthPath)
 
 	if in.DeploymentID != "" || in.Config != "" {
-		deploymentID, err := deploy.ResolveDeploymentID(in.DeploymentID, in.App, in.Config)
+		deploymentID, err := deploy.ResolveDeploymentID(client, in.DeploymentID, in.App, in.Config)
 		if err != nil {
 			return nil, "", false, err
 		}
-		deployment, err := api.GetDeployment(deploymentID)
+		deployment, err := client.GetDeployment(deploymentID)
 		if err != nil {
 			return nil, "", false, utils.NewError(fmt.Sprintf("failed to load deployment %s: %s", deploymentID, err.Error()), nil)
 		}
@@ -192,9 +198,9 @@ func resolveDoctorTargets(in doctorInput) ([]api.Deployment, string, bool, error
 		return []api.Deployment{*deployment}, healthPath, true, nil
 	}
 
-	deployments, err := api.ListDeployments()
+	deployments, err := client.ListDeployments()
 	if err != nil {
-		return nil, "", false, utils.NewError(fmt.Sprintf("failed to list deployments: %s", err.Error()), nil)
+		return nil, "", false, utils.NewError("failed to list deployments", err)
 	}
 	return deployments, healthPath, false, nil
 }
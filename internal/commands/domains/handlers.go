@@ -23,7 +23,7 @@ func handleDomainsList(ctx context.Context, appFilter string) error {
 	}
 	ingresses, err := api.ListIngresses()
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to list domains: %s", err.Error()), nil)
+		return utils.NewError("failed to list domains", err)
 	}
 
 	entries := make([]domainListEntry, 0, len(ingresses))
@@ -102,7 +102,7 @@ func handleDomainsAdd(ctx context.Context, in domainsAddInput) error {
 
 	services, err := api.ListServices()
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to list services: %s", err.Error()), nil)
+		return utils.NewError("failed to list services", err)
 	}
 	var serviceID string
 	for _, s := range services {
@@ -122,7 +122,7 @@ func handleDomainsAdd(ctx context.Context, in domainsAddInput) error {
 
 	existingIngress, err := api.GetIngressByDeploymentID(dep.DeploymentID.String())
 	if err != nil && !strings.Contains(strings.ToLower(err.Error()), "not found") {
-		return utils.NewError(fmt.Sprintf("failed to inspect existing domain setup: %s", err.Error()), nil)
+		return utils.NewError("failed to inspect existing domain setup", err)
 	}
 
 	dnsCfg := api.DnsConfigDefault
@@ -162,7 +162,7 @@ func handleDomainsAdd(ctx context.Context, in domainsAddInput) error {
 			WithWWWRedirect: in.WithWWW,
 		})
 		if err != nil {
-			return utils.NewError(fmt.Sprintf("failed to add domain: %s", err.Error()), nil)
+			return utils.NewError("failed to add domain", err)
 		}
 		utils.PrintSuccess("Domain %s attached to app %s", alias.DomainName, appName)
 
@@ -194,7 +194,7 @@ func handleDomainsAdd(ctx context.Context, in domainsAddInput) error {
 	}
 	resp, err := api.UpsertIngress(ingress)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to add domain: %s", err.Error()), nil)
+		return utils.NewError("failed to add domain", err)
 	}
 
 	utils.PrintSuccess("Domain %s attached to app %s", resp.DomainName, appName)
@@ -238,7 +238,7 @@ func handleDomainsRemove(ctx context.Context, in domainsRemoveInput) error {
 		return err
 	}
 	if err := api.DetachDomain(ing.IngressID.String(), api.DetachDomainRequest{OrgID: orgID, DomainName: domain}); err != nil {
-		return utils.NewError(fmt.Sprintf("failed to remove domain: %s", err.Error()), nil)
+		return utils.NewError("failed to remove domain", err)
 	}
 	utils.PrintSuccess("Domain %s removed from app %s", domain, appName)
 	return nil
@@ -314,7 +314,7 @@ func handleDomainsAvailable(ctx context.Context, in domainsAvailableInput) error
 	}
 	results, err := api.CheckDomainAvailability(userID, orgID, api.DomainCheckRequest{Domains: domains, WithPrice: in.Price})
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to check availability: %s", err.Error()), nil)
+		return utils.NewError("failed to check availability", err)
 	}
 	if utils.TryPrintJSON(results) {
 		return nil
@@ -347,7 +347,7 @@ func handleDomainsSearch(ctx context.Context, in domainsSearchInput) error {
 		Period:     in.Period,
 	})
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to search domains: %s", err.Error()), nil)
+		return utils.NewError("failed to search domains", err)
 	}
 	if utils.TryPrintJSON(results) {
 		return nil
@@ -374,7 +374,7 @@ func handleManagedDomainsList(ctx context.Context) error {
 	}
 	domains, err := api.ListManagedDomains(userID, orgID)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to list managed domains: %s", err.Error()), nil)
+		return utils.NewError("failed to list managed domains", err)
 	}
 	if utils.TryPrintJSON(domains) {
 		return nil
@@ -397,7 +397,7 @@ func handleManagedDomainsAdd(ctx context.Context, in domainsManagedAddInput) err
 	}
 	created, ns, err := api.CreateManagedDomain(userID, orgID, api.DomainCreateRequest{Name: domain, IPAddress: in.IP})
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to add managed domain: %s", err.Error()), nil)
+		return utils.NewError("failed to add managed domain", err)
 	}
 	if utils.TryPrintJSON(map[string]interface{}{"domain": created, "nameserver_status": ns}) {
 		return nil
@@ -422,7 +422,7 @@ func handleManagedDomainsVerify(ctx context.Context, in domainsManagedVerifyInpu
 	}
 	domain, ns, err := api.VerifyManagedDomain(userID, orgID, domainID)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to verify managed domain: %s", err.Error()), nil)
+		return utils.NewError("failed to verify managed domain", err)
 	}
 	if utils.TryPrintJSON(map[string]interface{}{"domain": domain, "nameserver_status": ns}) {
 		return nil
@@ -449,7 +449,7 @@ func handleManagedDomainsDelete(ctx context.Context, in domainsManagedDeleteInpu
 		return nil
 	}
 	if err := api.DeleteManagedDomain(userID, orgID, domainID); err != nil {
-		return utils.NewError(fmt.Sprintf("failed to delete managed domain: %s", err.Error()), nil)
+		return utils.NewError("failed to delete managed domain", err)
 	}
 	utils.PrintSuccess("Managed domain deleted")
 	return nil
@@ -471,7 +471,7 @@ func handleDNSList(ctx context.Context, in dnsListInput) error {
 	}
 	records, err := api.ListDNSRecords(userID, orgID, domainID)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to list DNS records: %s", err.Error()), nil)
+		return utils.NewError("failed to list DNS records", err)
 	}
 	if utils.TryPrintJSON(records) {
 		return nil
@@ -503,7 +503,7 @@ func handleDNSCreate(ctx context.Context, in dnsCreateInput) error {
 	}
 	record, err := api.CreateDNSRecord(userID, orgID, domainID, dnsCreateReqFromInput(in))
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to create DNS record: %s", err.Error()), nil)
+		return utils.NewError("failed to create DNS record", err)
 	}
 	if utils.TryPrintJSON(record) {
 		return nil
@@ -524,7 +524,7 @@ func handleDNSUpdate(ctx context.Context, in dnsUpdateInput) error {
 	}
 	record, err := api.UpdateDNSRecord(userID, orgID, domainID, in.RecordID, dnsUpdateReqFromInput(in))
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to update DNS record: %s", err.Error()), nil)
+		return utils.NewError("failed to update DNS record", err)
 	}
 	if utils.TryPrintJSON(record) {
 		return nil
@@ -548,7 +548,7 @@ func handleDNSDelete(ctx context.Context, in dnsDeleteInput) error {
 		return nil
 	}
 	if err := api.DeleteDNSRecord(userID, orgID, domainID, in.RecordID); err != nil {
-		return utils.NewError(fmt.Sprintf("failed to delete DNS record: %s", err.Error()), nil)
+		return utils.NewError("failed to delete DNS record", err)
 	}
 	utils.PrintSuccess("DNS record deleted")
 	return nil
@@ -587,7 +587,7 @@ func handleDomainsPurchase(ctx context.Context, in domainsPurchaseInput) error {
 		},
 	})
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to start domain purchase: %s", err.Error()), nil)
+		return utils.NewError("failed to start domain purchase", err)
 	}
 	if utils.TryPrintJSON(resp) {
 		return nil
@@ -610,7 +610,7 @@ func handleDomainsPurchaseStatus(ctx context.Context, in domainsPurchaseStatusIn
 	}
 	status, err := api.GetDomainPurchaseStatus(userID, orgID, in.IntentID)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to get purchase status: %s", err.Error()), nil)
+		return utils.NewError("failed to get purchase status", err)
 	}
 	if utils.TryPrintJSON(status) {
 		return nil
@@ -656,7 +656,7 @@ func resolveManagedDomainID(userID, orgID, value string) (string, error) {
 	}
 	domains, err := api.ListManagedDomains(userID, orgID)
 	if err != nil {
-		return "", utils.NewError(fmt.Sprintf("failed to resolve managed domain: %s", err.Error()), nil)
+		return "", utils.NewError("failed to resolve managed domain", err)
 	}
 	for _, domain := range domains {
 		if strings.EqualFold(domain.Name, name) {
@@ -16,6 +16,11 @@ const (
 	flagName         = "name"
 	flagEnv          = "env"
 	flagKey          = "key"
+	flagFile         = "file"
+	flagFormat       = "format"
+	flagPrune        = "prune"
+	flagYes          = "yes"
+	flagNoRestart    = "no-restart"
 )
 
 // --- Input structs ------------------------------------------------------
@@ -26,6 +31,7 @@ type envCreateInput struct {
 	Config       string
 	Name         string
 	Env          []string
+	NoRestart    bool
 }
 
 type envListInput struct {
@@ -38,6 +44,47 @@ type envUnsetInput struct {
 	App          string
 	Config       string
 	Key          string
+	NoRestart    bool
+}
+
+type envImportInput struct {
+	DeploymentID string
+	App          string
+	Config       string
+	File         string
+	Format       string
+	Prune        bool
+	Yes          bool
+}
+
+type envHistoryInput struct {
+	DeploymentID string
+	App          string
+	Config       string
+}
+
+type envDiffInput struct {
+	DeploymentID string
+	App          string
+	Config       string
+	From         string
+	To           string // empty compares against the live environment
+}
+
+type envRollbackInput struct {
+	DeploymentID string
+	App          string
+	Config       string
+	Version      string
+	Yes          bool
+	NoRestart    bool
+}
+
+type envExportInput struct {
+	DeploymentID string
+	App          string
+	Config       string
+	Format       string
 }
 
 // --- Command tree -------------------------------------------------------
@@ -54,6 +101,11 @@ Secrets are managed separately via "1ctl secret".`,
 			envCreateCommand(),
 			envListCommand(),
 			envUnsetCommand(),
+			envImportCommand(),
+			envExportCommand(),
+			envHistoryCommand(),
+			envDiffCommand(),
+			envRollbackCommand(),
 		},
 	}
 }
@@ -89,6 +141,11 @@ func envCreateCommand() *cli.Command {
 				Usage:       "Environment variables (format: KEY=VALUE)",
 				Destination: &in.Env,
 			},
+			&cli.BoolFlag{
+				Name:        flagNoRestart,
+				Usage:       "Stage the change without restarting (apply later with '1ctl app apply-config')",
+				Destination: &in.NoRestart,
+			},
 		},
 		Action: func(ctx context.Context, cmd *cli.Command) error { return handleCreateEnvironment(ctx, in) },
 	}
@@ -144,7 +201,209 @@ func envUnsetCommand() *cli.Command {
 				Required:    true,
 				Destination: &in.Key,
 			},
+			&cli.BoolFlag{
+				Name:        flagNoRestart,
+				Usage:       "Stage the change without restarting (apply later with '1ctl app apply-config')",
+				Destination: &in.NoRestart,
+			},
 		},
 		Action: func(ctx context.Context, cmd *cli.Command) error { return handleEnvUnset(ctx, in) },
 	}
 }
+
+func envImportCommand() *cli.Command {
+	var in envImportInput
+	return &cli.Command{
+		Name:  "import",
+		Usage: "Set environment variables from a dotenv or JSON file",
+		Description: `Apply every key in a dotenv or JSON file as one change, with a single restart.
+A key-level diff (values masked) is shown before confirmation. With --prune,
+keys not present in the file are removed.
+
+Examples:
+   1ctl env import --file .env.production
+   1ctl env import --file env.json --prune --yes`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        flagFile,
+				Aliases:     []string{"f"},
+				Usage:       "File to import (.env or .json)",
+				Required:    true,
+				Destination: &in.File,
+			},
+			&cli.StringFlag{
+				Name:        flagFormat,
+				Usage:       "File format: dotenv or json (default: from file extension)",
+				Destination: &in.Format,
+			},
+			&cli.BoolFlag{
+				Name:        flagPrune,
+				Usage:       "Remove keys that are not in the file",
+				Destination: &in.Prune,
+			},
+			&cli.BoolFlag{
+				Name:        flagYes,
+				Aliases:     []string{"y"},
+				Usage:       "Apply without confirmation",
+				Destination: &in.Yes,
+			},
+			&cli.StringFlag{
+				Name:        flagConfig,
+				Usage:       "Config name or path",
+				Destination: &in.Config,
+			},
+			&cli.StringFlag{
+				Name:        flagDeploymentID,
+				Aliases:     []string{"d"},
+				Usage:       "Deployment ID",
+				Destination: &in.DeploymentID,
+			},
+			&cli.StringFlag{
+				Name:        flagApp,
+				Usage:       "App name to resolve (alternative to --deployment-id)",
+				Destination: &in.App,
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error { return handleEnvImport(ctx, in) },
+	}
+}
+
+func envExportCommand() *cli.Command {
+	var in envExportInput
+	return &cli.Command{
+		Name:  "export",
+		Usage: "Print environment variables as dotenv, JSON or shell exports",
+		Description: `Print the environment variables of a deployment to stdout.
+
+Examples:
+   1ctl env export --app api > .env
+   1ctl env export --app api --format json
+   eval "$(1ctl env export --app api --format shell)"`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        flagFormat,
+				Usage:       "Output format: dotenv, json or shell",
+				Value:       "dotenv",
+				Destination: &in.Format,
+			},
+			&cli.StringFlag{
+				Name:        flagConfig,
+				Usage:       "Config name or path",
+				Destination: &in.Config,
+			},
+			&cli.StringFlag{
+				Name:        flagDeploymentID,
+				Aliases:     []string{"d"},
+				Usage:       "Deployment ID",
+				Destination: &in.DeploymentID,
+			},
+			&cli.StringFlag{
+				Name:        flagApp,
+				Usage:       "App name to resolve (alternative to --deployment-id)",
+				Destination: &in.App,
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error { return handleEnvExport(ctx, in) },
+	}
+}
+
+// deploymentFlags returns the --app, --config and --deployment-id flags
+// shared by the history commands.
+func deploymentFlags(app, config, deploymentID *string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:        flagApp,
+			Usage:       "App name to resolve (alternative to --deployment-id)",
+			Destination: app,
+		},
+		&cli.StringFlag{
+			Name:        flagConfig,
+			Usage:       "Config name or path",
+			Destination: config,
+		},
+		&cli.StringFlag{
+			Name:        flagDeploymentID,
+			Aliases:     []string{"d"},
+			Usage:       "Deployment ID",
+			Destination: deploymentID,
+		},
+	}
+}
+
+func envHistoryCommand() *cli.Command {
+	var in envHistoryInput
+	return &cli.Command{
+		Name:  "history",
+		Usage: "List recorded versions of a deployment's environment",
+		Description: `List the environment snapshots recorded for a deployment. Every change made
+with this CLI (env create, unset, import, rollback and deploy) records a new
+version in a journal under ~/.satusky/env-history/. Changes made elsewhere
+appear as an "observed" version the next time the environment is read.
+
+Examples:
+   1ctl env history --app api
+   1ctl env history --app api --output json`,
+		Flags:  deploymentFlags(&in.App, &in.Config, &in.DeploymentID),
+		Action: func(ctx context.Context, cmd *cli.Command) error { return handleEnvHistory(ctx, in) },
+	}
+}
+
+func envDiffCommand() *cli.Command {
+	var in envDiffInput
+	return &cli.Command{
+		Name:      "diff",
+		Usage:     "Show the changes between two environment versions",
+		ArgsUsage: "<version> [version]",
+		Description: `Show the keys added, changed and removed between two versions from
+"1ctl env history". With one version, compare it to the live environment.
+
+Examples:
+   1ctl env diff v3 v5 --app api
+   1ctl env diff v3 --app api`,
+		Flags: deploymentFlags(&in.App, &in.Config, &in.DeploymentID),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			if cmd.Args().Len() < 1 || cmd.Args().Len() > 2 {
+				return cli.ShowSubcommandHelp(cmd)
+			}
+			in.From = cmd.Args().Get(0)
+			in.To = cmd.Args().Get(1)
+			return handleEnvDiff(ctx, in)
+		},
+	}
+}
+
+func envRollbackCommand() *cli.Command {
+	var in envRollbackInput
+	return &cli.Command{
+		Name:      "rollback",
+		Usage:     "Restore the environment to a recorded version",
+		ArgsUsage: "<version>",
+		Description: `Re-apply a version from "1ctl env history": its values are upserted and keys
+added since are removed, then the deployment restarts. The rollback itself is
+recorded as a new version.
+
+Examples:
+   1ctl env rollback v3 --app api
+   1ctl env rollback v3 --app api --yes --no-restart`,
+		Flags: append(deploymentFlags(&in.App, &in.Config, &in.DeploymentID),
+			&cli.BoolFlag{
+				Name:        flagYes,
+				Aliases:     []string{"y"},
+				Usage:       "Apply without confirmation",
+				Destination: &in.Yes,
+			},
+			&cli.BoolFlag{
+				Name:        flagNoRestart,
+				Usage:       "Stage the change without restarting (apply later with '1ctl app apply-config')",
+				Destination: &in.NoRestart,
+			},
+		),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			if cmd.Args().Len() != 1 {
+				return cli.ShowSubcommandHelp(cmd)
+			}
+			in.Version = cmd.Args().First()
+			return handleEnvRollback(ctx, in)
+		},
+	}
+}
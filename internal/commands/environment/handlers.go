@@ -3,10 +3,13 @@ package environment
 import (
 	"context"
 	"fmt"
+	"os"
 	"strings"
 
 	"1ctl/internal/api"
 	"1ctl/internal/deploy"
+	"1ctl/internal/envfile"
+	"1ctl/internal/envhistory"
 	"1ctl/internal/utils"
 
 	"github.com/google/uuid"
@@ -15,14 +18,15 @@ import (
 // --- Handlers -----------------------------------------------------------
 
 func handleCreateEnvironment(ctx context.Context, in envCreateInput) error {
-	deploymentIDStr, err := deploy.ResolveDeploymentID(in.DeploymentID, in.App, in.Config)
+	client := api.FromContext(ctx)
+	deploymentIDStr, err := deploy.ResolveDeploymentID(client, in.DeploymentID, in.App, in.Config)
 	if err != nil {
 		return err
 	}
 
 	deploymentID, err := uuid.Parse(deploymentIDStr)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("invalid deployment-id: %s", err.Error()), nil)
+		return utils.NewError("invalid deployment-id", err)
 	}
 
 	keyValues := make([]api.KeyValuePair, 0, len(in.Env))
@@ -39,22 +43,27 @@ func handleCreateEnvironment(ctx context.Context, in envCreateInput) error {
 
 	appLabel := in.Name
 	if appLabel == "" {
-		deployment, err := api.GetDeployment(deploymentIDStr)
+		deployment, err := client.GetDeployment(deploymentIDStr)
 		if err != nil {
-			return utils.NewError(fmt.Sprintf("failed to resolve deployment name: %s", err.Error()), nil)
+			return utils.NewError("failed to resolve deployment name", err)
 		}
 		appLabel = deployment.AppLabel
 	}
 
+	_, before, err := liveEnvironment(client, deploymentIDStr)
+	if err != nil {
+		return err
+	}
+
 	env := api.Environment{
 		DeploymentID: deploymentID,
 		AppLabel:     appLabel,
 		KeyValues:    keyValues,
 	}
 
-	envResp, err := api.UpsertEnvironment(env)
+	envResp, err := client.UpsertEnvironment(env)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to upsert environment: %s", err.Error()), nil)
+		return utils.NewError("failed to upsert environment", err)
 	}
 
 	displayName := envResp.AppLabel
@@ -62,20 +71,24 @@ func handleCreateEnvironment(ctx context.Context, in envCreateInput) error {
 		displayName = appLabel
 	}
 	utils.PrintSuccess("Environment %s created successfully\n", displayName)
+	set := envfile.ToMap(keyValues)
+	recordHistory(deploymentIDStr, before, envfile.Apply(before, envfile.Diff(before, set, false), set), "env create")
+	deploy.RestartAfterChange(client, deploymentIDStr, displayName, "the environment", in.NoRestart)
 	return nil
 }
 
 func handleListEnvironments(ctx context.Context, in envListInput) error {
-	environments, err := api.ListEnvironments()
+	client := api.FromContext(ctx)
+	environments, err := client.ListEnvironments()
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to list environments: %s", err.Error()), nil)
+		return utils.NewError("failed to list environments", err)
 	}
 
 	// Filter by --app or --deployment-id if provided
 	if in.App != "" || in.DeploymentID != "" {
-		depID, err := deploy.ResolveDeploymentID(in.DeploymentID, in.App, "")
+		depID, err := deploy.ResolveDeploymentID(client, in.DeploymentID, in.App, "")
 		if err != nil {
-			return utils.NewError(fmt.Sprintf("failed to resolve deployment: %s", err.Error()), nil)
+			return utils.NewError("failed to resolve deployment", err)
 		}
 		var filtered []api.Environment
 		for _, env := range environments {
@@ -105,20 +118,150 @@ func handleListEnvironments(ctx context.Context, in envListInput) error {
 }
 
 func handleEnvUnset(ctx context.Context, in envUnsetInput) error {
-	deploymentID, err := deploy.ResolveDeploymentID(in.DeploymentID, in.App, in.Config)
+	client := api.FromContext(ctx)
+	deploymentID, err := deploy.ResolveDeploymentID(client, in.DeploymentID, in.App, in.Config)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to resolve deployment: %s", err.Error()), nil)
+		return utils.NewError("failed to resolve deployment", err)
 	}
 
-	envs, err := api.GetEnvironmentsByDeploymentID(deploymentID)
+	envs, err := client.GetEnvironmentsByDeploymentID(deploymentID)
 	if err != nil || len(envs) == 0 {
 		return utils.NewError("no environment found for this deployment", nil)
 	}
 
-	if err := api.UnsetEnvironmentKey(envs[0].EnvironmentID.String(), in.Key); err != nil {
-		return utils.NewError(fmt.Sprintf("failed to unset key: %s", err.Error()), nil)
+	if err := client.UnsetEnvironmentKey(envs[0].EnvironmentID.String(), in.Key); err != nil {
+		return utils.NewError("failed to unset key", err)
 	}
 
 	utils.PrintSuccess("Key %q removed from environment", in.Key)
+	before := envfile.ToMap(envs[0].KeyValues)
+	after := envfile.Apply(before, []envfile.Change{{Key: in.Key, Op: envfile.OpRemove}}, nil)
+	recordHistory(deploymentID, before, after, "env unset")
+	deploy.RestartAfterChange(client, deploymentID, envs[0].AppLabel, "the environment", in.NoRestart)
+	return nil
+}
+
+func handleEnvImport(ctx context.Context, in envImportInput) error {
+	client := api.FromContext(ctx)
+	desired, err := envfile.ReadFile(in.File, in.Format)
+	if err != nil {
+		return utils.NewError(fmt.Sprintf("failed to read %s: %s", in.File, err.Error()), nil)
+	}
+
+	deploymentIDStr, err := deploy.ResolveDeploymentID(client, in.DeploymentID, in.App, in.Config)
+	if err != nil {
+		return err
+	}
+	deploymentID, err := uuid.Parse(deploymentIDStr)
+	if err != nil {
+		return utils.NewError("invalid deployment-id", err)
+	}
+
+	existing, live, err := liveEnvironment(client, deploymentIDStr)
+	if err != nil {
+		return err
+	}
+	changes := envfile.Diff(live, desired, in.Prune)
+	if len(changes) == 0 {
+		utils.PrintSuccess("Environment already matches %s", in.File)
+		return nil
+	}
+
+	utils.PrintInfo("Environment changes from %s (%s):", in.File, envfile.Summary(changes))
+	envfile.PrintDiff(changes)
+	if !utils.Confirm("Apply these changes and restart the deployment?", in.Yes) {
+		fmt.Println("Aborted.")
+		return nil
+	}
+
+	appLabel, err := commitEnvChanges(client, deploymentID, existing, live, desired, changes, "env import")
+	if err != nil {
+		return err
+	}
+	utils.PrintSuccess("Environment updated: %s", envfile.Summary(changes))
+
+	deploy.RestartAfterChange(client, deploymentIDStr, appLabel, "the environment", false)
+	return nil
+}
+
+// liveEnvironment returns the deployment's environment bundle and its
+// values. Deploy and "env create" write to the first bundle; none yet
+// yields nil and an empty map. Any other failed read is returned, so it is
+// never mistaken for an empty environment.
+func liveEnvironment(client api.EnvironmentsAPI, deploymentID string) (*api.Environment, map[string]string, error) {
+	envs, err := client.GetEnvironmentsByDeploymentID(deploymentID)
+	if err != nil && utils.KindOf(err) != utils.ErrNotFound {
+		return nil, nil, utils.NewError("failed to read the current environment", err)
+	}
+	if len(envs) == 0 {
+		return nil, map[string]string{}, nil
+	}
+	return &envs[0], envfile.ToMap(envs[0].KeyValues), nil
+}
+
+// commitEnvChanges writes changes as one upsert plus an unset per removed
+// key, records the result in the history journal, and returns the app label.
+func commitEnvChanges(client api.API, deploymentID uuid.UUID, existing *api.Environment, live, desired map[string]string, changes []envfile.Change, source string) (string, error) {
+	appLabel := ""
+	if existing != nil {
+		appLabel = existing.AppLabel
+	}
+	if appLabel == "" {
+		deployment, err := client.GetDeployment(deploymentID.String())
+		if err != nil {
+			return "", utils.NewError("failed to resolve deployment name", err)
+		}
+		appLabel = deployment.AppLabel
+	}
+
+	if upserts := envfile.Upserts(changes, desired); len(upserts) > 0 {
+		resp, err := client.UpsertEnvironment(api.Environment{
+			DeploymentID: deploymentID,
+			AppLabel:     appLabel,
+			KeyValues:    upserts,
+		})
+		if err != nil {
+			return "", utils.NewError("failed to update environment", err)
+		}
+		if existing == nil {
+			existing = resp
+		}
+	}
+	for _, key := range envfile.Removals(changes) {
+		if err := client.UnsetEnvironmentKey(existing.EnvironmentID.String(), key); err != nil {
+			return "", utils.NewError(fmt.Sprintf("failed to remove key %q: %s", key, err.Error()), nil)
+		}
+	}
+	recordHistory(deploymentID.String(), live, envfile.Apply(live, changes, desired), source)
+	return appLabel, nil
+}
+
+// recordHistory journals an environment change. History is best effort: a
+// failure to write it only warns, since the change itself already happened.
+func recordHistory(deploymentID string, before, after map[string]string, source string) {
+	journal, err := envhistory.Open(deploymentID)
+	if err == nil {
+		_, err = journal.Track(before, after, source)
+	}
+	if err != nil {
+		utils.PrintWarning("Failed to record environment history: %s", err.Error())
+	}
+}
+
+func handleEnvExport(ctx context.Context, in envExportInput) error {
+	client := api.FromContext(ctx)
+	deploymentID, err := deploy.ResolveDeploymentID(client, in.DeploymentID, in.App, in.Config)
+	if err != nil {
+		return utils.NewError("failed to resolve deployment", err)
+	}
+
+	envs, err := client.GetEnvironmentsByDeploymentID(deploymentID)
+	if err != nil || len(envs) == 0 {
+		return utils.NewError("no environment found for this deployment", nil)
+	}
+
+	if err := envfile.Write(os.Stdout, envfile.ToMap(envs[0].KeyValues), in.Format); err != nil {
+		return utils.NewError(err.Error(), nil)
+	}
 	return nil
 }
@@ -0,0 +1,127 @@
+package environment
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"1ctl/internal/api"
+	"1ctl/internal/api/apitest"
+	satuskyctx "1ctl/internal/context"
+	"1ctl/internal/envfile"
+)
+
+// useTestProfile points the env history journal at a temporary profile.
+func useTestProfile(t *testing.T) {
+	t.Helper()
+	original := satuskyctx.Default()
+	t.Cleanup(func() { satuskyctx.SetDefault(original) })
+	store := satuskyctx.NewTestStore(t.TempDir())
+	store.SetProfileOverride("test")
+	satuskyctx.SetDefault(store)
+}
+
+func writeEnvFile(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// writes returns the requests that changed something.
+func writes(srv *apitest.Server) []string {
+	var out []string
+	for _, r := range srv.Requests() {
+		if !strings.HasPrefix(r, http.MethodGet+" ") {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func liveValues(t *testing.T, srv *apitest.Server) map[string]string {
+	t.Helper()
+	envs := srv.Environments()
+	if len(envs) != 1 {
+		t.Fatalf("environments = %+v, want one bundle", envs)
+	}
+	return envfile.ToMap(envs[0].KeyValues)
+}
+
+func TestEnvImportAndRollbackAgainstFakeAPI(t *testing.T) {
+	useTestProfile(t)
+	if err := satuskyctx.SetCurrentNamespace("acme"); err != nil {
+		t.Fatal(err)
+	}
+	srv := apitest.NewServer(t)
+	ctx := api.WithClient(context.Background(), srv.Client())
+	dep := srv.AddDeployment(api.Deployment{Namespace: "acme", AppLabel: "web", Image: "web:1"})
+	id := dep.DeploymentID.String()
+
+	// --app is looked up on the injected client too.
+	if err := handleEnvImport(ctx, envImportInput{App: "web", File: writeEnvFile(t, "A=1\nB=2\n"), Yes: true}); err != nil {
+		t.Fatalf("first import: %v", err)
+	}
+	if got := liveValues(t, srv); len(got) != 2 || got["A"] != "1" || got["B"] != "2" {
+		t.Errorf("environment after import = %v", got)
+	}
+	if got := writes(srv); len(got) != 2 || got[1] != "POST /deployments/"+id+"/restart" {
+		t.Errorf("writes after import = %v, want an upsert and one restart", got)
+	}
+
+	if err := handleEnvImport(ctx, envImportInput{DeploymentID: id, File: writeEnvFile(t, "A=3\n"), Prune: true, Yes: true}); err != nil {
+		t.Fatalf("pruning import: %v", err)
+	}
+	if got := liveValues(t, srv); len(got) != 1 || got["A"] != "3" {
+		t.Errorf("environment after a pruning import = %v, want only A=3", got)
+	}
+
+	if err := handleEnvRollback(ctx, envRollbackInput{DeploymentID: id, Version: "v2", Yes: true}); err != nil {
+		t.Fatalf("rollback: %v", err)
+	}
+	if got := liveValues(t, srv); len(got) != 2 || got["A"] != "1" || got["B"] != "2" {
+		t.Errorf("environment after rollback = %v, want A=1 and B=2", got)
+	}
+}
+
+func TestEnvChangesAbortWhenTheLiveEnvironmentCannotBeRead(t *testing.T) {
+	useTestProfile(t)
+	srv := apitest.NewServer(t)
+	ctx := api.WithClient(context.Background(), srv.Client())
+	dep := srv.AddDeployment(api.Deployment{Namespace: "acme", AppLabel: "web", Image: "web:1"})
+	id := dep.DeploymentID.String()
+	if err := handleEnvImport(ctx, envImportInput{DeploymentID: id, File: writeEnvFile(t, "A=1\n"), Yes: true}); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	before := len(writes(srv))
+
+	srv.Fail(http.MethodGet, "/environments/deploymentId/"+id, http.StatusInternalServerError, "database unavailable")
+	steps := map[string]func() error{
+		"import": func() error {
+			return handleEnvImport(ctx, envImportInput{DeploymentID: id, File: writeEnvFile(t, "B=2\n"), Prune: true, Yes: true})
+		},
+		"create": func() error {
+			return handleCreateEnvironment(ctx, envCreateInput{DeploymentID: id, Name: "web", Env: []string{"B=2"}})
+		},
+		"rollback": func() error {
+			return handleEnvRollback(ctx, envRollbackInput{DeploymentID: id, Version: "v1", Yes: true})
+		},
+	}
+	for name, step := range steps {
+		err := step()
+		if err == nil || !strings.Contains(err.Error(), "failed to read the current environment") {
+			t.Errorf("%s: error = %v, want the failed read", name, err)
+		}
+	}
+	if got := writes(srv); len(got) != before {
+		t.Errorf("writes after failed reads = %v, want none after the first %d", got, before)
+	}
+	if got := liveValues(t, srv); len(got) != 1 || got["A"] != "1" {
+		t.Errorf("environment = %v, want A=1 untouched", got)
+	}
+}
@@ -0,0 +1,182 @@
+package environment
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"1ctl/internal/api"
+	"1ctl/internal/deploy"
+	"1ctl/internal/envfile"
+	"1ctl/internal/envhistory"
+	"1ctl/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+func handleEnvHistory(ctx context.Context, in envHistoryInput) error {
+	client := api.FromContext(ctx)
+	deploymentID, err := deploy.ResolveDeploymentID(client, in.DeploymentID, in.App, in.Config)
+	if err != nil {
+		return err
+	}
+	journal, err := openJournal(deploymentID)
+	if err != nil {
+		return err
+	}
+
+	// Pick up edits made elsewhere so the latest version matches what runs.
+	_, live, err := liveEnvironment(client, deploymentID)
+	if err != nil {
+		return err
+	}
+	if len(live) > 0 {
+		if _, err := journal.Record(live, envhistory.SourceObserved); err != nil {
+			utils.PrintWarning("Failed to record environment history: %s", err.Error())
+		}
+	}
+
+	snaps, err := journal.Snapshots()
+	if err != nil {
+		return utils.NewError("failed to read environment history", err)
+	}
+	if utils.PrintListOrJSON(snaps, "No environment history recorded for this deployment") {
+		return nil
+	}
+
+	rows := make([][]string, 0, len(snaps))
+	prev := map[string]string{}
+	for _, s := range snaps {
+		changes := "initial"
+		if s.Version > snaps[0].Version {
+			changes = envfile.Summary(envfile.Diff(prev, s.Values, true))
+		}
+		actor := s.Actor
+		if actor == "" {
+			actor = "-"
+		}
+		rows = append(rows, []string{
+			"v" + strconv.Itoa(s.Version),
+			utils.FormatTimeAgo(s.Time),
+			actor,
+			s.Source,
+			strconv.Itoa(len(s.Values)),
+			changes,
+		})
+		prev = s.Values
+	}
+	// Newest first, as "1ctl deploy list" shows deployments.
+	for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+		rows[i], rows[j] = rows[j], rows[i]
+	}
+	utils.PrintTable([]string{"VERSION", "WHEN", "BY", "SOURCE", "KEYS", "CHANGES"}, rows)
+	return nil
+}
+
+func handleEnvDiff(ctx context.Context, in envDiffInput) error {
+	client := api.FromContext(ctx)
+	deploymentID, err := deploy.ResolveDeploymentID(client, in.DeploymentID, in.App, in.Config)
+	if err != nil {
+		return err
+	}
+	journal, err := openJournal(deploymentID)
+	if err != nil {
+		return err
+	}
+
+	from, err := snapshot(journal, in.From)
+	if err != nil {
+		return err
+	}
+	toLabel := "live"
+	var to map[string]string
+	if in.To == "" {
+		if _, to, err = liveEnvironment(client, deploymentID); err != nil {
+			return err
+		}
+	} else {
+		snap, err := snapshot(journal, in.To)
+		if err != nil {
+			return err
+		}
+		to = snap.Values
+		toLabel = "v" + strconv.Itoa(snap.Version)
+	}
+
+	changes := envfile.Diff(from.Values, to, true)
+	if len(changes) == 0 {
+		utils.PrintSuccess("No differences between v%d and %s", from.Version, toLabel)
+		return nil
+	}
+	utils.PrintInfo("Changes from v%d to %s (%s):", from.Version, toLabel, envfile.Summary(changes))
+	envfile.PrintValueDiff(changes, from.Values, to)
+	return nil
+}
+
+func handleEnvRollback(ctx context.Context, in envRollbackInput) error {
+	client := api.FromContext(ctx)
+	deploymentIDStr, err := deploy.ResolveDeploymentID(client, in.DeploymentID, in.App, in.Config)
+	if err != nil {
+		return err
+	}
+	deploymentID, err := uuid.Parse(deploymentIDStr)
+	if err != nil {
+		return utils.NewError("invalid deployment-id", err)
+	}
+	journal, err := openJournal(deploymentIDStr)
+	if err != nil {
+		return err
+	}
+	snap, err := snapshot(journal, in.Version)
+	if err != nil {
+		return err
+	}
+
+	existing, live, err := liveEnvironment(client, deploymentIDStr)
+	if err != nil {
+		return err
+	}
+	changes := envfile.Diff(live, snap.Values, true)
+	if len(changes) == 0 {
+		utils.PrintSuccess("Environment already matches v%d", snap.Version)
+		return nil
+	}
+
+	utils.PrintInfo("Rolling back to v%d (%s):", snap.Version, envfile.Summary(changes))
+	envfile.PrintValueDiff(changes, live, snap.Values)
+	if !utils.Confirm("Apply these changes?", in.Yes) {
+		fmt.Println("Aborted.")
+		return nil
+	}
+
+	source := fmt.Sprintf("rollback v%d", snap.Version)
+	appLabel, err := commitEnvChanges(client, deploymentID, existing, live, snap.Values, changes, source)
+	if err != nil {
+		return err
+	}
+	utils.PrintSuccess("Environment rolled back to v%d", snap.Version)
+
+	deploy.RestartAfterChange(client, deploymentIDStr, appLabel, "the environment", in.NoRestart)
+	return nil
+}
+
+func openJournal(deploymentID string) (*envhistory.Journal, error) {
+	journal, err := envhistory.Open(deploymentID)
+	if err != nil {
+		return nil, utils.NewError(err.Error(), nil)
+	}
+	return journal, nil
+}
+
+// snapshot parses a version argument such as "v3" and loads it.
+func snapshot(journal *envhistory.Journal, arg string) (*envhistory.Snapshot, error) {
+	v, err := envhistory.ParseVersion(arg)
+	if err != nil {
+		return nil, utils.NewError(err.Error(), nil)
+	}
+	snap, err := journal.Version(v)
+	if err != nil {
+		return nil, utils.NewError(err.Error(), nil)
+	}
+	return snap, nil
+}
@@ -13,6 +13,7 @@ import (
 // --- Handlers -----------------------------------------------------------
 
 func handleUpsertIngress(ctx context.Context, in ingressUpsertInput) error {
+	client := api.FromContext(ctx)
 	if in.DeploymentID == "" {
 		return utils.NewError("--deployment-id flag is required for ingress", nil)
 	}
@@ -28,7 +29,7 @@ func handleUpsertIngress(ctx context.Context, in ingressUpsertInput) error {
 
 	deploymentID, err := uuid.Parse(in.DeploymentID)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("invalid deployment-id: %s", err.Error()), nil)
+		return utils.NewError("invalid deployment-id", err)
 	}
 
 	if in.ServiceID == "" {
@@ -36,7 +37,7 @@ func handleUpsertIngress(ctx context.Context, in ingressUpsertInput) error {
 	}
 	serviceID, err := uuid.Parse(in.ServiceID)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("invalid service-id: %s", err.Error()), nil)
+		return utils.NewError("invalid service-id", err)
 	}
 
 	port, err := api.SafeInt32(in.Port)
@@ -59,9 +60,9 @@ func handleUpsertIngress(ctx context.Context, in ingressUpsertInput) error {
 		DnsConfig:    dnsConfig,
 	}
 
-	ingressResp, err := api.UpsertIngress(ingress)
+	ingressResp, err := client.UpsertIngress(ingress)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to upsert ingress: %s", err.Error()), nil)
+		return utils.NewError("failed to upsert ingress", err)
 	}
 
 	utils.PrintSuccess("Ingress for domain %s upserted successfully\n", ingressResp.DomainName)
@@ -69,9 +70,10 @@ func handleUpsertIngress(ctx context.Context, in ingressUpsertInput) error {
 }
 
 func handleListIngresses(ctx context.Context) error {
-	ingresses, err := api.ListIngresses()
+	client := api.FromContext(ctx)
+	ingresses, err := client.ListIngresses()
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to list ingresses: %s", err.Error()), nil)
+		return utils.NewError("failed to list ingresses", err)
 	}
 
 	if utils.PrintListOrJSON(ingresses, "No ingresses found") {
@@ -94,12 +96,13 @@ func handleListIngresses(ctx context.Context) error {
 }
 
 func handleDeleteIngress(ctx context.Context, in ingressDeleteInput) error {
+	client := api.FromContext(ctx)
 	if !utils.Confirm(fmt.Sprintf("Delete ingress %s? This cannot be undone.", in.IngressID), in.Yes) {
 		fmt.Println("Aborted.")
 		return nil
 	}
-	if err := api.DeleteIngress(in.IngressID); err != nil {
-		return utils.NewError(fmt.Sprintf("failed to delete ingress: %s", err.Error()), nil)
+	if err := client.DeleteIngress(in.IngressID); err != nil {
+		return utils.NewError("failed to delete ingress", err)
 	}
 
 	utils.PrintSuccess("Ingress %s deleted successfully\n", in.IngressID)
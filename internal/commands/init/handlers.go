@@ -33,7 +33,7 @@ func handleInit(ctx context.Context, in initInput) error {
 
 	dir, err := os.Getwd()
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to get working directory: %s", err.Error()), nil)
+		return utils.NewError("failed to get working directory", err)
 	}
 	if base.App.Name == "" {
 		base.App.Name = filepath.Base(dir)
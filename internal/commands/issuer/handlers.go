@@ -2,7 +2,6 @@ package issuer
 
 import (
 	"context"
-	"fmt"
 
 	"1ctl/internal/api"
 	satuskyctx "1ctl/internal/context"
@@ -20,7 +19,7 @@ func handleCreateIssuer(ctx context.Context, in issuerCreateInput) error {
 
 	deploymentID, err := uuid.Parse(in.DeploymentID)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("invalid deployment-id: %s", err.Error()), nil)
+		return utils.NewError("invalid deployment-id", err)
 	}
 
 	issuer := api.Issuer{
@@ -32,7 +31,7 @@ func handleCreateIssuer(ctx context.Context, in issuerCreateInput) error {
 
 	resp, err := api.CreateIssuer(issuer)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to create issuer: %s", err.Error()), nil)
+		return utils.NewError("failed to create issuer", err)
 	}
 
 	utils.PrintSuccess("Certificate issuer created successfully (ID: %s)\n", resp.IssuerID)
@@ -41,7 +40,7 @@ func handleCreateIssuer(ctx context.Context, in issuerCreateInput) error {
 
 func handleDeleteIssuer(ctx context.Context, in issuerDeleteInput) error {
 	if err := api.DeleteIssuer(in.IssuerID); err != nil {
-		return utils.NewError(fmt.Sprintf("failed to delete issuer: %s", err.Error()), nil)
+		return utils.NewError("failed to delete issuer", err)
 	}
 	utils.PrintSuccess("Certificate issuer %s deleted successfully", in.IssuerID)
 	return nil
@@ -50,7 +49,7 @@ func handleDeleteIssuer(ctx context.Context, in issuerDeleteInput) error {
 func handleListIssuers(ctx context.Context) error {
 	issuers, err := api.ListIssuers()
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to list issuers: %s", err.Error()), nil)
+		return utils.NewError("failed to list issuers", err)
 	}
 
 	if utils.PrintListOrJSON(issuers, "No certificate issuers found") {
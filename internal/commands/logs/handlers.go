@@ -7,16 +7,13 @@ import (
 
 	"1ctl/internal/api"
 	"1ctl/internal/deploy"
-	satuskyctx "1ctl/internal/context"
 	"1ctl/internal/utils"
-
-	gorillaws "github.com/gorilla/websocket"
 )
 
 // --- Handlers -----------------------------------------------------------
 
 func handleLogs(ctx context.Context, in logsInput) error {
-	deploymentID, err := deploy.ResolveDeploymentID(in.DeploymentID, in.App, in.Config)
+	deploymentID, err := deploy.ResolveDeploymentID(api.FromContext(ctx), in.DeploymentID, in.App, in.Config)
 	if err != nil {
 		return err
 	}
@@ -25,7 +22,7 @@ func handleLogs(ctx context.Context, in logsInput) error {
 
 	logs, meta, err := api.GetStoredLogs(deploymentID, tail)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to get logs: %s", err.Error()), nil)
+		return utils.NewError("failed to get logs", err)
 	}
 
 	if meta != nil {
@@ -69,7 +66,7 @@ func handleLogsStream(ctx context.Context, in logsStreamInput) error {
 
 	// Resolve deployment-id from --config if not provided directly
 	if in.DeploymentID == "" && in.Namespace == "" {
-		id, err := deploy.ResolveDeploymentID("", in.App, in.Config)
+		id, err := deploy.ResolveDeploymentID(api.FromContext(ctx), "", in.App, in.Config)
 		if err == nil && id != "" {
 			in.DeploymentID = id
 		}
@@ -79,7 +76,7 @@ func handleLogsStream(ctx context.Context, in logsStreamInput) error {
 	if in.DeploymentID != "" {
 		deployment, err := api.GetDeployment(in.DeploymentID)
 		if err != nil {
-			return utils.NewError(fmt.Sprintf("failed to get deployment: %s", err.Error()), nil)
+			return utils.NewError("failed to get deployment", err)
 		}
 		namespace = deployment.Namespace
 		appLabel = deployment.AppLabel
@@ -99,12 +96,16 @@ func handleLogsStream(ctx context.Context, in logsStreamInput) error {
 		wsURL = fmt.Sprintf("%s?batchSize=%d", wsURL, batchSize)
 	}
 
+	token, err := api.AuthToken()
+	if err != nil {
+		return err
+	}
 	headers := http.Header{}
-	headers.Set("x-satusky-api-key", satuskyctx.GetToken())
+	headers.Set("x-satusky-api-key", token)
 
-	conn, _, err := gorillaws.DefaultDialer.Dial(wsURL, headers)
+	conn, err := api.DialWebSocket(wsURL, headers)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to connect to log stream: %s", err.Error()), nil)
+		return utils.NewError("failed to connect to log stream", err)
 	}
 	defer conn.Close() //nolint:errcheck // cleanup on exit, error unactionable
 
@@ -19,7 +19,7 @@ func handleMachineList(ctx context.Context) error {
 
 	machines, err := api.GetMachinesByOwnerID(api.ToUUID(userID))
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to list machines: %s", err.Error()), nil)
+		return utils.NewError("failed to list machines", err)
 	}
 
 	if len(machines) == 0 {
@@ -65,7 +65,7 @@ func handleMachineCreate(ctx context.Context, in machineCreateInput) error {
 	machine := machineFromInput(&in, nil)
 	id, err := api.CreateMachine(machine)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to create machine: %s", err.Error()), nil)
+		return utils.NewError("failed to create machine", err)
 	}
 	if utils.TryPrintJSON(map[string]interface{}{"id": id}) {
 		return nil
@@ -82,7 +82,7 @@ func handleMachineUpdate(ctx context.Context, in machineUpdateInput) error {
 	}
 	updated := machineFromInput(&in.machineCreateInput, machine)
 	if err := api.UpdateMachine(machine.MachineID, updated); err != nil {
-		return utils.NewError(fmt.Sprintf("failed to update machine: %s", err.Error()), nil)
+		return utils.NewError("failed to update machine", err)
 	}
 	utils.PrintSuccess("Machine updated")
 	utils.PrintStatusLine("Machine ID", machine.MachineID)
@@ -99,7 +99,7 @@ func handleMachineDelete(ctx context.Context, in machineDeleteInput) error {
 		return nil
 	}
 	if err := api.DeleteMachine(machine.MachineID); err != nil {
-		return utils.NewError(fmt.Sprintf("failed to delete machine: %s", err.Error()), nil)
+		return utils.NewError("failed to delete machine", err)
 	}
 	utils.PrintSuccess("Machine decommissioned")
 	return nil
@@ -184,7 +184,7 @@ func handleMachineLogs(ctx context.Context, in machineLogsInput) error {
 		IncludePrevLog: in.Previous,
 	})
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to fetch machine logs: %s", err.Error()), nil)
+		return utils.NewError("failed to fetch machine logs", err)
 	}
 	if utils.TryPrintJSON(logs) {
 		return nil
@@ -213,7 +213,7 @@ func handleMachineEvents(ctx context.Context, in machineEventsInput) error {
 	}
 	events, err := api.GetMachineEvents(machine.MachineID, in.Tail)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to fetch machine events: %s", err.Error()), nil)
+		return utils.NewError("failed to fetch machine events", err)
 	}
 	if utils.TryPrintJSON(events) {
 		return nil
@@ -234,7 +234,7 @@ func handleMachineEvents(ctx context.Context, in machineEventsInput) error {
 func handleMachineAvailable(ctx context.Context, in machineAvailableInput) error {
 	machines, err := api.GetAvailableMachines()
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to list available machines: %s", err.Error()), nil)
+		return utils.NewError("failed to list available machines", err)
 	}
 
 	filteredMachines := filterMachines(machines, in)
@@ -260,7 +260,7 @@ func handleMachineUsageList(ctx context.Context) error {
 
 	usages, err := api.GetUserMachineUsages(userID)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to get machine usages: %s", err.Error()), nil)
+		return utils.NewError("failed to get machine usages", err)
 	}
 
 	if len(usages) == 0 {
@@ -287,7 +287,7 @@ func handleMachineUsageList(ctx context.Context) error {
 func handleMachineUsageGet(ctx context.Context, in machineUsageIDInput) error {
 	usage, err := api.GetMachineUsageByID(in.UsageID)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to get usage record: %s", err.Error()), nil)
+		return utils.NewError("failed to get usage record", err)
 	}
 
 	utils.PrintHeader("Machine Usage Record")
@@ -312,7 +312,7 @@ func handleMachineUsageGet(ctx context.Context, in machineUsageIDInput) error {
 func handleMachineUsageCost(ctx context.Context, in machineUsageIDInput) error {
 	cost, err := api.GetUsageCost(in.UsageID)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to get usage cost: %s", err.Error()), nil)
+		return utils.NewError("failed to get usage cost", err)
 	}
 
 	utils.PrintHeader("Usage Cost")
@@ -522,7 +522,7 @@ func printMapStatus(values map[string]interface{}) {
 func handleMachineLabelsList(ctx context.Context, machineID string) error {
 	labels, err := api.GetMachineLabels(machineID)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to get labels: %s", err.Error()), nil)
+		return utils.NewError("failed to get labels", err)
 	}
 	if len(labels) == 0 {
 		utils.PrintInfo("No labels found on this machine")
@@ -550,7 +550,7 @@ func handleMachineLabelsSet(ctx context.Context, machineID string, pairs []strin
 		labels[key] = parts[1]
 	}
 	if err := api.UpdateMachineLabels(machineID, labels); err != nil {
-		return utils.NewError(fmt.Sprintf("failed to set labels: %s", err.Error()), nil)
+		return utils.NewError("failed to set labels", err)
 	}
 	utils.PrintSuccess("Labels updated on machine %s", machineID)
 	return handleMachineLabelsList(ctx, machineID)
@@ -563,7 +563,7 @@ func handleMachineLabelsUnset(ctx context.Context, machineID, key string) error
 	}
 	labels := map[string]string{key: ""}
 	if err := api.UpdateMachineLabels(machineID, labels); err != nil {
-		return utils.NewError(fmt.Sprintf("failed to unset label: %s", err.Error()), nil)
+		return utils.NewError("failed to unset label", err)
 	}
 	utils.PrintSuccess("Label %q removed from machine %s", key, machineID)
 	return handleMachineLabelsList(ctx, machineID)
@@ -572,7 +572,7 @@ func handleMachineLabelsUnset(ctx context.Context, machineID, key string) error
 func handleMachineLabelsKeys(ctx context.Context) error {
 	keys, err := api.GetAvailableLabelKeys()
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to get label keys: %s", err.Error()), nil)
+		return utils.NewError("failed to get label keys", err)
 	}
 	if len(keys) == 0 {
 		utils.PrintInfo("No label keys found")
@@ -17,15 +17,19 @@ const (
 	flagStorageSize  = "storage-size"
 	flagLimit        = "limit"
 	flagOffset       = "offset"
+	flagAll          = "all"
+	flagPageSize     = "page-size"
 	flagSort         = "sort"
 )
 
 // --- Input structs ------------------------------------------------------
 
 type marketplaceListInput struct {
-	Limit  int
-	Offset int
-	Sort   string
+	Limit    int
+	All      bool
+	PageSize int
+	Offset   int
+	Sort     string
 }
 
 type marketplaceDeployInput struct {
@@ -62,13 +66,23 @@ func marketplaceListCommand() *cli.Command {
 		Flags: []cli.Flag{
 			&cli.IntFlag{
 				Name:        flagLimit,
-				Usage:       "Number of apps to show",
+				Usage:       "Number of apps to show, fetching more pages as needed",
 				Destination: &in.Limit,
 				Value:       25,
 			},
+			&cli.BoolFlag{
+				Name:        flagAll,
+				Usage:       "Show every app, fetching all pages (up to --limit if also given)",
+				Destination: &in.All,
+			},
+			&cli.IntFlag{
+				Name:        flagPageSize,
+				Usage:       "Number of apps to fetch per request (default: --limit, or the API's page size)",
+				Destination: &in.PageSize,
+			},
 			&cli.IntFlag{
 				Name:        flagOffset,
-				Usage:       "Offset for pagination",
+				Usage:       "Number of apps to skip",
 				Destination: &in.Offset,
 				Value:       0,
 			},
@@ -79,6 +93,9 @@ func marketplaceListCommand() *cli.Command {
 			},
 		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
+			if in.All && !cmd.IsSet(flagLimit) {
+				in.Limit = 0
+			}
 			return handleMarketplaceList(ctx, in)
 		},
 	}
@@ -3,6 +3,7 @@ package marketplace
 import (
 	"context"
 	"fmt"
+	"os"
 
 	"1ctl/internal/api"
 	satuskyctx "1ctl/internal/context"
@@ -11,16 +12,19 @@ import (
 )
 
 func handleMarketplaceList(ctx context.Context, in marketplaceListInput) error {
-	apps, err := api.GetMarketplaceApps(in.Limit, in.Offset, in.Sort)
-	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to list marketplace apps: %s", err.Error()), nil)
+	stream := utils.NewListStream("No marketplace apps available", printMarketplaceApps)
+	opts := api.PageOptions{All: in.All, Limit: in.Limit, PageSize: in.PageSize}
+	if err := api.ListMarketplaceApps(in.Offset, in.Sort, opts, stream.Add); err != nil {
+		return utils.NewError("failed to list marketplace apps", err)
 	}
+	stream.Close()
+	return nil
+}
 
-	if utils.PrintListOrJSON(apps, "No marketplace apps available") {
-		return nil
+func printMarketplaceApps(apps []api.MarketplaceApp, first bool) {
+	if first {
+		utils.PrintHeader("Marketplace Apps")
 	}
-
-	utils.PrintHeader("Marketplace Apps")
 	for _, app := range apps {
 		status := "Available"
 		if app.ComingSoon {
@@ -37,7 +41,6 @@ func handleMarketplaceList(ctx context.Context, in marketplaceListInput) error {
 		}
 		utils.PrintDivider()
 	}
-	return nil
 }
 
 func handleMarketplaceGet(ctx context.Context, nameOrID string) error {
@@ -108,10 +111,11 @@ func handleMarketplaceDeploy(ctx context.Context, in marketplaceDeployInput) err
 
 	resp, err := api.DeployMarketplaceApp(namespace, app.MarketplaceID.String(), req)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to deploy marketplace app: %s", err.Error()), nil)
+		return utils.NewError("failed to deploy marketplace app", err)
 	}
 
-	ingressID := deploypkg.ResolveIngressID(resp.DeploymentID.String())
-	publicURL := deploypkg.WaitForPublicURL(ingressID, resp.Domain)
-	return deploypkg.ReportDeployResult(resp.AppLabel, resp.DeploymentID.String(), resp.Domain, publicURL, "", true)
+	client := api.FromContext(ctx)
+	ingressID := deploypkg.ResolveIngressID(client, resp.DeploymentID.String())
+	publicURL := deploypkg.WaitForPublicURL(client, os.Stdout, ingressID, resp.Domain)
+	return deploypkg.ReportDeployResult(os.Stdout, resp.AppLabel, resp.DeploymentID.String(), resp.Domain, publicURL, "", true)
 }
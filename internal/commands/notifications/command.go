@@ -13,17 +13,20 @@ import (
 // --- Flag name constants ------------------------------------------------
 const (
 	flagUnread = "unread"
-	flagLimit  = "limit"
-	flagID     = "id"
-	flagAll    = "all"
-	flagYes    = "yes"
+	flagLimit    = "limit"
+	flagPageSize = "page-size"
+	flagID       = "id"
+	flagAll      = "all"
+	flagYes      = "yes"
 )
 
 // --- Input structs ------------------------------------------------------
 
 type notifListInput struct {
-	Unread bool
-	Limit  int
+	Unread   bool
+	Limit    int
+	All      bool
+	PageSize int
 }
 
 type notifReadInput struct {
@@ -66,12 +69,25 @@ func notifListCommand() *cli.Command {
 			},
 			&cli.IntFlag{
 				Name:        flagLimit,
-				Usage:       "Number of notifications to show",
+				Usage:       "Number of notifications to show, fetching more pages as needed",
 				Value:       20,
 				Destination: &in.Limit,
 			},
+			&cli.BoolFlag{
+				Name:        flagAll,
+				Usage:       "Show every notification, fetching all pages (up to --limit if also given)",
+				Destination: &in.All,
+			},
+			&cli.IntFlag{
+				Name:        flagPageSize,
+				Usage:       "Number of notifications to fetch per request (default: --limit, or the API's page size)",
+				Destination: &in.PageSize,
+			},
 		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
+			if in.All && !cmd.IsSet(flagLimit) {
+				in.Limit = 0
+			}
 			return handleNotifList(ctx, in)
 		},
 	}
@@ -17,32 +17,42 @@ func handleNotifList(ctx context.Context, in notifListInput) error {
 		return utils.NewError("organization ID not found. Please run '1ctl auth login' first", nil)
 	}
 
-	notifications, err := api.GetNotifications(orgID, in.Unread, in.Limit)
-	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to get notifications: %s", err.Error()), nil)
-	}
-
-	if utils.PrintListOrJSON(notifications, "No notifications found") {
-		return nil
+	stream := utils.NewListStream("No notifications found", notifTablePrinter())
+	opts := api.PageOptions{All: in.All, Limit: in.Limit, PageSize: in.PageSize}
+	if err := api.ListNotifications(orgID, in.Unread, opts, stream.Add); err != nil {
+		return utils.NewError("failed to get notifications", err)
 	}
+	stream.Close()
+	return nil
+}
 
-	headers := []string{"TYPE", "SUBJECT", "PRIORITY", "STATUS", "CREATED"}
-	rows := make([][]string, 0, len(notifications))
-	for _, notif := range notifications {
-		readStatus := "unread"
-		if notif.ReadAt != nil {
-			readStatus = "read"
+// notifTablePrinter prints pages of notifications as one table, its columns
+// sized by the first page.
+func notifTablePrinter() func([]api.Notification, bool) {
+	config := utils.DefaultTableConfig()
+	config.Headers = []string{"TYPE", "SUBJECT", "PRIORITY", "STATUS", "CREATED"}
+	return func(notifications []api.Notification, first bool) {
+		rows := make([][]string, 0, len(notifications))
+		for _, notif := range notifications {
+			readStatus := "unread"
+			if notif.ReadAt != nil {
+				readStatus = "read"
+			}
+			rows = append(rows, []string{
+				notif.Type,
+				notif.Subject,
+				notif.Priority,
+				readStatus,
+				utils.FormatTimeAgo(notif.CreatedAt),
+			})
 		}
-		rows = append(rows, []string{
-			notif.Type,
-			notif.Subject,
-			notif.Priority,
-			readStatus,
-			utils.FormatTimeAgo(notif.CreatedAt),
-		})
+		if first {
+			config.MinWidths = utils.ColumnWidths(config.Headers, rows)
+		} else {
+			config.NoHeader = true
+		}
+		utils.PrintTableWithConfig(rows, config)
 	}
-	utils.PrintTable(headers, rows)
-	return nil
 }
 
 func handleNotifCount(ctx context.Context) error {
@@ -53,7 +63,7 @@ func handleNotifCount(ctx context.Context) error {
 
 	count, err := api.GetUnreadCount(orgID)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to get unread count: %s", err.Error()), nil)
+		return utils.NewError("failed to get unread count", err)
 	}
 
 	utils.PrintHeader("Unread Notifications")
@@ -69,12 +79,12 @@ func handleNotifRead(ctx context.Context, in notifReadInput) error {
 
 	if in.All {
 		if err := api.MarkAllNotificationsAsRead(orgID); err != nil {
-			return utils.NewError(fmt.Sprintf("failed to mark all as read: %s", err.Error()), nil)
+			return utils.NewError("failed to mark all as read", err)
 		}
 		utils.PrintSuccess("All notifications marked as read")
 	} else {
 		if err := api.MarkNotificationAsRead(orgID, in.ID); err != nil {
-			return utils.NewError(fmt.Sprintf("failed to mark as read: %s", err.Error()), nil)
+			return utils.NewError("failed to mark as read", err)
 		}
 		utils.PrintSuccess("Notification marked as read")
 	}
@@ -93,7 +103,7 @@ func handleNotifDelete(ctx context.Context, in notifDeleteInput) error {
 	}
 
 	if err := api.DeleteNotification(orgID, in.ID); err != nil {
-		return utils.NewError(fmt.Sprintf("failed to delete notification: %s", err.Error()), nil)
+		return utils.NewError("failed to delete notification", err)
 	}
 
 	utils.PrintSuccess("Notification deleted successfully")
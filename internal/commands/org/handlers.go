@@ -14,7 +14,7 @@ import (
 func handleOrgList(ctx context.Context) error {
 	orgs, err := api.GetUserOrganizations()
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to list organizations: %s", err.Error()), nil)
+		return utils.NewError("failed to list organizations", err)
 	}
 
 	if utils.PrintListOrJSON(orgs, "No organizations found") {
@@ -68,12 +68,12 @@ func handleOrgSwitch(ctx context.Context, in orgSwitchInput) error {
 	if orgID != "" {
 		org, err = api.GetOrganizationByID(api.ToUUID(orgID))
 		if err != nil {
-			return utils.NewError(fmt.Sprintf("failed to get organization: %s", err.Error()), nil)
+			return utils.NewError("failed to get organization", err)
 		}
 	} else {
 		orgs, err := api.GetUserOrganizations()
 		if err != nil {
-			return utils.NewError(fmt.Sprintf("failed to list organizations: %s", err.Error()), nil)
+			return utils.NewError("failed to list organizations", err)
 		}
 		for _, o := range orgs {
 			if o.OrganizationName == orgName {
@@ -90,7 +90,7 @@ func handleOrgSwitch(ctx context.Context, in orgSwitchInput) error {
 		return utils.NewError(fmt.Sprintf("organization '%s' has no namespace assigned — contact support", org.OrganizationName), nil)
 	}
 	if err := satuskyctx.SetCurrentOrganization(org.OrganizationID.String(), org.OrganizationName, org.Namespace); err != nil {
-		return utils.NewError(fmt.Sprintf("failed to switch organization: %s", err.Error()), nil)
+		return utils.NewError("failed to switch organization", err)
 	}
 
 	utils.PrintSuccess("Switched to organization: %s", org.OrganizationName)
@@ -107,7 +107,7 @@ func handleOrgCreate(ctx context.Context, in orgCreateInput) error {
 
 	org, err := api.CreateOrganization(req)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to create organization: %s", err.Error()), nil)
+		return utils.NewError("failed to create organization", err)
 	}
 
 	utils.PrintSuccess("Organization created successfully")
@@ -125,7 +125,7 @@ func handleOrgDelete(ctx context.Context, orgID string) error {
 	}
 
 	if err := api.DeleteOrganization(orgID); err != nil {
-		return utils.NewError(fmt.Sprintf("failed to delete organization: %s", err.Error()), nil)
+		return utils.NewError("failed to delete organization", err)
 	}
 
 	utils.PrintSuccess("Organization deleted successfully")
@@ -140,7 +140,7 @@ func handleOrgTeamList(ctx context.Context) error {
 
 	members, err := api.GetOrganizationTeam(orgID)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to list team members: %s", err.Error()), nil)
+		return utils.NewError("failed to list team members", err)
 	}
 
 	if len(members) == 0 {
@@ -177,7 +177,7 @@ func handleOrgTeamAdd(ctx context.Context, in orgTeamAddInput) error {
 
 	member, err := api.AddTeamMember(orgID, req)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to add team member: %s", err.Error()), nil)
+		return utils.NewError("failed to add team member", err)
 	}
 
 	utils.PrintSuccess("Team member added successfully")
@@ -198,7 +198,7 @@ func handleOrgTeamRole(ctx context.Context, in orgTeamRoleInput) error {
 	}
 
 	if err := api.UpdateTeamMemberRole(orgID, in.OrgUserID, in.Role); err != nil {
-		return utils.NewError(fmt.Sprintf("failed to update team member role: %s", err.Error()), nil)
+		return utils.NewError("failed to update team member role", err)
 	}
 
 	utils.PrintSuccess("Team member role updated to '%s'", in.Role)
@@ -216,7 +216,7 @@ func handleOrgTeamRemove(ctx context.Context, orgUserID string) error {
 	}
 
 	if err := api.RemoveTeamMember(orgID, orgUserID); err != nil {
-		return utils.NewError(fmt.Sprintf("failed to remove team member: %s", err.Error()), nil)
+		return utils.NewError("failed to remove team member", err)
 	}
 
 	utils.PrintSuccess("Team member removed successfully")
@@ -0,0 +1,387 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"1ctl/internal/api"
+	"1ctl/internal/config"
+	satuskyctx "1ctl/internal/context"
+	"1ctl/internal/deploy"
+	"1ctl/internal/utils"
+)
+
+// attachment is an app secret holding a connection URI for a cluster.
+//
+// The secret itself is the record of an attachment: clusters are matched by
+// their internal host, so ones made by hand with "secret create" are found
+// too. Listing a cluster's attachments only reads the secrets of apps that
+// wait for it, which attach always sets up.
+type attachment struct {
+	App          string `json:"app"`
+	DeploymentID string `json:"deployment_id"`
+	SecretID     string `json:"-"`
+	Var          string `json:"var"`
+	User         string `json:"user"`
+}
+
+func handlePostgresAttach(ctx context.Context, in postgresAttachInput) error {
+	client := api.FromContext(ctx)
+	storageID, err := resolvePostgresStorageID(in.StorageID)
+	if err != nil {
+		return err
+	}
+	creds, err := client.GetPostgresCredentials(storageID)
+	if err != nil {
+		return utils.NewError("failed to get Postgres credentials", err)
+	}
+	if creds.InternalURI == "" {
+		return utils.NewError("no internal connection URI available for this Postgres cluster — is it ready? Check '1ctl postgres status'", nil)
+	}
+	host, port, err := uriHostPort(creds.InternalURI)
+	if err != nil {
+		return utils.NewError("invalid internal URI", err)
+	}
+
+	deploymentID, err := deploy.ResolveDeploymentID(client, in.DeploymentID, in.App, in.Config)
+	if err != nil {
+		return err
+	}
+	deployment, err := client.GetDeployment(deploymentID)
+	if err != nil {
+		return utils.NewError("failed to fetch deployment", err)
+	}
+
+	// Refuse to silently repoint a variable at a different database.
+	pointsElsewhere, err := varPointsElsewhere(client, deploymentID, in.Var, host)
+	if err != nil {
+		return err
+	}
+	if pointsElsewhere && !utils.Confirm(fmt.Sprintf("%s on %s is already set to another database. Replace it?", in.Var, deployment.AppLabel), in.Yes) {
+		fmt.Println("Aborted.")
+		return nil
+	}
+
+	uri := creds.InternalURI
+	if in.User != "" {
+		resp, err := client.CreatePostgresUser(storageID, api.CreateDatabaseUserRequest{
+			Username: in.User,
+			Comment:  fmt.Sprintf("attached to %s", deployment.AppLabel),
+		})
+		if err != nil {
+			return utils.NewError("failed to create Postgres user", err)
+		}
+		if uri, err = uriWithUser(creds.InternalURI, resp.User.Username, resp.Password); err != nil {
+			return utils.NewError("failed to build connection URI", err)
+		}
+		utils.PrintSuccess("Database user %s created", resp.User.Username)
+		if !resp.Ready && resp.ReadinessMessage != "" {
+			utils.PrintInfo("%s", resp.ReadinessMessage)
+		}
+	}
+
+	dep := api.WaitFor{Host: host, Port: port}
+	if err := attachURI(client, deployment, in.Var, uri, dep); err != nil {
+		return err
+	}
+	warnConfigWaitFor(in.Config, deployment.AppLabel, dep)
+
+	deploy.RestartAfterChange(client, deploymentID, deployment.AppLabel, "the database connection", in.NoRestart)
+	return nil
+}
+
+// varPointsElsewhere reports whether the app's secret name is already set
+// to something other than a URI for host.
+func varPointsElsewhere(client api.SecretsAPI, deploymentID, name, host string) (bool, error) {
+	secrets, err := client.GetSecretsByDeploymentID(deploymentID)
+	if err != nil && utils.KindOf(err) != utils.ErrNotFound {
+		return false, utils.NewError("failed to read the current secrets", err)
+	}
+	for _, secret := range secrets {
+		for _, kv := range secret.KeyValues {
+			if kv.Key != name {
+				continue
+			}
+			if h, _, err := uriHostPort(kv.Value); err != nil || !strings.EqualFold(h, host) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// attachURI stores uri as the app's secret name and makes the app wait for
+// dep.
+func attachURI(client api.API, deployment *api.Deployment, name, uri string, dep api.WaitFor) error {
+	if _, err := client.CreateSecret(api.Secret{
+		DeploymentID: deployment.DeploymentID,
+		AppLabel:     deployment.AppLabel,
+		Namespace:    satuskyctx.GetCurrentNamespace(),
+		KeyValues:    []api.KeyValuePair{{Key: name, Value: uri}},
+	}); err != nil {
+		return utils.NewError(fmt.Sprintf("failed to store %s", name), err)
+	}
+	utils.PrintSuccess("Stored %s on %s", name, deployment.AppLabel)
+
+	if containsWaitFor(deployment.WaitFor, dep) {
+		return nil
+	}
+	req := deploy.UpdateRequest(*deployment)
+	req.WaitFor = append(append([]api.WaitFor(nil), deployment.WaitFor...), dep)
+	var resp string
+	if err := client.UpsertDeployment(req, &resp); err != nil {
+		return utils.NewError(fmt.Sprintf("failed to add wait_for %s:%d", dep.Host, dep.Port), err)
+	}
+	utils.PrintSuccess("Added wait_for %s:%d", dep.Host, dep.Port)
+	return nil
+}
+
+func handlePostgresDetach(ctx context.Context, in postgresDetachInput) error {
+	client := api.FromContext(ctx)
+	storageID, err := resolvePostgresStorageID(in.StorageID)
+	if err != nil {
+		return err
+	}
+	cluster, err := client.GetPostgresCluster(storageID)
+	if err != nil {
+		return utils.NewError("failed to get Postgres cluster", err)
+	}
+	creds, err := client.GetPostgresCredentials(storageID)
+	if err != nil {
+		return utils.NewError("failed to get Postgres credentials", err)
+	}
+	host, port, err := uriHostPort(creds.InternalURI)
+	if err != nil {
+		return utils.NewError("invalid internal URI", err)
+	}
+
+	deploymentID, err := deploy.ResolveDeploymentID(client, in.DeploymentID, in.App, in.Config)
+	if err != nil {
+		return err
+	}
+	secrets, err := client.GetSecretsByDeploymentID(deploymentID)
+	if err != nil {
+		return utils.NewError("failed to fetch secrets", err)
+	}
+	all := findAttachments(secrets, host)
+	var detach []attachment
+	for _, a := range all {
+		if in.Var == "" || a.Var == in.Var {
+			detach = append(detach, a)
+		}
+	}
+	if len(detach) == 0 {
+		return utils.NewError(fmt.Sprintf("postgres cluster %s is not attached to this app", stringValue(cluster.ClusterName)), nil)
+	}
+
+	vars := make([]string, 0, len(detach))
+	for _, a := range detach {
+		vars = append(vars, a.Var)
+	}
+	if !utils.Confirm(fmt.Sprintf("Detach %s from %s (removes %s)?", stringValue(cluster.ClusterName), detach[0].App, strings.Join(vars, ", ")), in.Yes) {
+		fmt.Println("Aborted.")
+		return nil
+	}
+
+	// Keep the wait_for while another variable still points at the cluster.
+	keepWaitFor := len(detach) < len(all)
+	if err := detachURIs(client, deploymentID, detach, api.WaitFor{Host: host, Port: port}, keepWaitFor); err != nil {
+		return err
+	}
+
+	if in.DeleteUser {
+		for _, a := range detach {
+			if a.User == "" || a.User == stringValue(cluster.Username) || a.User == creds.Username {
+				continue // never drop the cluster owner
+			}
+			if err := client.DeletePostgresUser(storageID, a.User); err != nil {
+				utils.PrintWarning("Failed to delete database user %s: %s", a.User, err.Error())
+				continue
+			}
+			utils.PrintSuccess("Database user %s deleted", a.User)
+		}
+	}
+
+	deploy.RestartAfterChange(client, deploymentID, detach[0].App, "the database connection", in.NoRestart)
+	return nil
+}
+
+// detachURIs removes the attachments' secret keys and, unless keepWaitFor
+// is set, stops the app waiting for dep.
+func detachURIs(client api.API, deploymentID string, detach []attachment, dep api.WaitFor, keepWaitFor bool) error {
+	for _, a := range detach {
+		if err := client.UnsetSecretKey(a.SecretID, a.Var); err != nil {
+			return utils.NewError(fmt.Sprintf("failed to remove %s", a.Var), err)
+		}
+		utils.PrintSuccess("Removed %s", a.Var)
+	}
+	if keepWaitFor {
+		return nil
+	}
+
+	deployment, err := client.GetDeployment(deploymentID)
+	if err != nil {
+		return utils.NewError("failed to fetch deployment", err)
+	}
+	if !containsWaitFor(deployment.WaitFor, dep) {
+		return nil
+	}
+	req := deploy.UpdateRequest(*deployment)
+	req.WaitFor = removeWaitFor(deployment.WaitFor, dep)
+	var resp string
+	if err := client.UpsertDeployment(req, &resp); err != nil {
+		return utils.NewError(fmt.Sprintf("failed to remove wait_for %s:%d", dep.Host, dep.Port), err)
+	}
+	utils.PrintSuccess("Removed wait_for %s:%d", dep.Host, dep.Port)
+	return nil
+}
+
+// clusterAttachments lists the app secrets pointing at a cluster.
+func clusterAttachments(client api.API, storageID string) ([]attachment, error) {
+	creds, err := client.GetPostgresCredentials(storageID)
+	if err != nil {
+		return nil, err
+	}
+	host, port, err := uriHostPort(creds.InternalURI)
+	if err != nil {
+		return nil, err
+	}
+	return attachmentsFor(client, api.WaitFor{Host: host, Port: port})
+}
+
+// attachmentsFor lists the app secrets pointing at dep, reading only the
+// secrets of apps that wait for it.
+func attachmentsFor(client api.API, dep api.WaitFor) ([]attachment, error) {
+	deployments, err := client.ListDeployments()
+	if err != nil {
+		return nil, err
+	}
+	var secrets []api.Secret
+	for _, d := range deployments {
+		if !containsWaitFor(d.WaitFor, dep) {
+			continue
+		}
+		found, err := client.GetSecretsByDeploymentID(d.DeploymentID.String())
+		if err != nil && utils.KindOf(err) != utils.ErrNotFound {
+			return nil, err
+		}
+		secrets = append(secrets, found...)
+	}
+	return findAttachments(secrets, dep.Host), nil
+}
+
+func printAttachments(attachments []attachment) {
+	if len(attachments) == 0 {
+		utils.PrintStatusLine("Attached apps", "none")
+		return
+	}
+	fmt.Println()
+	rows := make([][]string, 0, len(attachments))
+	for _, a := range attachments {
+		rows = append(rows, []string{a.App, a.Var, a.User})
+	}
+	utils.PrintTable([]string{"APP", "VARIABLE", "USER"}, rows)
+}
+
+// findAttachments returns every secret key whose value is a Postgres URI
+// for host, sorted by app and variable.
+func findAttachments(secrets []api.Secret, host string) []attachment {
+	var found []attachment
+	for _, s := range secrets {
+		for _, kv := range s.KeyValues {
+			u, err := url.Parse(kv.Value)
+			if err != nil || (u.Scheme != "postgres" && u.Scheme != "postgresql") {
+				continue
+			}
+			if !strings.EqualFold(u.Hostname(), host) {
+				continue
+			}
+			a := attachment{
+				App:          s.AppLabel,
+				DeploymentID: s.DeploymentID.String(),
+				SecretID:     s.SecretID.String(),
+				Var:          kv.Key,
+			}
+			if u.User != nil {
+				a.User = u.User.Username()
+			}
+			found = append(found, a)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].App != found[j].App {
+			return found[i].App < found[j].App
+		}
+		return found[i].Var < found[j].Var
+	})
+	return found
+}
+
+// uriWithUser returns rawURI with its credentials replaced.
+func uriWithUser(rawURI, user, password string) (string, error) {
+	u, err := url.Parse(rawURI)
+	if err != nil {
+		return "", err
+	}
+	u.User = url.UserPassword(user, password)
+	return u.String(), nil
+}
+
+// uriHostPort returns the host and port of a Postgres URI, defaulting the
+// port to 5432.
+func uriHostPort(rawURI string) (string, int32, error) {
+	u, err := url.Parse(rawURI)
+	if err != nil {
+		return "", 0, err
+	}
+	if u.Hostname() == "" {
+		return "", 0, fmt.Errorf("no host in URI")
+	}
+	port := int64(5432)
+	if p := u.Port(); p != "" {
+		if port, err = strconv.ParseInt(p, 10, 32); err != nil {
+			return "", 0, fmt.Errorf("invalid port %q", p)
+		}
+	}
+	return u.Hostname(), int32(port), nil // #nosec G115 -- ParseInt with bitSize=32
+}
+
+func containsWaitFor(deps []api.WaitFor, dep api.WaitFor) bool {
+	for _, d := range deps {
+		if strings.EqualFold(d.Host, dep.Host) && d.Port == dep.Port {
+			return true
+		}
+	}
+	return false
+}
+
+func removeWaitFor(deps []api.WaitFor, dep api.WaitFor) []api.WaitFor {
+	kept := make([]api.WaitFor, 0, len(deps))
+	for _, d := range deps {
+		if !strings.EqualFold(d.Host, dep.Host) || d.Port != dep.Port {
+			kept = append(kept, d)
+		}
+	}
+	return kept
+}
+
+// warnConfigWaitFor points out that the next deploy will replace the
+// deployment's wait_for list when satusky.toml declares one without dep.
+func warnConfigWaitFor(configArg, appLabel string, dep api.WaitFor) {
+	cfg, err := config.FindConfig(configArg)
+	if err != nil || cfg == nil || cfg.App.Name != appLabel || len(cfg.Deploy.WaitFor) == 0 {
+		return
+	}
+	want := net.JoinHostPort(dep.Host, strconv.Itoa(int(dep.Port)))
+	for _, v := range cfg.Deploy.WaitFor {
+		if strings.EqualFold(strings.TrimSpace(v), want) {
+			return
+		}
+	}
+	utils.PrintWarning("satusky.toml sets wait_for without %s — add it there, or the next deploy will drop it", want)
+}
@@ -36,6 +36,12 @@ const (
 	flagComment      = "comment"
 	flagCIDR         = "cidr"
 	flagDescription  = "description"
+	flagApp          = "app"
+	flagConfig       = "config"
+	flagDeploymentID = "deployment-id"
+	flagVar          = "var"
+	flagNoRestart    = "no-restart"
+	flagDeleteUser   = "delete-user"
 )
 
 // --- Input structs ------------------------------------------------------
@@ -113,6 +119,28 @@ type postgresFirewallRemoveInput struct {
 	Yes       bool
 }
 
+type postgresAttachInput struct {
+	StorageID    string
+	App          string
+	Config       string
+	DeploymentID string
+	Var          string
+	User         string
+	NoRestart    bool
+	Yes          bool
+}
+
+type postgresDetachInput struct {
+	StorageID    string
+	App          string
+	Config       string
+	DeploymentID string
+	Var          string
+	DeleteUser   bool
+	NoRestart    bool
+	Yes          bool
+}
+
 // --- Flag constructors --------------------------------------------------
 
 func optionalStringFlag(name, usage string, dest *string, def string) *cli.StringFlag {
@@ -157,6 +185,8 @@ func Command() *cli.Command {
 			postgresCredentialsCommand(),
 			postgresConnectCommand(),
 			postgresProxyCommand(),
+			postgresAttachCommand(),
+			postgresDetachCommand(),
 			postgresRedeployCommand(),
 			postgresDestroyCommand(),
 			postgresUsersCommand(),
@@ -291,6 +321,73 @@ func postgresProxyCommand() *cli.Command {
 	}
 }
 
+func postgresAttachCommand() *cli.Command {
+	var in postgresAttachInput
+	return &cli.Command{
+		Name:      "attach",
+		Usage:     "Connect an app to a Postgres cluster through a secret",
+		ArgsUsage: "<cluster>",
+		Description: `Store the cluster's internal connection URI as a secret on an app, add a
+wait_for on the database host so the app starts only once Postgres is
+reachable, and restart the app. With --user, a dedicated database user is
+created for the app instead of sharing the owner credentials.
+
+Re-run attach to refresh the stored URI after credentials change.
+
+Examples:
+   1ctl postgres attach my-db --app api
+   1ctl postgres attach my-db --app worker --var WORKER_DB_URL --user worker`,
+		Flags: []cli.Flag{
+			optionalStringFlag(flagApp, "App name to resolve (alternative to --deployment-id)", &in.App, ""),
+			optionalStringFlag(flagConfig, "Config name or path", &in.Config, ""),
+			optionalStringFlag(flagDeploymentID, "Deployment ID", &in.DeploymentID, ""),
+			optionalStringFlag(flagVar, "Secret to store the connection URI in", &in.Var, "DATABASE_URL"),
+			optionalStringFlag(flagUser, "Create a dedicated database user with this name", &in.User, ""),
+			optionalBoolFlag(flagNoRestart, "Stage the change without restarting (apply later with '1ctl app apply-config')", &in.NoRestart),
+			optionalBoolFlag(flagYes, "Replace an existing variable without prompting", &in.Yes),
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			if cmd.Args().Len() < 1 {
+				return cli.ShowSubcommandHelp(cmd)
+			}
+			in.StorageID = cmd.Args().First()
+			return handlePostgresAttach(ctx, in)
+		},
+	}
+}
+
+func postgresDetachCommand() *cli.Command {
+	var in postgresDetachInput
+	return &cli.Command{
+		Name:      "detach",
+		Usage:     "Disconnect an app from a Postgres cluster",
+		ArgsUsage: "<cluster>",
+		Description: `Remove the secrets pointing an app at the cluster and the matching wait_for,
+then restart the app. With --delete-user, dedicated users created by attach
+are dropped as well; the cluster owner is never deleted.
+
+Examples:
+   1ctl postgres detach my-db --app api
+   1ctl postgres detach my-db --app worker --var WORKER_DB_URL --delete-user`,
+		Flags: []cli.Flag{
+			optionalStringFlag(flagApp, "App name to resolve (alternative to --deployment-id)", &in.App, ""),
+			optionalStringFlag(flagConfig, "Config name or path", &in.Config, ""),
+			optionalStringFlag(flagDeploymentID, "Deployment ID", &in.DeploymentID, ""),
+			optionalStringFlag(flagVar, "Only remove this variable (default: every variable pointing at the cluster)", &in.Var, ""),
+			optionalBoolFlag(flagDeleteUser, "Also delete the dedicated database user", &in.DeleteUser),
+			optionalBoolFlag(flagNoRestart, "Stage the change without restarting (apply later with '1ctl app apply-config')", &in.NoRestart),
+			optionalBoolFlag(flagYes, "Skip confirmation prompt", &in.Yes),
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			if cmd.Args().Len() < 1 {
+				return cli.ShowSubcommandHelp(cmd)
+			}
+			in.StorageID = cmd.Args().First()
+			return handlePostgresDetach(ctx, in)
+		},
+	}
+}
+
 func postgresRedeployCommand() *cli.Command {
 	var in postgresStorageIDInput
 	return &cli.Command{
@@ -54,7 +54,7 @@ func handlePostgresCreate(ctx context.Context, in postgresCreateInput) error {
 			calculated := int64(float64(size) * 0.2)
 			minWAL, err := parseStorageSize("5Gi")
 			if err != nil {
-				return utils.NewError(fmt.Sprintf("failed to determine minimum WAL size: %s", err.Error()), nil)
+				return utils.NewError("failed to determine minimum WAL size", err)
 			}
 			if calculated < minWAL {
 				calculated = minWAL
@@ -99,7 +99,7 @@ func handlePostgresCreate(ctx context.Context, in postgresCreateInput) error {
 
 	cluster, err := api.CreatePostgresCluster(opts)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to create Postgres cluster: %s", err.Error()), nil)
+		return utils.NewError("failed to create Postgres cluster", err)
 	}
 	if utils.TryPrintJSON(cluster) {
 		return nil
@@ -114,7 +114,7 @@ func handlePostgresCreate(ctx context.Context, in postgresCreateInput) error {
 func handlePostgresList(ctx context.Context) error {
 	clusters, err := api.ListPostgresClusters("")
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to list Postgres clusters: %s", err.Error()), nil)
+		return utils.NewError("failed to list Postgres clusters", err)
 	}
 	if utils.PrintListOrJSON(clusters, "No Postgres clusters found") {
 		return nil
@@ -143,12 +143,15 @@ func handlePostgresGet(ctx context.Context, in postgresStorageIDInput) error {
 	}
 	cluster, err := api.GetPostgresCluster(storageID)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to get Postgres cluster: %s", err.Error()), nil)
+		return utils.NewError("failed to get Postgres cluster", err)
 	}
 	if utils.TryPrintJSON(cluster) {
 		return nil
 	}
 	printPostgresCluster(cluster)
+	if attachments, err := clusterAttachments(api.FromContext(ctx), storageID); err == nil {
+		printAttachments(attachments)
+	}
 	return nil
 }
 
@@ -159,7 +162,7 @@ func handlePostgresStatus(ctx context.Context, in postgresStorageIDInput) error
 	}
 	status, err := api.GetPostgresStatus(storageID)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to get Postgres status: %s", err.Error()), nil)
+		return utils.NewError("failed to get Postgres status", err)
 	}
 	if utils.TryPrintJSON(status) {
 		return nil
@@ -175,7 +178,7 @@ func handlePostgresCredentials(ctx context.Context, in postgresStorageIDInput) e
 	}
 	creds, err := api.GetPostgresCredentials(storageID)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to get Postgres credentials: %s", err.Error()), nil)
+		return utils.NewError("failed to get Postgres credentials", err)
 	}
 	if utils.TryPrintJSON(creds) {
 		return nil
@@ -191,7 +194,7 @@ func handlePostgresConnect(ctx context.Context, in postgresConnectInput) error {
 	}
 	creds, err := api.GetPostgresCredentials(storageID)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to get Postgres credentials: %s", err.Error()), nil)
+		return utils.NewError("failed to get Postgres credentials", err)
 	}
 	uri := creds.URI
 	if uri == "" {
@@ -205,13 +208,13 @@ func handlePostgresConnect(ctx context.Context, in postgresConnectInput) error {
 	if env, err := postgresEnvFromURI(uri); err == nil {
 		psqlCmd.Env = append(os.Environ(), env...)
 	} else {
-		return utils.NewError(fmt.Sprintf("failed to prepare psql connection: %s", err.Error()), nil)
+		return utils.NewError("failed to prepare psql connection", err)
 	}
 	psqlCmd.Stdin = os.Stdin
 	psqlCmd.Stdout = os.Stdout
 	psqlCmd.Stderr = os.Stderr
 	if err := psqlCmd.Run(); err != nil {
-		return utils.NewError(fmt.Sprintf("failed to run psql: %s", err.Error()), nil)
+		return utils.NewError("failed to run psql", err)
 	}
 	return nil
 }
@@ -223,7 +226,7 @@ func handlePostgresProxy(ctx context.Context, in postgresProxyInput) error {
 	}
 	creds, err := api.GetPostgresCredentials(storageID)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to get Postgres credentials: %s", err.Error()), nil)
+		return utils.NewError("failed to get Postgres credentials", err)
 	}
 
 	remoteHost := creds.Host
@@ -243,7 +246,7 @@ func handlePostgresRedeploy(ctx context.Context, in postgresStorageIDInput) erro
 		return err
 	}
 	if err := api.RedeployPostgresCluster(storageID); err != nil {
-		return utils.NewError(fmt.Sprintf("failed to redeploy Postgres cluster: %s", err.Error()), nil)
+		return utils.NewError("failed to redeploy Postgres cluster", err)
 	}
 	utils.PrintSuccess("Postgres redeploy started")
 	return nil
@@ -259,7 +262,7 @@ func handlePostgresDestroy(ctx context.Context, in postgresDestroyInput) error {
 		return nil
 	}
 	if err := api.DeletePostgresCluster(storageID); err != nil {
-		return utils.NewError(fmt.Sprintf("failed to destroy Postgres cluster: %s", err.Error()), nil)
+		return utils.NewError("failed to destroy Postgres cluster", err)
 	}
 	utils.PrintSuccess("Postgres cluster destroy started")
 	return nil
@@ -272,7 +275,7 @@ func handlePostgresUsersList(ctx context.Context, in postgresStorageIDInput) err
 	}
 	users, err := api.ListPostgresUsers(storageID)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to list Postgres users: %s", err.Error()), nil)
+		return utils.NewError("failed to list Postgres users", err)
 	}
 	if utils.TryPrintJSON(users) {
 		return nil
@@ -307,7 +310,7 @@ func handlePostgresUsersCreate(ctx context.Context, in postgresUsersCreateInput)
 		Comment:        in.Comment,
 	})
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to create Postgres user: %s", err.Error()), nil)
+		return utils.NewError("failed to create Postgres user", err)
 	}
 	if utils.TryPrintJSON(resp) {
 		return nil
@@ -335,7 +338,7 @@ func handlePostgresUsersDelete(ctx context.Context, in postgresUsersDeleteInput)
 		return nil
 	}
 	if err := api.DeletePostgresUser(storageID, in.Username); err != nil {
-		return utils.NewError(fmt.Sprintf("failed to delete Postgres user: %s", err.Error()), nil)
+		return utils.NewError("failed to delete Postgres user", err)
 	}
 	utils.PrintSuccess("Database user deleted")
 	return nil
@@ -348,7 +351,7 @@ func handlePostgresFirewallList(ctx context.Context, in postgresStorageIDInput)
 	}
 	rules, err := api.ListPostgresFirewallRules(storageID)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to list firewall rules: %s", err.Error()), nil)
+		return utils.NewError("failed to list firewall rules", err)
 	}
 	if utils.TryPrintJSON(rules) {
 		return nil
@@ -381,7 +384,7 @@ func handlePostgresFirewallAdd(ctx context.Context, in postgresFirewallAddInput)
 		Description: in.Description,
 	})
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to add firewall rule: %s", err.Error()), nil)
+		return utils.NewError("failed to add firewall rule", err)
 	}
 	if utils.TryPrintJSON(rule) {
 		return nil
@@ -399,7 +402,7 @@ func handlePostgresFirewallSetEnabled(ctx context.Context, storageID, ruleID str
 	}
 	rule, err := api.UpdatePostgresFirewallRule(sid, ruleID, api.UpdateFirewallRuleRequest{Enabled: &enabled})
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to update firewall rule: %s", err.Error()), nil)
+		return utils.NewError("failed to update firewall rule", err)
 	}
 	if utils.TryPrintJSON(rule) {
 		return nil
@@ -420,7 +423,7 @@ func handlePostgresFirewallRemove(ctx context.Context, in postgresFirewallRemove
 		return nil
 	}
 	if err := api.DeletePostgresFirewallRule(storageID, in.RuleID); err != nil {
-		return utils.NewError(fmt.Sprintf("failed to remove firewall rule: %s", err.Error()), nil)
+		return utils.NewError("failed to remove firewall rule", err)
 	}
 	utils.PrintSuccess("Firewall rule removed")
 	return nil
@@ -429,7 +432,7 @@ func handlePostgresFirewallRemove(ctx context.Context, in postgresFirewallRemove
 func handlePostgresStorageClasses(ctx context.Context) error {
 	classes, err := api.ListStorageClasses()
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to list storage classes: %s", err.Error()), nil)
+		return utils.NewError("failed to list storage classes", err)
 	}
 	if utils.TryPrintJSON(classes) {
 		return nil
@@ -572,11 +575,11 @@ func resolvePostgresStorageID(arg string) (string, error) {
 	// Resolve by name.
 	ns := satuskyctx.GetCurrentNamespace()
 	if ns == "" {
-		return "", utils.NewError("not authenticated — run '1ctl auth login' first", nil)
+		return "", utils.NewKindError(utils.ErrAuth, "not authenticated — run '1ctl auth login' first", nil)
 	}
 	clusters, err := api.ListPostgresClusters(ns)
 	if err != nil {
-		return "", utils.NewError(fmt.Sprintf("failed to list postgres clusters: %s", err.Error()), nil)
+		return "", utils.NewError("failed to list postgres clusters", err)
 	}
 	for _, cluster := range clusters {
 		if cluster.ClusterName != nil && strings.EqualFold(*cluster.ClusterName, arg) {
@@ -635,6 +638,10 @@ func printPostgresCredentials(creds *api.PostgresCredentials) {
 	if creds.ExternalURI != "" {
 		utils.PrintStatusLine("External URI", creds.ExternalURI)
 	}
+	if !utils.IsRevealed() {
+		fmt.Println()
+		utils.PrintInfo("Password and URIs are masked — pass --reveal to show them")
+	}
 }
 
 func effectiveInstances(cluster api.StorageConfig) int {
@@ -713,7 +720,7 @@ func runTCPProxy(localAddr, remoteAddr string) error {
 		fmt.Println()
 		return nil
 	case err := <-errCh:
-		return utils.NewError(fmt.Sprintf("proxy stopped: %s", err.Error()), nil)
+		return utils.NewError("proxy stopped", err)
 	}
 }
 
@@ -16,7 +16,7 @@ import (
 func handlePricingList(ctx context.Context) error {
 	configs, err := api.ListPricingConfigs()
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to list pricing configs: %s", err.Error()), nil)
+		return utils.NewError("failed to list pricing configs", err)
 	}
 
 	if utils.PrintListOrJSON(configs, "No pricing configurations found") {
@@ -34,7 +34,7 @@ func handlePricingList(ctx context.Context) error {
 func handlePricingGet(ctx context.Context, in pricingGetInput) error {
 	config, err := api.GetPricingConfig(in.ConfigID)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to get pricing config: %s", err.Error()), nil)
+		return utils.NewError("failed to get pricing config", err)
 	}
 
 	if utils.TryPrintJSON(config) {
@@ -49,7 +49,7 @@ func handlePricingGet(ctx context.Context, in pricingGetInput) error {
 func handlePricingLookup(ctx context.Context, in pricingLookupInput) error {
 	config, err := api.GetPricingByRegionAndType(in.Region, in.MachineType, in.SLA)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to look up pricing: %s", err.Error()), nil)
+		return utils.NewError("failed to look up pricing", err)
 	}
 
 	if utils.TryPrintJSON(config) {
@@ -69,7 +69,7 @@ func handlePricingCalculate(ctx context.Context, in pricingCalculateInput) error
 
 	result, err := api.CalculateMachineCost(in.MachineRefID, in.MachineID, req)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to calculate cost: %s", err.Error()), nil)
+		return utils.NewError("failed to calculate cost", err)
 	}
 
 	if utils.TryPrintJSON(result) {
@@ -10,7 +10,11 @@ import (
 // --- Flag name constants ------------------------------------------------
 
 const (
-	flagURL = "url"
+	flagURL        = "url"
+	flagCAFile     = "ca-file"
+	flagClientCert = "client-cert"
+	flagClientKey  = "client-key"
+	flagClear      = "clear"
 )
 
 // --- Input structs ------------------------------------------------------
@@ -24,6 +28,13 @@ type profileNameInput struct {
 	Name string
 }
 
+type profileTLSInput struct {
+	CAFile     string
+	ClientCert string
+	ClientKey  string
+	Clear      bool
+}
+
 // --- Command tree -------------------------------------------------------
 
 // Command returns the root profile command tree.
@@ -36,6 +47,7 @@ func Command() *cli.Command {
 			profileCreateCommand(),
 			profileUseCommand(),
 			profileCurrentCommand(),
+			profileTLSCommand(),
 			profileDeleteCommand(),
 		},
 	}
@@ -100,6 +112,47 @@ func profileCurrentCommand() *cli.Command {
 	}
 }
 
+func profileTLSCommand() *cli.Command {
+	var in profileTLSInput
+	return &cli.Command{
+		Name:  "tls",
+		Usage: "Set the CA bundle and client certificate the active profile uses to reach its API",
+		Description: `Files are stored as absolute paths and used for every request made with
+the profile, including WebSocket connections. Proxies are taken from
+HTTPS_PROXY, HTTP_PROXY and NO_PROXY.
+
+Examples:
+  1ctl profile tls --ca-file ./corp-root.pem
+  1ctl profile tls --client-cert ./me.crt --client-key ./me.key
+  1ctl profile tls --clear`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        flagCAFile,
+				Usage:       "PEM file of extra root CAs to trust",
+				Destination: &in.CAFile,
+			},
+			&cli.StringFlag{
+				Name:        flagClientCert,
+				Usage:       "PEM client certificate for mutual TLS (needs --client-key)",
+				Destination: &in.ClientCert,
+			},
+			&cli.StringFlag{
+				Name:        flagClientKey,
+				Usage:       "PEM private key of the client certificate",
+				Destination: &in.ClientKey,
+			},
+			&cli.BoolFlag{
+				Name:        flagClear,
+				Usage:       "Remove the profile's TLS files",
+				Destination: &in.Clear,
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return handleProfileTLS(ctx, in)
+		},
+	}
+}
+
 func profileDeleteCommand() *cli.Command {
 	var in profileNameInput
 	return &cli.Command{
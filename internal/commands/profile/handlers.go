@@ -3,9 +3,11 @@ package profile
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 
 	"1ctl/internal/config"
 	satuskyctx "1ctl/internal/context"
+	"1ctl/internal/transport"
 	"1ctl/internal/utils"
 )
 
@@ -16,7 +18,7 @@ func defaultAPIURLDisplay() string {
 func handleProfileList(ctx context.Context) error {
 	profiles, err := satuskyctx.ListProfiles()
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to list profiles: %s", err.Error()), nil)
+		return utils.NewError("failed to list profiles", err)
 	}
 
 	if len(profiles) == 0 {
@@ -47,6 +49,9 @@ func handleProfileList(ctx context.Context) error {
 		if p.OrgName != "" {
 			utils.PrintStatusLine("  Org", p.OrgName)
 		}
+		if p.CredentialStore != "" {
+			utils.PrintStatusLine("  Credentials", p.CredentialStore)
+		}
 		utils.PrintDivider()
 	}
 	return nil
@@ -54,7 +59,7 @@ func handleProfileList(ctx context.Context) error {
 
 func handleProfileCreate(ctx context.Context, in profileCreateInput) error {
 	if err := satuskyctx.CreateProfile(in.Name, in.URL); err != nil {
-		return utils.NewError(fmt.Sprintf("failed to create profile: %s", err.Error()), nil)
+		return utils.NewError("failed to create profile", err)
 	}
 
 	utils.PrintSuccess("Profile '%s' created", in.Name)
@@ -71,10 +76,14 @@ func handleProfileCreate(ctx context.Context, in profileCreateInput) error {
 
 func handleProfileUse(ctx context.Context, in profileNameInput) error {
 	if err := satuskyctx.UseProfile(in.Name); err != nil {
-		return utils.NewError(fmt.Sprintf("failed to switch profile: %s", err.Error()), nil)
+		return utils.NewError("failed to switch profile", err)
 	}
 
 	utils.PrintSuccess("Switched to profile '%s'", in.Name)
+	if pin := config.ActivePin(); pin != nil && pin.Profile != "" && pin.Profile != in.Name {
+		utils.PrintWarning("Commands in this directory keep using profile '%s', pinned in %s", pin.Profile, pin.Path)
+		return nil
+	}
 	utils.PrintStatusLine("API URL", config.GetConfig().ApiURL)
 
 	if satuskyctx.GetToken() == "" {
@@ -85,7 +94,7 @@ func handleProfileUse(ctx context.Context, in profileNameInput) error {
 }
 
 func handleProfileCurrent(ctx context.Context) error {
-	name := satuskyctx.GetActiveProfileName()
+	name := satuskyctx.GetEffectiveProfileName()
 
 	if name == "" {
 		utils.PrintInfo("No profile active. Run '1ctl profile use <name>' to select one.")
@@ -94,6 +103,7 @@ func handleProfileCurrent(ctx context.Context) error {
 
 	utils.PrintHeader("Active Profile")
 	utils.PrintStatusLine("Profile", name)
+	utils.PrintStatusLine("Selected by", satuskyctx.GetProfileSource())
 	utils.PrintStatusLine("API URL", config.GetConfig().ApiURL)
 
 	if email := satuskyctx.GetEmail(); email != "" {
@@ -105,13 +115,84 @@ func handleProfileCurrent(ctx context.Context) error {
 	if org := satuskyctx.GetCurrentOrgName(); org != "" {
 		utils.PrintStatusLine("Org", org)
 	}
+	printTLSFiles(satuskyctx.GetTLSFiles())
 
 	return nil
 }
 
+func handleProfileTLS(ctx context.Context, in profileTLSInput) error {
+	if satuskyctx.GetEffectiveProfileName() == "" {
+		return utils.NewKindError(utils.ErrUsage, "no profile active. Run '1ctl profile use <name>' first", nil)
+	}
+
+	files := satuskyctx.GetTLSFiles()
+	switch {
+	case in.Clear:
+		files = satuskyctx.TLSFiles{}
+	case in.CAFile == "" && in.ClientCert == "" && in.ClientKey == "":
+		if files == (satuskyctx.TLSFiles{}) {
+			utils.PrintInfo("No TLS files set. The profile uses the system CA roots.")
+			return nil
+		}
+		utils.PrintHeader("Profile TLS")
+		printTLSFiles(files)
+		return nil
+	default:
+		for _, f := range []struct {
+			in  string
+			out *string
+		}{
+			{in.CAFile, &files.CAFile},
+			{in.ClientCert, &files.ClientCert},
+			{in.ClientKey, &files.ClientKey},
+		} {
+			if f.in == "" {
+				continue
+			}
+			abs, err := filepath.Abs(f.in)
+			if err != nil {
+				return utils.NewError("failed to resolve "+f.in, err)
+			}
+			*f.out = abs
+		}
+	}
+
+	// Load the files now so a typo fails here rather than on the next request
+	if err := transport.Configure(transport.Config{
+		CAFiles:    []string{files.CAFile},
+		ClientCert: files.ClientCert,
+		ClientKey:  files.ClientKey,
+	}); err != nil {
+		return err
+	}
+	if err := satuskyctx.SetTLSFiles(files); err != nil {
+		return utils.NewError("failed to save TLS files", err)
+	}
+
+	if in.Clear {
+		utils.PrintSuccess("TLS files removed from profile '%s'", satuskyctx.GetEffectiveProfileName())
+		return nil
+	}
+	utils.PrintSuccess("TLS files saved to profile '%s'", satuskyctx.GetEffectiveProfileName())
+	printTLSFiles(files)
+	return nil
+}
+
+func printTLSFiles(files satuskyctx.TLSFiles) {
+	if files.CAFile != "" {
+		utils.PrintStatusLine("CA File", files.CAFile)
+	}
+	if files.ClientCert != "" {
+		utils.PrintStatusLine("Client Cert", files.ClientCert)
+	}
+	if files.ClientKey != "" {
+		utils.PrintStatusLine("Client Key", files.ClientKey)
+	}
+}
+
 func handleProfileDelete(ctx context.Context, in profileNameInput) error {
 	if err := satuskyctx.DeleteProfile(in.Name); err != nil {
-		return utils.NewError(fmt.Sprintf("failed to delete profile: %s", err.Error()), nil)
+		return utils.NewError("failed to delete profile", err)
 	}
 
 	utils.PrintSuccess("Profile '%s' deleted", in.Name)
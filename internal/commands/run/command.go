@@ -0,0 +1,112 @@
+// Package run defines the "1ctl run" command — flag names, input struct,
+// and CLI wiring. Handler logic lives in handlers.go.
+package run
+
+import (
+	"context"
+
+	"github.com/urfave/cli/v3"
+)
+
+// --- Flag name constants ------------------------------------------------
+
+const (
+	flagDeploymentID = "deployment-id"
+	flagApp          = "app"
+	flagConfig       = "config"
+	flagEnv          = "env"
+	flagOnly         = "only"
+	flagEnvFile      = "env-file"
+	flagNoSecrets    = "no-secrets"
+)
+
+// envFilePlaceholder is replaced in the child's arguments with the path of
+// the temporary file written by --env-file.
+const envFilePlaceholder = "{env-file}"
+
+// --- Input structs ------------------------------------------------------
+
+type runInput struct {
+	DeploymentID string
+	App          string
+	Config       string
+	Env          []string // KEY=VALUE overrides, as for "1ctl deploy --env"
+	Only         []string
+	EnvFile      bool
+	NoSecrets    bool
+	Args         []string // the command to run and its arguments
+}
+
+// --- Command tree -------------------------------------------------------
+
+// Command returns the "1ctl run" command.
+func Command() *cli.Command {
+	var in runInput
+	return &cli.Command{
+		Name:      "run",
+		Usage:     "Run a local command with an app's environment and secrets",
+		ArgsUsage: "-- <command> [args...]",
+		Description: `Fetch a deployment's environment variables and secrets and run a local
+command with them, without writing values to disk. Values are layered in
+increasing precedence: the deployment's environment, then [env] from
+satusky.toml, then secrets, then --env flags. The local environment is
+inherited underneath all of them.
+
+Signals are forwarded to the command and 1ctl exits with its exit code.
+
+With --env-file the values are written to a private temporary file instead,
+for tools that only read files. Its path is passed as $SATUSKY_ENV_FILE and
+replaces {env-file} in the arguments; it is removed when the command exits.
+
+Examples:
+   1ctl run --app api -- go run ./cmd/server
+   1ctl run --app api --only DATABASE_URL,REDIS_URL -- npm test
+   1ctl run --app api --env-file -- docker compose --env-file {env-file} up`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        flagApp,
+				Usage:       "App name to resolve (alternative to --deployment-id)",
+				Destination: &in.App,
+			},
+			&cli.StringFlag{
+				Name:        flagConfig,
+				Usage:       "Config name or path (e.g. staging, satusky.staging.toml). Default: satusky.toml",
+				Destination: &in.Config,
+			},
+			&cli.StringFlag{
+				Name:        flagDeploymentID,
+				Aliases:     []string{"d"},
+				Usage:       "Deployment ID",
+				Destination: &in.DeploymentID,
+			},
+			&cli.StringSliceFlag{
+				Name:        flagEnv,
+				Aliases:     []string{"e"},
+				Usage:       "Override a variable (format: KEY=VALUE, repeatable)",
+				Destination: &in.Env,
+			},
+			&cli.StringSliceFlag{
+				Name:        flagOnly,
+				Usage:       "Only inject these keys (comma-separated)",
+				Destination: &in.Only,
+			},
+			&cli.BoolFlag{
+				Name:        flagEnvFile,
+				Usage:       "Write values to a temporary dotenv file instead of the process environment",
+				Destination: &in.EnvFile,
+			},
+			&cli.BoolFlag{
+				Name:        flagNoSecrets,
+				Usage:       "Inject environment variables only, not secrets",
+				Destination: &in.NoSecrets,
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			in.Args = cmd.Args().Slice()
+			if len(in.Args) == 0 {
+				return cli.ShowSubcommandHelp(cmd)
+			}
+			return handleRun(ctx, in)
+		},
+	}
+}
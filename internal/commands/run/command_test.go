@@ -0,0 +1,61 @@
+package run
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeEnvPrecedence(t *testing.T) {
+	remote := map[string]string{"A": "remote", "B": "remote", "C": "remote", "D": "remote"}
+	cfg := map[string]string{"B": "config", "C": "config", "D": "config"}
+	secrets := map[string]string{"C": "secret", "D": "secret"}
+	overrides := map[string]string{"D": "flag"}
+
+	got := mergeEnv(remote, cfg, secrets, overrides)
+	want := map[string]string{"A": "remote", "B": "config", "C": "secret", "D": "flag"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeEnv = %v, want %v", got, want)
+	}
+}
+
+func TestFilterKeys(t *testing.T) {
+	values := map[string]string{"A": "1", "B": "2", "C": "3"}
+
+	got, missing := filterKeys(values, nil)
+	if !reflect.DeepEqual(got, values) || missing != nil {
+		t.Errorf("filterKeys(nil) = %v, %v", got, missing)
+	}
+
+	got, missing = filterKeys(values, []string{"A", " C", "Z"})
+	if !reflect.DeepEqual(got, map[string]string{"A": "1", "C": "3"}) {
+		t.Errorf("filterKeys = %v", got)
+	}
+	if !reflect.DeepEqual(missing, []string{"Z"}) {
+		t.Errorf("missing = %v, want [Z]", missing)
+	}
+}
+
+func TestExecChildExitCode(t *testing.T) {
+	code, err := execChild([]string{"sh", "-c", "exit 7"}, nil)
+	if err != nil {
+		t.Skipf("sh not available: %v", err)
+	}
+	if code != 7 {
+		t.Errorf("exit code = %d, want 7", code)
+	}
+
+	code, err = execChild([]string{"sh", "-c", "kill -TERM $$"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code != 128+15 {
+		t.Errorf("exit code after SIGTERM = %d, want 143", code)
+	}
+}
+
+func TestSubstitute(t *testing.T) {
+	got := substitute([]string{"compose", "--env-file", envFilePlaceholder}, envFilePlaceholder, "/tmp/x.env")
+	if got[2] != "/tmp/x.env" {
+		t.Errorf("substitute = %v", got)
+	}
+}
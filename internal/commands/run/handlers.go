@@ -0,0 +1,235 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+
+	"1ctl/internal/api"
+	"1ctl/internal/config"
+	"1ctl/internal/deploy"
+	"1ctl/internal/envfile"
+	"1ctl/internal/utils"
+
+	"golang.org/x/term"
+)
+
+// envFileEnv names the variable that carries the --env-file path.
+const envFileEnv = "SATUSKY_ENV_FILE"
+
+// --- Handlers -----------------------------------------------------------
+
+func handleRun(ctx context.Context, in runInput) error {
+	client := api.FromContext(ctx)
+	deploymentID, err := deploy.ResolveDeploymentID(client, in.DeploymentID, in.App, in.Config)
+	if err != nil {
+		return err
+	}
+
+	var remoteEnv, secrets map[string]string
+	envs, err := client.GetEnvironmentsByDeploymentID(deploymentID)
+	if err != nil {
+		return utils.NewError("failed to fetch environment", err)
+	}
+	if len(envs) > 0 {
+		remoteEnv = envfile.ToMap(envs[0].KeyValues)
+	}
+	if !in.NoSecrets {
+		bundles, err := client.GetSecretsByDeploymentID(deploymentID)
+		if err != nil {
+			return utils.NewError("failed to fetch secrets", err)
+		}
+		if len(bundles) > 0 {
+			secrets = envfile.ToMap(bundles[0].KeyValues)
+		}
+	}
+
+	// [env] only describes this app when the config is the one that named
+	// it; "--app other" from inside a project must not pick it up.
+	var configEnv map[string]string
+	if cfg, err := config.FindConfig(in.Config); err == nil && cfg != nil {
+		if in.App == "" || in.App == cfg.App.Name {
+			configEnv = cfg.Env
+		}
+	}
+
+	overrides := make(map[string]string, len(in.Env))
+	for _, kv := range in.Env {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok || key == "" {
+			return utils.NewError(fmt.Sprintf("invalid --env format (expected KEY=VALUE): %s", kv), nil)
+		}
+		overrides[key] = value
+	}
+
+	values := mergeEnv(remoteEnv, configEnv, secrets, overrides)
+	values, missing := filterKeys(values, in.Only)
+	for _, key := range missing {
+		utils.PrintWarning("--only key %q is not set for this app", key)
+	}
+
+	args := in.Args
+	env := os.Environ()
+	if in.EnvFile {
+		path, cleanup, err := writeEnvFile(values)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		args = substitute(args, envFilePlaceholder, path)
+		env = append(env, envFileEnv+"="+path)
+	} else {
+		env = append(env, environ(values)...)
+	}
+
+	code, err := execChild(args, env)
+	if err != nil {
+		return err
+	}
+	if code != 0 {
+		return utils.NewExitStatusError(code)
+	}
+	return nil
+}
+
+// mergeEnv layers the sources in increasing precedence. As in deploy,
+// satusky.toml [env] is upserted over the deployment's environment; secrets
+// win over plain variables, and an explicit --env overrides everything.
+func mergeEnv(remoteEnv, configEnv, secrets, overrides map[string]string) map[string]string {
+	merged := make(map[string]string, len(remoteEnv)+len(configEnv)+len(secrets)+len(overrides))
+	for _, layer := range []map[string]string{remoteEnv, configEnv, secrets, overrides} {
+		for k, v := range layer {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+// filterKeys keeps only the given keys of values, returning the keys that
+// were requested but not present. An empty filter keeps everything.
+func filterKeys(values map[string]string, only []string) (map[string]string, []string) {
+	if len(only) == 0 {
+		return values, nil
+	}
+	filtered := make(map[string]string, len(only))
+	var missing []string
+	for _, key := range only {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+		if v, ok := values[key]; ok {
+			filtered[key] = v
+		} else {
+			missing = append(missing, key)
+		}
+	}
+	return filtered, missing
+}
+
+// environ returns values as KEY=VALUE entries in key order.
+func environ(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	entries := make([]string, 0, len(keys))
+	for _, k := range keys {
+		entries = append(entries, k+"="+values[k])
+	}
+	return entries
+}
+
+func substitute(args []string, placeholder, value string) []string {
+	out := make([]string, len(args))
+	for i, a := range args {
+		out[i] = strings.ReplaceAll(a, placeholder, value)
+	}
+	return out
+}
+
+// writeEnvFile writes values to an owner-only temporary dotenv file and
+// returns a function that removes it.
+func writeEnvFile(values map[string]string) (string, func(), error) {
+	f, err := os.CreateTemp("", "1ctl-run-*.env")
+	if err != nil {
+		return "", nil, utils.NewError("failed to create env file", err)
+	}
+	cleanup := func() { _ = os.Remove(f.Name()) } //nolint:errcheck
+	if err := envfile.Write(f, values, envfile.FormatDotenv); err != nil {
+		_ = f.Close() //nolint:errcheck
+		cleanup()
+		return "", nil, utils.NewError("failed to write env file", err)
+	}
+	if err := f.Close(); err != nil {
+		cleanup()
+		return "", nil, utils.NewError("failed to write env file", err)
+	}
+	return f.Name(), cleanup, nil
+}
+
+// execChild runs args with env attached to this terminal, forwarding
+// signals until it exits. It returns the child's exit code, using the shell
+// convention of 128+signal when the child was killed by a signal.
+//
+// The child shares 1ctl's process group, so when stdin is a terminal,
+// Ctrl-C and Ctrl-\ already reach it from the terminal; they are caught so
+// 1ctl outlives the child, but not forwarded, which would deliver them
+// twice.
+func execChild(args, env []string) (int, error) {
+	path, err := exec.LookPath(args[0])
+	if err != nil {
+		return 0, utils.NewError(fmt.Sprintf("command not found: %s", args[0]), nil)
+	}
+
+	child := exec.Command(path, args[1:]...) // #nosec G204 -- running the user's command is the point
+	child.Env = env
+	child.Stdin = os.Stdin
+	child.Stdout = os.Stdout
+	child.Stderr = os.Stderr
+
+	sigs := make(chan os.Signal, 4)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP, syscall.SIGQUIT)
+	defer signal.Stop(sigs)
+	fromTerminal := term.IsTerminal(int(os.Stdin.Fd())) // #nosec G115 -- a file descriptor
+
+	if err := child.Start(); err != nil {
+		return 0, utils.NewError(fmt.Sprintf("failed to start %s: %s", args[0], err.Error()), nil)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case sig := <-sigs:
+				if fromTerminal && (sig == os.Interrupt || sig == syscall.SIGQUIT) {
+					continue
+				}
+				_ = child.Process.Signal(sig) //nolint:errcheck
+			case <-done:
+				return
+			}
+		}
+	}()
+	err = child.Wait()
+	close(done)
+
+	if err == nil {
+		return 0, nil
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return 0, utils.NewError(fmt.Sprintf("failed to run %s: %s", args[0], err.Error()), nil)
+	}
+	if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		return 128 + int(status.Signal()), nil
+	}
+	return exitErr.ExitCode(), nil
+}
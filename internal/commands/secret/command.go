@@ -17,6 +17,16 @@ const (
 	flagKV           = "kv"
 	flagKey          = "key"
 	flagID           = "id"
+	flagFile         = "file"
+	flagFormat       = "format"
+	flagPrune        = "prune"
+	flagYes          = "yes"
+	flagForce        = "force"
+	flagRecipient    = "recipient"
+	flagPassphrase   = "passphrase"
+	flagComment      = "comment"
+	flagNoRestart    = "no-restart"
+	flagUnset        = "unset"
 )
 
 // --- Input structs ------------------------------------------------------
@@ -28,6 +38,7 @@ type secretCreateInput struct {
 	Name         string
 	KV           []string
 	Args         []string // positional args (KEY=VALUE pairs)
+	NoRestart    bool
 }
 
 type secretUnsetInput struct {
@@ -35,6 +46,16 @@ type secretUnsetInput struct {
 	App          string
 	Config       string
 	Key          string
+	NoRestart    bool
+}
+
+type secretSetInput struct {
+	DeploymentID string
+	App          string
+	Config       string
+	Args         []string // positional args (KEY=VALUE pairs)
+	Unset        []string
+	NoRestart    bool
 }
 
 type secretGetInput struct {
@@ -47,18 +68,75 @@ type secretListInput struct {
 	App string // optional --app filter
 }
 
+type secretImportInput struct {
+	DeploymentID string
+	App          string
+	Config       string
+	File         string
+	Format       string
+	Prune        bool
+	Yes          bool
+}
+
+type secretExportInput struct {
+	DeploymentID string
+	App          string
+	Config       string
+	Format       string
+}
+
+type secretKeygenInput struct {
+	Force bool
+}
+
+type secretEncryptInput struct {
+	File       string // positional: plaintext dotenv or JSON file
+	Format     string
+	Recipients []string // in addition to the repo recipients file
+	Passphrase bool
+}
+
+type secretDecryptInput struct {
+	File   string // positional: encrypted file
+	Format string
+}
+
+type secretApplyInput struct {
+	DeploymentID string
+	App          string
+	Config       string
+	File         string // positional: encrypted file
+	Prune        bool
+	Yes          bool
+}
+
+type secretRecipientsInput struct {
+	Key     string // positional: public key (or comment, for remove)
+	Comment string
+	Files   []string // encrypted files to re-encrypt for the new set
+}
+
 // --- Command tree -------------------------------------------------------
 
 // Command returns the root secret command tree.
 func Command() *cli.Command {
 	return &cli.Command{
-		Name:  "secret",
-		Usage: "Manage secrets",
+		Name:   "secret",
+		Usage:  "Manage secrets",
+		Before: checkLogin,
 		Commands: []*cli.Command{
 			secretCreateCommand(),
+			secretSetCommand(),
 			secretListCommand(),
 			secretGetCommand(),
 			secretUnsetCommand(),
+			secretImportCommand(),
+			secretExportCommand(),
+			secretKeygenCommand(),
+			secretEncryptCommand(),
+			secretDecryptCommand(),
+			secretApplyCommand(),
+			secretRecipientsCommand(),
 		},
 	}
 }
@@ -95,6 +173,11 @@ func secretCreateCommand() *cli.Command {
 				Usage:       "Secret key-value pairs (format: KEY=VALUE)",
 				Destination: &in.KV,
 			},
+			&cli.BoolFlag{
+				Name:        flagNoRestart,
+				Usage:       "Stage the change without restarting (apply later with '1ctl app apply-config')",
+				Destination: &in.NoRestart,
+			},
 		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
 			in.Args = cmd.Args().Slice()
@@ -103,6 +186,55 @@ func secretCreateCommand() *cli.Command {
 	}
 }
 
+func secretSetCommand() *cli.Command {
+	var in secretSetInput
+	return &cli.Command{
+		Name:      "set",
+		Usage:     "Set and unset several secrets with a single restart",
+		ArgsUsage: "KEY=VALUE...",
+		Description: `Apply every KEY=VALUE and --unset KEY as one change. The result is read
+back and verified before the deployment restarts once; if anything fails,
+the keys already written are rolled back and nothing is restarted.
+
+Examples:
+   1ctl secret set DB_PASSWORD=s3cret API_KEY=abc --app api
+   1ctl secret set NEW_TOKEN=xyz --unset OLD_TOKEN --app api
+   1ctl secret set A=1 --no-restart --app api`,
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:        flagUnset,
+				Usage:       "Key to remove (repeatable)",
+				Destination: &in.Unset,
+			},
+			&cli.BoolFlag{
+				Name:        flagNoRestart,
+				Usage:       "Stage the change without restarting (apply later with '1ctl app apply-config')",
+				Destination: &in.NoRestart,
+			},
+			&cli.StringFlag{
+				Name:        flagApp,
+				Usage:       "App name to resolve (alternative to --deployment-id)",
+				Destination: &in.App,
+			},
+			&cli.StringFlag{
+				Name:        flagConfig,
+				Usage:       "Config name or path",
+				Destination: &in.Config,
+			},
+			&cli.StringFlag{
+				Name:        flagDeploymentID,
+				Aliases:     []string{"d"},
+				Usage:       "Deployment ID",
+				Destination: &in.DeploymentID,
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			in.Args = cmd.Args().Slice()
+			return handleSecretSet(ctx, in)
+		},
+	}
+}
+
 func secretListCommand() *cli.Command {
 	var in secretListInput
 	return &cli.Command{
@@ -179,7 +311,325 @@ func secretUnsetCommand() *cli.Command {
 				Required:    true,
 				Destination: &in.Key,
 			},
+			&cli.BoolFlag{
+				Name:        flagNoRestart,
+				Usage:       "Stage the change without restarting (apply later with '1ctl app apply-config')",
+				Destination: &in.NoRestart,
+			},
 		},
 		Action: func(ctx context.Context, cmd *cli.Command) error { return handleSecretUnset(ctx, in) },
 	}
 }
+
+func secretImportCommand() *cli.Command {
+	var in secretImportInput
+	return &cli.Command{
+		Name:  "import",
+		Usage: "Set secrets from a dotenv or JSON file",
+		Description: `Apply every key in a dotenv or JSON file as one change, with a single restart.
+A key-level diff (values masked) is shown before confirmation. With --prune,
+keys not present in the file are removed.
+
+Examples:
+   1ctl secret import --file .env.production
+   1ctl secret import --file secrets.json --prune --yes`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        flagFile,
+				Aliases:     []string{"f"},
+				Usage:       "File to import (.env or .json)",
+				Required:    true,
+				Destination: &in.File,
+			},
+			&cli.StringFlag{
+				Name:        flagFormat,
+				Usage:       "File format: dotenv or json (default: from file extension)",
+				Destination: &in.Format,
+			},
+			&cli.BoolFlag{
+				Name:        flagPrune,
+				Usage:       "Remove keys that are not in the file",
+				Destination: &in.Prune,
+			},
+			&cli.BoolFlag{
+				Name:        flagYes,
+				Aliases:     []string{"y"},
+				Usage:       "Apply without confirmation",
+				Destination: &in.Yes,
+			},
+			&cli.StringFlag{
+				Name:        flagApp,
+				Usage:       "App name to resolve (alternative to --deployment-id)",
+				Destination: &in.App,
+			},
+			&cli.StringFlag{
+				Name:        flagConfig,
+				Usage:       "Config name or path",
+				Destination: &in.Config,
+			},
+			&cli.StringFlag{
+				Name:        flagDeploymentID,
+				Aliases:     []string{"d"},
+				Usage:       "Deployment ID",
+				Destination: &in.DeploymentID,
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error { return handleSecretImport(ctx, in) },
+	}
+}
+
+func secretExportCommand() *cli.Command {
+	var in secretExportInput
+	return &cli.Command{
+		Name:  "export",
+		Usage: "Print secrets as dotenv, JSON or shell exports",
+		Description: `Print the secret values of a deployment to stdout.
+
+Examples:
+   1ctl secret export --app api > .env.production
+   1ctl secret export --app api --format json
+   eval "$(1ctl secret export --app api --format shell)"`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        flagFormat,
+				Usage:       "Output format: dotenv, json or shell",
+				Value:       "dotenv",
+				Destination: &in.Format,
+			},
+			&cli.StringFlag{
+				Name:        flagApp,
+				Usage:       "App name to resolve (alternative to --deployment-id)",
+				Destination: &in.App,
+			},
+			&cli.StringFlag{
+				Name:        flagConfig,
+				Usage:       "Config name or path",
+				Destination: &in.Config,
+			},
+			&cli.StringFlag{
+				Name:        flagDeploymentID,
+				Aliases:     []string{"d"},
+				Usage:       "Deployment ID",
+				Destination: &in.DeploymentID,
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error { return handleSecretExport(ctx, in) },
+	}
+}
+
+func secretKeygenCommand() *cli.Command {
+	var in secretKeygenInput
+	return &cli.Command{
+		Name:  "keygen",
+		Usage: "Generate a key for decrypting secrets files",
+		Description: `Generate an X25519 key pair in ~/.satusky/secret.key and print the public key.
+Add the public key to the repository with "1ctl secret recipients add" so
+encrypted files are sealed for it.`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:        flagForce,
+				Usage:       "Replace an existing key (files sealed only for it become unreadable)",
+				Destination: &in.Force,
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error { return handleSecretKeygen(ctx, in) },
+	}
+}
+
+func secretEncryptCommand() *cli.Command {
+	var in secretEncryptInput
+	return &cli.Command{
+		Name:      "encrypt",
+		Usage:     "Encrypt a dotenv or JSON file for committing",
+		ArgsUsage: "<file>",
+		Description: `Encrypt each value of a dotenv or JSON file and print the result as TOML.
+Keys stay readable so changes show up in diffs. The file is sealed for every
+key in .satusky-recipients at the repository root, any --recipient keys, and
+optionally a passphrase.
+
+Examples:
+   1ctl secret encrypt .env > secrets.enc.toml
+   1ctl secret encrypt .env --passphrase > secrets.enc.toml`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        flagFormat,
+				Usage:       "Input format: dotenv or json (default: from file extension)",
+				Destination: &in.Format,
+			},
+			&cli.StringSliceFlag{
+				Name:        flagRecipient,
+				Aliases:     []string{"r"},
+				Usage:       "Additional recipient public key (repeatable)",
+				Destination: &in.Recipients,
+			},
+			&cli.BoolFlag{
+				Name:        flagPassphrase,
+				Aliases:     []string{"p"},
+				Usage:       "Also allow decryption with a passphrase (read from $SATUSKY_SECRET_PASSPHRASE or prompted)",
+				Destination: &in.Passphrase,
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			if cmd.Args().Len() != 1 {
+				return cli.ShowSubcommandHelp(cmd)
+			}
+			in.File = cmd.Args().First()
+			return handleSecretEncrypt(ctx, in)
+		},
+	}
+}
+
+func secretDecryptCommand() *cli.Command {
+	var in secretDecryptInput
+	return &cli.Command{
+		Name:      "decrypt",
+		Usage:     "Print the values of an encrypted secrets file",
+		ArgsUsage: "<file>",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        flagFormat,
+				Usage:       "Output format: dotenv, json or shell",
+				Value:       "dotenv",
+				Destination: &in.Format,
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			if cmd.Args().Len() != 1 {
+				return cli.ShowSubcommandHelp(cmd)
+			}
+			in.File = cmd.Args().First()
+			return handleSecretDecrypt(ctx, in)
+		},
+	}
+}
+
+func secretApplyCommand() *cli.Command {
+	var in secretApplyInput
+	return &cli.Command{
+		Name:      "apply",
+		Usage:     "Decrypt a secrets file locally and sync it to a deployment",
+		ArgsUsage: "<file>",
+		Description: `Decrypt an encrypted secrets file with your key (or passphrase) and apply
+it as one change, with a single restart. Plaintext never leaves this machine
+except to the platform API.
+
+Examples:
+   1ctl secret apply secrets.enc.toml --app api
+   SATUSKY_SECRET_KEY=... 1ctl secret apply secrets.enc.toml --app api --prune --yes`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:        flagPrune,
+				Usage:       "Remove keys that are not in the file",
+				Destination: &in.Prune,
+			},
+			&cli.BoolFlag{
+				Name:        flagYes,
+				Aliases:     []string{"y"},
+				Usage:       "Apply without confirmation",
+				Destination: &in.Yes,
+			},
+			&cli.StringFlag{
+				Name:        flagApp,
+				Usage:       "App name to resolve (alternative to --deployment-id)",
+				Destination: &in.App,
+			},
+			&cli.StringFlag{
+				Name:        flagConfig,
+				Usage:       "Config name or path",
+				Destination: &in.Config,
+			},
+			&cli.StringFlag{
+				Name:        flagDeploymentID,
+				Aliases:     []string{"d"},
+				Usage:       "Deployment ID",
+				Destination: &in.DeploymentID,
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			if cmd.Args().Len() != 1 {
+				return cli.ShowSubcommandHelp(cmd)
+			}
+			in.File = cmd.Args().First()
+			return handleSecretApply(ctx, in)
+		},
+	}
+}
+
+func secretRecipientsCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "recipients",
+		Usage: "Manage who can decrypt secrets files in this repository",
+		Commands: []*cli.Command{
+			secretRecipientsListCommand(),
+			secretRecipientsAddCommand(),
+			secretRecipientsRemoveCommand(),
+		},
+	}
+}
+
+func secretRecipientsListCommand() *cli.Command {
+	return &cli.Command{
+		Name:   "list",
+		Usage:  "List recipient public keys",
+		Action: func(ctx context.Context, cmd *cli.Command) error { return handleRecipientsList(ctx) },
+	}
+}
+
+func secretRecipientsAddCommand() *cli.Command {
+	var in secretRecipientsInput
+	return &cli.Command{
+		Name:      "add",
+		Usage:     "Add a recipient public key",
+		ArgsUsage: "<public-key>",
+		Description: `Add a public key to .satusky-recipients. Pass --file to re-encrypt existing
+files so the new recipient can read them.
+
+Examples:
+   1ctl secret recipients add sky-x25519:... --comment alice
+   1ctl secret recipients add sky-x25519:... --file secrets.enc.toml`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        flagComment,
+				Usage:       "Label for the key, e.g. its owner",
+				Destination: &in.Comment,
+			},
+			&cli.StringSliceFlag{
+				Name:        flagFile,
+				Aliases:     []string{"f"},
+				Usage:       "Encrypted file to re-encrypt (repeatable)",
+				Destination: &in.Files,
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			if cmd.Args().Len() != 1 {
+				return cli.ShowSubcommandHelp(cmd)
+			}
+			in.Key = cmd.Args().First()
+			return handleRecipientsAdd(ctx, in)
+		},
+	}
+}
+
+func secretRecipientsRemoveCommand() *cli.Command {
+	var in secretRecipientsInput
+	return &cli.Command{
+		Name:      "remove",
+		Usage:     "Remove a recipient by public key or comment",
+		ArgsUsage: "<public-key|comment>",
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:        flagFile,
+				Aliases:     []string{"f"},
+				Usage:       "Encrypted file to re-encrypt without the removed key (repeatable)",
+				Destination: &in.Files,
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			if cmd.Args().Len() != 1 {
+				return cli.ShowSubcommandHelp(cmd)
+			}
+			in.Key = cmd.Args().First()
+			return handleRecipientsRemove(ctx, in)
+		},
+	}
+}
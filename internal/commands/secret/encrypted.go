@@ -0,0 +1,326 @@
+package secret
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"1ctl/internal/api"
+	"1ctl/internal/config"
+	"1ctl/internal/deploy"
+	"1ctl/internal/envfile"
+	"1ctl/internal/secretfile"
+	"1ctl/internal/utils"
+
+	"github.com/urfave/cli/v3"
+	"golang.org/x/term"
+)
+
+// localCommands work only on local files and keys, so they need no login.
+var localCommands = map[string]bool{"keygen": true, "encrypt": true, "decrypt": true, "recipients": true}
+
+// checkLogin requires a login for every secret subcommand but the local
+// ones. The subcommand is the first argument left once the secret command's
+// own flags are parsed, so global flags before it make no difference.
+func checkLogin(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+	if sub := cmd.Command(cmd.Args().First()); sub != nil && localCommands[sub.Name] {
+		return ctx, nil
+	}
+	return ctx, config.ValidateEnvironment()
+}
+
+func handleSecretKeygen(ctx context.Context, in secretKeygenInput) error {
+	path, err := secretfile.IdentityPath()
+	if err != nil {
+		return utils.NewError("failed to locate home directory", err)
+	}
+	id, err := secretfile.GenerateIdentity()
+	if err != nil {
+		return utils.NewError("failed to generate key", err)
+	}
+	if err := secretfile.SaveIdentity(id, path, in.Force); err != nil {
+		return utils.NewError(fmt.Sprintf("failed to save key: %s (use --force to replace it)", err.Error()), nil)
+	}
+
+	utils.PrintSuccess("Secret key written to %s", path)
+	utils.PrintStatusLine("Public key", id.Recipient())
+	utils.PrintInfo("Share the public key with a recipient, who runs: 1ctl secret recipients add %s", id.Recipient())
+	utils.PrintInfo("For CI, set %s to the contents of %s", secretfile.IdentityEnv, path)
+	return nil
+}
+
+func handleSecretEncrypt(ctx context.Context, in secretEncryptInput) error {
+	values, err := envfile.ReadFile(in.File, in.Format)
+	if err != nil {
+		return utils.NewError(fmt.Sprintf("failed to read %s: %s", in.File, err.Error()), nil)
+	}
+
+	recipients, err := loadRecipients()
+	if err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(recipients)+len(in.Recipients))
+	for _, r := range recipients {
+		keys = append(keys, r.Key)
+	}
+	keys = append(keys, in.Recipients...)
+
+	passphrase := ""
+	if in.Passphrase {
+		if passphrase, err = readPassphrase(true); err != nil {
+			return err
+		}
+	}
+
+	f, err := secretfile.Encrypt(values, keys, passphrase)
+	if err != nil {
+		return utils.NewError(err.Error(), nil)
+	}
+	if err := f.Write(os.Stdout); err != nil {
+		return utils.NewError("failed to write encrypted file", err)
+	}
+	return nil
+}
+
+func handleSecretDecrypt(ctx context.Context, in secretDecryptInput) error {
+	values, err := decryptFile(in.File)
+	if err != nil {
+		return err
+	}
+	if err := envfile.Write(os.Stdout, values, in.Format); err != nil {
+		return utils.NewError(err.Error(), nil)
+	}
+	return nil
+}
+
+func handleSecretApply(ctx context.Context, in secretApplyInput) error {
+	values, err := decryptFile(in.File)
+	if err != nil {
+		return err
+	}
+	client := api.FromContext(ctx)
+	deploymentID, err := deploy.ResolveDeploymentID(client, in.DeploymentID, in.App, in.Config)
+	if err != nil {
+		return err
+	}
+	return applySecretValues(client, deploymentID, values, in.File, in.Prune, in.Yes)
+}
+
+func handleRecipientsList(ctx context.Context) error {
+	recipients, err := loadRecipients()
+	if err != nil {
+		return err
+	}
+	if utils.PrintListOrJSON(recipients, "No recipients — add one with: 1ctl secret recipients add <public-key>") {
+		return nil
+	}
+	rows := make([][]string, 0, len(recipients))
+	for _, r := range recipients {
+		rows = append(rows, []string{r.Key, r.Comment})
+	}
+	utils.PrintTable([]string{"PUBLIC KEY", "COMMENT"}, rows)
+	return nil
+}
+
+func handleRecipientsAdd(ctx context.Context, in secretRecipientsInput) error {
+	if _, err := secretfile.ParseRecipient(in.Key); err != nil {
+		return utils.NewError(err.Error(), nil)
+	}
+	recipients, err := loadRecipients()
+	if err != nil {
+		return err
+	}
+	for _, r := range recipients {
+		if r.Key == in.Key {
+			utils.PrintInfo("%s is already a recipient", in.Key)
+			return rekeyFiles(in.Files, recipients)
+		}
+	}
+	recipients = append(recipients, secretfile.Recipient{Key: in.Key, Comment: in.Comment})
+	if err := rekeyFiles(in.Files, recipients); err != nil {
+		return err
+	}
+	if err := saveRecipients(recipients); err != nil {
+		return err
+	}
+	utils.PrintSuccess("Recipient added")
+	return nil
+}
+
+func handleRecipientsRemove(ctx context.Context, in secretRecipientsInput) error {
+	recipients, err := loadRecipients()
+	if err != nil {
+		return err
+	}
+	kept := recipients[:0]
+	for _, r := range recipients {
+		if r.Key != in.Key && (r.Comment == "" || r.Comment != in.Key) {
+			kept = append(kept, r)
+		}
+	}
+	if len(kept) == len(recipients) {
+		return utils.NewError(fmt.Sprintf("%s is not a recipient", in.Key), nil)
+	}
+	// Re-encrypt first so a failure leaves the recipients file unchanged.
+	if err := rekeyFiles(in.Files, kept); err != nil {
+		return err
+	}
+	if err := saveRecipients(kept); err != nil {
+		return err
+	}
+	utils.PrintSuccess("Recipient removed")
+	if len(in.Files) == 0 {
+		utils.PrintWarning("Existing encrypted files are still readable by the removed key — re-encrypt them with --file, and rotate the values it could read")
+	}
+	return nil
+}
+
+// rekeyFiles re-encrypts each file for recipients with a fresh data key, so
+// a removed recipient cannot open the new version even with an old wrapped
+// key from history. Passphrase protection is kept when the file had it.
+func rekeyFiles(paths []string, recipients []secretfile.Recipient) error {
+	keys := make([]string, 0, len(recipients))
+	for _, r := range recipients {
+		keys = append(keys, r.Key)
+	}
+	for _, path := range paths {
+		f, err := secretfile.Read(path)
+		if err != nil {
+			return utils.NewError(fmt.Sprintf("failed to read %s: %s", path, err.Error()), nil)
+		}
+		passphrase := ""
+		if f.HasPassphrase() {
+			if passphrase, err = readPassphrase(false); err != nil {
+				return err
+			}
+		}
+		id, err := secretfile.LoadIdentity()
+		if err != nil {
+			return utils.NewError("failed to load secret key", err)
+		}
+		values, err := f.Decrypt(id, func() (string, error) { return passphrase, nil })
+		if err != nil {
+			return utils.NewError(fmt.Sprintf("failed to decrypt %s: %s", path, err.Error()), nil)
+		}
+		updated, err := secretfile.Encrypt(values, keys, passphrase)
+		if err != nil {
+			return utils.NewError(fmt.Sprintf("failed to re-encrypt %s: %s", path, err.Error()), nil)
+		}
+		if err := writeFileAtomic(path, updated); err != nil {
+			return utils.NewError(fmt.Sprintf("failed to write %s: %s", path, err.Error()), nil)
+		}
+		utils.PrintSuccess("Re-encrypted %s for %d recipient(s)", path, len(keys))
+	}
+	return nil
+}
+
+func decryptFile(path string) (map[string]string, error) {
+	f, err := secretfile.Read(path)
+	if err != nil {
+		return nil, utils.NewError(fmt.Sprintf("failed to read %s: %s", path, err.Error()), nil)
+	}
+	id, err := secretfile.LoadIdentity()
+	if err != nil {
+		return nil, utils.NewError("failed to load secret key", err)
+	}
+	values, err := f.Decrypt(id, func() (string, error) { return readPassphrase(false) })
+	if err != nil {
+		if errors.Is(err, secretfile.ErrNoKey) && id == nil {
+			return nil, utils.NewError(fmt.Sprintf("no secret key found — run '1ctl secret keygen' or set %s", secretfile.IdentityEnv), nil)
+		}
+		return nil, utils.NewError(fmt.Sprintf("failed to decrypt %s: %s", path, err.Error()), nil)
+	}
+	return values, nil
+}
+
+// recipientsPath returns the recipients file at the repository root.
+func recipientsPath() (string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", utils.NewError("failed to get working directory", err)
+	}
+	return filepath.Join(config.FindRepoRoot(wd), secretfile.RecipientsFileName), nil
+}
+
+func loadRecipients() ([]secretfile.Recipient, error) {
+	path, err := recipientsPath()
+	if err != nil {
+		return nil, err
+	}
+	recipients, err := secretfile.ReadRecipients(path)
+	if err != nil {
+		return nil, utils.NewError("failed to read recipients", err)
+	}
+	return recipients, nil
+}
+
+func saveRecipients(recipients []secretfile.Recipient) error {
+	path, err := recipientsPath()
+	if err != nil {
+		return err
+	}
+	if err := secretfile.WriteRecipients(path, recipients); err != nil {
+		return utils.NewError(fmt.Sprintf("failed to write %s: %s", path, err.Error()), nil)
+	}
+	return nil
+}
+
+// readPassphrase returns SATUSKY_SECRET_PASSPHRASE or prompts on stderr, so
+// encrypted output on stdout can be redirected to a file.
+func readPassphrase(confirm bool) (string, error) {
+	if p := os.Getenv(secretfile.PassphraseEnv); p != "" {
+		return p, nil
+	}
+	prompt := func(label string) (string, error) {
+		fmt.Fprint(os.Stderr, label)
+		b, err := term.ReadPassword(int(syscall.Stdin))
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			line, readErr := bufio.NewReader(os.Stdin).ReadString('\n')
+			if readErr != nil {
+				return "", utils.NewError(fmt.Sprintf("failed to read passphrase (set %s for non-interactive use)", secretfile.PassphraseEnv), nil)
+			}
+			return strings.TrimSpace(line), nil
+		}
+		return string(b), nil
+	}
+	pass, err := prompt("Passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if pass == "" {
+		return "", utils.NewError("passphrase must not be empty", nil)
+	}
+	if confirm {
+		again, err := prompt("Confirm passphrase: ")
+		if err != nil {
+			return "", err
+		}
+		if again != pass {
+			return "", utils.NewError("passphrases do not match", nil)
+		}
+	}
+	return pass, nil
+}
+
+func writeFileAtomic(path string, f *secretfile.File) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	if err := f.Write(tmp); err != nil {
+		_ = tmp.Close()           //nolint:errcheck
+		_ = os.Remove(tmp.Name()) //nolint:errcheck
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name()) //nolint:errcheck
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -3,11 +3,13 @@ package secret
 import (
 	"context"
 	"fmt"
+	"os"
 	"strings"
 
 	"1ctl/internal/api"
 	satuskyctx "1ctl/internal/context"
 	"1ctl/internal/deploy"
+	"1ctl/internal/envfile"
 	"1ctl/internal/utils"
 
 	"github.com/google/uuid"
@@ -16,14 +18,15 @@ import (
 // --- Handlers -----------------------------------------------------------
 
 func handleCreateSecret(ctx context.Context, in secretCreateInput) error {
-	deploymentIDStr, err := deploy.ResolveDeploymentID(in.DeploymentID, in.App, in.Config)
+	client := api.FromContext(ctx)
+	deploymentIDStr, err := deploy.ResolveDeploymentID(client, in.DeploymentID, in.App, in.Config)
 	if err != nil {
 		return err
 	}
 
 	deploymentID, err := uuid.Parse(deploymentIDStr)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("invalid deployment-id: %s", err.Error()), nil)
+		return utils.NewError("invalid deployment-id", err)
 	}
 
 	// Collect key-value pairs from BOTH --kv/--env flags AND positional args.
@@ -49,9 +52,9 @@ func handleCreateSecret(ctx context.Context, in secretCreateInput) error {
 
 	appLabel := in.Name
 	if appLabel == "" {
-		deployment, err := api.GetDeployment(deploymentIDStr)
+		deployment, err := client.GetDeployment(deploymentIDStr)
 		if err != nil {
-			return utils.NewError(fmt.Sprintf("failed to resolve deployment name: %s", err.Error()), nil)
+			return utils.NewError("failed to resolve deployment name", err)
 		}
 		appLabel = deployment.AppLabel
 	}
@@ -63,9 +66,9 @@ func handleCreateSecret(ctx context.Context, in secretCreateInput) error {
 		KeyValues:    keyValues,
 	}
 
-	secretResp, err := api.CreateSecret(secret)
+	secretResp, err := client.CreateSecret(secret)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to create secret: %s", err.Error()), nil)
+		return utils.NewError("failed to create secret", err)
 	}
 
 	displayName := secretResp.AppLabel
@@ -74,20 +77,15 @@ func handleCreateSecret(ctx context.Context, in secretCreateInput) error {
 	}
 	utils.PrintSuccess("Secret %s created successfully\n", displayName)
 
-	utils.PrintInfo("Restarting deployment to activate secrets...")
-	if err := api.RestartDeployment(deploymentIDStr); err != nil {
-		utils.PrintWarning("Secret created, but restart failed: %s", err.Error())
-		utils.PrintInfo("Run: 1ctl deploy restart --app %s", displayName)
-	} else {
-		utils.PrintSuccess("Deployment restarting — secrets will be available shortly")
-	}
+	deploy.RestartAfterChange(client, deploymentIDStr, displayName, "secrets", in.NoRestart)
 	return nil
 }
 
 func handleListSecrets(ctx context.Context, in secretListInput) error {
-	secrets, err := api.ListSecrets()
+	client := api.FromContext(ctx)
+	secrets, err := client.ListSecrets()
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to list secrets: %s", err.Error()), nil)
+		return utils.NewError("failed to list secrets", err)
 	}
 
 	// Filter by app name if requested
@@ -101,7 +99,11 @@ func handleListSecrets(ctx context.Context, in secretListInput) error {
 		secrets = filtered
 	}
 
-	if utils.PrintListOrJSON(secrets, "No secrets found") {
+	masked := make([]api.Secret, 0, len(secrets))
+	for _, s := range secrets {
+		masked = append(masked, maskedSecret(s))
+	}
+	if utils.PrintListOrJSON(masked, "No secrets found") {
 		return nil
 	}
 
@@ -121,30 +123,264 @@ func handleListSecrets(ctx context.Context, in secretListInput) error {
 }
 
 func handleSecretUnset(ctx context.Context, in secretUnsetInput) error {
-	deploymentID, err := deploy.ResolveDeploymentID(in.DeploymentID, in.App, in.Config)
+	client := api.FromContext(ctx)
+	deploymentID, err := deploy.ResolveDeploymentID(client, in.DeploymentID, in.App, in.Config)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to resolve deployment: %s", err.Error()), nil)
+		return utils.NewError("failed to resolve deployment", err)
 	}
 
-	secrets, err := api.GetSecretsByDeploymentID(deploymentID)
+	secrets, err := client.GetSecretsByDeploymentID(deploymentID)
 	if err != nil || len(secrets) == 0 {
 		return utils.NewError("no secret found for this deployment", nil)
 	}
 
-	if err := api.UnsetSecretKey(secrets[0].SecretID.String(), in.Key); err != nil {
-		return utils.NewError(fmt.Sprintf("failed to unset key: %s", err.Error()), nil)
+	if err := client.UnsetSecretKey(secrets[0].SecretID.String(), in.Key); err != nil {
+		return utils.NewError("failed to unset key", err)
 	}
 
 	utils.PrintSuccess("Key %q removed from secrets", in.Key)
+	deploy.RestartAfterChange(client, deploymentID, secrets[0].AppLabel, "secrets", in.NoRestart)
+	return nil
+}
+
+func handleSecretSet(ctx context.Context, in secretSetInput) error {
+	client := api.FromContext(ctx)
+	if len(in.Args) == 0 && len(in.Unset) == 0 {
+		return utils.NewError("nothing to change: pass KEY=VALUE pairs and/or --unset KEY", nil)
+	}
+	sets := make(map[string]string, len(in.Args))
+	for _, kv := range in.Args {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok || key == "" {
+			return utils.NewError(fmt.Sprintf("invalid key-value format (expected KEY=VALUE): %s", kv), nil)
+		}
+		sets[key] = value
+	}
+	for _, key := range in.Unset {
+		if _, ok := sets[key]; ok {
+			return utils.NewError(fmt.Sprintf("key %q is both set and unset", key), nil)
+		}
+	}
+
+	deploymentIDStr, err := deploy.ResolveDeploymentID(client, in.DeploymentID, in.App, in.Config)
+	if err != nil {
+		return err
+	}
+	deploymentID, err := uuid.Parse(deploymentIDStr)
+	if err != nil {
+		return utils.NewError("invalid deployment-id", err)
+	}
+
+	existing, live, err := liveSecrets(client, deploymentIDStr)
+	if err != nil {
+		return err
+	}
+	desired := make(map[string]string, len(live)+len(sets))
+	for k, v := range live {
+		desired[k] = v
+	}
+	for k, v := range sets {
+		desired[k] = v
+	}
+	for _, key := range in.Unset {
+		if _, ok := live[key]; !ok {
+			utils.PrintWarning("Key %q is not set; skipping", key)
+		}
+		delete(desired, key)
+	}
+
+	changes := envfile.Diff(live, desired, true)
+	if len(changes) == 0 {
+		utils.PrintSuccess("Secrets already up to date")
+		return nil
+	}
+
+	appLabel, err := secretAppLabel(client, deploymentIDStr, existing)
+	if err != nil {
+		return err
+	}
+	if err := commitSecretChanges(client, deploymentID, appLabel, existing, live, desired, changes); err != nil {
+		return err
+	}
+	utils.PrintSuccess("Secrets updated: %s", envfile.Summary(changes))
+	envfile.PrintDiff(changes)
+
+	deploy.RestartAfterChange(client, deploymentIDStr, appLabel, "secrets", in.NoRestart)
+	return nil
+}
+
+func handleSecretImport(ctx context.Context, in secretImportInput) error {
+	desired, err := envfile.ReadFile(in.File, in.Format)
+	if err != nil {
+		return utils.NewError(fmt.Sprintf("failed to read %s: %s", in.File, err.Error()), nil)
+	}
+
+	client := api.FromContext(ctx)
+	deploymentID, err := deploy.ResolveDeploymentID(client, in.DeploymentID, in.App, in.Config)
+	if err != nil {
+		return err
+	}
+	return applySecretValues(client, deploymentID, desired, in.File, in.Prune, in.Yes)
+}
+
+// applySecretValues makes a deployment's secrets match desired as one
+// change: a masked key-level diff, one confirmation, one verified commit and
+// a single restart. source names where desired came from.
+func applySecretValues(client api.API, deploymentIDStr string, desired map[string]string, source string, prune, yes bool) error {
+	deploymentID, err := uuid.Parse(deploymentIDStr)
+	if err != nil {
+		return utils.NewError("invalid deployment-id", err)
+	}
+
+	existing, live, err := liveSecrets(client, deploymentIDStr)
+	if err != nil {
+		return err
+	}
+	changes := envfile.Diff(live, desired, prune)
+	if len(changes) == 0 {
+		utils.PrintSuccess("Secrets already match %s", source)
+		return nil
+	}
+
+	utils.PrintInfo("Secret changes from %s (%s):", source, envfile.Summary(changes))
+	envfile.PrintDiff(changes)
+	if !utils.Confirm("Apply these changes and restart the deployment?", yes) {
+		fmt.Println("Aborted.")
+		return nil
+	}
+
+	appLabel, err := secretAppLabel(client, deploymentIDStr, existing)
+	if err != nil {
+		return err
+	}
+	if err := commitSecretChanges(client, deploymentID, appLabel, existing, live, desired, changes); err != nil {
+		return err
+	}
+	utils.PrintSuccess("Secrets updated: %s", envfile.Summary(changes))
+
+	deploy.RestartAfterChange(client, deploymentIDStr, appLabel, "secrets", false)
+	return nil
+}
+
+// liveSecrets returns the deployment's secret bundle and its values. A
+// deployment has at most one bundle; none yet yields nil and an empty map.
+// Any other failed read is returned, so it is never mistaken for a
+// deployment without secrets.
+func liveSecrets(client api.SecretsAPI, deploymentID string) (*api.Secret, map[string]string, error) {
+	secrets, err := client.GetSecretsByDeploymentID(deploymentID)
+	if err != nil && utils.KindOf(err) != utils.ErrNotFound {
+		return nil, nil, utils.NewError("failed to read the current secrets", err)
+	}
+	if len(secrets) == 0 {
+		return nil, map[string]string{}, nil
+	}
+	return &secrets[0], envfile.ToMap(secrets[0].KeyValues), nil
+}
+
+func secretAppLabel(client api.DeploymentsAPI, deploymentID string, existing *api.Secret) (string, error) {
+	if existing != nil && existing.AppLabel != "" {
+		return existing.AppLabel, nil
+	}
+	deployment, err := client.GetDeployment(deploymentID)
+	if err != nil {
+		return "", utils.NewError("failed to resolve deployment name", err)
+	}
+	return deployment.AppLabel, nil
+}
+
+// commitSecretChanges writes changes as one upsert plus an unset per removed
+// key, then re-reads the bundle to check every key landed. If a step fails or
+// the result does not match, the keys already written are reverted to live
+// so a partial update is never left behind for the next restart to pick up.
+func commitSecretChanges(client api.SecretsAPI, deploymentID uuid.UUID, appLabel string, existing *api.Secret, live, desired map[string]string, changes []envfile.Change) error {
+	secretID := ""
+	if existing != nil {
+		secretID = existing.SecretID.String()
+	}
+
+	if upserts := envfile.Upserts(changes, desired); len(upserts) > 0 {
+		resp, err := client.CreateSecret(api.Secret{
+			DeploymentID: deploymentID,
+			AppLabel:     appLabel,
+			Namespace:    satuskyctx.GetCurrentNamespace(),
+			KeyValues:    upserts,
+		})
+		if err != nil {
+			return utils.NewError("failed to update secrets", err)
+		}
+		if secretID == "" {
+			secretID = resp.SecretID.String()
+		}
+	}
+	for _, key := range envfile.Removals(changes) {
+		if err := client.UnsetSecretKey(secretID, key); err != nil {
+			return revertSecretChanges(client, deploymentID, appLabel, secretID, live, changes,
+				fmt.Sprintf("failed to remove key %q: %s", key, err.Error()))
+		}
+	}
+
+	secrets, err := client.GetSecretsByDeploymentID(deploymentID.String())
+	if err != nil || len(secrets) == 0 {
+		return utils.NewError("secrets were updated but could not be read back to verify; the deployment was not restarted", nil)
+	}
+	if bad := envfile.Unverified(changes, envfile.ToMap(secrets[0].KeyValues), desired); len(bad) > 0 {
+		return revertSecretChanges(client, deploymentID, appLabel, secretID, live, changes,
+			fmt.Sprintf("secrets did not match after update (keys: %s)", strings.Join(bad, ", ")))
+	}
+	return nil
+}
+
+func revertSecretChanges(client api.SecretsAPI, deploymentID uuid.UUID, appLabel, secretID string, live map[string]string, changes []envfile.Change, cause string) error {
+	restore, remove := envfile.Revert(changes, live)
+	var failed []string
+	if len(restore) > 0 {
+		if _, err := client.CreateSecret(api.Secret{
+			DeploymentID: deploymentID,
+			AppLabel:     appLabel,
+			Namespace:    satuskyctx.GetCurrentNamespace(),
+			KeyValues:    restore,
+		}); err != nil {
+			for _, kv := range restore {
+				failed = append(failed, kv.Key)
+			}
+		}
+	}
+	for _, key := range remove {
+		if secretID == "" || client.UnsetSecretKey(secretID, key) != nil {
+			failed = append(failed, key)
+		}
+	}
+	if len(failed) > 0 {
+		return utils.NewError(fmt.Sprintf("%s; rollback failed for keys %s — check with '1ctl secret get --app %s'", cause, strings.Join(failed, ", "), appLabel), nil)
+	}
+	return utils.NewError(fmt.Sprintf("%s; changes were rolled back and the deployment was not restarted", cause), nil)
+}
+
+func handleSecretExport(ctx context.Context, in secretExportInput) error {
+	client := api.FromContext(ctx)
+	deploymentID, err := deploy.ResolveDeploymentID(client, in.DeploymentID, in.App, in.Config)
+	if err != nil {
+		return utils.NewError("failed to resolve deployment", err)
+	}
+
+	secrets, err := client.GetSecretsByDeploymentID(deploymentID)
+	if err != nil || len(secrets) == 0 {
+		return utils.NewError("no secret found for this deployment", nil)
+	}
+
+	if err := envfile.Write(os.Stdout, envfile.ToMap(secrets[0].KeyValues), in.Format); err != nil {
+		return utils.NewError(err.Error(), nil)
+	}
 	return nil
 }
 
 func handleGetSecret(ctx context.Context, in secretGetInput) error {
+	client := api.FromContext(ctx)
 	// --- Path 1: Lookup by --id (escape hatch) ---
 	if in.ID != "" {
-		secrets, err := api.ListSecrets()
+		secrets, err := client.ListSecrets()
 		if err != nil {
-			return utils.NewError(fmt.Sprintf("failed to fetch secrets: %s", err.Error()), nil)
+			return utils.NewError("failed to fetch secrets", err)
 		}
 		for _, s := range secrets {
 			if s.SecretID.String() == in.ID {
@@ -155,12 +391,12 @@ func handleGetSecret(ctx context.Context, in secretGetInput) error {
 	}
 
 	// --- Path 2: Lookup by --app [key] ---
-	deploymentID, err := deploy.ResolveDeploymentID("", in.App, "")
+	deploymentID, err := deploy.ResolveDeploymentID(client, "", in.App, "")
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to resolve app: %s", err.Error()), nil)
+		return utils.NewError("failed to resolve app", err)
 	}
 
-	secrets, err := api.GetSecretsByDeploymentID(deploymentID)
+	secrets, err := client.GetSecretsByDeploymentID(deploymentID)
 	if err != nil || len(secrets) == 0 {
 		return utils.NewError(fmt.Sprintf("no secrets found for app %q", in.App), nil)
 	}
@@ -170,7 +406,7 @@ func handleGetSecret(ctx context.Context, in secretGetInput) error {
 		// Show just the specified key
 		for _, kv := range secret.KeyValues {
 			if kv.Key == in.Key {
-				if utils.TryPrintJSON(kv) {
+				if utils.TryPrintJSON(maskedValue(kv)) {
 					return nil
 				}
 				utils.PrintHeader("Secret %s", in.Key)
@@ -187,11 +423,30 @@ func handleGetSecret(ctx context.Context, in secretGetInput) error {
 	return printSecretBundle(&secret)
 }
 
+// maskedSecret returns s with its values left empty unless --reveal was
+// passed. Output masking skips short values, so secrets are never printed
+// on the strength of it.
+func maskedSecret(s api.Secret) api.Secret {
+	kvs := make([]api.KeyValuePair, 0, len(s.KeyValues))
+	for _, kv := range s.KeyValues {
+		kvs = append(kvs, maskedValue(kv))
+	}
+	s.KeyValues = kvs
+	return s
+}
+
+func maskedValue(kv api.KeyValuePair) api.KeyValuePair {
+	if !utils.IsRevealed() {
+		kv.Value = ""
+	}
+	return kv
+}
+
 func printSecretBundle(s *api.Secret) error {
 	if s == nil {
 		return nil
 	}
-	if utils.TryPrintJSON(s) {
+	if utils.TryPrintJSON(maskedSecret(*s)) {
 		return nil
 	}
 	utils.PrintHeader("Secret %s", s.AppLabel)
@@ -0,0 +1,141 @@
+package secret
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"1ctl/internal/api"
+	"1ctl/internal/api/apitest"
+	satuskyctx "1ctl/internal/context"
+	"1ctl/internal/envfile"
+	"1ctl/internal/utils"
+)
+
+// useTestProfile keeps the namespace lookups off the real config.
+func useTestProfile(t *testing.T) {
+	t.Helper()
+	original := satuskyctx.Default()
+	t.Cleanup(func() { satuskyctx.SetDefault(original) })
+	store := satuskyctx.NewTestStore(t.TempDir())
+	store.SetProfileOverride("test")
+	satuskyctx.SetDefault(store)
+}
+
+func writeEnvFile(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestSecretImportAgainstFakeAPI(t *testing.T) {
+	useTestProfile(t)
+	srv := apitest.NewServer(t)
+	ctx := api.WithClient(context.Background(), srv.Client())
+	dep := srv.AddDeployment(api.Deployment{Namespace: "acme", AppLabel: "web", Image: "web:1"})
+	id := dep.DeploymentID.String()
+
+	if err := handleSecretImport(ctx, secretImportInput{DeploymentID: id, File: writeEnvFile(t, "TOKEN=a\nPASSWORD=b\n"), Yes: true}); err != nil {
+		t.Fatalf("first import: %v", err)
+	}
+	if err := handleSecretImport(ctx, secretImportInput{DeploymentID: id, File: writeEnvFile(t, "TOKEN=c\n"), Prune: true, Yes: true}); err != nil {
+		t.Fatalf("pruning import: %v", err)
+	}
+	secrets := srv.Secrets()
+	if len(secrets) != 1 {
+		t.Fatalf("secrets = %+v, want one bundle", secrets)
+	}
+	if got := envfile.ToMap(secrets[0].KeyValues); len(got) != 1 || got["TOKEN"] != "c" {
+		t.Errorf("secrets after a pruning import = %v, want only TOKEN=c", got)
+	}
+	restarts := 0
+	for _, r := range srv.Requests() {
+		if r == "POST /deployments/"+id+"/restart" {
+			restarts++
+		}
+	}
+	if restarts != 2 {
+		t.Errorf("restarts = %d, want one per import", restarts)
+	}
+}
+
+func TestSecretImportAbortsWhenSecretsCannotBeRead(t *testing.T) {
+	useTestProfile(t)
+	srv := apitest.NewServer(t)
+	ctx := api.WithClient(context.Background(), srv.Client())
+	dep := srv.AddDeployment(api.Deployment{Namespace: "acme", AppLabel: "web", Image: "web:1"})
+	id := dep.DeploymentID.String()
+	srv.Fail(http.MethodGet, "/secrets/deploymentId/"+id, http.StatusInternalServerError, "database unavailable")
+
+	err := handleSecretImport(ctx, secretImportInput{DeploymentID: id, File: writeEnvFile(t, "TOKEN=a\n"), Prune: true, Yes: true})
+	if err == nil || !strings.Contains(err.Error(), "failed to read the current secrets") {
+		t.Errorf("handleSecretImport() error = %v, want the failed read", err)
+	}
+	for _, r := range srv.Requests() {
+		if !strings.HasPrefix(r, http.MethodGet+" ") {
+			t.Errorf("request %q was sent after the secrets could not be read", r)
+		}
+	}
+}
+
+// captureStdout returns what fn prints to stdout.
+func captureStdout(t *testing.T, fn func() error) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	runErr := fn()
+	os.Stdout = stdout
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runErr != nil {
+		t.Fatal(runErr)
+	}
+	return string(out)
+}
+
+func TestSecretGetJSONLeavesOutShortValues(t *testing.T) {
+	useTestProfile(t)
+	if err := satuskyctx.SetCurrentNamespace("acme"); err != nil {
+		t.Fatal(err)
+	}
+	utils.SetOutputFormat("json")
+	t.Cleanup(func() { utils.SetOutputFormat("table") })
+	srv := apitest.NewServer(t)
+	ctx := api.WithClient(context.Background(), srv.Client())
+	dep := srv.AddDeployment(api.Deployment{Namespace: "acme", AppLabel: "web", Image: "web:1"})
+	if _, err := srv.Client().CreateSecret(api.Secret{DeploymentID: dep.DeploymentID, AppLabel: "web", Namespace: "acme", KeyValues: []api.KeyValuePair{{Key: "PIN", Value: "4821"}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, in := range map[string]secretGetInput{
+		"bundle": {App: "web"},
+		"key":    {App: "web", Key: "PIN"},
+	} {
+		out := captureStdout(t, func() error { return handleGetSecret(ctx, in) })
+		if strings.Contains(out, "4821") || !strings.Contains(out, "PIN") {
+			t.Errorf("%s: secret get -o json printed %s", name, out)
+		}
+	}
+
+	utils.SetReveal(true)
+	t.Cleanup(func() { utils.SetReveal(false) })
+	if out := captureStdout(t, func() error { return handleGetSecret(ctx, secretGetInput{App: "web", Key: "PIN"}) }); !strings.Contains(out, "4821") {
+		t.Errorf("secret get --reveal -o json printed %s, want the value", out)
+	}
+}
@@ -14,10 +14,18 @@ func TestSecretCommand(t *testing.T) {
 
 	// Check subcommands
 	expectedCommands := map[string]bool{
-		"create": false,
-		"list":   false,
-		"get":    false,
-		"unset":  false,
+		"create":     false,
+		"set":        false,
+		"list":       false,
+		"get":        false,
+		"unset":      false,
+		"import":     false,
+		"export":     false,
+		"keygen":     false,
+		"encrypt":    false,
+		"decrypt":    false,
+		"apply":      false,
+		"recipients": false,
 	}
 
 	for _, subcmd := range cmd.Commands {
@@ -13,6 +13,7 @@ import (
 // --- Handlers -----------------------------------------------------------
 
 func handleUpsertService(ctx context.Context, in serviceUpsertInput) error {
+	client := api.FromContext(ctx)
 	if in.DeploymentID == "" {
 		return utils.NewError("--deployment-id flag is required for service", nil)
 	}
@@ -25,7 +26,7 @@ func handleUpsertService(ctx context.Context, in serviceUpsertInput) error {
 
 	deploymentID, err := uuid.Parse(in.DeploymentID)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("invalid deployment-id: %s", err.Error()), nil)
+		return utils.NewError("invalid deployment-id", err)
 	}
 
 	port, err := api.SafeInt32(in.Port)
@@ -40,8 +41,8 @@ func handleUpsertService(ctx context.Context, in serviceUpsertInput) error {
 	}
 
 	var serviceID string
-	if err := api.UpsertService(svc, &serviceID); err != nil {
-		return utils.NewError(fmt.Sprintf("failed to upsert service: %s", err.Error()), nil)
+	if err := client.UpsertService(svc, &serviceID); err != nil {
+		return utils.NewError("failed to upsert service", err)
 	}
 
 	utils.PrintSuccess("Service %s upserted successfully (ID: %s)\n", svc.ServiceName, serviceID)
@@ -49,9 +50,10 @@ func handleUpsertService(ctx context.Context, in serviceUpsertInput) error {
 }
 
 func handleListServices(ctx context.Context) error {
-	services, err := api.ListServices()
+	client := api.FromContext(ctx)
+	services, err := client.ListServices()
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to list services: %s", err.Error()), nil)
+		return utils.NewError("failed to list services", err)
 	}
 
 	if utils.PrintListOrJSON(services, "No services found") {
@@ -74,12 +76,13 @@ func handleListServices(ctx context.Context) error {
 }
 
 func handleDeleteService(ctx context.Context, in serviceDeleteInput) error {
+	client := api.FromContext(ctx)
 	if !utils.Confirm(fmt.Sprintf("Delete service %s? This cannot be undone.", in.ServiceID), in.Yes) {
 		fmt.Println("Aborted.")
 		return nil
 	}
-	if err := api.DeleteService(in.ServiceID); err != nil {
-		return utils.NewError(fmt.Sprintf("failed to delete service: %s", err.Error()), nil)
+	if err := client.DeleteService(in.ServiceID); err != nil {
+		return utils.NewError("failed to delete service", err)
 	}
 
 	utils.PrintSuccess("Service %s deleted successfully\n", in.ServiceID)
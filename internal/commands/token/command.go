@@ -11,8 +11,11 @@ import (
 // --- Flag name constants ------------------------------------------------
 
 const (
-	flagExpires = "expires"
-	flagYes     = "yes"
+	flagExpires  = "expires"
+	flagYes      = "yes"
+	flagRepo     = "repo"
+	flagBranch   = "branch"
+	flagProvider = "provider"
 )
 
 // --- Input structs ------------------------------------------------------
@@ -31,6 +34,17 @@ type tokenDeleteInput struct {
 	Yes     bool
 }
 
+type oidcTrustAddInput struct {
+	Repo     string
+	Branch   string
+	Provider string
+}
+
+type oidcTrustRemoveInput struct {
+	TrustID string
+	Yes     bool
+}
+
 // --- Command tree -------------------------------------------------------
 
 // Command returns the root token command tree.
@@ -46,6 +60,7 @@ func Command() *cli.Command {
 			tokenEnableCommand(),
 			tokenDisableCommand(),
 			tokenDeleteCommand(),
+			oidcTrustCommand(),
 		},
 	}
 }
@@ -155,3 +170,86 @@ func tokenDeleteCommand() *cli.Command {
 		},
 	}
 }
+
+func oidcTrustCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "oidc-trust",
+		Usage: "Manage which CI repositories can log in with 'auth login --oidc'",
+		Commands: []*cli.Command{
+			oidcTrustListCommand(),
+			oidcTrustAddCommand(),
+			oidcTrustRemoveCommand(),
+		},
+	}
+}
+
+func oidcTrustListCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "list",
+		Usage: "List trusted CI repositories",
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return handleOIDCTrustList(ctx)
+		},
+	}
+}
+
+func oidcTrustAddCommand() *cli.Command {
+	var in oidcTrustAddInput
+	return &cli.Command{
+		Name:  "add",
+		Usage: "Trust CI jobs from a repository",
+		Description: `Allow CI jobs from a repository to exchange their OIDC identity token for
+a short-lived token in the current organization:
+
+   1ctl token oidc-trust add --repo acme/api --branch main
+   1ctl token oidc-trust add --provider gitlab --repo acme/infra/api
+
+Without --branch, jobs on any branch of the repository are trusted.`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        flagRepo,
+				Usage:       "Repository path, e.g. org/repo",
+				Required:    true,
+				Destination: &in.Repo,
+			},
+			&cli.StringFlag{
+				Name:        flagBranch,
+				Usage:       "Only trust jobs running on this branch",
+				Destination: &in.Branch,
+			},
+			&cli.StringFlag{
+				Name:        flagProvider,
+				Usage:       "CI provider: github or gitlab",
+				Value:       "github",
+				Destination: &in.Provider,
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return handleOIDCTrustAdd(ctx, in)
+		},
+	}
+}
+
+func oidcTrustRemoveCommand() *cli.Command {
+	var in oidcTrustRemoveInput
+	return &cli.Command{
+		Name:      "remove",
+		Usage:     "Stop trusting a CI repository",
+		ArgsUsage: "<trust-id>",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:        flagYes,
+				Aliases:     []string{"y"},
+				Usage:       "Skip confirmation prompt",
+				Destination: &in.Yes,
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			if cmd.Args().Len() < 1 {
+				return cli.ShowSubcommandHelp(cmd)
+			}
+			in.TrustID = cmd.Args().First()
+			return handleOIDCTrustRemove(ctx, in)
+		},
+	}
+}
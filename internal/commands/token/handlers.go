@@ -18,7 +18,7 @@ func handleTokenList(ctx context.Context) error {
 
 	tokens, err := api.GetCLITokens(userID, orgID)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to list tokens: %s", err.Error()), nil)
+		return utils.NewError("failed to list tokens", err)
 	}
 
 	// Redact tokens in JSON output — never expose full JWTs in machine-readable output
@@ -73,7 +73,7 @@ func handleTokenCreate(ctx context.Context, in tokenCreateInput) error {
 
 	token, err := api.CreateCLIToken(userID, orgID, req)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to create token: %s", err.Error()), nil)
+		return utils.NewError("failed to create token", err)
 	}
 
 	// Redact token in JSON output — never expose full JWTs in machine-readable output
@@ -103,7 +103,7 @@ func handleTokenGet(ctx context.Context, in tokenIDInput) error {
 
 	token, err := api.GetCLIToken(userID, in.TokenID)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to get token: %s", err.Error()), nil)
+		return utils.NewError("failed to get token", err)
 	}
 
 	// Redact token in JSON output — never expose full JWTs in machine-readable output
@@ -141,7 +141,7 @@ func handleTokenEnable(ctx context.Context, in tokenIDInput) error {
 	}
 
 	if err := api.SetCLITokenState(userID, in.TokenID, true); err != nil {
-		return utils.NewError(fmt.Sprintf("failed to enable token: %s", err.Error()), nil)
+		return utils.NewError("failed to enable token", err)
 	}
 
 	utils.PrintSuccess("Token enabled successfully")
@@ -155,7 +155,7 @@ func handleTokenDisable(ctx context.Context, in tokenIDInput) error {
 	}
 
 	if err := api.SetCLITokenState(userID, in.TokenID, false); err != nil {
-		return utils.NewError(fmt.Sprintf("failed to disable token: %s", err.Error()), nil)
+		return utils.NewError("failed to disable token", err)
 	}
 
 	utils.PrintSuccess("Token disabled successfully")
@@ -175,7 +175,7 @@ func handleTokenDelete(ctx context.Context, in tokenDeleteInput) error {
 	}
 
 	if err := api.DeleteCLIToken(userID, orgID, in.TokenID); err != nil {
-		return utils.NewError(fmt.Sprintf("failed to delete token: %s", err.Error()), nil)
+		return utils.NewError("failed to delete token", err)
 	}
 
 	utils.PrintSuccess("Token deleted successfully")
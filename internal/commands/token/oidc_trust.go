@@ -0,0 +1,94 @@
+package token
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"1ctl/internal/api"
+	satuskyctx "1ctl/internal/context"
+	"1ctl/internal/oidc"
+	"1ctl/internal/utils"
+)
+
+func handleOIDCTrustList(ctx context.Context) error {
+	orgID := satuskyctx.GetCurrentOrgID()
+	if orgID == "" {
+		return utils.NewError("organization ID not found. Please run '1ctl auth login' first", nil)
+	}
+
+	policies, err := api.ListOIDCTrustPolicies(orgID)
+	if err != nil {
+		return utils.NewError("failed to list trust policies", err)
+	}
+	if utils.PrintListOrJSON(policies, "No CI repositories are trusted. Add one with '1ctl token oidc-trust add --repo <org/repo>'") {
+		return nil
+	}
+
+	headers := []string{"ID", "PROVIDER", "REPOSITORY", "BRANCH", "CREATED"}
+	rows := make([][]string, 0, len(policies))
+	for _, p := range policies {
+		branch := p.Branch
+		if branch == "" {
+			branch = "(any)"
+		}
+		rows = append(rows, []string{p.ID.String(), p.Provider, p.Repository, branch, utils.FormatTimeAgo(p.CreatedAt)})
+	}
+	utils.PrintTable(headers, rows)
+	return nil
+}
+
+func handleOIDCTrustAdd(ctx context.Context, in oidcTrustAddInput) error {
+	orgID := satuskyctx.GetCurrentOrgID()
+	if orgID == "" {
+		return utils.NewError("organization ID not found. Please run '1ctl auth login' first", nil)
+	}
+	if in.Provider != oidc.ProviderGitHub && in.Provider != oidc.ProviderGitLab {
+		return utils.NewError(fmt.Sprintf("unknown provider %q (expected github or gitlab)", in.Provider), nil)
+	}
+	repo := strings.Trim(in.Repo, "/")
+	if !strings.Contains(repo, "/") {
+		return utils.NewError(fmt.Sprintf("--repo must be a repository path like org/repo, got %q", in.Repo), nil)
+	}
+
+	policy, err := api.CreateOIDCTrustPolicy(orgID, api.CreateOIDCTrustRequest{
+		Provider:   in.Provider,
+		Repository: repo,
+		Branch:     in.Branch,
+	})
+	if err != nil {
+		return utils.NewError("failed to add trust policy", err)
+	}
+	if utils.TryPrintJSON(policy) {
+		return nil
+	}
+
+	utils.PrintSuccess("CI jobs from %s can now log in with '1ctl auth login --oidc'", repo)
+	utils.PrintStatusLine("ID", policy.ID.String())
+	utils.PrintStatusLine("Provider", policy.Provider)
+	if policy.Branch != "" {
+		utils.PrintStatusLine("Branch", policy.Branch)
+	} else {
+		utils.PrintStatusLine("Branch", "(any)")
+	}
+	return nil
+}
+
+func handleOIDCTrustRemove(ctx context.Context, in oidcTrustRemoveInput) error {
+	orgID := satuskyctx.GetCurrentOrgID()
+	if orgID == "" {
+		return utils.NewError("organization ID not found. Please run '1ctl auth login' first", nil)
+	}
+
+	if !utils.Confirm(fmt.Sprintf("Remove trust policy %s? CI jobs relying on it will no longer be able to log in.", in.TrustID), in.Yes) {
+		fmt.Println("Aborted.")
+		return nil
+	}
+
+	if err := api.DeleteOIDCTrustPolicy(orgID, in.TrustID); err != nil {
+		return utils.NewError("failed to remove trust policy", err)
+	}
+
+	utils.PrintSuccess("Trust policy removed")
+	return nil
+}
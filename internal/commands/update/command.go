@@ -0,0 +1,70 @@
+// Package update defines the "1ctl update" command.
+package update
+
+import (
+	"context"
+
+	"github.com/urfave/cli/v3"
+)
+
+// --- Flag name constants ------------------------------------------------
+
+const (
+	flagVersion  = "version"
+	flagRollback = "rollback"
+	flagCheck    = "check"
+)
+
+// --- Input structs ------------------------------------------------------
+
+type updateInput struct {
+	Version  string
+	Rollback bool
+	Check    bool
+}
+
+// --- Command tree -------------------------------------------------------
+
+// Command returns the "1ctl update" command.
+func Command() *cli.Command {
+	var in updateInput
+	return &cli.Command{
+		Name:  "update",
+		Usage: "Update 1ctl to the latest release",
+		Description: `Downloads the release for this OS and architecture, checks that its
+checksums are signed by the 1ctl release key and that the archive matches
+them, and replaces this binary. The replaced binary is kept, so
+--rollback can go back to it.
+
+Binaries installed with Homebrew are updated with 'brew upgrade satuctl'.
+
+1ctl also checks for a new release once a day and mentions it after a
+command; set SATUSKY_NO_UPDATE_NOTIFIER=1 to turn that off.
+
+Examples:
+  1ctl update
+  1ctl update --check
+  1ctl update --version v1.4.2
+  1ctl update --rollback`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        flagVersion,
+				Usage:       "Install this release (e.g. v1.4.2) instead of the latest, including an older one",
+				Destination: &in.Version,
+			},
+			&cli.BoolFlag{
+				Name:        flagRollback,
+				Usage:       "Go back to the binary the last update replaced",
+				Destination: &in.Rollback,
+			},
+			&cli.BoolFlag{
+				Name:        flagCheck,
+				Usage:       "Only report whether a newer release is available",
+				Destination: &in.Check,
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return handleUpdate(ctx, in)
+		},
+	}
+}
@@ -0,0 +1,85 @@
+package update
+
+import (
+	"context"
+	"os"
+	"os/exec"
+
+	"1ctl/internal/selfupdate"
+	"1ctl/internal/utils"
+	"1ctl/internal/version"
+)
+
+func handleUpdate(ctx context.Context, in updateInput) error {
+	if in.Rollback && (in.Version != "" || in.Check) {
+		return utils.NewKindError(utils.ErrUsage, "--rollback cannot be combined with --version or --check", nil)
+	}
+	u, err := selfupdate.New()
+	if err != nil {
+		return err
+	}
+	if u.IsHomebrew() && !in.Check {
+		return brewUpgrade(ctx, in)
+	}
+
+	if in.Rollback {
+		if err := u.Rollback(); err != nil {
+			return err
+		}
+		utils.PrintSuccess("Rolled back to the previous 1ctl")
+		utils.PrintInfo("Run '1ctl update --rollback' again to undo")
+		return nil
+	}
+
+	var rel *selfupdate.Release
+	if in.Version != "" {
+		rel, err = u.Release(in.Version)
+	} else {
+		rel, err = u.Latest()
+	}
+	if err != nil {
+		return utils.NewError("failed to find the release", err)
+	}
+
+	current := version.Version
+	if in.Version == "" {
+		if cmp, ok := version.Compare(current, rel.Tag); ok && cmp >= 0 {
+			utils.PrintSuccess("1ctl %s is the latest version", current)
+			return nil
+		}
+	}
+	if in.Check {
+		how := "Run '1ctl update' to install it"
+		if u.IsHomebrew() {
+			how = "Run 'brew upgrade satuctl' to install it"
+		}
+		utils.PrintInfo("1ctl %s is available (you have %s). %s", rel.Tag, current, how)
+		return nil
+	}
+
+	utils.PrintInfo("Installing 1ctl %s for %s/%s...", rel.Tag, u.GOOS, u.GOARCH)
+	if err := u.Install(rel); err != nil {
+		return err
+	}
+	utils.PrintSuccess("Updated 1ctl %s to %s", current, rel.Tag)
+	utils.PrintInfo("Run '1ctl update --rollback' to go back to %s", current)
+	return nil
+}
+
+// brewUpgrade defers to Homebrew, which owns binaries it installed.
+func brewUpgrade(ctx context.Context, in updateInput) error {
+	if in.Version != "" || in.Rollback {
+		return utils.NewKindError(utils.ErrUsage, "1ctl was installed with Homebrew, which installs only the latest release. Run 'brew upgrade satuctl', or install a specific version with install.sh", nil)
+	}
+	brew, err := exec.LookPath("brew")
+	if err != nil {
+		return utils.NewError("1ctl was installed with Homebrew; run 'brew upgrade satuctl'", nil)
+	}
+	utils.PrintInfo("1ctl was installed with Homebrew; running 'brew upgrade satuctl'")
+	cmd := exec.CommandContext(ctx, brew, "upgrade", "satuctl") // #nosec G204 -- fixed arguments
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return utils.NewError("brew upgrade satuctl failed", err)
+	}
+	return nil
+}
@@ -18,7 +18,7 @@ import (
 func handleUserMe(ctx context.Context) error {
 	user, err := api.GetCurrentUser()
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to get user profile: %s", err.Error()), nil)
+		return utils.NewError("failed to get user profile", err)
 	}
 
 	if utils.TryPrintJSON(user) {
@@ -50,7 +50,7 @@ func handleUserUpdate(ctx context.Context, in userUpdateInput) error {
 
 	user, err := api.GetCurrentUser()
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to get current user: %s", err.Error()), nil)
+		return utils.NewError("failed to get current user", err)
 	}
 
 	req := api.UpdateUserRequest{
@@ -60,7 +60,7 @@ func handleUserUpdate(ctx context.Context, in userUpdateInput) error {
 
 	updatedUser, err := api.UpdateUser(user.UserID, req)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to update user: %s", err.Error()), nil)
+		return utils.NewError("failed to update user", err)
 	}
 
 	utils.PrintSuccess("User profile updated successfully")
@@ -125,7 +125,7 @@ func handleUserPassword(ctx context.Context) error {
 	}
 
 	if err := api.ChangePassword(currentPassword, newPassword); err != nil {
-		return utils.NewError(fmt.Sprintf("failed to change password: %s", err.Error()), nil)
+		return utils.NewError("failed to change password", err)
 	}
 
 	utils.PrintSuccess("Password changed successfully")
@@ -140,7 +140,7 @@ func handleUserPermissions(ctx context.Context) error {
 
 	permsList, err := api.GetUserPermissions(orgID)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to get permissions: %s", err.Error()), nil)
+		return utils.NewError("failed to get permissions", err)
 	}
 
 	if utils.TryPrintJSON(permsList) {
@@ -161,7 +161,7 @@ func handleUserPermissions(ctx context.Context) error {
 
 func handleUserSessionsRevoke(ctx context.Context) error {
 	if err := api.RevokeAllSessions(); err != nil {
-		return utils.NewError(fmt.Sprintf("failed to revoke sessions: %s", err.Error()), nil)
+		return utils.NewError("failed to revoke sessions", err)
 	}
 
 	utils.PrintSuccess("All sessions have been revoked")
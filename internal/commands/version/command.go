@@ -0,0 +1,49 @@
+// Package version defines the "1ctl version" command.
+package version
+
+import (
+	"context"
+
+	"github.com/urfave/cli/v3"
+)
+
+// --- Flag name constants ------------------------------------------------
+
+const (
+	flagCheck = "check"
+)
+
+// --- Input structs ------------------------------------------------------
+
+type versionInput struct {
+	Check bool
+}
+
+// --- Command tree -------------------------------------------------------
+
+// Command returns the "1ctl version" command.
+func Command() *cli.Command {
+	var in versionInput
+	return &cli.Command{
+		Name:  "version",
+		Usage: "Show the CLI version and whether the API supports it",
+		Description: `Prints the version of 1ctl. With --check, also asks the active profile's
+API which CLI versions it supports and which features it has. No login is
+needed.
+
+--check exits with code 12 (upgrade_required) when the API no longer
+supports this version, so CI can fail early:
+  1ctl version --check
+  1ctl --profile prod -o json version --check`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:        flagCheck,
+				Usage:       "Check compatibility with the active profile's API",
+				Destination: &in.Check,
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return handleVersion(ctx, in)
+		},
+	}
+}
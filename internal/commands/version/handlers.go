@@ -0,0 +1,85 @@
+package version
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apipkg "1ctl/internal/api"
+	"1ctl/internal/config"
+	"1ctl/internal/utils"
+	versionpkg "1ctl/internal/version"
+)
+
+// versionReport is the JSON form of "1ctl version".
+type versionReport struct {
+	Version       string                     `json:"version"`
+	Commit        string                     `json:"commit,omitempty"`
+	BuildDate     string                     `json:"build_date,omitempty"`
+	APIURL        string                     `json:"api_url,omitempty"`
+	Status        apipkg.CompatibilityStatus `json:"status,omitempty"`
+	Compatibility *apipkg.Compatibility      `json:"compatibility,omitempty"`
+}
+
+func handleVersion(ctx context.Context, in versionInput) error {
+	report := versionReport{
+		Version:   versionpkg.Version,
+		Commit:    versionpkg.CommitHash,
+		BuildDate: versionpkg.BuildDate,
+	}
+	if !in.Check {
+		if !utils.TryPrintJSON(report) {
+			fmt.Printf("1ctl %s\n", versionpkg.GetVersionInfo())
+		}
+		return nil
+	}
+
+	report.APIURL = config.GetConfig().ApiURL
+	compat, err := apipkg.Default().CheckCompatibility()
+	if err != nil {
+		return utils.NewError("failed to check API compatibility", err)
+	}
+	report.Status = compat.Status(versionpkg.Version)
+	report.Compatibility = &compat
+
+	if !utils.TryPrintJSON(report) {
+		printReport(report, compat)
+	}
+	if report.Status == apipkg.UpgradeRequired {
+		return utils.NewKindError(utils.ErrUpgradeRequired, fmt.Sprintf("the API needs 1ctl %s or newer", compat.MinCLIVersion), nil)
+	}
+	return nil
+}
+
+func printReport(report versionReport, compat apipkg.Compatibility) {
+	utils.PrintHeader("Version")
+	utils.PrintStatusLine("CLI", versionpkg.GetVersionInfo())
+	utils.PrintStatusLine("API URL", report.APIURL)
+	if compat.APIVersion != "" {
+		utils.PrintStatusLine("API Version", compat.APIVersion)
+	}
+	if compat.MinCLIVersion != "" {
+		utils.PrintStatusLine("Minimum CLI", compat.MinCLIVersion)
+	}
+	if compat.RecommendedCLIVersion != "" {
+		utils.PrintStatusLine("Recommended CLI", compat.RecommendedCLIVersion)
+	}
+	if len(compat.Features) > 0 {
+		utils.PrintStatusLine("Features", strings.Join(compat.Features, ", "))
+	}
+
+	switch report.Status {
+	case apipkg.Compatible:
+		utils.PrintSuccess("This version of 1ctl is supported")
+	case apipkg.UpgradeRecommended:
+		utils.PrintWarning("A newer 1ctl is recommended. %s", apipkg.UpgradeHint)
+	case apipkg.UpgradeRequired:
+		utils.PrintWarning("This version of 1ctl is no longer supported. %s", apipkg.UpgradeHint)
+	default:
+		if !versionpkg.IsRelease(versionpkg.Version) {
+			utils.PrintInfo("Development builds are not checked against the API")
+		} else {
+			utils.PrintInfo("The API does not advertise which CLI versions it supports")
+		}
+	}
+}
@@ -13,14 +13,15 @@ import (
 // --- Handlers -----------------------------------------------------------
 
 func handleVolumesList(ctx context.Context, in volumesListInput) error {
-	deploymentID, err := deploy.ResolveDeploymentID(in.DeploymentID, in.App, in.Config)
+	client := api.FromContext(ctx)
+	deploymentID, err := deploy.ResolveDeploymentID(client, in.DeploymentID, in.App, in.Config)
 	if err != nil {
 		return err
 	}
 
-	statuses, err := api.GetDeploymentVolumeLifecycleStatuses(deploymentID)
+	statuses, err := client.GetDeploymentVolumeLifecycleStatuses(deploymentID)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to list volumes: %s", err.Error()), nil)
+		return utils.NewError("failed to list volumes", err)
 	}
 	if utils.PrintListOrJSON(statuses, "No persistent volumes found for this deployment") {
 		return nil
@@ -43,6 +44,7 @@ func handleVolumesList(ctx context.Context, in volumesListInput) error {
 }
 
 func handleVolumesInspect(ctx context.Context, in volumesActionInput) error {
+	client := api.FromContext(ctx)
 	volumeID := in.VolumeID
 
 	// --- Path 1: Volume name + app/deployment scope ---
@@ -54,13 +56,13 @@ func handleVolumesInspect(ctx context.Context, in volumesActionInput) error {
 			// but we already have in.VolumeName from positional. We need --app or --deployment-id.
 			return utils.NewError("use --app to specify which app when inspecting by volume name", nil)
 		}
-		deploymentID, err := deploy.ResolveDeploymentID(depID, app, in.Config)
+		deploymentID, err := deploy.ResolveDeploymentID(client, depID, app, in.Config)
 		if err != nil {
-			return utils.NewError(fmt.Sprintf("failed to resolve deployment: %s", err.Error()), nil)
+			return utils.NewError("failed to resolve deployment", err)
 		}
-		statuses, err := api.GetDeploymentVolumeLifecycleStatuses(deploymentID)
+		statuses, err := client.GetDeploymentVolumeLifecycleStatuses(deploymentID)
 		if err != nil {
-			return utils.NewError(fmt.Sprintf("failed to list volumes: %s", err.Error()), nil)
+			return utils.NewError("failed to list volumes", err)
 		}
 		found := false
 		for _, s := range statuses {
@@ -77,13 +79,13 @@ func handleVolumesInspect(ctx context.Context, in volumesActionInput) error {
 
 	// --- Path 2: App/deployment only (no volume name) ---
 	if volumeID == "" && (in.App != "" || in.DeploymentID != "") {
-		deploymentID, err := deploy.ResolveDeploymentID(in.DeploymentID, in.App, in.Config)
+		deploymentID, err := deploy.ResolveDeploymentID(client, in.DeploymentID, in.App, in.Config)
 		if err != nil {
 			return err
 		}
-		statuses, err := api.GetDeploymentVolumeLifecycleStatuses(deploymentID)
+		statuses, err := client.GetDeploymentVolumeLifecycleStatuses(deploymentID)
 		if err != nil {
-			return utils.NewError(fmt.Sprintf("failed to list volumes: %s", err.Error()), nil)
+			return utils.NewError("failed to list volumes", err)
 		}
 		switch len(statuses) {
 		case 0:
@@ -99,23 +101,24 @@ func handleVolumesInspect(ctx context.Context, in volumesActionInput) error {
 		return utils.NewError("provide --volume-id, --app, or --deployment-id", nil)
 	}
 
-	status, err := api.GetVolumeLifecycleStatus(volumeID)
+	status, err := client.GetVolumeLifecycleStatus(volumeID)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to inspect volume: %s", err.Error()), nil)
+		return utils.NewError("failed to inspect volume", err)
 	}
 	printVolumeLifecycle(status)
 	return nil
 }
 
 func handleVolumesDetach(ctx context.Context, in volumesActionInput) error {
+	client := api.FromContext(ctx)
 	if !utils.Confirm(fmt.Sprintf("Detach volume %s? The PVC will be retained.", in.VolumeID), in.Yes) {
 		fmt.Println("Aborted.")
 		return nil
 	}
 
-	status, err := api.DetachVolume(in.VolumeID)
+	status, err := client.DetachVolume(in.VolumeID)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to detach volume: %s", err.Error()), nil)
+		return utils.NewError("failed to detach volume", err)
 	}
 	utils.PrintSuccess("Volume %s detached; PVC retained", in.VolumeID)
 	printVolumeLifecycle(status)
@@ -123,14 +126,15 @@ func handleVolumesDetach(ctx context.Context, in volumesActionInput) error {
 }
 
 func handleVolumesDestroy(ctx context.Context, in volumesActionInput) error {
+	client := api.FromContext(ctx)
 	if !utils.Confirm(fmt.Sprintf("Destroy volume %s? This detaches the volume and deletes its PVC.", in.VolumeID), in.Yes) {
 		fmt.Println("Aborted.")
 		return nil
 	}
 
-	status, err := api.DeleteVolumePVC(in.VolumeID)
+	status, err := client.DeleteVolumePVC(in.VolumeID)
 	if err != nil {
-		return utils.NewError(fmt.Sprintf("failed to destroy volume: %s", err.Error()), nil)
+		return utils.NewError("failed to destroy volume", err)
 	}
 	utils.PrintSuccess("Volume %s destroyed", in.VolumeID)
 	printVolumeLifecycle(status)
@@ -3,7 +3,6 @@ package config
 import (
 	"1ctl/internal/context"
 	"1ctl/internal/utils"
-	"errors"
 	"os"
 
 	"github.com/joho/godotenv"
@@ -57,10 +56,15 @@ func GetConfig() *Config {
 }
 
 func ValidateEnvironment() error {
+	if err := PinError(); err != nil {
+		return err
+	}
 	token := context.GetToken()
+	if err := context.LoadError(); err != nil {
+		return err
+	}
 	if token == "" {
-		utils.PrintError("not authenticated. Please run '1ctl auth login' to authenticate")
-		return errors.New("not authenticated")
+		return utils.NewKindError(utils.ErrAuth, "not authenticated. Please run '1ctl auth login' to authenticate", nil)
 	}
 	return nil
 }
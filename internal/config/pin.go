@@ -0,0 +1,183 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"1ctl/internal/context"
+	"1ctl/internal/utils"
+
+	"github.com/BurntSushi/toml"
+)
+
+// PinFile pins a directory tree to a profile and organization without a
+// satusky.toml, e.g. at the root of a monorepo. It takes the same two keys:
+//
+//	profile = "client-a"
+//	organization = "acme"
+const PinFile = ".satusky/profile"
+
+// Pin is the profile and organization a directory tree is pinned to.
+type Pin struct {
+	Profile      string `toml:"profile"`
+	Organization string `toml:"organization"`
+	// Path is the file the pin was read from.
+	Path string `toml:"-"`
+}
+
+var (
+	pinMu     sync.RWMutex
+	activePin *Pin
+	pinErr    error
+)
+
+// FindPin walks up from dir to the nearest satusky.toml or .satusky/profile
+// that sets profile or organization. Files that set neither are skipped, so
+// an app's satusky.toml inherits the pin at its repository root. At the
+// same level .satusky/profile wins. Returns nil, nil when nothing is pinned.
+func FindPin(dir string) (*Pin, error) {
+	configDir := filepath.Clean(context.Default().ConfigDir())
+	for {
+		candidates := []string{filepath.Join(dir, filepath.FromSlash(PinFile)), filepath.Join(dir, DefaultConfigFile)}
+		for _, path := range candidates {
+			// ~/.satusky is the config directory, not a pin.
+			if filepath.Dir(path) == configDir {
+				continue
+			}
+			if _, err := os.Stat(path); err != nil {
+				continue
+			}
+			var pin Pin
+			if _, err := toml.DecodeFile(path, &pin); err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", path, err)
+			}
+			if pin.Profile != "" || pin.Organization != "" {
+				pin.Path = path
+				return &pin, nil
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// ApplyPin finds the pin for the working directory and, unless a profile
+// was chosen explicitly, makes the pinned profile the effective one for
+// this process. Problems — an unreadable pin or a pinned profile that does
+// not exist — are reported by ValidateEnvironment, so auth and profile
+// commands still work and can fix them.
+func ApplyPin(explicitProfile string) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return
+	}
+	pin, err := FindPin(wd)
+
+	pinMu.Lock()
+	defer pinMu.Unlock()
+	activePin, pinErr = pin, nil
+	if err != nil {
+		pinErr = utils.NewError(err.Error(), nil)
+		return
+	}
+	if pin == nil || pin.Profile == "" || explicitProfile != "" {
+		return
+	}
+	if !context.ProfileExists(pin.Profile) {
+		pinErr = utils.NewError(fmt.Sprintf("%s pins profile %q, which does not exist. Create it with '1ctl profile create %s' or pass --profile", pin.Path, pin.Profile, pin.Profile), nil)
+		return
+	}
+	context.SetProfileOverride(pin.Profile)
+	context.SetProfileSource("pinned in " + pin.Path)
+}
+
+// PinError returns the problem with the applied pin, if any.
+func PinError() error {
+	pinMu.RLock()
+	defer pinMu.RUnlock()
+	return pinErr
+}
+
+// ActivePin returns the pin applied by ApplyPin, or nil.
+func ActivePin() *Pin {
+	pinMu.RLock()
+	defer pinMu.RUnlock()
+	return activePin
+}
+
+// PinnedOrganizationMatches reports whether the effective profile is logged
+// in to the pinned organization, given as its namespace, name or ID. It is
+// true when no organization is pinned.
+func PinnedOrganizationMatches() bool {
+	pin := ActivePin()
+	if pin == nil || pin.Organization == "" {
+		return true
+	}
+	for _, current := range []string{context.GetCurrentNamespace(), context.GetCurrentOrgID(), context.GetCurrentOrgName()} {
+		if current != "" && strings.EqualFold(current, pin.Organization) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckPinnedOrganization refuses to continue when the working directory is
+// pinned to a different organization than the effective profile's.
+func CheckPinnedOrganization() error {
+	if PinnedOrganizationMatches() {
+		return nil
+	}
+	pin := ActivePin()
+	current := context.GetCurrentNamespace()
+	if current == "" {
+		current = "no organization"
+	}
+	return utils.NewError(fmt.Sprintf("%s pins organization %q, but profile %q is using %q. Run '1ctl org switch %s' or use the pinned profile", pin.Path, pin.Organization, context.GetEffectiveProfileName(), current, pin.Organization), nil)
+}
+
+// EffectiveContext is the profile and organization commands run against
+// and where each came from, as shown by auth status and doctor.
+type EffectiveContext struct {
+	Profile            string `json:"profile"`
+	ProfileSource      string `json:"profile_source"`
+	Organization       string `json:"organization"`
+	OrganizationSource string `json:"organization_source"`
+	PinnedOrganization string `json:"pinned_organization,omitempty"`
+	PinPath            string `json:"pin_path,omitempty"`
+	Mismatch           bool   `json:"organization_mismatch,omitempty"`
+}
+
+// DescribeContext reports the effective profile and organization.
+func DescribeContext() EffectiveContext {
+	profile := context.GetEffectiveProfileName()
+	ec := EffectiveContext{
+		Profile:            profile,
+		ProfileSource:      context.GetProfileSource(),
+		Organization:       context.GetCurrentNamespace(),
+		OrganizationSource: fmt.Sprintf("profile %q ('1ctl org switch')", profile),
+	}
+	if pin := ActivePin(); pin != nil {
+		ec.PinPath = pin.Path
+		ec.PinnedOrganization = pin.Organization
+		ec.Mismatch = !PinnedOrganizationMatches()
+	}
+	return ec
+}
+
+// PrintContext prints ec as status lines.
+func PrintContext(ec EffectiveContext) {
+	utils.PrintStatusLine("Profile", fmt.Sprintf("%s (%s)", ec.Profile, ec.ProfileSource))
+	utils.PrintStatusLine("Organization from", ec.OrganizationSource)
+	if ec.PinnedOrganization != "" {
+		utils.PrintStatusLine("Pinned organization", fmt.Sprintf("%s (%s)", ec.PinnedOrganization, ec.PinPath))
+	}
+	if ec.Mismatch {
+		utils.PrintWarning("This directory is pinned to organization %q but the profile uses %q; deploys will refuse to run", ec.PinnedOrganization, ec.Organization)
+	}
+}
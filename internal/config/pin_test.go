@@ -0,0 +1,130 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"1ctl/internal/context"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// usePinTestStore gives the test a config directory with profiles "global"
+// (active, in org "globex") and "client-a" (in org "acme").
+func usePinTestStore(t *testing.T) *context.Store {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), ".satusky")
+	writeTestFile(t, filepath.Join(dir, "profiles", "global.json"), `{"organization":"globex","current_org_name":"Globex"}`)
+	writeTestFile(t, filepath.Join(dir, "profiles", "client-a.json"), `{"organization":"acme","current_org_name":"Acme Inc"}`)
+	writeTestFile(t, filepath.Join(dir, "context.json"), `{"active_profile":"global"}`)
+
+	original := context.Default()
+	store := context.NewTestStore(dir)
+	context.SetDefault(store)
+	t.Cleanup(func() {
+		context.SetDefault(original)
+		pinMu.Lock()
+		activePin, pinErr = nil, nil
+		pinMu.Unlock()
+	})
+	return store
+}
+
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) }) //nolint:errcheck
+}
+
+func TestFindPinInheritsFromRepoRoot(t *testing.T) {
+	usePinTestStore(t)
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, ".satusky", "profile"), "profile = \"client-a\"\norganization = \"acme\"\n")
+	app := filepath.Join(root, "services", "api")
+	writeTestFile(t, filepath.Join(app, DefaultConfigFile), "[app]\nname = \"api\"\n")
+
+	pin, err := FindPin(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pin == nil || pin.Profile != "client-a" || pin.Organization != "acme" {
+		t.Fatalf("FindPin() = %+v", pin)
+	}
+	if pin.Path != filepath.Join(root, ".satusky", "profile") {
+		t.Errorf("Path = %q", pin.Path)
+	}
+
+	// A pin in the app's own satusky.toml is nearer.
+	writeTestFile(t, filepath.Join(app, DefaultConfigFile), "profile = \"global\"\n\n[app]\nname = \"api\"\n")
+	if pin, _ := FindPin(app); pin == nil || pin.Profile != "global" {
+		t.Errorf("FindPin() = %+v, want the satusky.toml pin", pin)
+	}
+}
+
+func TestApplyPinSelectsProfile(t *testing.T) {
+	usePinTestStore(t)
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, DefaultConfigFile), "profile = \"client-a\"\norganization = \"acme\"\n")
+	chdir(t, root)
+
+	ApplyPin("")
+	if got := context.GetEffectiveProfileName(); got != "client-a" {
+		t.Errorf("effective profile = %q, want client-a", got)
+	}
+	if got := context.GetProfileSource(); !strings.HasPrefix(got, "pinned in ") {
+		t.Errorf("profile source = %q", got)
+	}
+	if err := CheckPinnedOrganization(); err != nil {
+		t.Errorf("CheckPinnedOrganization() = %v", err)
+	}
+}
+
+func TestPinnedOrganizationMismatchRefusesDeploy(t *testing.T) {
+	usePinTestStore(t)
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, DefaultConfigFile), "profile = \"client-a\"\norganization = \"acme\"\n")
+	chdir(t, root)
+
+	// --profile wins over the pin, but the organization is still checked.
+	context.SetProfileOverride("global")
+	ApplyPin("global")
+	if got := context.GetEffectiveProfileName(); got != "global" {
+		t.Errorf("effective profile = %q, want global", got)
+	}
+	err := CheckPinnedOrganization()
+	if err == nil || !strings.Contains(err.Error(), `pins organization "acme"`) {
+		t.Errorf("CheckPinnedOrganization() = %v, want a mismatch error", err)
+	}
+	if ec := DescribeContext(); !ec.Mismatch || ec.PinnedOrganization != "acme" || ec.Organization != "globex" {
+		t.Errorf("DescribeContext() = %+v", ec)
+	}
+}
+
+func TestPinToMissingProfile(t *testing.T) {
+	usePinTestStore(t)
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, ".satusky", "profile"), "profile = \"client-b\"\n")
+	chdir(t, root)
+
+	ApplyPin("")
+	err := ValidateEnvironment()
+	if err == nil || !strings.Contains(err.Error(), "profile create client-b") {
+		t.Errorf("ValidateEnvironment() = %v, want a missing profile error", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/BurntSushi/toml"
@@ -19,6 +20,10 @@ const DefaultConfigFile = "satusky.toml"
 //
 // Fields with the zero value are treated as "not set" by the merge.
 type ProjectConfig struct {
+	// Profile and Organization pin the profile and organization used by
+	// commands run in this directory tree; see FindPin.
+	Profile      string             `toml:"profile,omitempty"`
+	Organization string             `toml:"organization,omitempty"`
 	App          AppConfig          `toml:"app"`
 	Build        BuildConfig        `toml:"build"`
 	Checks       ChecksConfig       `toml:"checks"`
@@ -257,3 +262,60 @@ func findDefaultConfigDir() (string, error) {
 	}
 	return "", os.ErrNotExist
 }
+
+// skipDiscoveryDirs are directories DiscoverConfigs never descends into:
+// VCS metadata and dependency trees that can be huge and never hold an
+// app's own satusky.toml.
+var skipDiscoveryDirs = map[string]bool{
+	".git":         true,
+	"node_modules": true,
+	"vendor":       true,
+}
+
+// FindRepoRoot walks up from dir to the nearest directory containing .git
+// (a directory, or a file for worktrees and submodules). Falls back to dir
+// itself when no repository is found, so callers can still scan a plain
+// directory tree.
+func FindRepoRoot(dir string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return dir
+	}
+	for cur := abs; ; {
+		if _, err := os.Stat(filepath.Join(cur, ".git")); err == nil {
+			return cur
+		}
+		parent := filepath.Dir(cur)
+		if parent == cur {
+			return abs
+		}
+		cur = parent
+	}
+}
+
+// DiscoverConfigs returns the path of every satusky.toml below root, sorted.
+// Per-environment variants (satusky.<env>.toml) are not included: they are
+// selected explicitly with --config.
+func DiscoverConfigs(root string) ([]string, error) {
+	var paths []string
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && skipDiscoveryDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Name() == DefaultConfigFile {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
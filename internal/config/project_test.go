@@ -431,3 +431,36 @@ strategy = "rolling"
 		t.Errorf("App.Strategy = %q, want empty (legacy field should be cleared after Normalize)", cfg.App.Strategy)
 	}
 }
+
+func TestDiscoverConfigs(t *testing.T) {
+	root := t.TempDir()
+	for _, rel := range []string{
+		"api/satusky.toml",
+		"web/satusky.toml",
+		"web/node_modules/pkg/satusky.toml",
+		"vendor/x/satusky.toml",
+		"docs/satusky.toml.example",
+	} {
+		path := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("[app]\nname = \"x\"\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := DiscoverConfigs(root)
+	if err != nil {
+		t.Fatalf("DiscoverConfigs: %v", err)
+	}
+	want := []string{filepath.Join(root, "api", DefaultConfigFile), filepath.Join(root, "web", DefaultConfigFile)}
+	if len(got) != len(want) {
+		t.Fatalf("DiscoverConfigs = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("DiscoverConfigs[%d] = %s, want %s", i, got[i], want[i])
+		}
+	}
+}
@@ -22,8 +22,21 @@ type CLIContext struct {
 	CurrentOrgID     string `json:"current_org_id,omitempty"`
 	CurrentOrgName   string `json:"current_org_name,omitempty"`
 	Email            string `json:"email,omitempty"`
-	Token            string `json:"token"`
+	// Token is only stored here for the plaintext credential store; for the
+	// others it is empty and CredentialStore says where the token lives.
+	Token           string `json:"token"`
+	CredentialStore string `json:"credential_store,omitempty"`
+	// RefreshToken comes from "auth login --web" and is stored like Token.
+	RefreshToken string `json:"refresh_token,omitempty"`
+	// CredentialHelper is the program holding the tokens when
+	// CredentialStore is "helper".
+	CredentialHelper string `json:"credential_helper,omitempty"`
 	UserID           string `json:"user_id"`
+	// CAFile, ClientCert and ClientKey are PEM files for networks that
+	// intercept TLS or require a client certificate. See package transport.
+	CAFile     string `json:"ca_file,omitempty"`
+	ClientCert string `json:"client_cert,omitempty"`
+	ClientKey  string `json:"client_key,omitempty"`
 }
 
 // --- Package-level shims (delegate to Default()) ---
@@ -86,6 +99,11 @@ func SaveLoginState(token, userID, email, orgID, orgName, namespace string) erro
 	return Default().SaveLoginState(token, userID, email, orgID, orgName, namespace)
 }
 
+// SaveOAuthLoginState atomically writes every auth field of a browser login.
+func SaveOAuthLoginState(token, refreshToken, userID, email, orgID, orgName, namespace string) error {
+	return Default().SaveOAuthLoginState(token, refreshToken, userID, email, orgID, orgName, namespace)
+}
+
 // ClearAuthState atomically wipes every auth field.
 func ClearAuthState() error { return Default().ClearAuthState() }
 
@@ -95,5 +113,29 @@ func GetAPIURL() string { return Default().GetAPIURL() }
 // SetAPIURL persists an API URL override to the active profile.
 func SetAPIURL(apiURL string) error { return Default().SetAPIURL(apiURL) }
 
+// GetTLSFiles returns the active profile's CA bundle, client certificate
+// and client key files.
+func GetTLSFiles() TLSFiles { return Default().GetTLSFiles() }
+
+// SetTLSFiles persists the TLS files to the active profile.
+func SetTLSFiles(files TLSFiles) error { return Default().SetTLSFiles(files) }
+
+// SetCredentialStore selects the credential store for tokens saved by
+// this process.
+func SetCredentialStore(name string) error { return Default().SetCredentialStore(name) }
+
+// SetCredentialHelper makes this process use a credential helper program.
+func SetCredentialHelper(program string) { Default().SetCredentialHelper(program) }
+
+// GetCredentialStore returns the credential store of the active profile.
+func GetCredentialStore() string { return Default().CredentialStore() }
+
+// LoadError returns the error from recovering a corrupted profile or
+// context.json, or nil.
+func LoadError() error { return Default().LoadError() }
+
+// EnsureFreshToken refreshes an expiring browser-login token.
+func EnsureFreshToken() error { return Default().EnsureFreshToken() }
+
 // CheckTokenExpiry parses the JWT exp claim from the stored token.
 func CheckTokenExpiry() error { return Default().CheckTokenExpiry() }
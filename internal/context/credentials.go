@@ -0,0 +1,500 @@
+package context
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+
+	"1ctl/internal/credhelper"
+
+	"golang.org/x/term"
+)
+
+// Credential stores a profile's token can live in. The name is recorded in
+// the profile file so each profile reads its token from where it was saved.
+const (
+	// CredentialStoreKeyring uses the OS keyring: the Secret Service (via
+	// secret-tool) on Linux, the login keychain on macOS.
+	CredentialStoreKeyring = "keyring"
+	// CredentialStoreFile encrypts the token under a passphrase-derived key
+	// in <configDir>/credentials/, for headless machines without a keyring.
+	CredentialStoreFile = "file"
+	// CredentialStorePlaintext keeps the token in the profile JSON. It is
+	// never chosen automatically.
+	CredentialStorePlaintext = "plaintext"
+	// CredentialStoreEphemeral keeps a short-lived CI token in the runner's
+	// temporary directory, which is discarded when the job ends, or in
+	// memory where there is none.
+	CredentialStoreEphemeral = "ephemeral"
+	// CredentialStoreHelper delegates to an external credential helper
+	// program, recorded in the profile's credential_helper field. It is
+	// chosen with --credential-helper rather than --credential-store.
+	CredentialStoreHelper = "helper"
+)
+
+const (
+	// CredentialStoreEnv selects the credential store for new tokens.
+	CredentialStoreEnv = "SATUSKY_CREDENTIAL_STORE"
+	// CredentialPassphraseEnv supplies the file store passphrase
+	// non-interactively.
+	CredentialPassphraseEnv = "SATUSKY_CREDENTIAL_PASSPHRASE"
+	// CredentialHelperEnv names a credential helper program that overrides
+	// every profile's credential store.
+	CredentialHelperEnv = "SATUSKY_CREDENTIAL_HELPER"
+)
+
+// keyringService names the 1ctl entries in the OS keyring.
+const keyringService = "1ctl"
+
+// credentialPBKDF2Iterations matches the secret file passphrase stanza.
+const credentialPBKDF2Iterations = 600000
+
+// errNoPassphrase means the file store needs a passphrase and there is no
+// terminal to ask on.
+var errNoPassphrase = fmt.Errorf("no passphrase available: set %s, or choose another store with --credential-store", CredentialPassphraseEnv)
+
+// credentialBackend stores one token per profile name.
+type credentialBackend interface {
+	// Get returns the token for profile, or "" if none is stored.
+	Get(profile string) (string, error)
+	Set(profile, token string) error
+	Delete(profile string) error
+}
+
+// ValidCredentialStore reports whether name is a known credential store.
+func ValidCredentialStore(name string) bool {
+	switch name {
+	case CredentialStoreKeyring, CredentialStoreFile, CredentialStorePlaintext, CredentialStoreEphemeral:
+		return true
+	}
+	return false
+}
+
+func defaultBackends(configDir string) map[string]credentialBackend {
+	return map[string]credentialBackend{
+		CredentialStoreKeyring: &keyringBackend{configDir: configDir},
+		CredentialStoreFile:    &fileBackend{dir: filepath.Join(configDir, "credentials")},
+		CredentialStoreEphemeral: &ephemeralBackend{
+			dir:    ephemeralDir(),
+			prefix: fmt.Sprintf("%x-", sha256.Sum256([]byte(configDir)))[:17],
+		},
+	}
+}
+
+// --- OS keyring -----------------------------------------------------------
+
+// keyringBackend shells out to the platform keyring tool, so 1ctl needs no
+// cgo or D-Bus client of its own. Entries are keyed by config directory and
+// profile so separate config directories never share a token.
+type keyringBackend struct {
+	configDir string
+}
+
+// keyringAvailable reports whether the OS keyring can be used here.
+func keyringAvailable() bool {
+	switch runtime.GOOS {
+	case "darwin":
+		_, err := exec.LookPath("security")
+		return err == nil
+	case "linux", "freebsd", "openbsd", "netbsd":
+		if os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" {
+			return false
+		}
+		_, err := exec.LookPath("secret-tool")
+		return err == nil
+	}
+	return false
+}
+
+func (k *keyringBackend) account(profile string) string {
+	return filepath.Join(k.configDir, "profiles", profile)
+}
+
+func (k *keyringBackend) Get(profile string) (string, error) {
+	if !keyringAvailable() {
+		return "", errors.New("OS keyring is not available")
+	}
+	var cmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		cmd = exec.Command("security", "find-generic-password", "-s", keyringService, "-a", k.account(profile), "-w") // #nosec G204
+	} else {
+		cmd = exec.Command("secret-tool", "lookup", "service", keyringService, "account", k.account(profile)) // #nosec G204
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		// secret-tool exits 1 silently and security(1) says "could not be
+		// found" when there is no entry.
+		msg := strings.TrimSpace(stderr.String())
+		if _, ok := err.(*exec.ExitError); ok && (msg == "" || strings.Contains(msg, "could not be found")) {
+			return "", nil
+		}
+		return "", fmt.Errorf("read OS keyring: %s", keyringError(err, stderr))
+	}
+	return strings.TrimRight(string(out), "\r\n"), nil
+}
+
+func (k *keyringBackend) Set(profile, token string) error {
+	if !keyringAvailable() {
+		return errors.New("OS keyring is not available")
+	}
+	var cmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		// security(1) only takes the password as an argument, so the
+		// command is read from stdin in interactive mode to keep the token
+		// out of the process list.
+		cmd = exec.Command("security", "-i") // #nosec G204
+		cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n",
+			securityQuote(keyringService), securityQuote(k.account(profile)), securityQuote(token)))
+	} else {
+		cmd = exec.Command("secret-tool", "store", "--label", "1ctl token ("+profile+")", "service", keyringService, "account", k.account(profile)) // #nosec G204
+		cmd.Stdin = strings.NewReader(token)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("write OS keyring: %s", keyringError(err, stderr))
+	}
+	// In interactive mode security(1) exits 0 when a command fails, and
+	// only reports it on stderr.
+	if runtime.GOOS == "darwin" && strings.TrimSpace(stderr.String()) != "" {
+		return fmt.Errorf("write OS keyring: %s", strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+func (k *keyringBackend) Delete(profile string) error {
+	if !keyringAvailable() {
+		return nil
+	}
+	var cmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		cmd = exec.Command("security", "delete-generic-password", "-s", keyringService, "-a", k.account(profile)) // #nosec G204
+	} else {
+		cmd = exec.Command("secret-tool", "clear", "service", keyringService, "account", k.account(profile)) // #nosec G204
+	}
+	// A missing entry is not an error worth reporting.
+	_ = cmd.Run() //nolint:errcheck
+	return nil
+}
+
+// securityQuote quotes an argument for a security(1) interactive command
+// line, which splits on spaces outside double quotes and honours
+// backslash escapes.
+func securityQuote(arg string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(arg) + `"`
+}
+
+func keyringError(err error, stderr bytes.Buffer) string {
+	if msg := strings.TrimSpace(stderr.String()); msg != "" {
+		return msg
+	}
+	return err.Error()
+}
+
+// --- Encrypted file -------------------------------------------------------
+
+// fileBackend keeps each token in its own file, sealed with AES-256-GCM
+// under a key derived from a passphrase with PBKDF2-SHA256. The passphrase
+// comes from SATUSKY_CREDENTIAL_PASSPHRASE or a terminal prompt, once per
+// process.
+type fileBackend struct {
+	dir string
+
+	mu         sync.Mutex
+	passphrase string
+}
+
+type sealedToken struct {
+	Version    int    `json:"version"`
+	Iterations int    `json:"iterations"`
+	Salt       string `json:"salt"`
+	Nonce      string `json:"nonce"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+func (f *fileBackend) path(profile string) string {
+	return filepath.Join(f.dir, profile+".json")
+}
+
+func (f *fileBackend) Get(profile string) (string, error) {
+	data, err := os.ReadFile(f.path(profile)) // #nosec G304 -- profile name is sanitised
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	var sealed sealedToken
+	if err := json.Unmarshal(data, &sealed); err != nil {
+		return "", fmt.Errorf("read %s: %w", f.path(profile), err)
+	}
+	salt, err1 := base64.StdEncoding.DecodeString(sealed.Salt)
+	nonce, err2 := base64.StdEncoding.DecodeString(sealed.Nonce)
+	ciphertext, err3 := base64.StdEncoding.DecodeString(sealed.Ciphertext)
+	if err := errors.Join(err1, err2, err3); err != nil {
+		return "", fmt.Errorf("read %s: %w", f.path(profile), err)
+	}
+
+	pass, err := f.getPassphrase(false)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := credentialGCM(pass, salt, sealed.Iterations)
+	if err != nil {
+		return "", err
+	}
+	plain, err := gcm.Open(nil, nonce, ciphertext, []byte(profile))
+	if err != nil {
+		return "", errors.New("failed to decrypt stored token: wrong passphrase?")
+	}
+	return string(plain), nil
+}
+
+func (f *fileBackend) Set(profile, token string) error {
+	_, statErr := os.Stat(f.path(profile))
+	pass, err := f.getPassphrase(os.IsNotExist(statErr))
+	if err != nil {
+		return err
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	gcm, err := credentialGCM(pass, salt, credentialPBKDF2Iterations)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	// The profile name is authenticated so a file cannot be swapped in for
+	// another profile.
+	sealed := sealedToken{
+		Version:    1,
+		Iterations: credentialPBKDF2Iterations,
+		Salt:       base64.StdEncoding.EncodeToString(salt),
+		Nonce:      base64.StdEncoding.EncodeToString(nonce),
+		Ciphertext: base64.StdEncoding.EncodeToString(gcm.Seal(nil, nonce, []byte(token), []byte(profile))),
+	}
+	data, err := json.MarshalIndent(sealed, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(f.dir, 0700); err != nil {
+		return err
+	}
+	return writeFileAtomic(f.path(profile), data, 0600)
+}
+
+func (f *fileBackend) Delete(profile string) error {
+	if err := os.Remove(f.path(profile)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// getPassphrase returns the cached passphrase, SATUSKY_CREDENTIAL_PASSPHRASE,
+// or prompts on stderr. confirm asks twice, for a file being created.
+func (f *fileBackend) getPassphrase(confirm bool) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.passphrase != "" {
+		return f.passphrase, nil
+	}
+	if p := os.Getenv(CredentialPassphraseEnv); p != "" {
+		f.passphrase = p
+		return p, nil
+	}
+	fd := int(syscall.Stdin) // #nosec G115 -- stdin is fd 0
+	if !term.IsTerminal(fd) {
+		return "", errNoPassphrase
+	}
+	prompt := func(label string) (string, error) {
+		fmt.Fprint(os.Stderr, label)
+		b, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			line, readErr := bufio.NewReader(os.Stdin).ReadString('\n')
+			if readErr != nil {
+				return "", errNoPassphrase
+			}
+			return strings.TrimSpace(line), nil
+		}
+		return string(b), nil
+	}
+	pass, err := prompt("Credential store passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if pass == "" {
+		return "", errors.New("passphrase must not be empty")
+	}
+	if confirm {
+		again, err := prompt("Confirm passphrase: ")
+		if err != nil {
+			return "", err
+		}
+		if again != pass {
+			return "", errors.New("passphrases do not match")
+		}
+	}
+	f.passphrase = pass
+	return pass, nil
+}
+
+func credentialGCM(passphrase string, salt []byte, iterations int) (cipher.AEAD, error) {
+	if iterations < 1 {
+		return nil, errors.New("invalid key derivation parameters")
+	}
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, iterations, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// --- Ephemeral ------------------------------------------------------------
+
+// ephemeralBackend keeps tokens unencrypted but outside the config
+// directory, in the CI runner's temporary directory, so nothing outlives
+// the job. Entries are prefixed with a hash of the config directory.
+// Without a runner temporary directory the tokens are only kept in memory,
+// since a shared temporary directory could be prepared by another user.
+type ephemeralBackend struct {
+	dir    string
+	prefix string
+
+	mu     sync.Mutex
+	memory map[string]string
+}
+
+// ephemeralDir is RUNNER_TEMP on GitHub Actions, which the runner empties
+// after every job, and "" elsewhere.
+func ephemeralDir() string {
+	if dir := os.Getenv("RUNNER_TEMP"); dir != "" {
+		return filepath.Join(dir, "1ctl-credentials")
+	}
+	return ""
+}
+
+func (e *ephemeralBackend) path(profile string) string {
+	return filepath.Join(e.dir, e.prefix+profile)
+}
+
+func (e *ephemeralBackend) Get(profile string) (string, error) {
+	if e.dir == "" {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		return e.memory[profile], nil
+	}
+	if err := checkEphemeralDir(e.dir); os.IsNotExist(err) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(e.path(profile)) // #nosec G304 -- profile name is sanitised
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	return string(data), err
+}
+
+func (e *ephemeralBackend) Set(profile, token string) error {
+	if e.dir == "" {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		if e.memory == nil {
+			e.memory = map[string]string{}
+		}
+		e.memory[profile] = token
+		return nil
+	}
+	if err := os.MkdirAll(e.dir, 0700); err != nil {
+		return err
+	}
+	if err := checkEphemeralDir(e.dir); err != nil {
+		return err
+	}
+	return writeFileAtomic(e.path(profile), []byte(token), 0600)
+}
+
+func (e *ephemeralBackend) Delete(profile string) error {
+	if e.dir == "" {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		delete(e.memory, profile)
+		return nil
+	}
+	if err := os.Remove(e.path(profile)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// checkEphemeralDir refuses a token directory that is a symlink, or that
+// another user could read or has planted.
+func checkEphemeralDir(dir string) error {
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	return checkPrivateDir(dir, info)
+}
+
+// --- Credential helper ----------------------------------------------------
+
+// helperBackend asks an external program for tokens using the credhelper
+// protocol. Refresh tokens are requested with kind "refresh".
+type helperBackend struct {
+	program   string
+	serverURL string
+}
+
+func (h *helperBackend) request(key string) credhelper.Request {
+	req := credhelper.Request{ServerURL: h.serverURL, Profile: key, Kind: credhelper.KindAccess}
+	if profile, ok := strings.CutSuffix(key, refreshSuffix); ok {
+		req.Profile, req.Kind = profile, credhelper.KindRefresh
+	}
+	return req
+}
+
+func (h *helperBackend) Get(profile string) (string, error) {
+	return credhelper.Run(h.program, credhelper.ActionGet, h.request(profile))
+}
+
+func (h *helperBackend) Set(profile, token string) error {
+	req := h.request(profile)
+	req.Token = token
+	_, err := credhelper.Run(h.program, credhelper.ActionStore, req)
+	return err
+}
+
+func (h *helperBackend) Delete(profile string) error {
+	_, err := credhelper.Run(h.program, credhelper.ActionErase, h.request(profile))
+	return err
+}
@@ -0,0 +1,300 @@
+package context
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// fakeKeyring stands in for the OS keyring.
+type fakeKeyring map[string]string
+
+func (f fakeKeyring) Get(profile string) (string, error) { return f[profile], nil }
+func (f fakeKeyring) Set(profile, token string) error    { f[profile] = token; return nil }
+func (f fakeKeyring) Delete(profile string) error        { delete(f, profile); return nil }
+
+// newCredentialTestStore returns a Store with an active "test" profile
+// whose file holds profileJSON, and a fake keyring.
+func newCredentialTestStore(t *testing.T, profileJSON string) (*Store, fakeKeyring) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "profiles"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "profiles", "test.json"), []byte(profileJSON), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "context.json"), []byte(`{"active_profile":"test"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	s := NewTestStore(dir)
+	keyring := fakeKeyring{}
+	s.backends[CredentialStoreKeyring] = keyring
+	return s, keyring
+}
+
+func readProfile(t *testing.T, s *Store) CLIContext {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(s.ConfigDir(), "profiles", "test.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ctx CLIContext
+	if err := json.Unmarshal(data, &ctx); err != nil {
+		t.Fatal(err)
+	}
+	return ctx
+}
+
+func TestKeyringStoreKeepsTokenOutOfProfile(t *testing.T) {
+	s, keyring := newCredentialTestStore(t, "{}")
+	if err := s.SetCredentialStore(CredentialStoreKeyring); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveLoginState("tok-123", "user", "a@b.c", "org", "Org", "ns"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := readProfile(t, s)
+	if ctx.Token != "" || ctx.CredentialStore != CredentialStoreKeyring {
+		t.Errorf("profile token = %q, store = %q; want empty token in keyring store", ctx.Token, ctx.CredentialStore)
+	}
+	if keyring["test"] != "tok-123" {
+		t.Errorf("keyring token = %q, want tok-123", keyring["test"])
+	}
+	if got := s.GetToken(); got != "tok-123" {
+		t.Errorf("GetToken() = %q, want tok-123", got)
+	}
+
+	if err := s.ClearAuthState(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := keyring["test"]; ok {
+		t.Error("logout left the token in the keyring")
+	}
+	if got := s.GetToken(); got != "" {
+		t.Errorf("GetToken() after logout = %q, want empty", got)
+	}
+}
+
+func TestFileStoreEncryptsToken(t *testing.T) {
+	t.Setenv(CredentialPassphraseEnv, "correct horse")
+	s, _ := newCredentialTestStore(t, "{}")
+	if err := s.SetCredentialStore(CredentialStoreFile); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetToken("tok-secret-456"); err != nil {
+		t.Fatal(err)
+	}
+
+	sealed, err := os.ReadFile(filepath.Join(s.ConfigDir(), "credentials", "test.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(sealed), "tok-secret-456") {
+		t.Error("credential file contains the token in clear")
+	}
+	if ctx := readProfile(t, s); ctx.Token != "" {
+		t.Errorf("profile token = %q, want empty", ctx.Token)
+	}
+
+	// A fresh process reads it back with the same passphrase only.
+	fresh := NewTestStore(s.ConfigDir())
+	if got := fresh.GetToken(); got != "tok-secret-456" {
+		t.Errorf("GetToken() = %q, want tok-secret-456", got)
+	}
+	t.Setenv(CredentialPassphraseEnv, "wrong")
+	if got := NewTestStore(s.ConfigDir()).GetToken(); got != "" {
+		t.Errorf("GetToken() with wrong passphrase = %q, want empty", got)
+	}
+}
+
+func TestLegacyPlaintextTokenIsMigrated(t *testing.T) {
+	s, keyring := newCredentialTestStore(t, `{"token":"legacy-tok","email":"a@b.c"}`)
+	// As with SATUSKY_CREDENTIAL_STORE=keyring; the automatic choice depends
+	// on whether the test machine has a keyring.
+	s.credentialStore = CredentialStoreKeyring
+
+	if got := s.GetToken(); got != "legacy-tok" {
+		t.Fatalf("GetToken() = %q, want legacy-tok", got)
+	}
+	data, err := os.ReadFile(filepath.Join(s.ConfigDir(), "profiles", "test.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "legacy-tok") {
+		t.Errorf("profile still holds the token after the first read: %s", data)
+	}
+	ctx := readProfile(t, s)
+	if ctx.CredentialStore != CredentialStoreKeyring || ctx.Email != "a@b.c" {
+		t.Errorf("after migration store = %q, email = %q", ctx.CredentialStore, ctx.Email)
+	}
+	if keyring["test"] != "legacy-tok" {
+		t.Errorf("keyring token = %q, want legacy-tok", keyring["test"])
+	}
+	if got := s.GetToken(); got != "legacy-tok" {
+		t.Errorf("GetToken() after migration = %q, want legacy-tok", got)
+	}
+}
+
+func TestLegacyPlaintextTokenIsMigratedToFileWithPassphrase(t *testing.T) {
+	t.Setenv(CredentialPassphraseEnv, "correct horse")
+	s, _ := newCredentialTestStore(t, `{"token":"legacy-tok"}`)
+	s.credentialStore = CredentialStoreFile
+
+	if got := s.GetToken(); got != "legacy-tok" {
+		t.Fatalf("GetToken() = %q, want legacy-tok", got)
+	}
+	if ctx := readProfile(t, s); ctx.Token != "" || ctx.CredentialStore != CredentialStoreFile {
+		t.Errorf("after migration token = %q, store = %q", ctx.Token, ctx.CredentialStore)
+	}
+}
+
+func TestLegacyPlaintextTokenStaysWithoutPassphrase(t *testing.T) {
+	t.Setenv(CredentialPassphraseEnv, "")
+	s, _ := newCredentialTestStore(t, `{"token":"legacy-tok"}`)
+	s.credentialStore = CredentialStoreFile
+
+	if got := s.GetToken(); got != "legacy-tok" {
+		t.Fatalf("GetToken() = %q, want legacy-tok", got)
+	}
+	if ctx := readProfile(t, s); ctx.Token != "legacy-tok" || ctx.CredentialStore != "" {
+		t.Errorf("the token moved without a passphrase: %+v", ctx)
+	}
+}
+
+func TestSecurityQuote(t *testing.T) {
+	for arg, want := range map[string]string{
+		"1ctl":                `"1ctl"`,
+		"/Users/a b/.satusky": `"/Users/a b/.satusky"`,
+		`tok"en\`:             `"tok\"en\\"`,
+	} {
+		if got := securityQuote(arg); got != want {
+			t.Errorf("securityQuote(%q) = %s, want %s", arg, got, want)
+		}
+	}
+}
+
+func TestExplicitPlaintextIsNotMigrated(t *testing.T) {
+	s, _ := newCredentialTestStore(t, `{"token":"legacy-tok"}`)
+	s.credentialStore = CredentialStorePlaintext
+	if got := s.GetToken(); got != "legacy-tok" {
+		t.Fatalf("GetToken() = %q, want legacy-tok", got)
+	}
+	if ctx := readProfile(t, s); ctx.Token != "legacy-tok" {
+		t.Errorf("plaintext store migrated the token: %+v", ctx)
+	}
+
+	profiles, err := s.ListProfiles()
+	if err != nil || len(profiles) != 1 {
+		t.Fatalf("ListProfiles() = %v, %v", profiles, err)
+	}
+	if profiles[0].CredentialStore != "plaintext (legacy)" {
+		t.Errorf("CredentialStore = %q, want plaintext (legacy)", profiles[0].CredentialStore)
+	}
+}
+
+func TestSetCredentialStoreRejectsUnknown(t *testing.T) {
+	s := NewTestStore(t.TempDir())
+	if err := s.SetCredentialStore("vault"); err == nil {
+		t.Error("SetCredentialStore(vault) should fail")
+	}
+}
+
+func TestRefreshTokenFollowsAccessToken(t *testing.T) {
+	s, keyring := newCredentialTestStore(t, "{}")
+	if err := s.SetCredentialStore(CredentialStoreKeyring); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveOAuthLoginState("access-1", "refresh-1", "user", "a@b.c", "org", "Org", "ns"); err != nil {
+		t.Fatal(err)
+	}
+	if ctx := readProfile(t, s); ctx.RefreshToken != "" {
+		t.Errorf("profile refresh token = %q, want it in the keyring", ctx.RefreshToken)
+	}
+	if got := s.GetRefreshToken(); got != "refresh-1" {
+		t.Errorf("GetRefreshToken() = %q, want refresh-1", got)
+	}
+
+	// Other saves keep it; a new API token drops it.
+	if err := s.SetEmail("b@c.d"); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.GetRefreshToken(); got != "refresh-1" {
+		t.Errorf("GetRefreshToken() after SetEmail = %q, want refresh-1", got)
+	}
+	if err := s.SetToken("api-token"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := keyring["test"+refreshSuffix]; ok {
+		t.Error("a new API token left the old refresh token behind")
+	}
+}
+
+func TestEphemeralStoreKeepsTokenOutOfConfigDir(t *testing.T) {
+	runnerTemp := t.TempDir()
+	t.Setenv("RUNNER_TEMP", runnerTemp)
+	s, _ := newCredentialTestStore(t, "{}")
+	if err := s.SetCredentialStore(CredentialStoreEphemeral); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetToken("ci-token"); err != nil {
+		t.Fatal(err)
+	}
+
+	if ctx := readProfile(t, s); ctx.Token != "" || ctx.CredentialStore != CredentialStoreEphemeral {
+		t.Errorf("profile token = %q, store = %q", ctx.Token, ctx.CredentialStore)
+	}
+	if got := NewTestStore(s.ConfigDir()).GetToken(); got != "ci-token" {
+		t.Errorf("GetToken() = %q, want ci-token", got)
+	}
+
+	// Once the runner discards its temp directory the profile is logged out.
+	if err := os.RemoveAll(runnerTemp); err != nil {
+		t.Fatal(err)
+	}
+	if got := NewTestStore(s.ConfigDir()).GetToken(); got != "" {
+		t.Errorf("GetToken() after the job = %q, want empty", got)
+	}
+}
+
+func TestEphemeralStoreOutsideGitHubActionsKeepsTokenInMemory(t *testing.T) {
+	t.Setenv("RUNNER_TEMP", "")
+	s, _ := newCredentialTestStore(t, "{}")
+	if err := s.SetCredentialStore(CredentialStoreEphemeral); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetToken("ci-token"); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.GetToken(); got != "ci-token" {
+		t.Errorf("GetToken() = %q, want ci-token", got)
+	}
+	if got := NewTestStore(s.ConfigDir()).GetToken(); got != "" {
+		t.Errorf("GetToken() in another process = %q, want empty", got)
+	}
+}
+
+func TestEphemeralStoreRefusesAnOpenDirectory(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no Unix permissions")
+	}
+	runnerTemp := t.TempDir()
+	t.Setenv("RUNNER_TEMP", runnerTemp)
+	if err := os.Mkdir(filepath.Join(runnerTemp, "1ctl-credentials"), 0777); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(filepath.Join(runnerTemp, "1ctl-credentials"), 0777); err != nil { // #nosec G302 -- the directory under test
+		t.Fatal(err)
+	}
+	s, _ := newCredentialTestStore(t, "{}")
+	if err := s.SetCredentialStore(CredentialStoreEphemeral); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetToken("ci-token"); err == nil || !strings.Contains(err.Error(), "accessible to other users") {
+		t.Errorf("SetToken() error = %v, want the directory refused", err)
+	}
+}
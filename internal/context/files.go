@@ -0,0 +1,137 @@
+package context
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"1ctl/internal/utils"
+)
+
+// Several 1ctl processes may share a config directory (parallel CI jobs,
+// "org switch" during a deploy), so every write to a profile or
+// context.json happens under an advisory lock on a sibling ".lock" file and
+// lands with a rename, which readers never observe half-done.
+
+// lockTimeout bounds the wait for another process to finish a write.
+const lockTimeout = 10 * time.Second
+
+// lockFile takes an exclusive lock for path and returns its release.
+func lockFile(path string) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0600) // #nosec G304 -- path is under the config dir
+	if err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		ok, err := tryLock(f)
+		if err != nil {
+			_ = f.Close() //nolint:errcheck
+			return nil, fmt.Errorf("lock %s: %w", path, err)
+		}
+		if ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			_ = f.Close() //nolint:errcheck
+			return nil, utils.NewError(fmt.Sprintf("timed out waiting for another 1ctl process to finish writing %s", path), nil)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return func() {
+		_ = unlock(f) //nolint:errcheck
+		_ = f.Close() //nolint:errcheck
+	}, nil
+}
+
+// LockFile is lockFile for files other packages keep under the config
+// directory, such as the env history journals.
+func LockFile(path string) (func(), error) {
+	return lockFile(path)
+}
+
+// WriteFileAtomic is writeFileAtomic for files other packages keep under
+// the config directory, such as the API response cache.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	return writeFileAtomic(path, data, perm)
+}
+
+// writeFileAtomic writes data to a temporary file in path's directory and
+// renames it over path, so a crash or a concurrent reader never sees a
+// truncated file.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	cleanup := func() { _ = os.Remove(tmp.Name()) } //nolint:errcheck
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close() //nolint:errcheck
+		cleanup()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close() //nolint:errcheck
+		cleanup()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		cleanup()
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		cleanup()
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		cleanup()
+		return err
+	}
+	return nil
+}
+
+// readJSONFile decodes path into v. A missing or empty file leaves v
+// untouched. Invalid JSON is recovered with backupCorrupt.
+func readJSONFile(path string, v interface{}, hint string) error {
+	data, err := os.ReadFile(path) // #nosec G304 -- path is under the config dir
+	if os.IsNotExist(err) || (err == nil && len(data) == 0) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return backupCorrupt(path, err, hint)
+	}
+	return nil
+}
+
+// backupCorrupt moves an unparseable file aside, replaces it with an empty
+// object so the next command starts clean, and returns an error saying
+// where the original went and what to do. The caller holds path's lock.
+func backupCorrupt(path string, parseErr error, hint string) error {
+	backup := fmt.Sprintf("%s.corrupt-%s", path, time.Now().UTC().Format("20060102T150405Z"))
+	if err := os.Rename(path, backup); err != nil {
+		return utils.NewError(fmt.Sprintf("%s is corrupted (%s) and could not be moved aside: %s", path, parseErr.Error(), err.Error()), nil)
+	}
+	if err := writeFileAtomic(path, []byte("{}"), 0600); err != nil {
+		return utils.NewError(fmt.Sprintf("%s was corrupted and moved to %s, but could not be reset: %s", path, backup, err.Error()), nil)
+	}
+	return utils.NewError(fmt.Sprintf("%s was corrupted (%s) and has been reset; the original is saved as %s. %s", path, parseErr.Error(), backup, hint), nil)
+}
+
+// recoverJSONFile re-reads path under its lock, in case it was mid-write by
+// an older 1ctl, and backs it up if it is still invalid.
+func recoverJSONFile(path string, v interface{}, hint string) error {
+	release, err := lockFile(path)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return readJSONFile(path, v, hint)
+}
@@ -0,0 +1,90 @@
+package context
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestConcurrentSavesDoNotLoseWrites(t *testing.T) {
+	s, _ := newCredentialTestStore(t, "{}")
+
+	// Separate Stores open the files independently, like separate processes,
+	// so only the file lock keeps their read-modify-writes apart.
+	var wg sync.WaitGroup
+	errs := make(chan error, 40)
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			errs <- NewTestStore(s.ConfigDir()).SetEmail(fmt.Sprintf("user%d@example.com", i))
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			errs <- NewTestStore(s.ConfigDir()).SetAPIURL(fmt.Sprintf("https://api%d.example.com", i))
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("concurrent save: %v", err)
+		}
+	}
+
+	ctx := readProfile(t, s)
+	if ctx.Email == "" || ctx.APIURL == "" {
+		t.Errorf("a concurrent save was lost: email = %q, api_url = %q", ctx.Email, ctx.APIURL)
+	}
+}
+
+func TestCorruptProfileIsBackedUp(t *testing.T) {
+	s, _ := newCredentialTestStore(t, `{"token": "abc", "email": `)
+
+	if got := s.GetEmail(); got != "" {
+		t.Errorf("GetEmail() = %q, want empty", got)
+	}
+	err := s.LoadError()
+	if err == nil || !strings.Contains(err.Error(), "corrupted") || !strings.Contains(err.Error(), "auth login") {
+		t.Fatalf("LoadError() = %v, want an actionable corruption error", err)
+	}
+
+	backups, _ := filepath.Glob(filepath.Join(s.ConfigDir(), "profiles", "test.json.corrupt-*"))
+	if len(backups) != 1 {
+		t.Fatalf("backups = %v, want one", backups)
+	}
+	if data, _ := os.ReadFile(backups[0]); string(data) != `{"token": "abc", "email": ` {
+		t.Errorf("backup content = %q", data)
+	}
+
+	// The next process starts from a clean profile.
+	fresh := NewTestStore(s.ConfigDir())
+	if err := fresh.SetEmail("a@b.c"); err != nil {
+		t.Fatalf("SetEmail() after recovery: %v", err)
+	}
+	if err := fresh.LoadError(); err != nil {
+		t.Errorf("LoadError() after recovery = %v", err)
+	}
+}
+
+func TestCorruptContextFileIsBackedUp(t *testing.T) {
+	s, _ := newCredentialTestStore(t, "{}")
+	if err := os.WriteFile(filepath.Join(s.ConfigDir(), "context.json"), []byte("{\"active_pro"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.ActiveProfileName(); got != "" {
+		t.Errorf("ActiveProfileName() = %q, want empty", got)
+	}
+	if err := s.LoadError(); err == nil || !strings.Contains(err.Error(), "profile use") {
+		t.Errorf("LoadError() = %v, want an actionable corruption error", err)
+	}
+	if err := s.UseProfile("test"); err != nil {
+		t.Fatalf("UseProfile() after recovery: %v", err)
+	}
+	if got := NewTestStore(s.ConfigDir()).ActiveProfileName(); got != "test" {
+		t.Errorf("ActiveProfileName() = %q, want test", got)
+	}
+}
@@ -0,0 +1,169 @@
+package context
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"1ctl/internal/credhelper"
+)
+
+// The test binary doubles as a fake credential helper: when
+// fakeHelperStateEnv is set it serves one request against a JSON state file
+// and exits instead of running tests.
+const (
+	fakeHelperStateEnv = "SATUSKY_FAKE_HELPER_STATE"
+	fakeHelperFailEnv  = "SATUSKY_FAKE_HELPER_FAIL"
+)
+
+func TestMain(m *testing.M) {
+	if path := os.Getenv(fakeHelperStateEnv); path != "" {
+		if err := credhelper.Serve(os.Args[1:], os.Stdin, os.Stdout, fakeHelper{path: path}); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// fakeHelperState is the fake helper's state file: its tokens and a log of
+// the calls it received.
+type fakeHelperState struct {
+	Tokens map[string]string `json:"tokens"`
+	Calls  []string          `json:"calls"`
+}
+
+type fakeHelper struct{ path string }
+
+func (f fakeHelper) update(action string, req credhelper.Request, fn func(map[string]string) string) (string, error) {
+	if action == os.Getenv(fakeHelperFailEnv) {
+		return "", errors.New("vault is sealed")
+	}
+	var st fakeHelperState
+	data, _ := os.ReadFile(f.path) //nolint:errcheck
+	_ = json.Unmarshal(data, &st)  //nolint:errcheck
+	if st.Tokens == nil {
+		st.Tokens = map[string]string{}
+	}
+	st.Calls = append(st.Calls, action+" "+req.Profile+" "+req.Kind)
+	out := fn(st.Tokens)
+	data, _ = json.Marshal(st) //nolint:errcheck
+	return out, os.WriteFile(f.path, data, 0600)
+}
+
+func (f fakeHelper) Get(req credhelper.Request) (string, error) {
+	return f.update("get", req, func(t map[string]string) string { return t[req.Profile+"/"+req.Kind] })
+}
+
+func (f fakeHelper) Store(req credhelper.Request) error {
+	_, err := f.update("store", req, func(t map[string]string) string { t[req.Profile+"/"+req.Kind] = req.Token; return "" })
+	return err
+}
+
+func (f fakeHelper) Erase(req credhelper.Request) error {
+	_, err := f.update("erase", req, func(t map[string]string) string { delete(t, req.Profile+"/"+req.Kind); return "" })
+	return err
+}
+
+// useFakeHelper points helper calls at the test binary and returns a
+// function reading the helper's state.
+func useFakeHelper(t *testing.T, tokens map[string]string) func() fakeHelperState {
+	t.Helper()
+	path := t.TempDir() + "/helper.json"
+	data, err := json.Marshal(fakeHelperState{Tokens: tokens})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(fakeHelperStateEnv, path)
+	return func() fakeHelperState {
+		var st fakeHelperState
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(data, &st); err != nil {
+			t.Fatal(err)
+		}
+		return st
+	}
+}
+
+func TestCredentialHelperStoresToken(t *testing.T) {
+	state := useFakeHelper(t, nil)
+	s, _ := newCredentialTestStore(t, "{}")
+	s.SetCredentialHelper(os.Args[0])
+
+	if err := s.SaveLoginState("helper-tok", "user", "a@b.c", "org", "Org", "ns"); err != nil {
+		t.Fatal(err)
+	}
+	ctx := readProfile(t, s)
+	if ctx.Token != "" || ctx.CredentialStore != CredentialStoreHelper || ctx.CredentialHelper != os.Args[0] {
+		t.Errorf("profile = %+v, want the token in the helper", ctx)
+	}
+	if got := state().Tokens["test/access"]; got != "helper-tok" {
+		t.Errorf("helper token = %q, want helper-tok", got)
+	}
+
+	// A later process finds the helper from the profile and asks it once.
+	fresh := NewTestStore(s.ConfigDir())
+	before := len(state().Calls)
+	for i := 0; i < 3; i++ {
+		if got := fresh.GetToken(); got != "helper-tok" {
+			t.Fatalf("GetToken() = %q, want helper-tok", got)
+		}
+	}
+	if calls := state().Calls[before:]; len(calls) != 1 || calls[0] != "get test access" {
+		t.Errorf("helper calls = %v, want a single get", calls)
+	}
+	if got := fresh.CredentialStore(); !strings.HasPrefix(got, "helper (") {
+		t.Errorf("CredentialStore() = %q", got)
+	}
+
+	if err := fresh.ClearAuthState(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := state().Tokens["test/access"]; ok {
+		t.Error("logout did not erase the token from the helper")
+	}
+}
+
+func TestCredentialHelperSuppliesToken(t *testing.T) {
+	state := useFakeHelper(t, map[string]string{"test/access": "vault-tok"})
+	s, _ := newCredentialTestStore(t, `{"token":"old-plaintext"}`)
+	s.SetCredentialHelper(os.Args[0])
+
+	if got := s.GetToken(); got != "vault-tok" {
+		t.Fatalf("GetToken() = %q, want the helper's token", got)
+	}
+	// Saving the token the helper handed out does not store it back.
+	if err := s.SetToken("vault-tok"); err != nil {
+		t.Fatal(err)
+	}
+	for _, call := range state().Calls {
+		if strings.HasPrefix(call, "store test access") {
+			t.Errorf("helper was asked to store its own token: %v", state().Calls)
+		}
+	}
+	if ctx := readProfile(t, s); ctx.Token != "" {
+		t.Errorf("profile still holds the plaintext token %q", ctx.Token)
+	}
+}
+
+func TestCredentialHelperErrors(t *testing.T) {
+	useFakeHelper(t, nil)
+	t.Setenv(fakeHelperFailEnv, "store")
+	s, _ := newCredentialTestStore(t, "{}")
+	s.SetCredentialHelper(os.Args[0])
+
+	err := s.SetToken("tok")
+	if err == nil || !strings.Contains(err.Error(), "vault is sealed") {
+		t.Errorf("SetToken() error = %v, want the helper's message", err)
+	}
+}
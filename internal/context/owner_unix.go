@@ -0,0 +1,21 @@
+//go:build !windows
+
+package context
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// checkPrivateDir fails unless dir, described by info, belongs to the
+// current user and is closed to everyone else.
+func checkPrivateDir(dir string, info os.FileInfo) error {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok && int(st.Uid) != os.Getuid() {
+		return fmt.Errorf("%s belongs to another user", dir)
+	}
+	if info.Mode().Perm()&0077 != 0 {
+		return fmt.Errorf("%s is accessible to other users (mode %s)", dir, info.Mode().Perm())
+	}
+	return nil
+}
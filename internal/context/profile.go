@@ -6,6 +6,18 @@ package context
 // default Store. Used by the --profile global flag.
 func SetProfileOverride(name string) { Default().SetProfileOverride(name) }
 
+// SetProfileSource records where the profile override came from.
+func SetProfileSource(source string) { Default().SetProfileSource(source) }
+
+// GetProfileSource describes where the effective profile was chosen.
+func GetProfileSource() string { return Default().ProfileSource() }
+
+// GetEffectiveProfileName returns the profile commands run against.
+func GetEffectiveProfileName() string { return Default().EffectiveProfileName() }
+
+// ProfileExists reports whether the named profile exists.
+func ProfileExists(name string) bool { return Default().ProfileExists(name) }
+
 // GetActiveProfileName returns the active profile name (or "").
 func GetActiveProfileName() string { return Default().ActiveProfileName() }
 
@@ -30,10 +30,26 @@ import (
 type Store struct {
 	configDir       string
 	profileOverride string
+	profileSource   string
 
 	cacheMu    sync.RWMutex
 	cachedCtx  *CLIContext
 	cacheValid bool
+
+	errMu   sync.Mutex
+	loadErr error // set when a corrupted file was recovered
+
+	// credentialStore is the store for tokens saved by this process; ""
+	// picks the keyring when available, else the encrypted file.
+	credentialStore string
+	backends        map[string]credentialBackend
+	// credentialHelper, when set, names a program that supplies and stores
+	// tokens for every profile, overriding the profile's own store.
+	credentialHelper string
+
+	tokenMu     sync.Mutex
+	cachedToken string
+	tokenValid  bool
 }
 
 // NewStore constructs a production Store rooted at ~/.satusky/. The
@@ -49,21 +65,41 @@ func NewStore() (*Store, error) {
 	if err := os.MkdirAll(dir, 0750); err != nil {
 		return nil, fmt.Errorf("create config directory %s: %w", dir, err)
 	}
-	return &Store{configDir: dir}, nil
+	return &Store{
+		configDir:        dir,
+		credentialStore:  os.Getenv(CredentialStoreEnv),
+		backends:         defaultBackends(dir),
+		credentialHelper: os.Getenv(CredentialHelperEnv),
+	}, nil
 }
 
 // NewTestStore returns a Store rooted at the given directory. Intended
 // for unit and integration tests — pass a t.TempDir() result. No I/O
 // happens at construction; the caller decides whether to create the
-// directory.
+// directory. Tokens are kept in plaintext so tests never touch the OS
+// keyring or prompt for a passphrase.
 func NewTestStore(configDir string) *Store {
-	return &Store{configDir: configDir}
+	return &Store{
+		configDir:       configDir,
+		credentialStore: CredentialStorePlaintext,
+		backends:        defaultBackends(configDir),
+	}
 }
 
 // ConfigDir returns the absolute path to the Store's root directory.
 // Exposed for callers that need to construct paths under it (e.g. tests).
 func (s *Store) ConfigDir() string { return s.configDir }
 
+// CacheDir returns the directory for the active profile's cached API
+// responses, <configDir>/cache/<profile>.
+func (s *Store) CacheDir() string {
+	name := s.EffectiveProfileName()
+	if name == "" {
+		name = "default"
+	}
+	return filepath.Join(s.configDir, "cache", name)
+}
+
 // SetProfileOverride applies a process-scoped profile override. Used by
 // the --profile global flag. Not persisted. Resets the in-memory cache
 // so subsequent reads pick up the new profile.
@@ -75,6 +111,50 @@ func (s *Store) SetProfileOverride(name string) {
 	s.cacheMu.Unlock()
 }
 
+// SetProfileSource records where the profile override came from (a flag,
+// an environment variable or a pin file), for display.
+func (s *Store) SetProfileSource(source string) { s.profileSource = source }
+
+// ProfileSource describes where the effective profile was chosen.
+func (s *Store) ProfileSource() string {
+	if s.profileOverride != "" {
+		if s.profileSource != "" {
+			return s.profileSource
+		}
+		return "--profile flag"
+	}
+	return "context.json ('1ctl profile use')"
+}
+
+// EffectiveProfileName returns the profile commands run against: the
+// process override if one is set, else the active profile.
+func (s *Store) EffectiveProfileName() string { return s.profileName() }
+
+// ProfileExists reports whether a profile with the given name exists.
+func (s *Store) ProfileExists(name string) bool {
+	_, err := os.Stat(filepath.Join(s.configDir, "profiles", sanitizeProfileName(name)+".json"))
+	return err == nil
+}
+
+// SetCredentialStore selects where tokens saved by this process are
+// stored (the auth login --credential-store flag). Saving a token moves it
+// out of the profile's previous store.
+func (s *Store) SetCredentialStore(name string) error {
+	if !ValidCredentialStore(name) {
+		return utils.NewError(fmt.Sprintf("unknown credential store %q (expected keyring, file, ephemeral or plaintext)", name), nil)
+	}
+	s.credentialStore = name
+	return nil
+}
+
+// SetCredentialHelper makes this process read and save tokens through the
+// given credential helper program (the auth login --credential-helper
+// flag). Saving a token records the helper in the profile.
+func (s *Store) SetCredentialHelper(program string) {
+	s.credentialHelper = program
+	s.invalidateCache()
+}
+
 // invalidateCache clears the in-memory CLIContext cache. Called from
 // Save and from anything that mutates the active profile path.
 func (s *Store) invalidateCache() {
@@ -82,6 +162,10 @@ func (s *Store) invalidateCache() {
 	s.cachedCtx = nil
 	s.cacheValid = false
 	s.cacheMu.Unlock()
+	s.tokenMu.Lock()
+	s.cachedToken = ""
+	s.tokenValid = false
+	s.tokenMu.Unlock()
 }
 
 // validatePath ensures path traversal can't escape the Store's root.
@@ -96,6 +180,15 @@ func (s *Store) validatePath(path string) error {
 	return nil
 }
 
+// profileName returns the sanitised name of the profile being read and
+// written, or "" when none is active.
+func (s *Store) profileName() string {
+	if s.profileOverride != "" {
+		return s.profileOverride
+	}
+	return sanitizeProfileName(s.ActiveProfileName())
+}
+
 // contextFilePath returns the active profile's CLIContext JSON path,
 // or a sentinel non-existent path when no profile is active.
 func (s *Store) contextFilePath() string {
@@ -138,7 +231,10 @@ func (s *Store) load() *CLIContext {
 	loaded := &CLIContext{}
 	data, err := os.ReadFile(contextFile) // #nosec G304 -- Path validated above
 	if err == nil && len(data) > 0 {
-		_ = json.Unmarshal(data, loaded) //nolint:errcheck
+		if json.Unmarshal(data, loaded) != nil {
+			loaded = &CLIContext{}
+			s.setLoadErr(recoverJSONFile(contextFile, loaded, s.profileRecoveryHint()))
+		}
 	}
 	s.cachedCtx = loaded
 	s.cacheValid = true
@@ -146,40 +242,244 @@ func (s *Store) load() *CLIContext {
 	return &ctx
 }
 
+// LoadError returns the error from recovering a corrupted profile or
+// context.json during this process, or nil.
+func (s *Store) LoadError() error {
+	s.load()
+	s.errMu.Lock()
+	defer s.errMu.Unlock()
+	return s.loadErr
+}
+
+func (s *Store) setLoadErr(err error) {
+	if err == nil {
+		return
+	}
+	s.errMu.Lock()
+	if s.loadErr == nil {
+		s.loadErr = err
+	}
+	s.errMu.Unlock()
+}
+
+func (s *Store) profileRecoveryHint() string {
+	return fmt.Sprintf("Run '1ctl auth login' to sign in to profile '%s' again.", s.profileName())
+}
+
 // save writes profile data changes to the active profile file. Errors
-// if no profile is currently active. Atomic with respect to other
-// concurrent reads — cache is invalidated after the write commits.
+// if no profile is currently active. The read-modify-write runs under a
+// file lock shared with other 1ctl processes and lands with an atomic
+// rename; the cache is invalidated after the write commits.
 func (s *Store) save(modifier func(*CLIContext)) error {
 	if s.ActiveProfileName() == "" && s.profileOverride == "" {
 		return utils.NewError("no profile is active. Create one with '1ctl profile create [--url <url>] <name>' then run '1ctl profile use <name>'", nil)
 	}
 
 	contextFile := s.contextFilePath()
-	if err := os.MkdirAll(filepath.Dir(contextFile), 0750); err != nil {
+	release, err := lockFile(contextFile)
+	if err != nil {
 		return err
 	}
+	defer release()
 
 	var ctx CLIContext
-	data, err := os.ReadFile(contextFile) // #nosec G304 -- Path resolved via s.contextFilePath()
-	if err == nil && len(data) > 0 {
-		if err := json.Unmarshal(data, &ctx); err != nil {
-			return err
-		}
+	if err := readJSONFile(contextFile, &ctx, s.profileRecoveryHint()); err != nil {
+		s.invalidateCache()
+		return err
 	}
 
+	// The tokens may live outside the file, so detect a change with a
+	// sentinel rather than comparing against what is on disk. A new access
+	// token always replaces the refresh token, which belongs to the old one.
+	onDisk, onDiskRefresh := ctx.Token, ctx.RefreshToken
+	ctx.Token, ctx.RefreshToken = tokenUnchanged, tokenUnchanged
 	modifier(&ctx)
+	if ctx.Token == tokenUnchanged {
+		ctx.Token, ctx.RefreshToken = onDisk, onDiskRefresh
+	} else {
+		refresh := ctx.RefreshToken
+		if refresh == tokenUnchanged {
+			refresh = ""
+		}
+		if err := s.storeToken(&ctx, ctx.Token, refresh); err != nil {
+			return err
+		}
+	}
 
-	data, err = json.MarshalIndent(ctx, "", "  ")
+	data, err := json.MarshalIndent(ctx, "", "  ")
 	if err != nil {
 		return err
 	}
-	if err := os.WriteFile(contextFile, data, 0600); err != nil {
+	if err := writeFileAtomic(contextFile, data, 0600); err != nil {
 		return err
 	}
 	s.invalidateCache()
 	return nil
 }
 
+// tokenUnchanged marks CLIContext.Token as untouched by a save modifier.
+const tokenUnchanged = "\x00unchanged"
+
+// refreshSuffix names a profile's refresh token entry in a credential
+// store. Profile names cannot contain dots, so it never collides.
+const refreshSuffix = ".refresh"
+
+// storeToken writes the access and refresh tokens to the credential store
+// for the profile and updates ctx to match: the store's name, and the
+// tokens themselves only for plaintext. An empty token deletes both.
+func (s *Store) storeToken(ctx *CLIContext, token, refresh string) error {
+	name := s.profileName()
+	previous, previousHelper := ctx.CredentialStore, ctx.CredentialHelper
+	erase := func(b credentialBackend) error {
+		if err := b.Delete(name); err != nil {
+			return err
+		}
+		_ = b.Delete(name + refreshSuffix) //nolint:errcheck
+		return nil
+	}
+
+	if token == "" {
+		if b := s.storedBackend(ctx); b != nil {
+			if err := erase(b); err != nil {
+				return err
+			}
+		}
+		if s.credentialHelper != "" && (previous != CredentialStoreHelper || previousHelper != s.credentialHelper) {
+			if err := erase(s.backend(CredentialStoreHelper, s.credentialHelper, ctx.APIURL)); err != nil {
+				return err
+			}
+		}
+		ctx.Token, ctx.RefreshToken = "", ""
+		return nil
+	}
+
+	store, helper := s.credentialStore, ""
+	switch {
+	case s.credentialHelper != "":
+		store, helper = CredentialStoreHelper, s.credentialHelper
+	case store != "":
+	case previous != "":
+		store, helper = previous, previousHelper
+	default:
+		store = autoCredentialStore()
+	}
+	if store != CredentialStoreHelper {
+		helper = ""
+	}
+
+	if store == CredentialStorePlaintext {
+		ctx.Token, ctx.RefreshToken = token, refresh
+	} else {
+		b := s.backend(store, helper, ctx.APIURL)
+		if b == nil {
+			return utils.NewError(fmt.Sprintf("unknown credential store %q", store), nil)
+		}
+		// A helper that handed out the token need not be asked to store it.
+		if current, err := b.Get(name); store != CredentialStoreHelper || err != nil || current != token {
+			if err := b.Set(name, token); err != nil {
+				return utils.NewError(fmt.Sprintf("failed to store token in %s credential store: %s", store, err.Error()), nil)
+			}
+		}
+		var err error
+		if refresh != "" {
+			err = b.Set(name+refreshSuffix, refresh)
+		} else {
+			err = b.Delete(name + refreshSuffix)
+		}
+		if err != nil {
+			return utils.NewError(fmt.Sprintf("failed to store refresh token in %s credential store: %s", store, err.Error()), nil)
+		}
+		ctx.Token, ctx.RefreshToken = "", ""
+	}
+	if previous != store || previousHelper != helper {
+		if b := s.storedBackend(ctx); b != nil {
+			_ = erase(b) //nolint:errcheck
+		}
+	}
+	ctx.CredentialStore, ctx.CredentialHelper = store, helper
+	return nil
+}
+
+// backend returns the credential backend for a store name, or nil for
+// plaintext and unknown stores.
+func (s *Store) backend(store, helper, serverURL string) credentialBackend {
+	if store == CredentialStoreHelper {
+		if helper == "" {
+			return nil
+		}
+		return &helperBackend{program: helper, serverURL: serverURL}
+	}
+	if b, ok := s.backends[store]; ok {
+		return b
+	}
+	return nil
+}
+
+// storedBackend returns the backend the profile's token was saved in.
+func (s *Store) storedBackend(ctx *CLIContext) credentialBackend {
+	return s.backend(ctx.CredentialStore, ctx.CredentialHelper, ctx.APIURL)
+}
+
+// readBackend returns the backend to read the profile's token from: the
+// process-wide credential helper if one is set, else storedBackend.
+func (s *Store) readBackend(ctx *CLIContext) (string, credentialBackend) {
+	if s.credentialHelper != "" {
+		return CredentialStoreHelper, s.backend(CredentialStoreHelper, s.credentialHelper, ctx.APIURL)
+	}
+	return ctx.CredentialStore, s.storedBackend(ctx)
+}
+
+// autoCredentialStore picks the store for a profile with none chosen: the
+// OS keyring when there is one, otherwise the encrypted file. Plaintext is
+// only ever used when asked for.
+func autoCredentialStore() string {
+	if keyringAvailable() {
+		return CredentialStoreKeyring
+	}
+	return CredentialStoreFile
+}
+
+// migrateToken moves a token saved in plaintext by an older 1ctl into the
+// credential store. It is best effort and never prompts: without a keyring
+// or SATUSKY_CREDENTIAL_PASSPHRASE the profile stays as it was. The profile
+// is re-read under its lock, so a concurrent login or migration wins.
+func (s *Store) migrateToken() {
+	store := s.credentialStore
+	if store == "" {
+		store = autoCredentialStore()
+	}
+	switch {
+	case store == CredentialStoreKeyring:
+	case store == CredentialStoreFile && os.Getenv(CredentialPassphraseEnv) != "":
+	default:
+		return
+	}
+
+	contextFile := s.contextFilePath()
+	release, err := lockFile(contextFile)
+	if err != nil {
+		return
+	}
+	defer release()
+	var ctx CLIContext
+	if err := readJSONFile(contextFile, &ctx, s.profileRecoveryHint()); err != nil {
+		return
+	}
+	if ctx.CredentialStore != "" || ctx.Token == "" {
+		return
+	}
+	if err := s.storeToken(&ctx, ctx.Token, ctx.RefreshToken); err != nil {
+		return
+	}
+	data, err := json.MarshalIndent(ctx, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := writeFileAtomic(contextFile, data, 0600); err == nil {
+		s.invalidateCache()
+	}
+}
+
 // --- Profile-level state (rootContext, ~/.satusky/context.json) ---
 
 // rootContext is the only thing stored in ~/.satusky/context.json — it
@@ -194,33 +494,105 @@ func (s *Store) ActiveProfileName() string {
 	if s.profileOverride != "" {
 		return s.profileOverride
 	}
-	data, err := os.ReadFile(filepath.Join(s.configDir, "context.json")) // #nosec G304
+	path := filepath.Join(s.configDir, "context.json")
+	data, err := os.ReadFile(path) // #nosec G304
 	if err != nil {
 		return ""
 	}
 	var root rootContext
 	if err := json.Unmarshal(data, &root); err != nil {
-		return ""
+		root = rootContext{}
+		s.setLoadErr(recoverJSONFile(path, &root, "Select a profile again with '1ctl profile use <name>'."))
 	}
 	return root.ActiveProfile
 }
 
-// SetActiveProfileName writes the active profile name to context.json.
-// Invalidates the cache so subsequent reads see the new profile.
+// SetActiveProfileName writes the active profile name to context.json
+// under its file lock. Invalidates the cache so subsequent reads see the
+// new profile.
 func (s *Store) SetActiveProfileName(name string) error {
 	defer s.invalidateCache()
+	path := filepath.Join(s.configDir, "context.json")
+	release, err := lockFile(path)
+	if err != nil {
+		return err
+	}
+	defer release()
 	root := rootContext{ActiveProfile: name}
 	data, err := json.MarshalIndent(root, "", "  ")
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(filepath.Join(s.configDir, "context.json"), data, 0600)
+	return writeFileAtomic(path, data, 0600)
 }
 
 // --- Per-field accessors (delegate to the cached CLIContext load) ---
 
-// GetToken returns the API token from the active profile.
-func (s *Store) GetToken() string { return s.load().Token }
+// GetToken returns the API token from the active profile, reading it from
+// the profile's credential store. A failure to read the store is reported
+// as a warning and treated as not logged in.
+func (s *Store) GetToken() string {
+	s.tokenMu.Lock()
+	if s.tokenValid {
+		defer s.tokenMu.Unlock()
+		return s.cachedToken
+	}
+	s.tokenMu.Unlock()
+
+	ctx := s.load()
+	token := ctx.Token
+	if store, b := s.readBackend(ctx); b != nil {
+		var err error
+		if token, err = b.Get(s.profileName()); err != nil {
+			utils.PrintWarning("Failed to read token from %s credential store: %s", store, err.Error())
+			token = ""
+		}
+	} else if ctx.CredentialStore == "" && token != "" {
+		s.migrateToken()
+	}
+
+	s.tokenMu.Lock()
+	s.cachedToken = token
+	s.tokenValid = true
+	s.tokenMu.Unlock()
+	return token
+}
+
+// GetRefreshToken returns the refresh token from a browser login, or "".
+func (s *Store) GetRefreshToken() string {
+	ctx := s.load()
+	if _, b := s.readBackend(ctx); b != nil {
+		refresh, err := b.Get(s.profileName() + refreshSuffix)
+		if err != nil {
+			return ""
+		}
+		return refresh
+	}
+	return ctx.RefreshToken
+}
+
+// CredentialStore returns the credential store holding the active
+// profile's token, or "" for a profile saved before stores existed. A
+// credential helper is shown with its program name.
+func (s *Store) CredentialStore() string {
+	ctx := s.load()
+	store, _ := s.readBackend(ctx)
+	return credentialStoreLabel(store, s.helperName(ctx))
+}
+
+func (s *Store) helperName(ctx *CLIContext) string {
+	if s.credentialHelper != "" {
+		return s.credentialHelper
+	}
+	return ctx.CredentialHelper
+}
+
+func credentialStoreLabel(store, helper string) string {
+	if store == CredentialStoreHelper {
+		return fmt.Sprintf("%s (%s)", store, helper)
+	}
+	return store
+}
 
 // SetToken persists the API token to the active profile.
 func (s *Store) SetToken(token string) error {
@@ -304,6 +676,20 @@ func (s *Store) SaveLoginState(token, userID, email, orgID, orgName, namespace s
 	})
 }
 
+// SaveOAuthLoginState is SaveLoginState for a browser login, which also
+// returns a refresh token.
+func (s *Store) SaveOAuthLoginState(token, refreshToken, userID, email, orgID, orgName, namespace string) error {
+	return s.save(func(ctx *CLIContext) {
+		ctx.Token = token
+		ctx.RefreshToken = refreshToken
+		ctx.UserID = userID
+		ctx.Email = email
+		ctx.CurrentOrgID = orgID
+		ctx.CurrentOrgName = orgName
+		ctx.CurrentNamespace = namespace
+	})
+}
+
 // ClearAuthState wipes every auth field in a single atomic write.
 func (s *Store) ClearAuthState() error {
 	return s.save(func(ctx *CLIContext) {
@@ -325,6 +711,29 @@ func (s *Store) SetAPIURL(apiURL string) error {
 	return s.save(func(ctx *CLIContext) { ctx.APIURL = apiURL })
 }
 
+// TLSFiles are the PEM files a profile uses to reach its API.
+type TLSFiles struct {
+	CAFile     string
+	ClientCert string
+	ClientKey  string
+}
+
+// GetTLSFiles returns the TLS files stored on the active profile.
+func (s *Store) GetTLSFiles() TLSFiles {
+	ctx := s.load()
+	return TLSFiles{CAFile: ctx.CAFile, ClientCert: ctx.ClientCert, ClientKey: ctx.ClientKey}
+}
+
+// SetTLSFiles persists the TLS files to the active profile; empty fields
+// clear them.
+func (s *Store) SetTLSFiles(files TLSFiles) error {
+	return s.save(func(ctx *CLIContext) {
+		ctx.CAFile = files.CAFile
+		ctx.ClientCert = files.ClientCert
+		ctx.ClientKey = files.ClientKey
+	})
+}
+
 // --- Profile management (ListProfiles, CreateProfile, UseProfile, DeleteProfile) ---
 
 // ProfileInfo holds displayable metadata for a profile.
@@ -334,6 +743,10 @@ type ProfileInfo struct {
 	Email    string
 	OrgName  string
 	IsActive bool
+	// CredentialStore is where the token lives: keyring, file, ephemeral,
+	// plaintext, "helper (<program>)", or "plaintext (legacy)" for a token
+	// saved by an older 1ctl that could not be migrated without a prompt.
+	CredentialStore string
 }
 
 // ListProfiles returns all profiles found under <configDir>/profiles/.
@@ -365,12 +778,17 @@ func (s *Store) ListProfiles() ([]ProfileInfo, error) {
 		if err := json.Unmarshal(data, &ctx); err != nil {
 			continue
 		}
+		store := credentialStoreLabel(ctx.CredentialStore, ctx.CredentialHelper)
+		if store == "" && ctx.Token != "" {
+			store = CredentialStorePlaintext + " (legacy)"
+		}
 		profiles = append(profiles, ProfileInfo{
-			Name:     name,
-			APIURL:   ctx.APIURL,
-			Email:    ctx.Email,
-			OrgName:  ctx.CurrentOrgName,
-			IsActive: name == activeName,
+			Name:            name,
+			APIURL:          ctx.APIURL,
+			Email:           ctx.Email,
+			OrgName:         ctx.CurrentOrgName,
+			IsActive:        name == activeName,
+			CredentialStore: store,
 		})
 	}
 	return profiles, nil
@@ -391,6 +809,11 @@ func (s *Store) CreateProfile(name, apiURL string) error {
 		return fmt.Errorf("failed to create profiles directory: %w", err)
 	}
 	profilePath := filepath.Join(profilesDir, name+".json")
+	release, err := lockFile(profilePath)
+	if err != nil {
+		return err
+	}
+	defer release()
 	if _, err := os.Stat(profilePath); err == nil {
 		return utils.NewError(fmt.Sprintf("profile '%s' already exists", name), nil)
 	}
@@ -399,7 +822,7 @@ func (s *Store) CreateProfile(name, apiURL string) error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(profilePath, data, 0600)
+	return writeFileAtomic(profilePath, data, 0600)
 }
 
 // UseProfile switches the active profile. Errors if the profile doesn't exist.
@@ -415,8 +838,8 @@ func (s *Store) UseProfile(name string) error {
 	return s.SetActiveProfileName(name)
 }
 
-// DeleteProfile removes the named profile file. Refuses to delete the
-// active profile.
+// DeleteProfile removes the named profile file and its stored token.
+// Refuses to delete the active profile.
 func (s *Store) DeleteProfile(name string) error {
 	name = sanitizeProfileName(name)
 	profilePath := filepath.Join(s.configDir, "profiles", name+".json")
@@ -429,37 +852,124 @@ func (s *Store) DeleteProfile(name string) error {
 			nil,
 		)
 	}
-	return os.Remove(profilePath)
+	var ctx CLIContext
+	if data, err := os.ReadFile(profilePath); err == nil { // #nosec G304
+		_ = json.Unmarshal(data, &ctx) //nolint:errcheck
+	}
+	if b := s.storedBackend(&ctx); b != nil {
+		if err := b.Delete(name); err != nil {
+			return fmt.Errorf("failed to delete stored token: %w", err)
+		}
+		_ = b.Delete(name + refreshSuffix) //nolint:errcheck
+	}
+	if err := os.Remove(profilePath); err != nil {
+		return err
+	}
+	_ = os.Remove(profilePath + ".lock") //nolint:errcheck
+	return nil
 }
 
-// CheckTokenExpiry parses the JWT exp claim from the stored token. Returns
-// a clear error if expired; returns nil for non-JWT tokens (the backend
-// is the authority on those).
+// refreshWindow is how long before expiry a token is refreshed, so it
+// cannot expire between the check and the request.
+const refreshWindow = time.Minute
+
+// TokenRefresher exchanges a refresh token for a new access token and
+// refresh token. The api package provides it, since this package cannot
+// make HTTP requests without an import cycle.
+type TokenRefresher func(refreshToken string) (accessToken, newRefreshToken string, err error)
+
+var (
+	refresherMu sync.RWMutex
+	refresher   TokenRefresher
+)
+
+// SetTokenRefresher installs the function every Store uses to refresh
+// browser logins.
+func SetTokenRefresher(fn TokenRefresher) {
+	refresherMu.Lock()
+	refresher = fn
+	refresherMu.Unlock()
+}
+
+// EnsureFreshToken refreshes the access token when it is a JWT that expires
+// within refreshWindow and a refresh token is available. Tokens without an
+// expiry or without a refresh token are left alone. Parallel processes
+// serialise on a lock and re-read the token, so only one of them refreshes.
+func (s *Store) EnsureFreshToken() error {
+	refresherMu.RLock()
+	refresh := refresher
+	refresherMu.RUnlock()
+	if refresh == nil || !tokenExpiresWithin(s.GetToken(), refreshWindow) || s.GetRefreshToken() == "" {
+		return nil
+	}
+
+	release, err := lockFile(s.contextFilePath() + refreshSuffix)
+	if err != nil {
+		return err
+	}
+	defer release()
+	s.invalidateCache()
+	refreshToken := s.GetRefreshToken()
+	if !tokenExpiresWithin(s.GetToken(), refreshWindow) || refreshToken == "" {
+		return nil
+	}
+
+	access, newRefresh, err := refresh(refreshToken)
+	if err != nil {
+		return utils.NewError(fmt.Sprintf("failed to refresh session: %s. Please run '1ctl auth login --web' to sign in again", err.Error()), nil)
+	}
+	if newRefresh == "" {
+		newRefresh = refreshToken
+	}
+	return s.save(func(ctx *CLIContext) {
+		ctx.Token = access
+		ctx.RefreshToken = newRefresh
+	})
+}
+
+// CheckTokenExpiry parses the JWT exp claim from the stored token, first
+// refreshing it if possible. Returns a clear error if expired; returns nil
+// for non-JWT tokens (the backend is the authority on those).
 func (s *Store) CheckTokenExpiry() error {
+	refreshErr := s.EnsureFreshToken()
 	token := s.GetToken()
 	if token == "" {
-		return utils.NewError("not authenticated. Please run '1ctl auth login' to authenticate", nil)
+		return utils.NewKindError(utils.ErrAuth, "not authenticated. Please run '1ctl auth login' to authenticate", nil)
 	}
 
+	expiry, ok := tokenExpiry(token)
+	if ok && time.Now().After(expiry) {
+		if refreshErr != nil {
+			return refreshErr
+		}
+		return utils.NewKindError(utils.ErrAuth, fmt.Sprintf("session expired at %s. Please run '1ctl auth login' to re-authenticate", expiry.Format("Jan 2, 2006 15:04 MST")), nil)
+	}
+	return nil
+}
+
+// tokenExpiry returns the exp claim of a JWT. ok is false for tokens that
+// are not JWTs or carry no expiry.
+func tokenExpiry(token string) (expiry time.Time, ok bool) {
 	parts := strings.Split(token, ".")
 	if len(parts) != 3 {
-		return nil
+		return time.Time{}, false
 	}
 	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
 	if err != nil {
-		return nil
+		return time.Time{}, false
 	}
 	var claims struct {
 		Exp float64 `json:"exp"`
 	}
 	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
-		return nil
-	}
-	expiry := time.Unix(int64(claims.Exp), 0)
-	if time.Now().After(expiry) {
-		return utils.NewError(fmt.Sprintf("session expired at %s. Please run '1ctl auth login' to re-authenticate", expiry.Format("Jan 2, 2006 15:04 MST")), nil)
+		return time.Time{}, false
 	}
-	return nil
+	return time.Unix(int64(claims.Exp), 0), true
+}
+
+func tokenExpiresWithin(token string, d time.Duration) bool {
+	expiry, ok := tokenExpiry(token)
+	return ok && time.Until(expiry) < d
 }
 
 // --- Package-level default + delegation ---
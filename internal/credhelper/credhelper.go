@@ -0,0 +1,142 @@
+// Package credhelper implements the protocol 1ctl uses to ask an external
+// program for API tokens instead of storing them itself, in the manner of
+// Docker credential helpers.
+//
+// 1ctl runs the helper with a single argument naming the action — get,
+// store or erase — and writes one JSON Request to its stdin:
+//
+//	{"server_url": "https://api.satusky.com/v1/cli", "profile": "prod", "kind": "access"}
+//
+// store adds "token". get answers with a JSON Response on stdout:
+//
+//	{"token": "..."}
+//
+// An empty or missing token means none is stored. store and erase need not
+// print anything; helpers that only hand out tokens should accept them and
+// do nothing. Any failure is reported by exiting non-zero with a message on
+// stderr.
+package credhelper
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Actions a helper is invoked with.
+const (
+	ActionGet   = "get"
+	ActionStore = "store"
+	ActionErase = "erase"
+)
+
+// Token kinds. A refresh token from "auth login --web" is stored next to
+// the access token it belongs to.
+const (
+	KindAccess  = "access"
+	KindRefresh = "refresh"
+)
+
+// Timeout bounds a helper call. It is generous so a helper can wait for
+// the user to unlock a vault.
+var Timeout = 2 * time.Minute
+
+// waitDelay bounds how long Run waits for the helper's output once it has
+// exited or been killed; tests shorten it.
+var waitDelay = 5 * time.Second
+
+// Request is written to the helper's stdin.
+type Request struct {
+	ServerURL string `json:"server_url,omitempty"`
+	Profile   string `json:"profile"`
+	Kind      string `json:"kind"`
+	Token     string `json:"token,omitempty"`
+}
+
+// Response is read from the helper's stdout for get.
+type Response struct {
+	Token string `json:"token"`
+}
+
+// Run invokes program with action and req and returns the token it
+// answers with, which is only meaningful for get.
+func Run(program, action string, req Request) (string, error) {
+	if req.Kind == "" {
+		req.Kind = KindAccess
+	}
+	input, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, program, action) // #nosec G204 -- the helper is configured by the user
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// A process the helper started can keep its output pipes open after
+	// the helper is killed; stop waiting for them shortly after.
+	cmd.WaitDelay = waitDelay
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", fmt.Errorf("credential helper %s %s timed out after %s", program, action, Timeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("credential helper %s %s: %s", program, action, msg)
+		}
+		return "", fmt.Errorf("credential helper %s %s: %w", program, action, err)
+	}
+
+	if action != ActionGet || len(bytes.TrimSpace(stdout.Bytes())) == 0 {
+		return "", nil
+	}
+	var resp Response
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		return "", fmt.Errorf("credential helper %s returned invalid JSON: %w", program, err)
+	}
+	return resp.Token, nil
+}
+
+// Helper is implemented by credential helper programs and driven by Serve.
+type Helper interface {
+	Get(req Request) (string, error)
+	Store(req Request) error
+	Erase(req Request) error
+}
+
+// Serve runs one helper action: it reads the Request from in, calls h and
+// writes the Response to out. Helper programs call it from main with
+// os.Args[1:], os.Stdin and os.Stdout and exit non-zero on error.
+func Serve(args []string, in io.Reader, out io.Writer, h Helper) error {
+	if len(args) != 1 {
+		return errors.New("usage: <helper> get|store|erase < request.json")
+	}
+	var req Request
+	if err := json.NewDecoder(in).Decode(&req); err != nil {
+		return fmt.Errorf("invalid request: %w", err)
+	}
+	if req.Kind == "" {
+		req.Kind = KindAccess
+	}
+	switch args[0] {
+	case ActionGet:
+		token, err := h.Get(req)
+		if err != nil {
+			return err
+		}
+		return json.NewEncoder(out).Encode(Response{Token: token})
+	case ActionStore:
+		return h.Store(req)
+	case ActionErase:
+		return h.Erase(req)
+	}
+	return fmt.Errorf("unknown action %q", args[0])
+}
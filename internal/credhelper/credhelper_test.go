@@ -0,0 +1,72 @@
+package credhelper
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+type mapHelper map[string]string
+
+func (m mapHelper) Get(req Request) (string, error) { return m[req.Profile+"/"+req.Kind], nil }
+func (m mapHelper) Store(req Request) error         { m[req.Profile+"/"+req.Kind] = req.Token; return nil }
+func (m mapHelper) Erase(req Request) error         { delete(m, req.Profile+"/"+req.Kind); return nil }
+
+func TestServe(t *testing.T) {
+	h := mapHelper{}
+	serve := func(action, input string) string {
+		t.Helper()
+		var out bytes.Buffer
+		if err := Serve([]string{action}, strings.NewReader(input), &out, h); err != nil {
+			t.Fatalf("Serve(%s) error = %v", action, err)
+		}
+		return strings.TrimSpace(out.String())
+	}
+
+	serve(ActionStore, `{"profile":"prod","token":"tok"}`)
+	if h["prod/access"] != "tok" {
+		t.Errorf("store without kind = %v, want an access token", h)
+	}
+	if got := serve(ActionGet, `{"profile":"prod","kind":"access"}`); got != `{"token":"tok"}` {
+		t.Errorf("get = %s", got)
+	}
+	if got := serve(ActionGet, `{"profile":"prod","kind":"refresh"}`); got != `{"token":""}` {
+		t.Errorf("get refresh = %s, want empty token", got)
+	}
+	serve(ActionErase, `{"profile":"prod","kind":"access"}`)
+	if len(h) != 0 {
+		t.Errorf("erase left %v", h)
+	}
+
+	if err := Serve([]string{"list"}, strings.NewReader(`{}`), &bytes.Buffer{}, h); err == nil {
+		t.Error("Serve(list) should fail")
+	}
+}
+
+func TestRunTimesOutWhenTheHelperLeavesAChildBehind(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script helper")
+	}
+	helper := filepath.Join(t.TempDir(), "helper")
+	// The background sleep keeps the output pipes open after the helper
+	// itself is killed.
+	if err := os.WriteFile(helper, []byte("#!/bin/sh\nsleep 30 &\nsleep 30\n"), 0700); err != nil { // #nosec G306 -- test helper
+		t.Fatal(err)
+	}
+	oldTimeout, oldDelay := Timeout, waitDelay
+	Timeout, waitDelay = 100*time.Millisecond, 100*time.Millisecond
+	t.Cleanup(func() { Timeout, waitDelay = oldTimeout, oldDelay })
+
+	start := time.Now()
+	_, err := Run(helper, ActionGet, Request{Profile: "prod"})
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Run() error = %v, want a timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("Run() took %s after the timeout", elapsed)
+	}
+}
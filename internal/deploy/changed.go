@@ -0,0 +1,93 @@
+package deploy
+
+import (
+	"fmt"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"1ctl/internal/docker"
+)
+
+// ChangedFiles returns the files, relative to repoRoot and slash-separated,
+// that differ from the merge base of ref and HEAD. Uncommitted and untracked
+// files are included so a local run matches what CI would see after commit.
+func ChangedFiles(repoRoot, ref string) ([]string, error) {
+	base, err := git(repoRoot, "merge-base", ref, "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find merge base with %s: %w", ref, err)
+	}
+	diff, err := git(repoRoot, "diff", "--name-only", "--no-renames", strings.TrimSpace(base))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list changed files: %w", err)
+	}
+	untracked, err := git(repoRoot, "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list untracked files: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var files []string
+	for _, line := range strings.Split(diff+"\n"+untracked, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !seen[line] {
+			seen[line] = true
+			files = append(files, line)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// AffectedConfigs returns the satusky.toml paths (absolute, as passed in)
+// whose service is affected by changed. A service's build context is the
+// directory holding its satusky.toml; it is affected when a changed file is
+// inside that context and not excluded by its .dockerignore, or when the
+// satusky.toml or .dockerignore itself changed. Nested contexts are each
+// checked, so a root-level service sees changes in its subdirectories
+// unless it ignores them.
+func AffectedConfigs(repoRoot string, configPaths, changed []string) ([]string, error) {
+	var affected []string
+	for _, configPath := range configPaths {
+		contextDir := filepath.Dir(configPath)
+		rel, err := filepath.Rel(repoRoot, contextDir)
+		if err != nil {
+			return nil, err
+		}
+		prefix := filepath.ToSlash(rel) + "/"
+		if prefix == "./" {
+			prefix = ""
+		}
+
+		patterns, err := docker.LoadIgnorePatterns(contextDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read .dockerignore in %s: %w", contextDir, err)
+		}
+
+		for _, file := range changed {
+			if !strings.HasPrefix(file, prefix) {
+				continue
+			}
+			inContext := strings.TrimPrefix(file, prefix)
+			if inContext == path.Base(filepath.ToSlash(configPath)) || inContext == ".dockerignore" || !docker.IsIgnored(inContext, patterns) {
+				affected = append(affected, configPath)
+				break
+			}
+		}
+	}
+	return affected, nil
+}
+
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...) // #nosec G204 -- fixed git subcommands
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("%s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", err
+	}
+	return string(out), nil
+}
@@ -0,0 +1,56 @@
+package deploy
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestAffectedConfigs(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"satusky.toml":      "",
+		".dockerignore":     "services\ndocs\n",
+		"api/satusky.toml":  "",
+		"api/.dockerignore": "*.md\n",
+		"web/satusky.toml":  "",
+	}
+	for rel, contents := range files {
+		path := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rootCfg := filepath.Join(root, "satusky.toml")
+	apiCfg := filepath.Join(root, "api", "satusky.toml")
+	webCfg := filepath.Join(root, "web", "satusky.toml")
+	configs := []string{rootCfg, apiCfg, webCfg}
+
+	tests := []struct {
+		name    string
+		changed []string
+		want    []string
+	}{
+		{name: "source file in one service", changed: []string{"web/src/app.ts"}, want: []string{rootCfg, webCfg}},
+		{name: "ignored by service dockerignore", changed: []string{"api/README.md"}, want: []string{rootCfg}},
+		{name: "ignored by root dockerignore", changed: []string{"docs/guide.md"}, want: nil},
+		{name: "config change always deploys", changed: []string{"api/satusky.toml"}, want: []string{rootCfg, apiCfg}},
+		{name: "dockerignore change always deploys", changed: []string{".dockerignore"}, want: []string{rootCfg}},
+		{name: "sibling prefix is not a match", changed: []string{"api-docs/x.go"}, want: []string{rootCfg}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := AffectedConfigs(root, configs, tt.changed)
+			if err != nil {
+				t.Fatalf("AffectedConfigs: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AffectedConfigs = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,336 @@
+package deploy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"1ctl/internal/api"
+	"1ctl/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+// Resource kinds reported by Compare.
+const (
+	ResourceDeployment = "deployment"
+	ResourceIngress    = "ingress"
+	ResourceEnv        = "env"
+	ResourceVolume     = "volume"
+)
+
+// notSet is the display value used when one side of a comparison has no value.
+const notSet = "(not set)"
+
+// Change is a single field that differs between the desired state (satusky.toml
+// plus flags) and what is live on the platform. It is the one notion of
+// "differs" shared by drift detection and any plan/diff output.
+type Change struct {
+	Resource string `json:"resource"`
+	Field    string `json:"field"`
+	Desired  string `json:"desired"`
+	Live     string `json:"live"`
+}
+
+// DesiredState is what a deploy with the given options would produce.
+// Build it with DesiredStateFromOptions so it goes through the same
+// construction path as Deploy.
+type DesiredState struct {
+	Deployment api.Deployment
+	Domain     string
+	Env        map[string]string
+	Volume     *api.Volume
+}
+
+// LiveState is what is currently running for a deployment. Nil/empty fields
+// mean the resource does not exist on the platform.
+type LiveState struct {
+	Deployment *api.Deployment
+	Ingress    *api.Ingress
+	Env        []api.Environment
+	Volumes    []api.VolumeLifecycleStatus
+}
+
+// DesiredStateFromOptions builds the desired state for opts using the same
+// deployment payload construction as Deploy. The image is taken as given:
+// it is produced by the build and is never declared in the repo.
+func DesiredStateFromOptions(opts DeploymentOptions, image string) (DesiredState, error) {
+	deployment, err := buildDeployment(opts, image, opts.Name, "", opts.Organization, opts.Hostnames)
+	if err != nil {
+		return DesiredState{}, err
+	}
+	desired := DesiredState{
+		Deployment: deployment,
+		Domain:     opts.Domain,
+		Volume:     opts.Volume,
+	}
+	if opts.EnvEnabled && opts.Environment != nil {
+		desired.Env = make(map[string]string, len(opts.Environment.KeyValues))
+		for _, kv := range opts.Environment.KeyValues {
+			desired.Env[kv.Key] = kv.Value
+		}
+	}
+	return desired, nil
+}
+
+// FetchLiveState loads the deployment and its ingress, environment and
+// volumes. A missing ingress, env bundle or volume is a legitimate live
+// state; any other failed read is returned, so it is never mistaken for a
+// resource that was removed.
+func FetchLiveState(client api.API, deploymentID string) (*LiveState, error) {
+	deployment, err := client.GetDeployment(deploymentID)
+	if err != nil {
+		return nil, err
+	}
+	live := &LiveState{Deployment: deployment}
+	ing, err := client.GetIngressByDeploymentID(deploymentID)
+	if err != nil && !isNotFound(err) {
+		return nil, utils.NewError("failed to read ingress", err)
+	}
+	if ing != nil && ing.IngressID != uuid.Nil {
+		live.Ingress = ing
+	}
+	if live.Env, err = client.GetEnvironmentsByDeploymentID(deploymentID); err != nil && !isNotFound(err) {
+		return nil, utils.NewError("failed to read environment", err)
+	}
+	if live.Volumes, err = client.GetDeploymentVolumeLifecycleStatuses(deploymentID); err != nil && !isNotFound(err) {
+		return nil, utils.NewError("failed to read volumes", err)
+	}
+	return live, nil
+}
+
+func isNotFound(err error) bool {
+	return utils.KindOf(err) == utils.ErrNotFound
+}
+
+// Compare returns every field that differs between desired and live, ordered
+// by resource then field. An empty result means the two are in sync.
+//
+// Fields the repo does not own are ignored: the image (produced by the build),
+// the zone and hostnames when the repo leaves placement to the platform, the
+// replica count while an HPA manages it, and the domain when it is
+// auto-assigned.
+func Compare(desired DesiredState, live LiveState) []Change {
+	var changes []Change
+	add := func(resource, field, want, got string) {
+		if want != got {
+			changes = append(changes, Change{Resource: resource, Field: field, Desired: orNotSet(want), Live: orNotSet(got)})
+		}
+	}
+
+	if live.Deployment == nil {
+		return []Change{{Resource: ResourceDeployment, Field: "deployment", Desired: "present", Live: "missing"}}
+	}
+	d, l := desired.Deployment, *live.Deployment
+
+	add(ResourceDeployment, "port", fmt.Sprintf("%d", d.Port), fmt.Sprintf("%d", l.Port))
+	add(ResourceDeployment, "cpu_request", d.CpuRequest, l.CpuRequest)
+	add(ResourceDeployment, "cpu_limit", d.CPULimit, l.CPULimit)
+	add(ResourceDeployment, "memory_request", d.MemoryRequest, l.MemoryRequest)
+	add(ResourceDeployment, "memory_limit", d.MemoryLimit, l.MemoryLimit)
+	if !hpaEnabled(d.HPAConfig) && !hpaEnabled(l.HPAConfig) {
+		add(ResourceDeployment, "replicas", fmt.Sprintf("%d", d.Replicas), fmt.Sprintf("%d", l.Replicas))
+	}
+	if d.Zone != "" {
+		add(ResourceDeployment, "zone", d.Zone, l.Zone)
+	}
+	if len(d.Hostnames) > 0 {
+		add(ResourceDeployment, "hostnames", sortedJoin(d.Hostnames), sortedJoin(l.Hostnames))
+	}
+	add(ResourceDeployment, "strategy", StrategyText(d.StrategyConfig), StrategyText(l.StrategyConfig))
+	add(ResourceDeployment, "hpa", hpaText(d.HPAConfig), hpaText(l.HPAConfig))
+	add(ResourceDeployment, "vpa", vpaText(d.VPAConfig), vpaText(l.VPAConfig))
+	add(ResourceDeployment, "pdb", pdbText(d.PDBConfig), pdbText(l.PDBConfig))
+	add(ResourceDeployment, "multicluster", multiclusterText(d.MulticlusterConfig), multiclusterText(l.MulticlusterConfig))
+	add(ResourceDeployment, "wait_for", waitForText(d.WaitFor), waitForText(l.WaitFor))
+
+	if live.Ingress != nil {
+		if desired.Domain != "" {
+			add(ResourceIngress, "domain", desired.Domain, live.Ingress.DomainName)
+		}
+		add(ResourceIngress, "port", fmt.Sprintf("%d", d.Port), fmt.Sprintf("%d", live.Ingress.Port))
+	} else if desired.Domain != "" {
+		add(ResourceIngress, "domain", desired.Domain, "")
+	}
+
+	changes = append(changes, compareEnv(desired.Env, live.Env)...)
+	changes = append(changes, compareVolume(desired.Volume, live.Volumes)...)
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Resource != changes[j].Resource {
+			return resourceOrder(changes[i].Resource) < resourceOrder(changes[j].Resource)
+		}
+		return changes[i].Field < changes[j].Field
+	})
+	return changes
+}
+
+// compareEnv reports keys added, changed or removed outside the repo. Keys
+// are compared against the first environment bundle, which is the one
+// deploy and "1ctl env" write to.
+func compareEnv(desired map[string]string, live []api.Environment) []Change {
+	liveKV := map[string]string{}
+	if len(live) > 0 {
+		for _, kv := range live[0].KeyValues {
+			liveKV[kv.Key] = kv.Value
+		}
+	}
+	keys := make(map[string]bool, len(desired)+len(liveKV))
+	for k := range desired {
+		keys[k] = true
+	}
+	for k := range liveKV {
+		keys[k] = true
+	}
+
+	var changes []Change
+	for _, k := range sortedKeys(keys) {
+		want, inRepo := desired[k]
+		got, inLive := liveKV[k]
+		switch {
+		case inRepo && !inLive:
+			changes = append(changes, Change{Resource: ResourceEnv, Field: k, Desired: want, Live: notSet})
+		case !inRepo && inLive:
+			changes = append(changes, Change{Resource: ResourceEnv, Field: k, Desired: notSet, Live: got})
+		case want != got:
+			changes = append(changes, Change{Resource: ResourceEnv, Field: k, Desired: want, Live: got})
+		}
+	}
+	return changes
+}
+
+// compareVolume checks the attached volume against the [volume] section.
+// Detached volumes are ignored: they no longer affect the running app.
+func compareVolume(desired *api.Volume, live []api.VolumeLifecycleStatus) []Change {
+	var attached *api.Volume
+	for i := range live {
+		if live[i].Volume.DesiredAttached {
+			attached = &live[i].Volume
+			break
+		}
+	}
+	switch {
+	case desired == nil && attached == nil:
+		return nil
+	case desired == nil:
+		return []Change{{Resource: ResourceVolume, Field: "volume", Desired: notSet, Live: volumeText(attached)}}
+	case attached == nil:
+		return []Change{{Resource: ResourceVolume, Field: "volume", Desired: volumeText(desired), Live: notSet}}
+	}
+
+	var changes []Change
+	if desired.StorageSize != attached.StorageSize {
+		changes = append(changes, Change{Resource: ResourceVolume, Field: "size", Desired: desired.StorageSize, Live: attached.StorageSize})
+	}
+	if desired.MountPath != attached.MountPath {
+		changes = append(changes, Change{Resource: ResourceVolume, Field: "mount", Desired: desired.MountPath, Live: attached.MountPath})
+	}
+	return changes
+}
+
+// StrategyText renders a rollout strategy for display and comparison. A nil
+// config is the platform default, so it renders the same as an explicit
+// rolling 25%/25% strategy.
+func StrategyText(strategy *api.DeploymentStrategyConfig) string {
+	if strategy == nil {
+		return "rolling (maxSurge=25%, maxUnavailable=25%)"
+	}
+	if strategy.Rolling == nil {
+		return string(strategy.Type)
+	}
+	return fmt.Sprintf("%s (maxSurge=%s, maxUnavailable=%s)", strategy.Type, strategy.Rolling.MaxSurge, strategy.Rolling.MaxUnavailable)
+}
+
+func hpaEnabled(hpa *api.HPAConfig) bool {
+	return hpa != nil && hpa.Enabled
+}
+
+func hpaText(hpa *api.HPAConfig) string {
+	if !hpaEnabled(hpa) {
+		return "disabled"
+	}
+	text := fmt.Sprintf("min=%d max=%d", hpa.MinReplicas, hpa.MaxReplicas)
+	if hpa.CPUTarget != nil {
+		text += fmt.Sprintf(" cpu=%d%%", *hpa.CPUTarget)
+	}
+	if hpa.MemoryTarget != nil {
+		text += fmt.Sprintf(" memory=%d%%", *hpa.MemoryTarget)
+	}
+	return text
+}
+
+func vpaText(vpa *api.VPAConfig) string {
+	if vpa == nil || !vpa.Enabled {
+		return "disabled"
+	}
+	return fmt.Sprintf("mode=%s cpu=%s..%s memory=%s..%s", vpa.UpdateMode, vpa.MinCPU, vpa.MaxCPU, vpa.MinMemory, vpa.MaxMemory)
+}
+
+func pdbText(pdb *api.PDBConfig) string {
+	if pdb == nil || !pdb.Enabled {
+		return "disabled"
+	}
+	text := "type=" + pdb.Type
+	if pdb.MinAvailable != nil {
+		text += fmt.Sprintf(" min_available=%d", *pdb.MinAvailable)
+	}
+	if pdb.Percent != nil {
+		text += fmt.Sprintf(" percent=%d", *pdb.Percent)
+	}
+	return text
+}
+
+func multiclusterText(mc *api.MulticlusterConfig) string {
+	if mc == nil || !mc.Enabled {
+		return "disabled"
+	}
+	return "mode=" + mc.Mode
+}
+
+func waitForText(deps []api.WaitFor) string {
+	parts := make([]string, 0, len(deps))
+	for _, dep := range deps {
+		parts = append(parts, fmt.Sprintf("%s:%d", dep.Host, dep.Port))
+	}
+	return sortedJoin(parts)
+}
+
+func volumeText(v *api.Volume) string {
+	return fmt.Sprintf("%s at %s", v.StorageSize, v.MountPath)
+}
+
+func sortedJoin(values []string) string {
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ", ")
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func orNotSet(s string) string {
+	if s == "" {
+		return notSet
+	}
+	return s
+}
+
+func resourceOrder(resource string) int {
+	switch resource {
+	case ResourceDeployment:
+		return 0
+	case ResourceIngress:
+		return 1
+	case ResourceEnv:
+		return 2
+	case ResourceVolume:
+		return 3
+	}
+	return 4
+}
@@ -0,0 +1,82 @@
+package deploy
+
+import (
+	"testing"
+
+	"1ctl/internal/api"
+)
+
+func TestCompare(t *testing.T) {
+	base := func() api.Deployment {
+		return api.Deployment{Port: 8080, CpuRequest: "250m", CPULimit: "1", MemoryRequest: "256Mi", MemoryLimit: "256Mi", Replicas: 1}
+	}
+	tests := []struct {
+		name    string
+		desired DesiredState
+		live    LiveState
+		want    []Change
+	}{
+		{
+			name:    "in sync",
+			desired: DesiredState{Deployment: base()},
+			live:    LiveState{Deployment: ptr(base())},
+		},
+		{
+			name:    "scaled outside the repo",
+			desired: DesiredState{Deployment: base()},
+			live: LiveState{Deployment: func() *api.Deployment {
+				d := base()
+				d.Replicas = 3
+				return &d
+			}()},
+			want: []Change{{Resource: ResourceDeployment, Field: "replicas", Desired: "1", Live: "3"}},
+		},
+		{
+			name: "replicas ignored under HPA",
+			desired: DesiredState{Deployment: func() api.Deployment {
+				d := base()
+				d.HPAConfig = &api.HPAConfig{Enabled: true, MinReplicas: 1, MaxReplicas: 5}
+				return d
+			}()},
+			live: LiveState{Deployment: func() *api.Deployment {
+				d := base()
+				d.Replicas = 4
+				d.HPAConfig = &api.HPAConfig{Enabled: true, MinReplicas: 1, MaxReplicas: 5}
+				return &d
+			}()},
+		},
+		{
+			name:    "env added and removed",
+			desired: DesiredState{Deployment: base(), Env: map[string]string{"A": "1", "B": "2"}},
+			live: LiveState{
+				Deployment: ptr(base()),
+				Env:        []api.Environment{{KeyValues: []api.KeyValuePair{{Key: "A", Value: "1"}, {Key: "C", Value: "3"}}}},
+			},
+			want: []Change{
+				{Resource: ResourceEnv, Field: "B", Desired: "2", Live: notSet},
+				{Resource: ResourceEnv, Field: "C", Desired: notSet, Live: "3"},
+			},
+		},
+		{
+			name:    "missing deployment",
+			desired: DesiredState{Deployment: base()},
+			live:    LiveState{},
+			want:    []Change{{Resource: ResourceDeployment, Field: "deployment", Desired: "present", Live: "missing"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Compare(tt.desired, tt.live)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Compare() = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("change %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
@@ -5,11 +5,14 @@ import (
 	"1ctl/internal/cleanup"
 	"1ctl/internal/context"
 	"1ctl/internal/docker"
+	"1ctl/internal/envfile"
+	"1ctl/internal/envhistory"
 	"1ctl/internal/utils"
 	"1ctl/internal/validator"
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
@@ -21,10 +24,11 @@ type deploymentProgress struct {
 	message  string
 	resource string
 	done     bool
+	out      *utils.Printer
 }
 
 func (dp *deploymentProgress) print() {
-	utils.PrintLoadingStep(dp.step, dp.total, dp.message, dp.resource, dp.done)
+	dp.out.LoadingStep(dp.step, dp.total, dp.message, dp.resource, dp.done)
 }
 
 func (dp *deploymentProgress) complete() {
@@ -34,7 +38,8 @@ func (dp *deploymentProgress) complete() {
 
 // Deploy handles the sequential deployment process
 func Deploy(opts DeploymentOptions) (*api.CreateDeploymentResponse, error) {
-	progress := &deploymentProgress{total: 5}
+	out := opts.printer()
+	progress := &deploymentProgress{total: 5, out: out}
 	cmgr := cleanup.NewCleanupManager()
 
 	userID := context.GetUserID()
@@ -47,7 +52,7 @@ func Deploy(opts DeploymentOptions) (*api.CreateDeploymentResponse, error) {
 	// to managed cloud — issue #24 retires the implicit owner-machine
 	// auto-selection that bypassed quota enforcement and confused new users.
 	if len(opts.Hostnames) == 0 {
-		utils.PrintInfo("Deploying to managed cloud — backend will select the cheapest suitable machine.")
+		out.Info("Deploying to managed cloud — backend will select the cheapest suitable machine.")
 	}
 
 	var projectName string
@@ -59,7 +64,7 @@ func Deploy(opts DeploymentOptions) (*api.CreateDeploymentResponse, error) {
 		if err2 != nil {
 			return nil, utils.NewError("Failed to determine project name", err2)
 		}
-		utils.PrintInfo("App name: %s (auto-detected — use --name to override)", projectName)
+		out.Info("App name: %s (auto-detected — use --name to override)", projectName)
 	}
 
 	// K8s Services use DNS-1035: must start with a letter, only [a-z0-9-], end with alphanumeric.
@@ -74,7 +79,7 @@ func Deploy(opts DeploymentOptions) (*api.CreateDeploymentResponse, error) {
 	)
 	if opts.PrebuiltImage != "" {
 		image = opts.PrebuiltImage
-		utils.PrintInfo("Using pre-built image: %s", image)
+		out.Info("Using pre-built image: %s", image)
 	} else {
 		progress.step = 1
 		progress.message = "Building image (cloud)"
@@ -84,7 +89,7 @@ func Deploy(opts DeploymentOptions) (*api.CreateDeploymentResponse, error) {
 		progress.print()
 
 		var imageArch string
-		image, imageArch, err = submitRemoteBuild(opts.DockerfilePath, projectName, opts.FastBuild)
+		image, imageArch, err = submitRemoteBuild(opts, projectName)
 		if err != nil {
 			return nil, utils.NewError("Failed to build image", err)
 		}
@@ -115,8 +120,8 @@ func Deploy(opts DeploymentOptions) (*api.CreateDeploymentResponse, error) {
 
 	serviceID, err := upsertService(deploymentID, opts, projectName, opts.Organization)
 	if err != nil {
-		deployCleanup(cmgr)
-		return nil, utils.NewError(fmt.Sprintf("failed to create service: %s", err.Error()), nil)
+		deployCleanup(out, cmgr)
+		return nil, utils.NewError("failed to create service", err)
 	}
 	cmgr.AddResource(cleanup.ResourceService, serviceID, projectName)
 	progress.complete()
@@ -130,8 +135,8 @@ func Deploy(opts DeploymentOptions) (*api.CreateDeploymentResponse, error) {
 
 	envID, volumeName, err := handleEnvironmentAndVolumes(opts, deploymentID, projectName, opts.Organization)
 	if err != nil {
-		deployCleanup(cmgr)
-		return nil, utils.NewError(fmt.Sprintf("failed to setup environment and volumes: %s", err.Error()), nil)
+		deployCleanup(out, cmgr)
+		return nil, utils.NewError("failed to setup environment and volumes", err)
 	}
 	if envID != "" {
 		cmgr.AddResource(cleanup.ResourceEnv, envID, projectName)
@@ -150,8 +155,8 @@ func Deploy(opts DeploymentOptions) (*api.CreateDeploymentResponse, error) {
 
 	domainName, ingressID, err := handleIngressAndDependencies(opts, deploymentID, serviceID, userID, opts.Organization, projectName, opts.Hostnames)
 	if err != nil {
-		deployCleanup(cmgr)
-		return nil, utils.NewError(fmt.Sprintf("failed to configure ingress and dependencies: %s", err.Error()), nil)
+		deployCleanup(out, cmgr)
+		return nil, utils.NewError("failed to configure ingress and dependencies", err)
 	}
 	if ingressID != "" {
 		cmgr.AddResource(cleanup.ResourceIngress, ingressID, projectName)
@@ -167,29 +172,36 @@ func Deploy(opts DeploymentOptions) (*api.CreateDeploymentResponse, error) {
 }
 
 // deployCleanup runs best-effort cleanup on partial deployment failure.
-func deployCleanup(cmgr *cleanup.CleanupManager) {
-	utils.PrintWarning("Deployment failed, attempting cleanup of created resources...")
+func deployCleanup(out *utils.Printer, cmgr *cleanup.CleanupManager) {
+	out.Warning("Deployment failed, attempting cleanup of created resources...")
 	if errs := cmgr.Cleanup(); len(errs) > 0 {
-		utils.PrintWarning("Cleanup encountered errors:\n%s", cleanup.FormatCleanupErrors(errs))
+		out.Warning("Cleanup encountered errors:\n%s", cleanup.FormatCleanupErrors(errs))
 	} else {
-		utils.PrintSuccess("Successfully cleaned up partial deployment resources")
+		out.Success("Successfully cleaned up partial deployment resources")
 	}
 }
 
 // submitRemoteBuild packages the local build context, uploads it to the backend,
 // and waits for the cloud build to complete. No local Docker daemon is required.
 // Returns the image reference, image architecture, and any error.
-func submitRemoteBuild(dockerfilePath, projectName string, fastBuild bool) (imageRef, imageArch string, err error) {
+func submitRemoteBuild(opts DeploymentOptions, projectName string) (imageRef, imageArch string, err error) {
+	contextDir := opts.ContextDir
+	if contextDir == "" {
+		contextDir = "."
+	}
+	dockerfilePath, fastBuild := opts.DockerfilePath, opts.FastBuild
+	client, out := opts.client(), opts.printer()
+
 	// Validate that the Dockerfile exists and is well-formed before shipping anything.
-	if err = validator.ValidateDockerfile(dockerfilePath); err != nil {
-		return "", "", utils.NewError(fmt.Sprintf("invalid Dockerfile: %s", err.Error()), nil)
+	if err = validator.ValidateDockerfile(filepath.Join(contextDir, dockerfilePath)); err != nil {
+		return "", "", utils.NewError("invalid Dockerfile", err)
 	}
 
 	// Package the build context into a gzipped tar, respecting .dockerignore.
-	utils.PrintInfo("Packaging build context...")
-	contextPath, err := docker.PackageContext(".")
+	out.Info("Packaging build context...")
+	contextPath, err := docker.PackageContext(contextDir)
 	if err != nil {
-		return "", "", utils.NewError(fmt.Sprintf("failed to package build context: %s", err.Error()), nil)
+		return "", "", utils.NewError("failed to package build context", err)
 	}
 	defer func() { _ = os.Remove(contextPath) }() //nolint:errcheck
 
@@ -200,26 +212,26 @@ func submitRemoteBuild(dockerfilePath, projectName string, fastBuild bool) (imag
 
 	// Submit the context to the backend; it returns a build ID immediately.
 	if fastBuild {
-		utils.PrintInfo("Submitting fast build to cloud...")
+		out.Info("Submitting fast build to cloud...")
 	} else {
-		utils.PrintInfo("Submitting build to cloud...")
+		out.Info("Submitting build to cloud...")
 	}
-	buildID, err := api.SubmitBuild(contextPath, projectName, dockerfilePath, builder, nil)
+	buildID, err := client.SubmitBuild(contextPath, projectName, dockerfilePath, builder, nil)
 	if err != nil {
-		return "", "", utils.NewError(fmt.Sprintf("failed to submit build: %s", err.Error()), nil)
+		return "", "", utils.NewError("failed to submit build", err)
 	}
-	utils.PrintInfo("Build queued (ID: %s)", buildID)
+	out.Info("Build queued (ID: %s)", buildID)
 
 	// Poll until the cloud build finishes, streaming log output as it arrives.
 	// TODO: Should we be polling? is there a better way other than polling?
-	result, err := api.WaitForBuildResult(buildID, os.Stdout)
+	result, err := client.WaitForBuildResult(buildID, opts.output())
 	if err != nil {
 		return "", "", err
 	}
 
-	utils.PrintSuccess("Cloud build complete: %s", result.ImageRef)
+	out.Success("Cloud build complete: %s", result.ImageRef)
 	if result.ImageArch != "" {
-		utils.PrintInfo("Image architecture: %s", result.ImageArch)
+		out.Info("Image architecture: %s", result.ImageArch)
 	}
 	return result.ImageRef, result.ImageArch, nil
 }
@@ -244,9 +256,35 @@ func normalizeTargetArch(imageArch string) string {
 }
 
 func mainDeploy(opts DeploymentOptions, image, name, userID, organization string, hostnames []string) (string, error) {
+	deployment, err := buildDeployment(opts, image, name, userID, organization, hostnames)
+	if err != nil {
+		return "", err
+	}
+
+	var deploymentID string
+	if err := opts.client().UpsertDeployment(deployment, &deploymentID); err != nil {
+		// Check if this is a resource exhausted error and handle it specially
+		if resourceErr, ok := err.(*utils.ResourceExhaustedCLIError); ok {
+			// The full breakdown is a multi-line table; deploys writing to
+			// their own Output report the error in their summary instead.
+			if opts.Output == nil {
+				utils.PrintResourceExhaustedError(resourceErr.ResourceError)
+			}
+			return "", resourceErr
+		}
+		return "", utils.NewError("failed to upsert deployment", err)
+	}
+
+	return deploymentID, nil
+}
+
+// buildDeployment converts DeploymentOptions into the api.Deployment payload
+// sent to the upsert endpoint. Shared with DesiredStateFromOptions so drift
+// detection compares against exactly what a deploy would send.
+func buildDeployment(opts DeploymentOptions, image, name, userID, organization string, hostnames []string) (api.Deployment, error) {
 	port, err := api.SafeInt32(opts.Port)
 	if err != nil {
-		return "", utils.NewError(fmt.Sprintf("invalid port: %s", err.Error()), nil)
+		return api.Deployment{}, utils.NewError("invalid port", err)
 	}
 	cpuRequest := opts.CPURequest
 	if cpuRequest == "" {
@@ -265,12 +303,12 @@ func mainDeploy(opts DeploymentOptions, image, name, userID, organization string
 	if opts.Replicas > 0 {
 		replicas, err = api.SafeInt32(opts.Replicas)
 		if err != nil {
-			return "", utils.NewError(fmt.Sprintf("invalid replicas count: %s", err.Error()), nil)
+			return api.Deployment{}, utils.NewError("invalid replicas count", err)
 		}
 	} else {
 		replicas, err = api.SafeInt32(len(hostnames))
 		if err != nil {
-			return "", utils.NewError(fmt.Sprintf("invalid replicas count: %s", err.Error()), nil)
+			return api.Deployment{}, utils.NewError("invalid replicas count", err)
 		}
 	}
 
@@ -371,23 +409,47 @@ func mainDeploy(opts DeploymentOptions, image, name, userID, organization string
 	// Pass image architecture so the backend sets the kubernetes.io/arch nodeSelector.
 	deployment.TargetArch = opts.TargetArch
 
-	var deploymentID string
-	if err := api.UpsertDeployment(deployment, &deploymentID); err != nil {
-		// Check if this is a resource exhausted error and handle it specially
-		if resourceErr, ok := err.(*utils.ResourceExhaustedCLIError); ok {
-			utils.PrintResourceExhaustedError(resourceErr.ResourceError)
-			return "", resourceErr
-		}
-		return "", utils.NewError(fmt.Sprintf("failed to upsert deployment: %s", err.Error()), nil)
-	}
+	return deployment, nil
+}
 
-	return deploymentID, nil
+// UpdateRequest returns the upsert request that re-applies current's
+// settings after a caller has changed some of them. It carries only the
+// fields buildDeployment sends, leaving status, timestamps and the other
+// fields the API owns out of the request.
+func UpdateRequest(current api.Deployment) api.Deployment {
+	return api.Deployment{
+		UserID:             current.UserID,
+		Type:               current.Type,
+		Environment:        current.Environment,
+		Hostnames:          current.Hostnames,
+		CpuRequest:         current.CpuRequest,
+		CPULimit:           current.CPULimit,
+		MemoryRequest:      current.MemoryRequest,
+		MemoryLimit:        current.MemoryLimit,
+		Namespace:          current.Namespace,
+		Port:               current.Port,
+		Image:              current.Image,
+		Zone:               current.Zone,
+		SSD:                current.SSD,
+		GPU:                current.GPU,
+		AppLabel:           current.AppLabel,
+		Replicas:           current.Replicas,
+		EnvEnabled:         current.EnvEnabled,
+		VolumeEnabled:      current.VolumeEnabled,
+		MulticlusterConfig: current.MulticlusterConfig,
+		PDBConfig:          current.PDBConfig,
+		HPAConfig:          current.HPAConfig,
+		VPAConfig:          current.VPAConfig,
+		WaitFor:            current.WaitFor,
+		StrategyConfig:     current.StrategyConfig,
+		TargetArch:         current.TargetArch,
+	}
 }
 
 func upsertService(deploymentID string, opts DeploymentOptions, projectName, organization string) (string, error) {
 	port, err := api.SafeInt32(opts.Port)
 	if err != nil {
-		return "", utils.NewError(fmt.Sprintf("invalid port: %s", err.Error()), nil)
+		return "", utils.NewError("invalid port", err)
 	}
 
 	service := api.Service{
@@ -398,26 +460,27 @@ func upsertService(deploymentID string, opts DeploymentOptions, projectName, org
 	}
 
 	var serviceID string
-	if err := api.UpsertService(service, &serviceID); err != nil {
-		return "", utils.NewError(fmt.Sprintf("failed to upsert service: %s", err.Error()), nil)
+	if err := opts.client().UpsertService(service, &serviceID); err != nil {
+		return "", utils.NewError("failed to upsert service", err)
 	}
 
 	return serviceID, nil
 }
 
 func upsertIngress(deploymentID string, serviceID string, opts DeploymentOptions, organization, projectName string) (domainName, ingressID string, err error) {
+	client, out := opts.client(), opts.printer()
 	// Check if there's an existing ingress for this deployment
-	existingIngress, err := api.GetIngressByDeploymentID(deploymentID)
+	existingIngress, err := client.GetIngressByDeploymentID(deploymentID)
 	if err != nil {
-		utils.PrintInfo("No existing ingress found for deployment %s, will create new one: %s", deploymentID, err.Error())
+		out.Info("No existing ingress found for deployment %s, will create new one: %s", deploymentID, err.Error())
 
 		// Generate domain name if not provided and no existing ingress
 		if opts.Domain == "" {
-			domainName, err = api.GenerateDomainName(projectName)
+			domainName, err = client.GenerateDomainName(projectName)
 			if err != nil {
-				return "", "", utils.NewError(fmt.Sprintf("failed to generate domain name: %s", err.Error()), nil)
+				return "", "", utils.NewError("failed to generate domain name", err)
 			}
-			utils.PrintInfo("Generated new domain: %s", domainName)
+			out.Info("Generated new domain: %s", domainName)
 		} else {
 			domainName = opts.Domain
 		}
@@ -428,11 +491,11 @@ func upsertIngress(deploymentID string, serviceID string, opts DeploymentOptions
 		// pass --domain (or set domain in satusky.toml) to use a custom domain.
 		if opts.Domain == "" {
 			if !strings.HasSuffix(existingIngress.DomainName, ".satusky.com") {
-				domainName, err = api.GenerateDomainName(projectName)
+				domainName, err = client.GenerateDomainName(projectName)
 				if err != nil {
-					return "", "", utils.NewError(fmt.Sprintf("failed to generate domain name: %s", err.Error()), nil)
+					return "", "", utils.NewError("failed to generate domain name", err)
 				}
-				utils.PrintInfo("Generated new domain: %s", domainName)
+				out.Info("Generated new domain: %s", domainName)
 			} else {
 				domainName = existingIngress.DomainName
 			}
@@ -443,7 +506,7 @@ func upsertIngress(deploymentID string, serviceID string, opts DeploymentOptions
 
 	port, err := api.SafeInt32(opts.Port)
 	if err != nil {
-		return "", "", utils.NewError(fmt.Sprintf("invalid port: %s", err.Error()), nil)
+		return "", "", utils.NewError("invalid port", err)
 	}
 
 	ingress := api.Ingress{
@@ -456,17 +519,18 @@ func upsertIngress(deploymentID string, serviceID string, opts DeploymentOptions
 		Port:         port,
 	}
 
-	ingressResp, err := api.UpsertIngress(ingress)
+	ingressResp, err := client.UpsertIngress(ingress)
 	if err != nil {
-		return "", "", utils.NewError(fmt.Sprintf("failed to upsert ingress: %s", err.Error()), nil)
+		return "", "", utils.NewError("failed to upsert ingress", err)
 	}
 
 	return ingressResp.DomainName, ingressResp.IngressID.String(), nil
 }
 
-func handleDependencies(deps []api.Dependency, userID, organization string, hostnames []string) error {
+func handleDependencies(client api.API, deps []api.Dependency, userID, organization string, hostnames []string) error {
 	for _, dep := range deps {
 		opts := DeploymentOptions{
+			API:          client,
 			CPURequest:   "125m",  // TODO: change this when CPU is specified for each dependency
 			CPULimit:     "1000m", // TODO: change this when CPU is specified for each dependency
 			Memory:       "128Mi", // TODO: change this when memory is specified for each dependency
@@ -477,22 +541,22 @@ func handleDependencies(deps []api.Dependency, userID, organization string, host
 		// Create deployment for dependency
 		deploymentID, err := mainDeploy(opts, dep.Image, dep.Name, userID, organization, hostnames)
 		if err != nil {
-			return utils.NewError(fmt.Sprintf("failed to create dependency deployment: %s", err.Error()), nil)
+			return utils.NewError("failed to create dependency deployment", err)
 		}
 
 		// Create service for dependency
 		if dep.Service != nil {
 			dep.Service.DeploymentID = api.ToUUID(deploymentID)
-			if err := api.UpsertService(*dep.Service, nil); err != nil {
-				return utils.NewError(fmt.Sprintf("failed to upsert dependency service: %s", err.Error()), nil)
+			if err := client.UpsertService(*dep.Service, nil); err != nil {
+				return utils.NewError("failed to upsert dependency service", err)
 			}
 		}
 
 		// Create volume for dependency if specified
 		if dep.Volume != nil {
 			dep.Volume.DeploymentID = api.ToUUID(deploymentID)
-			if err := api.CreateVolume(*dep.Volume); err != nil {
-				return utils.NewError(fmt.Sprintf("failed to create dependency volume: %s", err.Error()), nil)
+			if err := client.CreateVolume(*dep.Volume); err != nil {
+				return utils.NewError("failed to create dependency volume", err)
 			}
 		}
 	}
@@ -514,6 +578,7 @@ func handleEnvironmentAndVolumes(opts DeploymentOptions, deploymentID, projectNa
 	}
 	envChan := make(chan envResult, 1)
 	volChan := make(chan volResult, 1)
+	client := opts.client()
 
 	go func() {
 		if opts.EnvEnabled && opts.Environment != nil {
@@ -521,11 +586,17 @@ func handleEnvironmentAndVolumes(opts DeploymentOptions, deploymentID, projectNa
 			opts.Environment.AppLabel = projectName
 			opts.Environment.Namespace = organization
 
-			created, e := api.UpsertEnvironment(*opts.Environment)
+			before, readErr := liveEnvironment(client, deploymentID)
+			created, e := client.UpsertEnvironment(*opts.Environment)
 			if e != nil {
 				envChan <- envResult{err: utils.NewError(fmt.Sprintf("failed to create environment: %s", e.Error()), nil)}
 				return
 			}
+			// Without the values it replaced, the journal would record a
+			// change from nothing; leave this deploy out of it instead.
+			if readErr == nil {
+				recordEnvHistory(deploymentID, before, opts.Environment.KeyValues)
+			}
 			envChan <- envResult{id: created.EnvironmentID.String()}
 			return
 		}
@@ -538,7 +609,7 @@ func handleEnvironmentAndVolumes(opts DeploymentOptions, deploymentID, projectNa
 			opts.Volume.VolumeName = fmt.Sprintf("%s-volume", projectName)
 			opts.Volume.ClaimName = fmt.Sprintf("%s-claim", projectName)
 			opts.Volume.DesiredAttached = true
-			if e := api.CreateVolume(*opts.Volume); e != nil {
+			if e := client.CreateVolume(*opts.Volume); e != nil {
 				volChan <- volResult{err: utils.NewError(fmt.Sprintf("failed to create volume: %s", e.Error()), nil)}
 				return
 			}
@@ -552,7 +623,7 @@ func handleEnvironmentAndVolumes(opts DeploymentOptions, deploymentID, projectNa
 			// creates the backing volume.
 			bound := false
 			for i := 0; i < 30; i++ {
-				statuses, sErr := api.GetDeploymentVolumeLifecycleStatuses(deploymentID)
+				statuses, sErr := client.GetDeploymentVolumeLifecycleStatuses(deploymentID)
 				if sErr == nil {
 					for _, s := range statuses {
 						if s.PVC.Exists && s.PVC.Phase == "Bound" {
@@ -573,7 +644,7 @@ func handleEnvironmentAndVolumes(opts DeploymentOptions, deploymentID, projectNa
 				time.Sleep(delay)
 			}
 			if !bound {
-				utils.PrintWarning("PVC %s is still provisioning after 60s — storage will be available shortly", opts.Volume.ClaimName)
+				opts.printer().Warning("PVC %s is still provisioning after 60s — storage will be available shortly", opts.Volume.ClaimName)
 			}
 			volChan <- volResult{name: opts.Volume.VolumeName}
 			return
@@ -643,7 +714,7 @@ func handleIngressAndDependencies(opts DeploymentOptions, deploymentID, serviceI
 
 	go func() {
 		if len(opts.Dependencies) > 0 {
-			if e := handleDependencies(opts.Dependencies, userID, organization, hostnames); e != nil {
+			if e := handleDependencies(opts.client(), opts.Dependencies, userID, organization, hostnames); e != nil {
 				depErrChan <- utils.NewError(fmt.Sprintf("failed to handle dependencies: %s", e.Error()), nil)
 				return
 			}
@@ -684,3 +755,28 @@ func validateAppName(name string) error {
 	}
 	return nil
 }
+
+// liveEnvironment returns the deployment's current environment values,
+// empty when it has none.
+func liveEnvironment(client api.EnvironmentsAPI, deploymentID string) (map[string]string, error) {
+	envs, err := client.GetEnvironmentsByDeploymentID(deploymentID)
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+	if len(envs) == 0 {
+		return map[string]string{}, nil
+	}
+	return envfile.ToMap(envs[0].KeyValues), nil
+}
+
+// recordEnvHistory journals the environment after deploy upserted set over
+// before, for "1ctl env history". It is best effort and never fails a deploy.
+func recordEnvHistory(deploymentID string, before map[string]string, set []api.KeyValuePair) {
+	journal, err := envhistory.Open(deploymentID)
+	if err != nil {
+		return
+	}
+	desired := envfile.ToMap(set)
+	after := envfile.Apply(before, envfile.Diff(before, desired, false), desired)
+	_, _ = journal.Track(before, after, "deploy") //nolint:errcheck
+}
@@ -1,11 +1,17 @@
 package deploy
 
 import (
+	"bytes"
+	"net/http"
+	"strings"
 	"testing"
 
 	"1ctl/internal/api"
+	"1ctl/internal/api/apitest"
 	"1ctl/internal/context"
-	"1ctl/internal/testutils"
+	"1ctl/internal/envhistory"
+
+	"github.com/google/uuid"
 )
 
 func TestBuildStrategyConfig(t *testing.T) {
@@ -105,107 +111,191 @@ func TestNormalizeTargetArch(t *testing.T) {
 	}
 }
 
-func TestDeploy(t *testing.T) {
-	// Skip this test in CI - it requires Docker daemon and actual API
-	// This is an integration test that should run with proper setup
-	t.Skip("Skipping integration test - requires Docker daemon and API")
-
-	// Setup test context
-	if err := context.SetToken("test-token"); err != nil {
-		t.Fatalf("failed to set token: %v", err)
-	}
-	if err := context.SetUserID("test-user"); err != nil {
-		t.Fatalf("failed to set user ID: %v", err)
-	}
-	if err := context.SetCurrentNamespace("test-org"); err != nil {
-		t.Fatalf("failed to set namespace: %v", err)
+// useTestProfile points the active profile at a temporary store with a
+// logged-in user, for code that still reads it.
+func useTestProfile(t *testing.T) {
+	t.Helper()
+	original := context.Default()
+	t.Cleanup(func() { context.SetDefault(original) })
+	store := context.NewTestStore(t.TempDir())
+	store.SetProfileOverride("test")
+	context.SetDefault(store)
+	if err := context.SetUserID(uuid.NewString()); err != nil {
+		t.Fatal(err)
 	}
+}
+
+func TestDeploy(t *testing.T) {
+	useTestProfile(t)
 
 	tests := []struct {
-		name    string
-		opts    DeploymentOptions
-		mockAPI *testutils.MockAPI
-		wantErr bool
+		name string
+		opts DeploymentOptions
+		fail string
+		// check inspects the fake after a successful deploy.
+		check func(t *testing.T, srv *apitest.Server, resp *api.CreateDeploymentResponse)
 	}{
 		{
 			name: "successful deployment",
-			opts: DeploymentOptions{
-				CPU:            "1",
-				Memory:         "512Mi",
-				Organization:   "test-project",
-				Port:           8080,
-				DockerfilePath: "Dockerfile",
+			opts: DeploymentOptions{Port: 8080},
+			check: func(t *testing.T, srv *apitest.Server, resp *api.CreateDeploymentResponse) {
+				d, ok := srv.Deployment(resp.DeploymentID.String())
+				if !ok || d.Image != "registry.example.com/web:1" || d.Namespace != "acme" {
+					t.Errorf("deployment = %+v, %v", d, ok)
+				}
+				if svcs := srv.Services(); len(svcs) != 1 || svcs[0].DeploymentID != resp.DeploymentID {
+					t.Errorf("services = %+v", svcs)
+				}
+				ings := srv.Ingresses()
+				if len(ings) != 1 || ings[0].IngressID != resp.IngressID || ings[0].DomainName != resp.Domain {
+					t.Errorf("ingresses = %+v, response = %+v", ings, resp)
+				}
+				if !strings.HasSuffix(resp.Domain, ".satusky.com") {
+					t.Errorf("domain = %q, want a generated one", resp.Domain)
+				}
 			},
-			mockAPI: testutils.DefaultMockAPI(),
-			wantErr: false,
 		},
 		{
 			name: "deployment with environment",
 			opts: DeploymentOptions{
-				CPU:            "1",
-				Memory:         "512Mi",
-				Organization:   "test-project",
-				Port:           8080,
-				DockerfilePath: "Dockerfile",
-				EnvEnabled:     true,
-				Environment: &api.Environment{
-					KeyValues: []api.KeyValuePair{
-						{Key: "TEST_KEY", Value: "test_value"},
-					},
-				},
+				Port:        8080,
+				EnvEnabled:  true,
+				Environment: &api.Environment{KeyValues: []api.KeyValuePair{{Key: "TEST_KEY", Value: "test_value"}}},
+			},
+			check: func(t *testing.T, srv *apitest.Server, resp *api.CreateDeploymentResponse) {
+				envs := srv.Environments()
+				if len(envs) != 1 || envs[0].DeploymentID != resp.DeploymentID || len(envs[0].KeyValues) != 1 || envs[0].KeyValues[0].Value != "test_value" {
+					t.Errorf("environments = %+v", envs)
+				}
 			},
-			mockAPI: testutils.DefaultMockAPI(),
-			wantErr: false,
 		},
 		{
 			name: "deployment with volume",
 			opts: DeploymentOptions{
-				CPU:            "1",
-				Memory:         "512Mi",
-				Organization:   "test-project",
-				Port:           8080,
-				DockerfilePath: "Dockerfile",
-				VolumeEnabled:  true,
-				Volume: &api.Volume{
-					StorageSize: "10Gi",
-					MountPath:   "/data",
-				},
+				Port:          8080,
+				VolumeEnabled: true,
+				Volume:        &api.Volume{StorageSize: "10Gi", MountPath: "/data"},
+			},
+			check: func(t *testing.T, srv *apitest.Server, resp *api.CreateDeploymentResponse) {
+				vols := srv.Volumes()
+				if len(vols) != 1 || vols[0].VolumeName != "web-volume" || vols[0].DeploymentID != resp.DeploymentID {
+					t.Errorf("volumes = %+v", vols)
+				}
 			},
-			mockAPI: testutils.DefaultMockAPI(),
-			wantErr: false,
 		},
 		{
 			name: "deployment error",
-			opts: DeploymentOptions{
-				CPU:            "1",
-				Memory:         "512Mi",
-				Organization:   "test-project",
-				Port:           8080,
-				DockerfilePath: "Dockerfile",
-			},
-			mockAPI: testutils.ErrorMockAPI(),
-			wantErr: true,
+			opts: DeploymentOptions{Port: 8080},
+			fail: "/deployments/upsert/acme/web",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// TODO: Replace actual API calls with mock
-			// This requires refactoring the deploy package to accept an API interface
+			srv := apitest.NewServer(t)
+			if tt.fail != "" {
+				srv.Fail(http.MethodPost, tt.fail, http.StatusInternalServerError, "database unavailable")
+			}
+			opts := tt.opts
+			opts.API = srv.Client()
+			opts.Name = "web"
+			opts.Organization = "acme"
+			opts.PrebuiltImage = "registry.example.com/web:1"
 
-			resp, err := Deploy(tt.opts)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("Deploy() error = %v, wantErr %v", err, tt.wantErr)
+			resp, err := Deploy(opts)
+			if tt.fail != "" {
+				if err == nil || !strings.Contains(err.Error(), "database unavailable") {
+					t.Errorf("Deploy() error = %v, want the API error", err)
+				}
+				if len(srv.Deployments()) != 0 {
+					t.Errorf("deployments = %+v after a failed deploy", srv.Deployments())
+				}
 				return
 			}
-
-			if !tt.wantErr && resp == nil {
-				t.Error("Deploy() returned nil response for successful deployment")
+			if err != nil {
+				t.Fatalf("Deploy() error = %v", err)
 			}
+			tt.check(t, srv, resp)
 		})
 	}
 }
 
+func TestDeployRedeployKeepsIDAndDomain(t *testing.T) {
+	useTestProfile(t)
+	srv := apitest.NewServer(t)
+	opts := DeploymentOptions{API: srv.Client(), Name: "web", Organization: "acme", Port: 8080, PrebuiltImage: "registry.example.com/web:1"}
+
+	first, err := Deploy(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts.PrebuiltImage = "registry.example.com/web:2"
+	second, err := Deploy(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if second.DeploymentID != first.DeploymentID || second.Domain != first.Domain {
+		t.Errorf("redeploy = %+v, want the deployment and domain of %+v", second, first)
+	}
+	versions, err := srv.Client().ListDeploymentVersions(first.DeploymentID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 2 || versions[0].Image != "registry.example.com/web:2" {
+		t.Errorf("versions = %+v, want two with the new image first", versions)
+	}
+}
+
+func TestDeployJournalsEnvironmentOnlyWhenItWasRead(t *testing.T) {
+	useTestProfile(t)
+	srv := apitest.NewServer(t)
+	opts := DeploymentOptions{
+		API: srv.Client(), Name: "web", Organization: "acme", Port: 8080, PrebuiltImage: "registry.example.com/web:1",
+		EnvEnabled:  true,
+		Environment: &api.Environment{KeyValues: []api.KeyValuePair{{Key: "MODE", Value: "one"}}},
+	}
+	resp, err := Deploy(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := resp.DeploymentID.String()
+
+	srv.Fail(http.MethodGet, "/environments/deploymentId/"+id, http.StatusInternalServerError, "database unavailable")
+	opts.Environment = &api.Environment{KeyValues: []api.KeyValuePair{{Key: "MODE", Value: "two"}}}
+	if _, err := Deploy(opts); err != nil {
+		t.Fatal(err)
+	}
+
+	journal, err := envhistory.Open(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshots, err := journal.Snapshots()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(snapshots); n == 0 || snapshots[n-1].Source != "deploy" || snapshots[n-1].Values["MODE"] != "one" {
+		t.Errorf("snapshots = %+v, want the first deploy's last", snapshots)
+	}
+}
+
+func TestDeployWritesProgressToOutput(t *testing.T) {
+	useTestProfile(t)
+	srv := apitest.NewServer(t)
+	var out bytes.Buffer
+	opts := DeploymentOptions{API: srv.Client(), Name: "web", Organization: "acme", Port: 8080, PrebuiltImage: "registry.example.com/web:1", Output: &out}
+
+	if _, err := Deploy(opts); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Using pre-built image", "Step 2/5", "Generated new domain"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output = %q, want %q", out.String(), want)
+		}
+	}
+}
+
 func TestSubmitRemoteBuild(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -223,7 +313,7 @@ func TestSubmitRemoteBuild(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, _, err := submitRemoteBuild(tt.dockerfilePath, tt.projectName, false)
+			_, _, err := submitRemoteBuild(DeploymentOptions{DockerfilePath: tt.dockerfilePath}, tt.projectName)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("submitRemoteBuild() error = %v, wantErr %v", err, tt.wantErr)
 			}
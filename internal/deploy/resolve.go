@@ -8,13 +8,14 @@ import (
 	"1ctl/internal/api"
 	"1ctl/internal/config"
 	"1ctl/internal/context"
+	"1ctl/internal/utils"
 )
 
 // ResolveDeploymentID returns the deployment ID to use for a command.
 // Precedence: explicit depID > appFlag > lookup by app name from config file.
 // Mirrors the original resolveDeploymentID in internal/commands/resolve.go
 // but exported so sub-package commands can use it without circular imports.
-func ResolveDeploymentID(depID, appFlag, configArg string) (string, error) {
+func ResolveDeploymentID(client api.DeploymentsAPI, depID, appFlag, configArg string) (string, error) {
 	if depID != "" {
 		return depID, nil
 	}
@@ -22,13 +23,9 @@ func ResolveDeploymentID(depID, appFlag, configArg string) (string, error) {
 	if appFlag != "" {
 		ns := context.GetCurrentNamespace()
 		if ns == "" {
-			return "", fmt.Errorf("not authenticated — run '1ctl auth login' first")
+			return "", utils.NewKindError(utils.ErrAuth, "not authenticated — run '1ctl auth login' first", nil)
 		}
-		dep, err := api.GetDeploymentByAppLabel(ns, appFlag)
-		if err != nil {
-			return "", fmt.Errorf("app %q not found in organization %s\nRun '1ctl app list' to see deployed apps", appFlag, ns)
-		}
-		return dep.DeploymentID.String(), nil
+		return lookupApp(client, ns, appFlag, "Run '1ctl app list' to see deployed apps")
 	}
 
 	cfg, err := config.FindConfig(configArg)
@@ -48,13 +45,22 @@ func ResolveDeploymentID(depID, appFlag, configArg string) (string, error) {
 
 	ns := context.GetCurrentNamespace()
 	if ns == "" {
-		return "", fmt.Errorf("not authenticated — run '1ctl auth login' first")
+		return "", utils.NewKindError(utils.ErrAuth, "not authenticated — run '1ctl auth login' first", nil)
 	}
 
-	dep, err := api.GetDeploymentByAppLabel(ns, cfg.App.Name)
+	return lookupApp(client, ns, cfg.App.Name, "Run '1ctl deploy' first or pass --deployment-id\nRun '1ctl app list' to see deployed apps")
+}
+
+// lookupApp returns the ID of the deployment named app. Only a 404 is
+// reported as a missing app, with hint; any other failure keeps its kind so
+// an auth or network problem exits with its own code.
+func lookupApp(client api.DeploymentsAPI, namespace, app, hint string) (string, error) {
+	dep, err := client.GetDeploymentByAppLabel(namespace, app)
+	if utils.KindOf(err) == utils.ErrNotFound {
+		return "", utils.NewKindError(utils.ErrNotFound, fmt.Sprintf("app %q not found in organization %s\n%s", app, namespace, hint), nil)
+	}
 	if err != nil {
-		return "", fmt.Errorf("app %q not found in organization %s\nRun '1ctl deploy' first or pass --deployment-id\nRun '1ctl app list' to see deployed apps", cfg.App.Name, ns)
+		return "", utils.NewError(fmt.Sprintf("failed to look up app %q", app), err)
 	}
-
 	return dep.DeploymentID.String(), nil
 }
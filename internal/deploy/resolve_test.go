@@ -0,0 +1,63 @@
+package deploy
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"1ctl/internal/api"
+	"1ctl/internal/api/apitest"
+	"1ctl/internal/context"
+	"1ctl/internal/utils"
+)
+
+func TestResolveDeploymentIDByApp(t *testing.T) {
+	useTestProfile(t)
+	if err := context.SetCurrentNamespace("acme"); err != nil {
+		t.Fatal(err)
+	}
+	srv := apitest.NewServer(t)
+	dep := srv.AddDeployment(api.Deployment{Namespace: "acme", AppLabel: "web", Image: "web:1"})
+
+	got, err := ResolveDeploymentID(srv.Client(), "", "web", "")
+	if err != nil {
+		t.Fatalf("ResolveDeploymentID(--app web) error = %v", err)
+	}
+	if got != dep.DeploymentID.String() {
+		t.Errorf("ResolveDeploymentID(--app web) = %s, want %s", got, dep.DeploymentID)
+	}
+	if reqs := srv.Requests(); len(reqs) != 1 || reqs[0] != "GET /deployments/namespace/acme/app/web" {
+		t.Errorf("requests = %v, want one lookup on the injected client", reqs)
+	}
+}
+
+func TestResolveDeploymentIDKeepsTheLookupErrorKind(t *testing.T) {
+	useTestProfile(t)
+	if err := context.SetCurrentNamespace("acme"); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		status   int
+		message  string
+		wantCode int
+		wantMsg  string
+	}{
+		{"missing app", http.StatusNotFound, "deployment not found", 5, `app "web" not found in organization acme`},
+		{"expired token", http.StatusUnauthorized, "token expired", 3, `failed to look up app "web": token expired`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := apitest.NewServer(t)
+			srv.Fail(http.MethodGet, "/deployments/namespace/acme/app/web", tt.status, tt.message)
+
+			_, err := ResolveDeploymentID(srv.Client(), "", "web", "")
+			if err == nil || !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Fatalf("ResolveDeploymentID() error = %v, want %q", err, tt.wantMsg)
+			}
+			if got := utils.ExitCode(err); got != tt.wantCode {
+				t.Errorf("ExitCode(%v) = %d, want %d", err, got, tt.wantCode)
+			}
+		})
+	}
+}
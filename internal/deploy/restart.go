@@ -0,0 +1,25 @@
+package deploy
+
+import (
+	"1ctl/internal/api"
+	"1ctl/internal/utils"
+)
+
+// RestartAfterChange restarts a deployment so a saved change to what it
+// runs with, such as its environment or secrets, takes effect. With
+// noRestart it only says how to apply the staged change later. what names
+// the change in messages. A failed restart warns rather than fails: the
+// change itself is already saved.
+func RestartAfterChange(client api.DeploymentsAPI, deploymentID, appLabel, what string, noRestart bool) {
+	if noRestart {
+		utils.PrintInfo("Restart skipped — run '1ctl app apply-config %s' to apply staged changes", appLabel)
+		return
+	}
+	utils.PrintInfo("Restarting deployment to apply %s...", what)
+	if err := client.RestartDeployment(deploymentID); err != nil {
+		utils.PrintWarning("The %s change is saved, but the restart failed: %s", what, err.Error())
+		utils.PrintInfo("Run: 1ctl app apply-config %s", appLabel)
+		return
+	}
+	utils.PrintSuccess("Deployment restarting — changes will be available shortly")
+}
@@ -2,6 +2,7 @@ package deploy
 
 import (
 	"fmt"
+	"io"
 	"time"
 
 	"1ctl/internal/api"
@@ -15,21 +16,22 @@ type PublicURLReadiness struct {
 }
 
 // WaitForPublicURL checks DNS propagation and domain readiness for a deployment
-// identified by its ingress ID and domain name. When ingressID is empty the check
-// is skipped and the result is reported as ready (smoke will catch any issues).
-func WaitForPublicURL(ingressID, domain string) PublicURLReadiness {
+// identified by its ingress ID and domain name, writing warnings to out. When
+// ingressID is empty the check is skipped and the result is reported as ready
+// (smoke will catch any issues).
+func WaitForPublicURL(client api.IngressesAPI, out io.Writer, ingressID, domain string) PublicURLReadiness {
 	if ingressID == "" || domain == "" {
 		return PublicURLReadiness{Ready: true}
 	}
 
 	r := PublicURLReadiness{Ready: true}
-	if _, err := api.WaitForIngressDNSStatus(ingressID, 2*time.Minute); err != nil {
+	if _, err := client.WaitForIngressDNSStatus(ingressID, 2*time.Minute); err != nil {
 		r.Ready = false
 		r.Reason = fmt.Sprintf("DNS propagation timed out: %s", err.Error())
-		utils.PrintWarning("DNS is still propagating for https://%s: %s", domain, err.Error())
+		utils.NewPrinter(out).Warning("DNS is still propagating for https://%s: %s", domain, err.Error())
 	}
 
-	status, err := api.GetDomainStatus(ingressID, domain, false)
+	status, err := client.GetDomainStatus(ingressID, domain, false)
 	if err != nil {
 		if r.Ready {
 			r.Ready = false
@@ -47,37 +49,38 @@ func WaitForPublicURL(ingressID, domain string) PublicURLReadiness {
 // ResolveIngressID looks up an ingress by deployment ID and returns its ID as a
 // string, or empty string if no ingress is found. This is useful for commands
 // (e.g. marketplace deploy) whose response does not include the ingress ID.
-func ResolveIngressID(deploymentID string) string {
-	ing, err := api.GetIngressByDeploymentID(deploymentID)
+func ResolveIngressID(client api.IngressesAPI, deploymentID string) string {
+	ing, err := client.GetIngressByDeploymentID(deploymentID)
 	if err != nil || ing == nil {
 		return ""
 	}
 	return ing.IngressID.String()
 }
 
-// ReportDeployResult prints the deployment result with optional DNS/smoke
-// testing. It handles three cases:
+// ReportDeployResult prints the deployment result to out with optional
+// DNS/smoke testing. It handles three cases:
 //   - No domain: deployment was accepted, print success.
 //   - Domain but URL not ready: print success + warning + next-step hint.
 //   - Domain and URL ready: run HTTP smoke probe (polls up to 30s).
-func ReportDeployResult(appLabel, deploymentID, domain string, ready PublicURLReadiness, smokePath string, strictSmoke bool) error {
-	utils.PrintStatusLine("Deployment ID", deploymentID)
+func ReportDeployResult(out io.Writer, appLabel, deploymentID, domain string, ready PublicURLReadiness, smokePath string, strictSmoke bool) error {
+	p := utils.NewPrinter(out)
+	p.StatusLine("Deployment ID", deploymentID)
 
 	if !ready.Ready || domain == "" {
-		utils.PrintSuccess("Deployment for %s was accepted by the platform.", appLabel)
+		p.Success("Deployment for %s was accepted by the platform.", appLabel)
 		if domain != "" {
-			utils.PrintWarning("Public URL is not ready yet: https://%s", domain)
+			p.Warning("Public URL is not ready yet: https://%s", domain)
 			if ready.Reason != "" {
-				utils.PrintStatusLine("Public URL reason", ready.Reason)
+				p.StatusLine("Public URL reason", ready.Reason)
 			}
-			utils.PrintInfo("Run: 1ctl domains check %s --probe", domain)
+			p.Info("Run: 1ctl domains check %s --probe", domain)
 		}
 		return nil
 	}
 
 	smokeURL := "https://" + domain
 	smokePaths := SmokePathCandidates(smokePath)
-	utils.PrintInfo("Waiting for app to respond at %s...", smokeURL)
+	p.Info("Waiting for app to respond at %s...", smokeURL)
 
 	var smoke PublicURLSmokeResult
 	deadline := time.Now().Add(30 * time.Second)
@@ -90,14 +93,14 @@ func ReportDeployResult(appLabel, deploymentID, domain string, ready PublicURLRe
 	}
 
 	if smoke.Ready {
-		utils.PrintSuccess("🚀 Deployment for %s is successful! Your app is live at: https://%s", appLabel, domain)
+		p.Success("🚀 Deployment for %s is successful! Your app is live at: https://%s", appLabel, domain)
 		if smoke.Path != "" {
-			utils.PrintInfo("Verified: %s%s", smokeURL, smoke.Path)
+			p.Info("Verified: %s%s", smokeURL, smoke.Path)
 		}
 	} else {
-		utils.PrintWarning("App is starting up — not reachable yet at https://%s", domain)
-		utils.PrintInfo("The platform accepted your deployment and pods are starting.")
-		utils.PrintInfo("Check status: 1ctl doctor --deployment-id %s", deploymentID)
+		p.Warning("App is starting up — not reachable yet at https://%s", domain)
+		p.Info("The platform accepted your deployment and pods are starting.")
+		p.Info("Check status: 1ctl doctor --deployment-id %s", deploymentID)
 		if strictSmoke {
 			return utils.NewError(fmt.Sprintf("smoke check failed for https://%s: %s", domain, smoke.Reason), nil)
 		}
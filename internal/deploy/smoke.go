@@ -5,11 +5,10 @@ import (
 	"fmt"
 	"net"
 	"net/http"
-	"net/url"
 	"strings"
 	"time"
 
-	"github.com/pkg/browser"
+	"1ctl/internal/transport"
 )
 
 // PublicURLSmokeResult holds the outcome of a single smoke-test probe.
@@ -66,17 +65,15 @@ func CheckPublicURLSmokeAtPath(baseURL, path string, strict bool) PublicURLSmoke
 	targetURL := strings.TrimRight(baseURL, "/") + path
 	client := &http.Client{
 		Timeout: 10 * time.Second,
-		Transport: &http.Transport{
-			DialContext: (&net.Dialer{
-				Resolver: &net.Resolver{
-					PreferGo: true,
-					Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
-						d := net.Dialer{Timeout: 3 * time.Second}
-						return d.DialContext(ctx, "udp", "8.8.8.8:53")
-					},
+		Transport: transport.WithDialer((&net.Dialer{
+			Resolver: &net.Resolver{
+				PreferGo: true,
+				Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+					d := net.Dialer{Timeout: 3 * time.Second}
+					return d.DialContext(ctx, "udp", "8.8.8.8:53")
 				},
-			}).DialContext,
-		},
+			},
+		}).DialContext),
 	}
 	req, err := http.NewRequest(http.MethodGet, targetURL, nil)
 	if err != nil {
@@ -125,20 +122,3 @@ func IsReachabilityStatus(code int) bool {
 func isReachabilityStatus(code int) bool {
 	return IsReachabilityStatus(code)
 }
-
-// OpenBrowser opens the given URL in the user's default browser.
-func OpenBrowser(targetURL string) error {
-	if targetURL == "" {
-		return fmt.Errorf("no URL to open")
-	}
-	parsed, err := url.ParseRequestURI(targetURL)
-	if err != nil {
-		return fmt.Errorf("invalid URL: %w", err)
-	}
-	switch parsed.Scheme {
-	case "http", "https":
-		return browser.OpenURL(parsed.String())
-	default:
-		return fmt.Errorf("unsupported URL scheme: %s", parsed.Scheme)
-	}
-}
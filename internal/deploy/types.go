@@ -1,6 +1,12 @@
 package deploy
 
-import "1ctl/internal/api"
+import (
+	"io"
+	"os"
+
+	"1ctl/internal/api"
+	"1ctl/internal/utils"
+)
 
 // PDBConfigType represents the type of PodDisruptionBudget configuration
 type PDBConfigType string
@@ -79,4 +85,35 @@ type DeploymentOptions struct {
 	// When false, 401/403/404 are treated as platform-reachable and only
 	// 5xx/connection failures fail the check.
 	StrictSmoke bool
+	// ContextDir is the build context directory; DockerfilePath is relative
+	// to it. Empty means the current directory. Monorepo deploys set it so
+	// several services can build concurrently without changing directory.
+	ContextDir string
+	// Output receives progress messages and streamed cloud build output.
+	// Nil means os.Stdout. Monorepo deploys give each service its own so
+	// concurrent deploys do not interleave.
+	Output io.Writer
+	// API is the SatuSky API the deploy talks to. Nil means api.Default(),
+	// the active profile.
+	API api.API
+}
+
+// output returns where the deploy writes its progress.
+func (o DeploymentOptions) output() io.Writer {
+	if o.Output != nil {
+		return o.Output
+	}
+	return os.Stdout
+}
+
+func (o DeploymentOptions) printer() *utils.Printer {
+	return utils.NewPrinter(o.output())
+}
+
+// client returns the API the deploy talks to.
+func (o DeploymentOptions) client() api.API {
+	if o.API != nil {
+		return o.API
+	}
+	return api.Default()
 }
@@ -141,6 +141,26 @@ func readDockerignore(contextDir string) ([]string, error) {
 	return patterns, scanner.Err()
 }
 
+// LoadIgnorePatterns returns the .dockerignore patterns PackageContext
+// applies to contextDir, or nil when there is no .dockerignore.
+func LoadIgnorePatterns(contextDir string) ([]string, error) {
+	return readDockerignore(contextDir)
+}
+
+// IsIgnored reports whether relPath (slash-separated, relative to the
+// context root) is left out of the build context. Like PackageContext, a
+// file under an ignored directory is excluded even if a later pattern
+// re-includes the file itself.
+func IsIgnored(relPath string, patterns []string) bool {
+	parts := strings.Split(relPath, "/")
+	for i := 1; i < len(parts); i++ {
+		if shouldIgnore(strings.Join(parts[:i], "/"), patterns) {
+			return true
+		}
+	}
+	return shouldIgnore(relPath, patterns)
+}
+
 // shouldIgnore returns true when the path should be excluded from the context.
 // Patterns are evaluated in order; later patterns override earlier ones.
 // A pattern starting with '!' negates the exclusion.
@@ -0,0 +1,164 @@
+package envfile
+
+import (
+	"fmt"
+	"sort"
+
+	"1ctl/internal/api"
+	"1ctl/internal/utils"
+)
+
+// Change operations.
+const (
+	OpAdd    = "add"
+	OpChange = "change"
+	OpRemove = "remove"
+)
+
+// Change is a single key that an import would add, change or remove.
+// Values are deliberately not carried: diffs are shown before confirmation
+// and must never echo secrets.
+type Change struct {
+	Key string `json:"key"`
+	Op  string `json:"op"`
+}
+
+// Diff returns the changes needed to make live match desired, sorted by key.
+// Keys only present in live are removed when prune is set and left alone
+// otherwise.
+func Diff(live, desired map[string]string, prune bool) []Change {
+	var changes []Change
+	for k, v := range desired {
+		cur, ok := live[k]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Key: k, Op: OpAdd})
+		case cur != v:
+			changes = append(changes, Change{Key: k, Op: OpChange})
+		}
+	}
+	if prune {
+		for k := range live {
+			if _, ok := desired[k]; !ok {
+				changes = append(changes, Change{Key: k, Op: OpRemove})
+			}
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes
+}
+
+// Upserts returns the key-value pairs from desired that changes adds or
+// modifies, in key order.
+func Upserts(changes []Change, desired map[string]string) []api.KeyValuePair {
+	var pairs []api.KeyValuePair
+	for _, c := range changes {
+		if c.Op != OpRemove {
+			pairs = append(pairs, api.KeyValuePair{Key: c.Key, Value: desired[c.Key]})
+		}
+	}
+	return pairs
+}
+
+// Removals returns the keys changes deletes, in key order.
+func Removals(changes []Change) []string {
+	var keys []string
+	for _, c := range changes {
+		if c.Op == OpRemove {
+			keys = append(keys, c.Key)
+		}
+	}
+	return keys
+}
+
+// PrintDiff writes changes one key per line with values masked.
+func PrintDiff(changes []Change) {
+	for _, c := range changes {
+		switch c.Op {
+		case OpAdd:
+			fmt.Printf("  + %s = ********\n", c.Key)
+		case OpChange:
+			fmt.Printf("  ~ %s = ******** (changed)\n", c.Key)
+		case OpRemove:
+			fmt.Printf("  - %s\n", c.Key)
+		}
+	}
+}
+
+// Summary describes changes as e.g. "2 added, 1 changed, 3 removed".
+func Summary(changes []Change) string {
+	var added, changed, removed int
+	for _, c := range changes {
+		switch c.Op {
+		case OpAdd:
+			added++
+		case OpChange:
+			changed++
+		case OpRemove:
+			removed++
+		}
+	}
+	return fmt.Sprintf("%d added, %d changed, %d removed", added, changed, removed)
+}
+
+// Unverified returns the keys of changes that live does not reflect: an
+// added or changed key whose value differs from desired, or a removed key
+// that is still present.
+func Unverified(changes []Change, live, desired map[string]string) []string {
+	var keys []string
+	for _, c := range changes {
+		v, ok := live[c.Key]
+		switch {
+		case c.Op == OpRemove && ok:
+			keys = append(keys, c.Key)
+		case c.Op != OpRemove && (!ok || v != desired[c.Key]):
+			keys = append(keys, c.Key)
+		}
+	}
+	return keys
+}
+
+// Revert returns the mutations that undo changes against the original live
+// values: previous values to write back and added keys to remove.
+func Revert(changes []Change, original map[string]string) (restore []api.KeyValuePair, remove []string) {
+	for _, c := range changes {
+		if c.Op == OpAdd {
+			remove = append(remove, c.Key)
+		} else {
+			restore = append(restore, api.KeyValuePair{Key: c.Key, Value: original[c.Key]})
+		}
+	}
+	return restore, remove
+}
+
+// Apply returns live with changes made, taking new values from desired.
+func Apply(live map[string]string, changes []Change, desired map[string]string) map[string]string {
+	out := make(map[string]string, len(live)+len(changes))
+	for k, v := range live {
+		out[k] = v
+	}
+	for _, c := range changes {
+		if c.Op == OpRemove {
+			delete(out, c.Key)
+		} else {
+			out[c.Key] = desired[c.Key]
+		}
+	}
+	return out
+}
+
+// PrintValueDiff writes changes one key per line with their values, for
+// plain environment variables where showing values is the point. Values
+// that are also registered secrets are still masked.
+func PrintValueDiff(changes []Change, from, to map[string]string) {
+	for _, c := range changes {
+		switch c.Op {
+		case OpAdd:
+			fmt.Printf("  + %s=%s\n", c.Key, utils.Redact(to[c.Key]))
+		case OpChange:
+			fmt.Printf("  ~ %s: %s → %s\n", c.Key, utils.Redact(from[c.Key]), utils.Redact(to[c.Key]))
+		case OpRemove:
+			fmt.Printf("  - %s=%s\n", c.Key, utils.Redact(from[c.Key]))
+		}
+	}
+}
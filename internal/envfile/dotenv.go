@@ -0,0 +1,127 @@
+package envfile
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// parseDotenv reads dotenv syntax the way godotenv does — comments,
+// "export " prefixes, KEY=value or KEY: value, single, double and unquoted
+// values, multiline quoted values — but takes every value literally. "$VAR"
+// and "${VAR}" are never expanded: an imported secret such as
+// "p@ss$LOCAL_ONLY" must reach the API unchanged.
+func parseDotenv(data []byte) (map[string]string, error) {
+	src := strings.ReplaceAll(string(data), "\r\n", "\n")
+	values := map[string]string{}
+	for {
+		src = strings.TrimLeftFunc(src, unicode.IsSpace)
+		if src == "" {
+			return values, nil
+		}
+		if src[0] == '#' {
+			src = skipLine(src)
+			continue
+		}
+
+		key, rest, err := dotenvKey(src)
+		if err != nil {
+			return nil, err
+		}
+		value, rest, err := dotenvValue(strings.TrimLeft(rest, " \t"))
+		if err != nil {
+			return nil, err
+		}
+		values[key] = value
+		src = rest
+	}
+}
+
+// dotenvKey reads "KEY=" or "KEY:" after an optional "export " prefix.
+func dotenvKey(src string) (string, string, error) {
+	if rest, ok := strings.CutPrefix(src, "export"); ok && rest != "" && (rest[0] == ' ' || rest[0] == '\t') {
+		src = strings.TrimLeft(rest, " \t")
+	}
+	for i, r := range src {
+		switch {
+		case r == '=' || r == ':':
+			key := strings.TrimRight(src[:i], " \t")
+			if key == "" || strings.ContainsAny(key, " \t") {
+				return "", "", fmt.Errorf("invalid variable name near %q", firstLine(src))
+			}
+			return key, src[i+1:], nil
+		case r == ' ' || r == '\t':
+		case r == '_' || r == '.' || unicode.IsLetter(r) || unicode.IsNumber(r):
+		default:
+			return "", "", fmt.Errorf("unexpected character %q in variable name near %q", string(r), firstLine(src))
+		}
+	}
+	return "", "", fmt.Errorf("missing '=' after %q", firstLine(src))
+}
+
+// dotenvValue reads one value and returns the input after it.
+func dotenvValue(src string) (string, string, error) {
+	if src == "" {
+		return "", "", nil
+	}
+	switch src[0] {
+	case '\'':
+		// Literal up to the closing quote; \' does not close it.
+		for i := 1; i < len(src); i++ {
+			if src[i] == '\'' && src[i-1] != '\\' {
+				return src[1:i], src[i+1:], nil
+			}
+		}
+	case '"':
+		var b strings.Builder
+		for i := 1; i < len(src); i++ {
+			switch c := src[i]; {
+			case c == '"':
+				return b.String(), src[i+1:], nil
+			case c == '\\' && i+1 < len(src):
+				i++
+				switch src[i] {
+				case 'n':
+					b.WriteByte('\n')
+				case 'r':
+					b.WriteByte('\r')
+				default:
+					// \" \\ \$ and any other escaped character stand for
+					// themselves.
+					b.WriteByte(src[i])
+				}
+			default:
+				b.WriteByte(c)
+			}
+		}
+	default:
+		end := strings.IndexAny(src, "\r\n")
+		if end == -1 {
+			end = len(src)
+		}
+		line := src[:end]
+		// " #" starts an inline comment.
+		for i := 1; i < len(line); i++ {
+			if line[i] == '#' && (line[i-1] == ' ' || line[i-1] == '\t') {
+				line = line[:i]
+				break
+			}
+		}
+		return strings.TrimSpace(line), src[end:], nil
+	}
+	return "", "", fmt.Errorf("unterminated quoted value %s", firstLine(src))
+}
+
+func skipLine(src string) string {
+	if i := strings.IndexByte(src, '\n'); i != -1 {
+		return src[i+1:]
+	}
+	return ""
+}
+
+func firstLine(src string) string {
+	if i := strings.IndexByte(src, '\n'); i != -1 {
+		return src[:i]
+	}
+	return src
+}
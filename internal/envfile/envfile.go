@@ -0,0 +1,152 @@
+// Package envfile reads and writes KEY=VALUE bundles — environment
+// variables and secrets — as dotenv, JSON or shell, and computes key-level
+// differences between a file and what is live on a deployment.
+package envfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"1ctl/internal/api"
+)
+
+// Supported formats.
+const (
+	FormatDotenv = "dotenv"
+	FormatJSON   = "json"
+	FormatShell  = "shell"
+)
+
+// ReadFile parses path as dotenv or JSON. An empty format is inferred from
+// the extension: ".json" is JSON, anything else (.env, .env.production) is
+// dotenv.
+func ReadFile(path, format string) (map[string]string, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- user-supplied import file
+	if err != nil {
+		return nil, err
+	}
+	if format == "" {
+		format = FormatDotenv
+		if strings.EqualFold(filepath.Ext(path), ".json") {
+			format = FormatJSON
+		}
+	}
+	return Parse(data, format)
+}
+
+// Parse decodes data in the given format. Dotenv input supports quoted and
+// multiline values, comments and "export " prefixes; "$" is taken literally. JSON input must be a
+// flat object; numbers and booleans are converted to their text form.
+func Parse(data []byte, format string) (map[string]string, error) {
+	switch format {
+	case FormatDotenv:
+		values, err := parseDotenv(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid dotenv: %w", err)
+		}
+		return values, nil
+	case FormatJSON:
+		var raw map[string]interface{}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("invalid JSON: expected an object of KEY: value pairs: %w", err)
+		}
+		values := make(map[string]string, len(raw))
+		for k, v := range raw {
+			if k == "" {
+				return nil, fmt.Errorf("invalid JSON: empty key")
+			}
+			switch v := v.(type) {
+			case string:
+				values[k] = v
+			case json.Number, bool:
+				values[k] = fmt.Sprint(v)
+			case nil:
+				values[k] = ""
+			default:
+				return nil, fmt.Errorf("invalid JSON: value for %q must be a string, number or boolean", k)
+			}
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("unsupported format %q (use dotenv or json)", format)
+}
+
+// Write encodes values to w, sorted by key. Dotenv output reads back
+// unchanged through Parse; shell output is "export KEY='value'" lines
+// suitable for eval.
+func Write(w io.Writer, values map[string]string, format string) error {
+	keys := SortedKeys(values)
+	switch format {
+	case FormatDotenv:
+		for _, k := range keys {
+			quoted, err := dotenvQuote(values[k])
+			if err != nil {
+				return fmt.Errorf("%s: %w", k, err)
+			}
+			if _, err := fmt.Fprintf(w, "%s=%s\n", k, quoted); err != nil {
+				return err
+			}
+		}
+		return nil
+	case FormatShell:
+		for _, k := range keys {
+			if _, err := fmt.Fprintf(w, "export %s=%s\n", k, shellQuote(values[k])); err != nil {
+				return err
+			}
+		}
+		return nil
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(values)
+	}
+	return fmt.Errorf("unsupported format %q (use dotenv, json or shell)", format)
+}
+
+// dotenvQuote single-quotes values that can be taken literally and falls
+// back to double quotes with escapes for values containing quotes or line
+// breaks. "$" is escaped so other dotenv readers do not expand it. godotenv,
+// which the project .env loader uses, cannot read back a double-quoted value
+// ending in an escaped quote, so those are rejected rather than written
+// corrupted.
+func dotenvQuote(v string) (string, error) {
+	if !strings.ContainsAny(v, "'\n\r") {
+		return "'" + v + "'", nil
+	}
+	if strings.HasSuffix(v, `"`) {
+		return "", fmt.Errorf("value cannot be represented in dotenv; use --format json")
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "$", `\$`)
+	return `"` + r.Replace(v) + `"`, nil
+}
+
+func shellQuote(v string) string {
+	return "'" + strings.ReplaceAll(v, "'", `'\''`) + "'"
+}
+
+// ToMap converts API key-value pairs to a map.
+func ToMap(pairs []api.KeyValuePair) map[string]string {
+	values := make(map[string]string, len(pairs))
+	for _, kv := range pairs {
+		values[kv.Key] = kv.Value
+	}
+	return values
+}
+
+// SortedKeys returns the keys of values in lexical order.
+func SortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
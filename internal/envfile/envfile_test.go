@@ -0,0 +1,175 @@
+package envfile
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"1ctl/internal/api"
+)
+
+func TestParseDotenv(t *testing.T) {
+	input := `# comment
+export DATABASE_URL=postgres://db:5432/app
+QUOTED="hello world"
+SINGLE='$NOT_EXPANDED'
+MULTI="line one
+line two"
+EMPTY=
+`
+	got, err := Parse([]byte(input), FormatDotenv)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := map[string]string{
+		"DATABASE_URL": "postgres://db:5432/app",
+		"QUOTED":       "hello world",
+		"SINGLE":       "$NOT_EXPANDED",
+		"MULTI":        "line one\nline two",
+		"EMPTY":        "",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse = %#v, want %#v", got, want)
+	}
+}
+
+func TestParseDotenvKeepsDollarSigns(t *testing.T) {
+	t.Setenv("LOCAL_ONLY", "expanded")
+	input := `DOUBLE="p@ss$LOCAL_ONLY"
+BRACES="x${LOCAL_ONLY}y"
+UNQUOTED=p@ss$LOCAL_ONLY # trailing comment
+ESCAPED="a\$b\"c\\d\ne"
+`
+	got, err := Parse([]byte(input), FormatDotenv)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := map[string]string{
+		"DOUBLE":   "p@ss$LOCAL_ONLY",
+		"BRACES":   "x${LOCAL_ONLY}y",
+		"UNQUOTED": "p@ss$LOCAL_ONLY",
+		"ESCAPED":  "a$b\"c\\d\ne",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse = %#v, want %#v", got, want)
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	got, err := Parse([]byte(`{"A": "x", "PORT": 8080, "DEBUG": true, "EMPTY": null}`), FormatJSON)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := map[string]string{"A": "x", "PORT": "8080", "DEBUG": "true", "EMPTY": ""}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse = %#v, want %#v", got, want)
+	}
+
+	if _, err := Parse([]byte(`{"A": {"nested": 1}}`), FormatJSON); err == nil {
+		t.Error("expected nested JSON value to be rejected")
+	}
+}
+
+func TestWriteDotenvRoundTrip(t *testing.T) {
+	values := map[string]string{
+		"PLAIN":   "value",
+		"ZEROS":   "007",
+		"DOLLAR":  "pa$$word",
+		"QUOTES":  `it's "quoted" here`,
+		"NEWLINE": "a\nb",
+		"SLASH":   `C:\path`,
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, values, FormatDotenv); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	got, err := Parse(buf.Bytes(), FormatDotenv)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", buf.String(), err)
+	}
+	if !reflect.DeepEqual(got, values) {
+		t.Errorf("round trip = %#v, want %#v\nfile:\n%s", got, values, buf.String())
+	}
+}
+
+func TestWriteDotenvUnrepresentable(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, map[string]string{"K": `it's "quoted"`}, FormatDotenv); err == nil {
+		t.Error("expected an error for a value the dotenv parser cannot read back")
+	}
+}
+
+func TestWriteShell(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, map[string]string{"B": "it's", "A": "x"}, FormatShell); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	want := "export A='x'\nexport B='it'\\''s'\n"
+	if buf.String() != want {
+		t.Errorf("Write shell = %q, want %q", buf.String(), want)
+	}
+	if err := Write(&buf, nil, "yaml"); err == nil || !strings.Contains(err.Error(), "unsupported") {
+		t.Errorf("expected unsupported format error, got %v", err)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	live := map[string]string{"KEEP": "1", "CHANGE": "old", "EXTRA": "x"}
+	desired := map[string]string{"KEEP": "1", "CHANGE": "new", "ADD": "y"}
+
+	got := Diff(live, desired, false)
+	want := []Change{{Key: "ADD", Op: OpAdd}, {Key: "CHANGE", Op: OpChange}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff = %v, want %v", got, want)
+	}
+
+	got = Diff(live, desired, true)
+	want = []Change{{Key: "ADD", Op: OpAdd}, {Key: "CHANGE", Op: OpChange}, {Key: "EXTRA", Op: OpRemove}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff(prune) = %v, want %v", got, want)
+	}
+	if keys := Removals(got); !reflect.DeepEqual(keys, []string{"EXTRA"}) {
+		t.Errorf("Removals = %v", keys)
+	}
+	if pairs := Upserts(got, desired); len(pairs) != 2 || pairs[1].Value != "new" {
+		t.Errorf("Upserts = %v", pairs)
+	}
+}
+
+func TestUnverifiedAndRevert(t *testing.T) {
+	original := map[string]string{"CHANGE": "old", "DROP": "x"}
+	desired := map[string]string{"CHANGE": "new", "ADD": "y"}
+	changes := Diff(original, desired, true)
+
+	if keys := Unverified(changes, desired, desired); len(keys) != 0 {
+		t.Errorf("Unverified(applied) = %v, want none", keys)
+	}
+	partial := map[string]string{"CHANGE": "new", "DROP": "x"}
+	if keys := Unverified(changes, partial, desired); !reflect.DeepEqual(keys, []string{"ADD", "DROP"}) {
+		t.Errorf("Unverified(partial) = %v", keys)
+	}
+
+	restore, remove := Revert(changes, original)
+	wantRestore := []api.KeyValuePair{{Key: "CHANGE", Value: "old"}, {Key: "DROP", Value: "x"}}
+	if !reflect.DeepEqual(restore, wantRestore) {
+		t.Errorf("Revert restore = %v, want %v", restore, wantRestore)
+	}
+	if !reflect.DeepEqual(remove, []string{"ADD"}) {
+		t.Errorf("Revert remove = %v", remove)
+	}
+}
+
+func TestApply(t *testing.T) {
+	live := map[string]string{"KEEP": "1", "CHANGE": "old", "DROP": "x"}
+	desired := map[string]string{"KEEP": "1", "CHANGE": "new", "ADD": "y"}
+	got := Apply(live, Diff(live, desired, true), desired)
+	if !reflect.DeepEqual(got, desired) {
+		t.Errorf("Apply(prune) = %v, want %v", got, desired)
+	}
+	got = Apply(live, Diff(live, desired, false), desired)
+	want := map[string]string{"KEEP": "1", "CHANGE": "new", "ADD": "y", "DROP": "x"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Apply = %v, want %v", got, want)
+	}
+}
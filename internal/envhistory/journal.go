@@ -0,0 +1,188 @@
+// Package envhistory keeps a journal of environment snapshots per deployment.
+//
+// The platform overwrites an environment bundle in place and has no history
+// endpoint, so the CLI records the full key set before and after each change
+// it makes. Journals live under ~/.satusky/env-history/, one JSON line per
+// snapshot, and only cover changes seen from this machine: edits made
+// elsewhere show up as an "observed" version the next time the CLI looks.
+package envhistory
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	satuskyctx "1ctl/internal/context"
+)
+
+// Sources for snapshots that were not produced by a CLI change.
+const (
+	SourceObserved = "observed"
+)
+
+// Snapshot is the complete environment of a deployment at one point.
+type Snapshot struct {
+	Version int               `json:"version"`
+	Time    time.Time         `json:"time"`
+	Actor   string            `json:"actor,omitempty"`
+	Source  string            `json:"source"`
+	Values  map[string]string `json:"values"`
+}
+
+// Journal is the append-only snapshot log for one deployment.
+type Journal struct {
+	path string
+}
+
+// Open returns the journal for deploymentID in the CLI config directory.
+func Open(deploymentID string) (*Journal, error) {
+	if deploymentID == "" || strings.ContainsAny(deploymentID, `/\.`) {
+		return nil, fmt.Errorf("invalid deployment ID %q", deploymentID)
+	}
+	dir := filepath.Join(satuskyctx.Default().ConfigDir(), "env-history")
+	return NewJournal(filepath.Join(dir, deploymentID+".jsonl")), nil
+}
+
+// NewJournal returns a journal stored at path.
+func NewJournal(path string) *Journal {
+	return &Journal{path: path}
+}
+
+// Snapshots returns every snapshot, oldest first. A missing journal has none.
+func (j *Journal) Snapshots() ([]Snapshot, error) {
+	f, err := os.Open(j.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }() //nolint:errcheck
+
+	var snaps []Snapshot
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var s Snapshot
+		if err := json.Unmarshal([]byte(line), &s); err != nil {
+			// A torn final write must not make the whole history unreadable.
+			continue
+		}
+		snaps = append(snaps, s)
+	}
+	return snaps, scanner.Err()
+}
+
+// Version returns snapshot v.
+func (j *Journal) Version(v int) (*Snapshot, error) {
+	snaps, err := j.Snapshots()
+	if err != nil {
+		return nil, err
+	}
+	for i := range snaps {
+		if snaps[i].Version == v {
+			return &snaps[i], nil
+		}
+	}
+	return nil, fmt.Errorf("version v%d not found — run '1ctl env history' to list versions", v)
+}
+
+// Record appends values as a new snapshot unless they equal the latest
+// one, in which case it returns nil.
+func (j *Journal) Record(values map[string]string, source string) (*Snapshot, error) {
+	release, err := j.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return j.record(values, source)
+}
+
+// lock holds the journal's file lock, so concurrent 1ctl runs never pick
+// the same version number.
+func (j *Journal) lock() (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(j.path), 0700); err != nil {
+		return nil, err
+	}
+	return satuskyctx.LockFile(j.path)
+}
+
+// record is Record with the lock held.
+func (j *Journal) record(values map[string]string, source string) (*Snapshot, error) {
+	snaps, err := j.Snapshots()
+	if err != nil {
+		return nil, err
+	}
+	next := 1
+	if n := len(snaps); n > 0 {
+		if maps.Equal(snaps[n-1].Values, values) {
+			return nil, nil
+		}
+		next = snaps[n-1].Version + 1
+	}
+
+	snap := Snapshot{
+		Version: next,
+		Time:    time.Now().UTC(),
+		Actor:   satuskyctx.GetEmail(),
+		Source:  source,
+		Values:  maps.Clone(values),
+	}
+	if snap.Values == nil {
+		snap.Values = map[string]string{}
+	}
+	line, err := json.Marshal(snap)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(j.path, os.O_CREATE|os.O_APPEND|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
+	// Start on a fresh line if an earlier write was cut short.
+	if info, err := f.Stat(); err == nil && info.Size() > 0 {
+		last := make([]byte, 1)
+		if _, err := f.ReadAt(last, info.Size()-1); err == nil && last[0] != '\n' {
+			line = append([]byte{'\n'}, line...)
+		}
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		_ = f.Close() //nolint:errcheck
+		return nil, err
+	}
+	return &snap, f.Close()
+}
+
+// Track records the environment around one change: before, if it differs
+// from the latest snapshot (an edit made elsewhere), then after.
+func (j *Journal) Track(before, after map[string]string, source string) (*Snapshot, error) {
+	release, err := j.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	if _, err := j.record(before, SourceObserved); err != nil {
+		return nil, err
+	}
+	return j.record(after, source)
+}
+
+// ParseVersion accepts "v3" or "3".
+func ParseVersion(s string) (int, error) {
+	v, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(s)), "v"))
+	if err != nil || v < 1 {
+		return 0, fmt.Errorf("invalid version %q (expected e.g. v3)", s)
+	}
+	return v, nil
+}
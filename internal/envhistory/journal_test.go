@@ -0,0 +1,127 @@
+package envhistory
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestJournalRecord(t *testing.T) {
+	j := NewJournal(filepath.Join(t.TempDir(), "dep.jsonl"))
+
+	snaps, err := j.Snapshots()
+	if err != nil || len(snaps) != 0 {
+		t.Fatalf("empty journal: %v, %v", snaps, err)
+	}
+
+	if _, err := j.Track(map[string]string{"A": "1"}, map[string]string{"A": "2"}, "env create"); err != nil {
+		t.Fatal(err)
+	}
+	// Unchanged before, and after equal to the latest: nothing recorded.
+	snap, err := j.Track(map[string]string{"A": "2"}, map[string]string{"A": "2"}, "env import")
+	if err != nil || snap != nil {
+		t.Fatalf("Track(no-op) = %v, %v", snap, err)
+	}
+	// An edit made elsewhere is recorded as observed before the change.
+	if _, err := j.Track(map[string]string{"A": "9"}, map[string]string{}, "env unset"); err != nil {
+		t.Fatal(err)
+	}
+
+	snaps, err = j.Snapshots()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		source string
+		a      string
+	}{{SourceObserved, "1"}, {"env create", "2"}, {SourceObserved, "9"}, {"env unset", ""}}
+	if len(snaps) != len(want) {
+		t.Fatalf("got %d snapshots, want %d: %+v", len(snaps), len(want), snaps)
+	}
+	for i, w := range want {
+		if snaps[i].Version != i+1 || snaps[i].Source != w.source || snaps[i].Values["A"] != w.a {
+			t.Errorf("snapshot %d = %+v, want v%d %s A=%q", i, snaps[i], i+1, w.source, w.a)
+		}
+	}
+
+	v2, err := j.Version(2)
+	if err != nil || v2.Values["A"] != "2" {
+		t.Errorf("Version(2) = %+v, %v", v2, err)
+	}
+	if _, err := j.Version(42); err == nil {
+		t.Error("Version(42) should fail")
+	}
+}
+
+func TestJournalSkipsTornLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dep.jsonl")
+	j := NewJournal(path)
+	if _, err := j.Record(map[string]string{"A": "1"}, "env create"); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString(`{"version":2,"values":{"A"`)
+	_ = f.Close()
+
+	snaps, err := j.Snapshots()
+	if err != nil || len(snaps) != 1 {
+		t.Fatalf("Snapshots = %+v, %v; want the one complete snapshot", snaps, err)
+	}
+
+	// The next write starts on its own line rather than extending the torn one.
+	if _, err := j.Record(map[string]string{"A": "2"}, "env create"); err != nil {
+		t.Fatal(err)
+	}
+	snaps, err = j.Snapshots()
+	if err != nil || len(snaps) != 2 || snaps[1].Values["A"] != "2" {
+		t.Fatalf("Snapshots after torn line = %+v, %v", snaps, err)
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	for in, want := range map[string]int{"v3": 3, "3": 3, "V12": 12} {
+		if got, err := ParseVersion(in); err != nil || got != want {
+			t.Errorf("ParseVersion(%q) = %d, %v", in, got, err)
+		}
+	}
+	for _, in := range []string{"", "v0", "x"} {
+		if _, err := ParseVersion(in); err == nil {
+			t.Errorf("ParseVersion(%q) should fail", in)
+		}
+	}
+}
+
+func TestJournalConcurrentRecordsGetDistinctVersions(t *testing.T) {
+	j := NewJournal(filepath.Join(t.TempDir(), "dep.jsonl"))
+	const runs = 8
+	var wg sync.WaitGroup
+	for i := 0; i < runs; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Each run opens its own journal, as separate processes would.
+			if _, err := NewJournal(j.path).Record(map[string]string{"RUN": strconv.Itoa(i)}, "env set"); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	snaps, err := j.Snapshots()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snaps) != runs {
+		t.Fatalf("got %d snapshots, want %d", len(snaps), runs)
+	}
+	for i, s := range snaps {
+		if s.Version != i+1 {
+			t.Errorf("snapshot %d has version %d, want %d", i, s.Version, i+1)
+		}
+	}
+}
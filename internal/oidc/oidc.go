@@ -0,0 +1,114 @@
+// Package oidc obtains the workload identity token a CI job presents to
+// SatuSky in place of a stored API key.
+package oidc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"1ctl/internal/transport"
+)
+
+// Providers an identity token can come from.
+const (
+	ProviderGitHub = "github"
+	ProviderGitLab = "gitlab"
+	ProviderFile   = "file"
+)
+
+// DefaultAudience is the audience SatuSky trust policies expect.
+const DefaultAudience = "satusky"
+
+// GitLabTokenEnv is the variable a GitLab job should declare under
+// id_tokens with aud: satusky. CI_JOB_JWT_V2 from older GitLab versions is
+// used when it is missing.
+const GitLabTokenEnv = "SATUSKY_ID_TOKEN"
+
+var httpClient = &http.Client{Timeout: 30 * time.Second, Transport: transport.Shared()}
+
+// Token returns an identity token and the provider it came from. A
+// non-empty idTokenFile wins over detection; otherwise GitHub Actions and
+// GitLab CI are detected from their environment.
+func Token(idTokenFile, audience string) (token, provider string, err error) {
+	if audience == "" {
+		audience = DefaultAudience
+	}
+	if idTokenFile != "" {
+		data, err := os.ReadFile(idTokenFile) // #nosec G304 -- user-supplied token file
+		if err != nil {
+			return "", "", fmt.Errorf("read identity token: %w", err)
+		}
+		token = strings.TrimSpace(string(data))
+		if token == "" {
+			return "", "", fmt.Errorf("identity token file %s is empty", idTokenFile)
+		}
+		return token, ProviderFile, nil
+	}
+
+	if reqURL := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_URL"); reqURL != "" {
+		token, err := githubToken(reqURL, os.Getenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN"), audience)
+		return token, ProviderGitHub, err
+	}
+	if os.Getenv("GITHUB_ACTIONS") == "true" {
+		return "", "", fmt.Errorf("GitHub Actions did not provide an identity token: add 'permissions: id-token: write' to the workflow job")
+	}
+
+	if os.Getenv("GITLAB_CI") == "true" || os.Getenv(GitLabTokenEnv) != "" || os.Getenv("CI_JOB_JWT_V2") != "" {
+		for _, name := range []string{GitLabTokenEnv, "CI_JOB_JWT_V2"} {
+			if token := os.Getenv(name); token != "" {
+				return token, ProviderGitLab, nil
+			}
+		}
+		return "", "", fmt.Errorf("GitLab CI did not provide an identity token: declare id_tokens: { %s: { aud: %s } } on the job", GitLabTokenEnv, audience)
+	}
+
+	return "", "", fmt.Errorf("no CI identity token found: run in GitHub Actions or GitLab CI, or pass --id-token-file")
+}
+
+// githubToken asks the Actions runtime for a token with the given audience.
+func githubToken(reqURL, bearer, audience string) (string, error) {
+	if bearer == "" {
+		return "", fmt.Errorf("ACTIONS_ID_TOKEN_REQUEST_TOKEN is not set")
+	}
+	u, err := url.Parse(reqURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid ACTIONS_ID_TOKEN_REQUEST_URL: %w", err)
+	}
+	q := u.Query()
+	q.Set("audience", audience)
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+bearer)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("request GitHub identity token: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }() //nolint:errcheck
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GitHub identity token request failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	var out struct {
+		Value string `json:"value"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil || out.Value == "" {
+		return "", fmt.Errorf("GitHub returned no identity token")
+	}
+	return out.Value, nil
+}
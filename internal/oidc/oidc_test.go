@@ -0,0 +1,95 @@
+package oidc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// clearCIEnv hides the CI variables of the machine running the tests.
+func clearCIEnv(t *testing.T) {
+	t.Helper()
+	for _, name := range []string{"ACTIONS_ID_TOKEN_REQUEST_URL", "ACTIONS_ID_TOKEN_REQUEST_TOKEN", "GITHUB_ACTIONS", "GITLAB_CI", GitLabTokenEnv, "CI_JOB_JWT_V2"} {
+		t.Setenv(name, "")
+	}
+}
+
+func TestTokenFromGitHubActions(t *testing.T) {
+	clearCIEnv(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer runtime-token" {
+			t.Errorf("Authorization = %q", got)
+		}
+		if got := r.URL.Query().Get("audience"); got != "satusky" {
+			t.Errorf("audience = %q, want satusky", got)
+		}
+		if got := r.URL.Query().Get("api-version"); got != "2.0" {
+			t.Errorf("existing query lost: api-version = %q", got)
+		}
+		_, _ = w.Write([]byte(`{"value":"gh-id-token"}`)) //nolint:errcheck
+	}))
+	defer server.Close()
+	t.Setenv("GITHUB_ACTIONS", "true")
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_URL", server.URL+"/token?api-version=2.0")
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN", "runtime-token")
+
+	token, provider, err := Token("", "")
+	if err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	if token != "gh-id-token" || provider != ProviderGitHub {
+		t.Errorf("Token() = %q, %q", token, provider)
+	}
+}
+
+func TestTokenGitHubWithoutPermission(t *testing.T) {
+	clearCIEnv(t)
+	t.Setenv("GITHUB_ACTIONS", "true")
+	_, _, err := Token("", "")
+	if err == nil || !strings.Contains(err.Error(), "id-token: write") {
+		t.Errorf("Token() error = %v, want a permissions hint", err)
+	}
+}
+
+func TestTokenFromGitLab(t *testing.T) {
+	clearCIEnv(t)
+	t.Setenv("GITLAB_CI", "true")
+	t.Setenv("CI_JOB_JWT_V2", "legacy-jwt")
+	t.Setenv(GitLabTokenEnv, "gl-id-token")
+
+	token, provider, err := Token("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "gl-id-token" || provider != ProviderGitLab {
+		t.Errorf("Token() = %q, %q; want the id_tokens variable first", token, provider)
+	}
+}
+
+func TestTokenFromFileWinsOverDetection(t *testing.T) {
+	clearCIEnv(t)
+	t.Setenv("GITLAB_CI", "true")
+	t.Setenv(GitLabTokenEnv, "gl-id-token")
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("file-token\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	token, provider, err := Token(path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "file-token" || provider != ProviderFile {
+		t.Errorf("Token() = %q, %q", token, provider)
+	}
+}
+
+func TestTokenOutsideCI(t *testing.T) {
+	clearCIEnv(t)
+	if _, _, err := Token("", ""); err == nil || !strings.Contains(err.Error(), "--id-token-file") {
+		t.Errorf("Token() error = %v", err)
+	}
+}
@@ -0,0 +1,395 @@
+package secretfile
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+
+	"github.com/BurntSushi/toml"
+)
+
+const (
+	fileVersion      = 1
+	dataKeySize      = 32
+	pbkdf2Iterations = 600000
+	wrapInfo         = "1ctl secret file v1 wrap"
+	macInfo          = "1ctl secret file v1 mac"
+)
+
+// maxPBKDF2Iterations bounds the iteration count read from a file, so a
+// crafted file cannot make decryption run for hours.
+const maxPBKDF2Iterations = 10 * pbkdf2Iterations
+
+// ErrNoKey is returned when neither the identity nor a passphrase can open
+// the file.
+var ErrNoKey = errors.New("no matching key: this file is not encrypted for your identity — ask a recipient to run '1ctl secret recipients add' with your public key")
+
+var valuePattern = regexp.MustCompile(`^ENC\[AES256_GCM,data:([A-Za-z0-9+/=]+),iv:([A-Za-z0-9+/=]+)\]$`)
+
+// File is an encrypted secrets file. Values maps each plaintext key to its
+// sealed value; Satusky holds the wrapped data keys and the integrity MAC.
+type File struct {
+	Values  map[string]string `toml:"values"`
+	Satusky Metadata          `toml:"satusky"`
+}
+
+// Metadata describes how a File's data key is wrapped.
+type Metadata struct {
+	Version    int               `toml:"version"`
+	MAC        string            `toml:"mac"`
+	Recipients []RecipientStanza `toml:"recipient"`
+	Passphrase *PassphraseStanza `toml:"passphrase,omitempty"`
+}
+
+// RecipientStanza is the data key wrapped for one X25519 recipient.
+type RecipientStanza struct {
+	Recipient string `toml:"recipient"`
+	Ephemeral string `toml:"ephemeral"`
+	Key       string `toml:"key"`
+}
+
+// PassphraseStanza is the data key wrapped with a passphrase-derived key.
+type PassphraseStanza struct {
+	Salt       string `toml:"salt"`
+	Iterations int    `toml:"iterations"`
+	Key        string `toml:"key"`
+}
+
+// Encrypt seals values for every recipient and, when passphrase is not
+// empty, for the passphrase. At least one of the two is required.
+func Encrypt(values map[string]string, recipients []string, passphrase string) (*File, error) {
+	if len(recipients) == 0 && passphrase == "" {
+		return nil, errors.New("no recipients: add one with '1ctl secret recipients add' or pass --passphrase")
+	}
+
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+
+	f := &File{Values: make(map[string]string, len(values)), Satusky: Metadata{Version: fileVersion}}
+	for k, v := range values {
+		sealed, err := sealValue(dataKey, k, v)
+		if err != nil {
+			return nil, err
+		}
+		f.Values[k] = sealed
+	}
+
+	seen := make(map[string]bool)
+	for _, r := range recipients {
+		if seen[r] {
+			continue
+		}
+		seen[r] = true
+		stanza, err := wrapForRecipient(dataKey, r)
+		if err != nil {
+			return nil, err
+		}
+		f.Satusky.Recipients = append(f.Satusky.Recipients, stanza)
+	}
+	if passphrase != "" {
+		stanza, err := wrapForPassphrase(dataKey, passphrase)
+		if err != nil {
+			return nil, err
+		}
+		f.Satusky.Passphrase = stanza
+	}
+
+	mac, err := computeMAC(dataKey, f.Values)
+	if err != nil {
+		return nil, err
+	}
+	f.Satusky.MAC = mac
+	return f, nil
+}
+
+// Decrypt opens f with id when it is one of the recipients, and otherwise
+// with the passphrase returned by passphrase (called only when the file has
+// a passphrase stanza). id and passphrase may each be nil.
+func (f *File) Decrypt(id *Identity, passphrase func() (string, error)) (map[string]string, error) {
+	if f.Satusky.Version != fileVersion {
+		return nil, fmt.Errorf("unsupported secrets file version %d", f.Satusky.Version)
+	}
+	dataKey, err := f.unwrap(id, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	mac, err := computeMAC(dataKey, f.Values)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal([]byte(mac), []byte(f.Satusky.MAC)) {
+		return nil, errors.New("integrity check failed: values were added, removed or altered after encryption")
+	}
+
+	values := make(map[string]string, len(f.Values))
+	for k, sealed := range f.Values {
+		v, err := openValue(dataKey, k, sealed)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", k, err)
+		}
+		values[k] = v
+	}
+	return values, nil
+}
+
+// RecipientKeys returns the public keys f is encrypted for.
+func (f *File) RecipientKeys() []string {
+	keys := make([]string, 0, len(f.Satusky.Recipients))
+	for _, r := range f.Satusky.Recipients {
+		keys = append(keys, r.Recipient)
+	}
+	return keys
+}
+
+// HasPassphrase reports whether f can be opened with a passphrase.
+func (f *File) HasPassphrase() bool {
+	return f.Satusky.Passphrase != nil
+}
+
+// Read loads an encrypted secrets file.
+func Read(path string) (*File, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- user-supplied secrets file
+	if err != nil {
+		return nil, err
+	}
+	var f File
+	if _, err := toml.Decode(string(data), &f); err != nil {
+		return nil, fmt.Errorf("invalid secrets file: %w", err)
+	}
+	if f.Satusky.Version == 0 || f.Satusky.MAC == "" {
+		return nil, errors.New("invalid secrets file: missing [satusky] metadata — was it written by '1ctl secret encrypt'?")
+	}
+	return &f, nil
+}
+
+// Write encodes f as TOML.
+func (f *File) Write(w io.Writer) error {
+	if _, err := io.WriteString(w, "# Encrypted with 1ctl secret encrypt. Keys are plaintext; values are not.\n"); err != nil {
+		return err
+	}
+	return toml.NewEncoder(w).Encode(f)
+}
+
+func (f *File) unwrap(id *Identity, passphrase func() (string, error)) ([]byte, error) {
+	if id != nil {
+		self := id.Recipient()
+		for _, r := range f.Satusky.Recipients {
+			if r.Recipient != self {
+				continue
+			}
+			eph, err := decodeB64(r.Ephemeral)
+			if err != nil {
+				return nil, err
+			}
+			ephPub, err := ecdh.X25519().NewPublicKey(eph)
+			if err != nil {
+				return nil, err
+			}
+			shared, err := id.key.ECDH(ephPub)
+			if err != nil {
+				return nil, err
+			}
+			kek, err := hkdf.Key(sha256.New, shared, append(append([]byte{}, eph...), id.key.PublicKey().Bytes()...), wrapInfo, 32)
+			if err != nil {
+				return nil, err
+			}
+			return unwrapKey(kek, r.Key)
+		}
+	}
+
+	if p := f.Satusky.Passphrase; p != nil && passphrase != nil {
+		if p.Iterations < 1 || p.Iterations > maxPBKDF2Iterations {
+			return nil, fmt.Errorf("passphrase iteration count %d is outside 1-%d", p.Iterations, maxPBKDF2Iterations)
+		}
+		pass, err := passphrase()
+		if err != nil {
+			return nil, err
+		}
+		salt, err := decodeB64(p.Salt)
+		if err != nil {
+			return nil, err
+		}
+		kek, err := pbkdf2.Key(sha256.New, pass, salt, p.Iterations, 32)
+		if err != nil {
+			return nil, err
+		}
+		dataKey, err := unwrapKey(kek, p.Key)
+		if err != nil {
+			return nil, errors.New("incorrect passphrase")
+		}
+		return dataKey, nil
+	}
+	return nil, ErrNoKey
+}
+
+func wrapForRecipient(dataKey []byte, recipient string) (RecipientStanza, error) {
+	pub, err := ParseRecipient(recipient)
+	if err != nil {
+		return RecipientStanza{}, err
+	}
+	eph, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return RecipientStanza{}, err
+	}
+	shared, err := eph.ECDH(pub)
+	if err != nil {
+		return RecipientStanza{}, err
+	}
+	ephPub := eph.PublicKey().Bytes()
+	kek, err := hkdf.Key(sha256.New, shared, append(append([]byte{}, ephPub...), pub.Bytes()...), wrapInfo, 32)
+	if err != nil {
+		return RecipientStanza{}, err
+	}
+	wrapped, err := wrapKey(kek, dataKey)
+	if err != nil {
+		return RecipientStanza{}, err
+	}
+	return RecipientStanza{
+		Recipient: EncodeRecipient(pub),
+		Ephemeral: base64.StdEncoding.EncodeToString(ephPub),
+		Key:       wrapped,
+	}, nil
+}
+
+func wrapForPassphrase(dataKey []byte, passphrase string) (*PassphraseStanza, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	kek, err := pbkdf2.Key(sha256.New, passphrase, salt, pbkdf2Iterations, 32)
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := wrapKey(kek, dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return &PassphraseStanza{
+		Salt:       base64.StdEncoding.EncodeToString(salt),
+		Iterations: pbkdf2Iterations,
+		Key:        wrapped,
+	}, nil
+}
+
+func wrapKey(kek, dataKey []byte) (string, error) {
+	gcm, err := newGCM(kek)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, dataKey, nil)), nil
+}
+
+func unwrapKey(kek []byte, wrapped string) ([]byte, error) {
+	raw, err := decodeB64(wrapped)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(kek)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) < gcm.NonceSize() {
+		return nil, errors.New("wrapped key is truncated")
+	}
+	return gcm.Open(nil, raw[:gcm.NonceSize()], raw[gcm.NonceSize():], nil)
+}
+
+// sealValue encrypts one value. The key name is authenticated as additional
+// data so ciphertexts cannot be swapped between keys.
+func sealValue(dataKey []byte, key, value string) (string, error) {
+	gcm, err := newGCM(dataKey)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	ct := gcm.Seal(nil, nonce, []byte(value), []byte(key))
+	return fmt.Sprintf("ENC[AES256_GCM,data:%s,iv:%s]",
+		base64.StdEncoding.EncodeToString(ct), base64.StdEncoding.EncodeToString(nonce)), nil
+}
+
+func openValue(dataKey []byte, key, sealed string) (string, error) {
+	m := valuePattern.FindStringSubmatch(sealed)
+	if m == nil {
+		return "", errors.New("value is not in ENC[...] form")
+	}
+	ct, err := decodeB64(m[1])
+	if err != nil {
+		return "", err
+	}
+	nonce, err := decodeB64(m[2])
+	if err != nil {
+		return "", err
+	}
+	gcm, err := newGCM(dataKey)
+	if err != nil {
+		return "", err
+	}
+	if len(nonce) != gcm.NonceSize() {
+		return "", errors.New("invalid iv")
+	}
+	plain, err := gcm.Open(nil, nonce, ct, []byte(key))
+	if err != nil {
+		return "", errors.New("decryption failed")
+	}
+	return string(plain), nil
+}
+
+// computeMAC authenticates the full set of keys and sealed values, so
+// deleting or duplicating a line is detected as well as editing one.
+func computeMAC(dataKey []byte, sealed map[string]string) (string, error) {
+	macKey, err := hkdf.Key(sha256.New, dataKey, nil, macInfo, 32)
+	if err != nil {
+		return "", err
+	}
+	keys := make([]string, 0, len(sealed))
+	for k := range sealed {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	h := hmac.New(sha256.New, macKey)
+	for _, k := range keys {
+		h.Write([]byte(k))
+		h.Write([]byte{0})
+		h.Write([]byte(sealed[k]))
+		h.Write([]byte{0})
+	}
+	return base64.StdEncoding.EncodeToString(h.Sum(nil)), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func decodeB64(s string) ([]byte, error) {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64: %w", err)
+	}
+	return b, nil
+}
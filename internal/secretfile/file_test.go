@@ -0,0 +1,152 @@
+package secretfile
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEncryptDecryptRecipient(t *testing.T) {
+	alice, err := GenerateIdentity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := GenerateIdentity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := map[string]string{"DATABASE_URL": "postgres://u:p@db/app", "EMPTY": ""}
+
+	f, err := Encrypt(values, []string{alice.Recipient()}, "")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	for k, v := range f.Values {
+		if !strings.HasPrefix(v, "ENC[") || (values[k] != "" && strings.Contains(v, values[k])) {
+			t.Errorf("value for %s is not sealed: %q", k, v)
+		}
+	}
+
+	got, err := f.Decrypt(alice, nil)
+	if err != nil {
+		t.Fatalf("Decrypt: %v", err)
+	}
+	if !reflect.DeepEqual(got, values) {
+		t.Errorf("Decrypt = %v, want %v", got, values)
+	}
+
+	if _, err := f.Decrypt(bob, nil); !errors.Is(err, ErrNoKey) {
+		t.Errorf("Decrypt with non-recipient: got %v, want ErrNoKey", err)
+	}
+}
+
+func TestEncryptDecryptPassphrase(t *testing.T) {
+	f, err := Encrypt(map[string]string{"A": "1"}, nil, "correct horse")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	got, err := f.Decrypt(nil, func() (string, error) { return "correct horse", nil })
+	if err != nil || got["A"] != "1" {
+		t.Fatalf("Decrypt = %v, %v", got, err)
+	}
+	if _, err := f.Decrypt(nil, func() (string, error) { return "wrong", nil }); err == nil {
+		t.Error("expected wrong passphrase to fail")
+	}
+}
+
+func TestDecryptRejectsExcessiveIterations(t *testing.T) {
+	f, err := Encrypt(map[string]string{"A": "1"}, nil, "correct horse")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	f.Satusky.Passphrase.Iterations = 1 << 40
+	asked := false
+	_, err = f.Decrypt(nil, func() (string, error) { asked = true; return "correct horse", nil })
+	if err == nil || !strings.Contains(err.Error(), "iteration count") {
+		t.Errorf("Decrypt error = %v, want the iteration count rejected", err)
+	}
+	if asked {
+		t.Error("asked for the passphrase of a file that cannot be opened")
+	}
+}
+
+func TestDecryptDetectsTampering(t *testing.T) {
+	id, err := GenerateIdentity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := Encrypt(map[string]string{"A": "1", "B": "2"}, []string{id.Recipient()}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	removed := *f
+	removed.Values = map[string]string{"A": f.Values["A"]}
+	if _, err := removed.Decrypt(id, nil); err == nil {
+		t.Error("expected removing a key to fail the integrity check")
+	}
+
+	swapped := *f
+	swapped.Values = map[string]string{"A": f.Values["B"], "B": f.Values["A"]}
+	if _, err := swapped.Decrypt(id, nil); err == nil {
+		t.Error("expected swapping values between keys to fail")
+	}
+}
+
+func TestFileRoundTrip(t *testing.T) {
+	id, err := GenerateIdentity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := Encrypt(map[string]string{"KEY": "value"}, []string{id.Recipient()}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "secrets.enc.toml")
+	if err := os.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+	read, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	got, err := read.Decrypt(id, nil)
+	if err != nil || got["KEY"] != "value" {
+		t.Fatalf("Decrypt after round trip = %v, %v", got, err)
+	}
+	if !strings.Contains(buf.String(), "KEY = ") {
+		t.Errorf("expected plaintext key in file:\n%s", buf.String())
+	}
+}
+
+func TestRecipientsFile(t *testing.T) {
+	id, err := GenerateIdentity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), RecipientsFileName)
+	want := []Recipient{{Key: id.Recipient(), Comment: "alice"}}
+	if err := WriteRecipients(path, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadRecipients(path)
+	if err != nil {
+		t.Fatalf("ReadRecipients: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadRecipients = %v, want %v", got, want)
+	}
+
+	parsed, err := ParseIdentity(id.String())
+	if err != nil || parsed.Recipient() != id.Recipient() {
+		t.Errorf("ParseIdentity round trip failed: %v", err)
+	}
+}
@@ -0,0 +1,196 @@
+// Package secretfile encrypts KEY=VALUE bundles into a TOML file that can be
+// committed to a repository. Keys stay readable so changes can be reviewed in
+// a diff; each value is sealed with AES-256-GCM under a per-file data key,
+// and the data key is wrapped for every recipient (X25519) and, optionally,
+// for a passphrase (PBKDF2-SHA256).
+//
+// The format is specific to 1ctl. It follows the same construction as
+// age/sops but is not interchangeable with them.
+package secretfile
+
+import (
+	"bufio"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	publicKeyPrefix  = "sky-x25519:"
+	identityPrefix   = "SKY-X25519-SECRET:"
+	identityFileName = "secret.key"
+
+	// RecipientsFileName is the repo-level list of public keys that
+	// "1ctl secret encrypt" seals files for.
+	RecipientsFileName = ".satusky-recipients"
+
+	// IdentityEnv overrides the identity file with an identity string,
+	// for CI where there is no home directory to keep it in.
+	IdentityEnv = "SATUSKY_SECRET_KEY"
+	// PassphraseEnv supplies the passphrase non-interactively.
+	PassphraseEnv = "SATUSKY_SECRET_PASSPHRASE"
+)
+
+// Identity is an X25519 private key able to decrypt files sealed for its
+// public key.
+type Identity struct {
+	key *ecdh.PrivateKey
+}
+
+// GenerateIdentity creates a new random identity.
+func GenerateIdentity() (*Identity, error) {
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &Identity{key: key}, nil
+}
+
+// ParseIdentity decodes an identity string as produced by Identity.String.
+func ParseIdentity(s string) (*Identity, error) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, identityPrefix) {
+		return nil, fmt.Errorf("invalid identity: expected %s prefix", identityPrefix)
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(s, identityPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("invalid identity: %w", err)
+	}
+	key, err := ecdh.X25519().NewPrivateKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid identity: %w", err)
+	}
+	return &Identity{key: key}, nil
+}
+
+// String returns the identity in its storable text form.
+func (id *Identity) String() string {
+	return identityPrefix + base64.RawURLEncoding.EncodeToString(id.key.Bytes())
+}
+
+// Recipient returns the public key files are sealed for.
+func (id *Identity) Recipient() string {
+	return EncodeRecipient(id.key.PublicKey())
+}
+
+// EncodeRecipient returns the text form of an X25519 public key.
+func EncodeRecipient(pub *ecdh.PublicKey) string {
+	return publicKeyPrefix + base64.RawURLEncoding.EncodeToString(pub.Bytes())
+}
+
+// ParseRecipient decodes a public key as produced by Identity.Recipient.
+func ParseRecipient(s string) (*ecdh.PublicKey, error) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, publicKeyPrefix) {
+		return nil, fmt.Errorf("invalid recipient %q: expected %s prefix", s, publicKeyPrefix)
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(s, publicKeyPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient %q: %w", s, err)
+	}
+	pub, err := ecdh.X25519().NewPublicKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient %q: %w", s, err)
+	}
+	return pub, nil
+}
+
+// IdentityPath returns ~/.satusky/secret.key.
+func IdentityPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".satusky", identityFileName), nil
+}
+
+// LoadIdentity returns the identity from SATUSKY_SECRET_KEY or, failing
+// that, ~/.satusky/secret.key. It returns nil without error when neither
+// exists, so passphrase-only files can still be decrypted.
+func LoadIdentity() (*Identity, error) {
+	if s := os.Getenv(IdentityEnv); s != "" {
+		return ParseIdentity(s)
+	}
+	path, err := IdentityPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path) // #nosec G304 -- fixed path under the user's home
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ParseIdentity(string(data))
+}
+
+// SaveIdentity writes id to path with owner-only permissions. It refuses to
+// overwrite an existing identity unless force is set, since files sealed
+// only for the old key would become unreadable.
+func SaveIdentity(id *Identity, path string, force bool) error {
+	if !force {
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("%s already exists", path)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(id.String()+"\n"), 0600)
+}
+
+// Recipient is one entry of a recipients file.
+type Recipient struct {
+	Key     string
+	Comment string
+}
+
+// ReadRecipients parses a recipients file: one public key per line,
+// optionally followed by a "# comment". Blank lines and comment-only lines
+// are skipped. A missing file yields no recipients.
+func ReadRecipients(path string) ([]Recipient, error) {
+	f, err := os.Open(path) // #nosec G304 -- recipients file in the repository
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }() //nolint:errcheck
+
+	var recipients []Recipient
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, comment, _ := strings.Cut(line, "#")
+		key = strings.TrimSpace(key)
+		if _, err := ParseRecipient(key); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		recipients = append(recipients, Recipient{Key: key, Comment: strings.TrimSpace(comment)})
+	}
+	return recipients, scanner.Err()
+}
+
+// WriteRecipients replaces the recipients file at path.
+func WriteRecipients(path string, recipients []Recipient) error {
+	var b strings.Builder
+	b.WriteString("# Public keys that 1ctl secret encrypt seals files for.\n")
+	b.WriteString("# Manage with: 1ctl secret recipients add|remove\n")
+	for _, r := range recipients {
+		b.WriteString(r.Key)
+		if r.Comment != "" {
+			b.WriteString(" # " + r.Comment)
+		}
+		b.WriteString("\n")
+	}
+	return os.WriteFile(path, []byte(b.String()), 0644) // #nosec G306 -- public keys, meant to be committed
+}
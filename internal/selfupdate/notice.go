@@ -0,0 +1,72 @@
+package selfupdate
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"1ctl/internal/context"
+	"1ctl/internal/version"
+)
+
+// NoticeEnv turns off the daily "new version available" notice when set.
+const NoticeEnv = "SATUSKY_NO_UPDATE_NOTIFIER"
+
+// noticeInterval is how often the notice checks the release feed.
+const noticeInterval = 24 * time.Hour
+
+// noticeTimeout bounds the check, which runs after the command.
+const noticeTimeout = 2 * time.Second
+
+// noticeState is the last check, kept in <configDir>/update-check.json.
+type noticeState struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Latest    string    `json:"latest,omitempty"`
+}
+
+// NoticeFile returns where the last check is kept.
+func NoticeFile() string {
+	return filepath.Join(context.Default().ConfigDir(), "update-check.json")
+}
+
+// Notice checks the release feed if it has not in a day and returns a
+// message when the check found a version newer than currentVersion. It
+// returns "" otherwise, including when the feed cannot be reached: the
+// notice is advice, not a failure.
+func (u *Updater) Notice(stateFile, currentVersion string, now time.Time) string {
+	var state noticeState
+	if data, err := os.ReadFile(stateFile); err == nil { // #nosec G304 -- file under the config dir
+		_ = json.Unmarshal(data, &state) //nolint:errcheck // a damaged file is checked again
+	}
+	if now.Sub(state.CheckedAt) < noticeInterval {
+		return ""
+	}
+
+	checker := *u
+	client := &http.Client{Timeout: noticeTimeout}
+	if u.HTTPClient != nil {
+		client.Transport = u.HTTPClient.Transport
+	}
+	checker.HTTPClient = client
+	state = noticeState{CheckedAt: now}
+	if rel, err := checker.Latest(); err == nil {
+		state.Latest = rel.Tag
+	}
+	if data, err := json.Marshal(state); err == nil {
+		if err := os.MkdirAll(filepath.Dir(stateFile), 0700); err == nil {
+			_ = context.WriteFileAtomic(stateFile, data, 0600) //nolint:errcheck // checked again tomorrow
+		}
+	}
+
+	if cmp, ok := version.Compare(currentVersion, state.Latest); !ok || cmp >= 0 {
+		return ""
+	}
+	how := "Run '1ctl update' to upgrade"
+	if u.IsHomebrew() {
+		how = "Run 'brew upgrade satuctl' to upgrade"
+	}
+	return fmt.Sprintf("1ctl %s is available (you have %s). %s, or set %s=1 to stop these notices.", state.Latest, currentVersion, how, NoticeEnv)
+}
@@ -0,0 +1,360 @@
+// Package selfupdate replaces the running 1ctl with a release from the
+// release feed.
+//
+// A release is trusted only when its checksums file carries a valid
+// ed25519 signature from the release key, whose public half is built into
+// release binaries, and the downloaded archive matches its checksum there.
+// The replaced binary is kept next to the new one as <binary>.old, so an
+// update can be rolled back.
+package selfupdate
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"1ctl/internal/transport"
+	"1ctl/internal/utils"
+)
+
+// DefaultReleasesURL is the release feed: the GitHub releases API of the
+// 1ctl repository.
+const DefaultReleasesURL = "https://api.github.com/repos/SatuSkyCloud/1ctl/releases"
+
+// ReleasesURLEnv overrides the release feed, e.g. with a local server for
+// testing.
+const ReleasesURLEnv = "SATUSKY_RELEASES_URL"
+
+// PublicKey is the base64 ed25519 public key that release checksums are
+// signed with. Release builds set it with
+// -ldflags "-X 1ctl/internal/selfupdate.PublicKey=...".
+var PublicKey = ""
+
+// maxDownload bounds every download, so a broken feed cannot fill the disk.
+const maxDownload = 200 << 20
+
+// Release is a release in the feed.
+type Release struct {
+	Tag    string  `json:"tag_name"`
+	Assets []Asset `json:"assets"`
+}
+
+// Asset is a file attached to a release.
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// Version returns the release's version without the leading "v", as used
+// in its file names.
+func (r *Release) Version() string {
+	return strings.TrimPrefix(r.Tag, "v")
+}
+
+func (r *Release) asset(name string) (Asset, bool) {
+	for _, a := range r.Assets {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return Asset{}, false
+}
+
+// Updater finds, verifies and installs releases.
+type Updater struct {
+	// ReleasesURL is the release feed.
+	ReleasesURL string
+	// PublicKey verifies the signature of a release's checksums.
+	PublicKey ed25519.PublicKey
+	// Executable is the binary to replace.
+	Executable string
+	// GOOS and GOARCH select the archive to install.
+	GOOS, GOARCH string
+	// HTTPClient downloads the feed and the release files.
+	HTTPClient *http.Client
+}
+
+// New returns an Updater for the running binary, the release feed and the
+// built-in release key.
+func New() (*Updater, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, utils.NewError("failed to find the running 1ctl", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	u := &Updater{
+		ReleasesURL: DefaultReleasesURL,
+		Executable:  exe,
+		GOOS:        runtime.GOOS,
+		GOARCH:      runtime.GOARCH,
+		HTTPClient:  &http.Client{Timeout: 5 * time.Minute, Transport: transport.Shared()},
+	}
+	if feed := os.Getenv(ReleasesURLEnv); feed != "" {
+		u.ReleasesURL = feed
+	}
+	if PublicKey != "" {
+		key, err := base64.StdEncoding.DecodeString(PublicKey)
+		if err != nil || len(key) != ed25519.PublicKeySize {
+			return nil, utils.NewError("this build's release signing key is invalid", err)
+		}
+		u.PublicKey = key
+	}
+	return u, nil
+}
+
+// IsHomebrew reports whether the binary was installed by Homebrew, which
+// must then also update it.
+func (u *Updater) IsHomebrew() bool {
+	path := filepath.ToSlash(u.Executable)
+	for _, dir := range []string{"/Cellar/", "/Homebrew/", "/homebrew/", "/linuxbrew/"} {
+		if strings.Contains(path, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// Latest returns the newest release.
+func (u *Updater) Latest() (*Release, error) {
+	return u.fetchRelease(strings.TrimSuffix(u.ReleasesURL, "/") + "/latest")
+}
+
+// Release returns the release tagged tag, e.g. v1.4.2.
+func (u *Updater) Release(tag string) (*Release, error) {
+	if !strings.HasPrefix(tag, "v") {
+		tag = "v" + tag
+	}
+	return u.fetchRelease(strings.TrimSuffix(u.ReleasesURL, "/") + "/tags/" + tag)
+}
+
+func (u *Updater) fetchRelease(url string) (*Release, error) {
+	body, err := u.download(url)
+	if err != nil {
+		return nil, err
+	}
+	var rel Release
+	if err := json.Unmarshal(body, &rel); err != nil || rel.Tag == "" {
+		return nil, utils.NewError(fmt.Sprintf("unexpected response from the release feed %s", url), err)
+	}
+	return &rel, nil
+}
+
+// ArchiveName returns the name of rel's archive for the updater's OS and
+// architecture.
+func (u *Updater) ArchiveName(rel *Release) string {
+	ext := ".tar.gz"
+	if u.GOOS == "windows" {
+		ext = ".zip"
+	}
+	return fmt.Sprintf("1ctl-%s-%s-%s%s", rel.Version(), u.GOOS, u.GOARCH, ext)
+}
+
+// Install downloads rel, verifies it and replaces the binary with it,
+// keeping the replaced binary as <binary>.old.
+func (u *Updater) Install(rel *Release) error {
+	if len(u.PublicKey) == 0 {
+		return utils.NewError("this build of 1ctl has no release signing key, so it cannot verify updates. Install releases from https://github.com/SatuSkyCloud/1ctl/releases", nil)
+	}
+
+	checksums, err := u.downloadAsset(rel, fmt.Sprintf("1ctl-%s-checksums.sha256", rel.Version()))
+	if err != nil {
+		return err
+	}
+	signature, err := u.downloadAsset(rel, fmt.Sprintf("1ctl-%s-checksums.sha256.sig", rel.Version()))
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(u.PublicKey, checksums, decodeSignature(signature)) {
+		return utils.NewError(fmt.Sprintf("the checksums of %s are not signed by the 1ctl release key; not installing", rel.Tag), nil)
+	}
+
+	name := u.ArchiveName(rel)
+	want, ok := checksumFor(checksums, name)
+	if !ok {
+		return utils.NewError(fmt.Sprintf("%s has no checksum for %s", rel.Tag, name), nil)
+	}
+	archive, err := u.downloadAsset(rel, name)
+	if err != nil {
+		return err
+	}
+	if sum := sha256.Sum256(archive); hex.EncodeToString(sum[:]) != want {
+		return utils.NewError(fmt.Sprintf("%s does not match its signed checksum; not installing", name), nil)
+	}
+
+	binary, err := extractBinary(archive, u.GOOS == "windows")
+	if err != nil {
+		return utils.NewError(fmt.Sprintf("failed to unpack %s", name), err)
+	}
+	return u.replace(binary)
+}
+
+// Rollback swaps the binary with the one the last update replaced, so a
+// second rollback undoes the first.
+func (u *Updater) Rollback() error {
+	old := u.Executable + ".old"
+	if _, err := os.Stat(old); err != nil {
+		return utils.NewError("no previous version to roll back to", nil)
+	}
+	tmp := u.Executable + ".rollback"
+	if err := os.Rename(u.Executable, tmp); err != nil {
+		return utils.NewError("failed to roll back", err)
+	}
+	if err := os.Rename(old, u.Executable); err != nil {
+		_ = os.Rename(tmp, u.Executable) //nolint:errcheck
+		return utils.NewError("failed to roll back", err)
+	}
+	if err := os.Rename(tmp, old); err != nil {
+		return utils.NewError("rolled back, but failed to keep the newer version", err)
+	}
+	return nil
+}
+
+// replace writes binary next to the executable and renames it into place,
+// so the executable is never partly written.
+func (u *Updater) replace(binary []byte) error {
+	dir := filepath.Dir(u.Executable)
+	tmp, err := os.CreateTemp(dir, ".1ctl-update-*")
+	if err != nil {
+		return utils.NewError(fmt.Sprintf("failed to write to %s; run the update with permission to change it", dir), err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }() //nolint:errcheck
+	if _, err := tmp.Write(binary); err != nil {
+		_ = tmp.Close() //nolint:errcheck
+		return utils.NewError("failed to write the new binary", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return utils.NewError("failed to write the new binary", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0755); err != nil { // #nosec G302 -- an executable
+		return utils.NewError("failed to make the new binary executable", err)
+	}
+
+	old := u.Executable + ".old"
+	_ = os.Remove(old) //nolint:errcheck
+	if err := os.Rename(u.Executable, old); err != nil {
+		return utils.NewError("failed to keep the current binary", err)
+	}
+	if err := os.Rename(tmp.Name(), u.Executable); err != nil {
+		_ = os.Rename(old, u.Executable) //nolint:errcheck
+		return utils.NewError("failed to install the new binary", err)
+	}
+	return nil
+}
+
+func (u *Updater) downloadAsset(rel *Release, name string) ([]byte, error) {
+	asset, ok := rel.asset(name)
+	if !ok {
+		return nil, utils.NewError(fmt.Sprintf("%s has no %s", rel.Tag, name), nil)
+	}
+	return u.download(asset.URL)
+}
+
+func (u *Updater) download(url string) ([]byte, error) {
+	client := u.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, utils.NewError("failed to create request", err)
+	}
+	req.Header.Set("User-Agent", "1ctl-update")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, utils.NewKindError(utils.ErrNetwork, fmt.Sprintf("failed to download %s", url), err)
+	}
+	defer func() { _ = resp.Body.Close() }() //nolint:errcheck
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, utils.NewKindError(utils.ErrNotFound, fmt.Sprintf("%s not found", url), nil)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, utils.NewError(fmt.Sprintf("failed to download %s: %s", url, resp.Status), nil)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxDownload))
+	if err != nil {
+		return nil, utils.NewKindError(utils.ErrNetwork, fmt.Sprintf("failed to download %s", url), err)
+	}
+	return body, nil
+}
+
+// decodeSignature accepts a raw 64-byte signature, as openssl writes it,
+// or its base64 encoding.
+func decodeSignature(sig []byte) []byte {
+	if len(sig) == ed25519.SignatureSize {
+		return sig
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+	if err != nil {
+		return nil
+	}
+	return decoded
+}
+
+// checksumFor finds name in a sha256sum-style checksums file.
+func checksumFor(checksums []byte, name string) (string, bool) {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), true
+		}
+	}
+	return "", false
+}
+
+// extractBinary returns the 1ctl binary in a release archive.
+func extractBinary(archive []byte, isZip bool) ([]byte, error) {
+	if isZip {
+		zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range zr.File {
+			if filepath.Base(f.Name) == "1ctl.exe" {
+				rc, err := f.Open()
+				if err != nil {
+					return nil, err
+				}
+				defer func() { _ = rc.Close() }() //nolint:errcheck
+				return io.ReadAll(io.LimitReader(rc, maxDownload))
+			}
+		}
+		return nil, errors.New("no 1ctl.exe in the archive")
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, err
+	}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil, errors.New("no 1ctl in the archive")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag == tar.TypeReg && filepath.Base(hdr.Name) == "1ctl" {
+			return io.ReadAll(io.LimitReader(tr, maxDownload))
+		}
+	}
+}
@@ -0,0 +1,321 @@
+package selfupdate
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// releaseServer is a fake release feed serving GitHub-style release JSON
+// and signed release files.
+type releaseServer struct {
+	*httptest.Server
+	key      ed25519.PrivateKey
+	releases map[string]map[string][]byte // tag -> file name -> contents
+	latest   string
+	requests atomic.Int32
+}
+
+func newReleaseServer(t *testing.T) *releaseServer {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &releaseServer{key: key, releases: map[string]map[string][]byte{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *releaseServer) serve(w http.ResponseWriter, r *http.Request) {
+	s.requests.Add(1)
+	path := r.URL.Path
+	switch {
+	case path == "/releases/latest":
+		s.writeRelease(w, s.latest)
+	case strings.HasPrefix(path, "/releases/tags/"):
+		s.writeRelease(w, strings.TrimPrefix(path, "/releases/tags/"))
+	case strings.HasPrefix(path, "/download/"):
+		parts := strings.SplitN(strings.TrimPrefix(path, "/download/"), "/", 2)
+		if body, ok := s.releases[parts[0]][parts[1]]; ok && len(parts) == 2 {
+			_, _ = w.Write(body) //nolint:errcheck
+			return
+		}
+		http.NotFound(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *releaseServer) writeRelease(w http.ResponseWriter, tag string) {
+	files, ok := s.releases[tag]
+	if !ok {
+		http.NotFound(w, nil)
+		return
+	}
+	rel := Release{Tag: tag}
+	for name := range files {
+		rel.Assets = append(rel.Assets, Asset{Name: name, URL: fmt.Sprintf("%s/download/%s/%s", s.URL, tag, name)})
+	}
+	_ = json.NewEncoder(w).Encode(rel) //nolint:errcheck
+}
+
+// publish adds a release whose linux and windows archives hold a binary
+// containing "1ctl <tag>", with signed checksums, and makes it the latest.
+func (s *releaseServer) publish(t *testing.T, tag string) map[string][]byte {
+	t.Helper()
+	v := strings.TrimPrefix(tag, "v")
+	files := map[string][]byte{
+		fmt.Sprintf("1ctl-%s-linux-amd64.tar.gz", v): tarGz(t, "1ctl", "1ctl "+tag),
+		fmt.Sprintf("1ctl-%s-windows-amd64.zip", v):  zipped(t, "1ctl.exe", "1ctl "+tag),
+	}
+	var checksums bytes.Buffer
+	for name, body := range files {
+		sum := sha256.Sum256(body)
+		fmt.Fprintf(&checksums, "%s  %s\n", hex.EncodeToString(sum[:]), name)
+	}
+	files[fmt.Sprintf("1ctl-%s-checksums.sha256", v)] = checksums.Bytes()
+	files[fmt.Sprintf("1ctl-%s-checksums.sha256.sig", v)] = ed25519.Sign(s.key, checksums.Bytes())
+	s.releases[tag] = files
+	s.latest = tag
+	return files
+}
+
+func (s *releaseServer) updater(t *testing.T, goos string) *Updater {
+	t.Helper()
+	exe := filepath.Join(t.TempDir(), "1ctl")
+	if err := os.WriteFile(exe, []byte("1ctl v1.0.0"), 0755); err != nil { // #nosec G306 -- test binary
+		t.Fatal(err)
+	}
+	return &Updater{
+		ReleasesURL: s.URL + "/releases",
+		PublicKey:   s.key.Public().(ed25519.PublicKey),
+		Executable:  exe,
+		GOOS:        goos,
+		GOARCH:      "amd64",
+		HTTPClient:  s.Client(),
+	}
+}
+
+func tarGz(t *testing.T, name, contents string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(contents)), Typeflag: tar.TypeReg}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tw.Write([]byte(contents)); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func zipped(t *testing.T, name, contents string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	f, err := zw.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte(contents)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path) // #nosec G304 -- test file
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestInstallAndRollback(t *testing.T) {
+	s := newReleaseServer(t)
+	s.publish(t, "v1.1.0")
+	s.publish(t, "v1.2.0")
+	u := s.updater(t, "linux")
+
+	rel, err := u.Latest()
+	if err != nil {
+		t.Fatalf("Latest() error = %v", err)
+	}
+	if err := u.Install(rel); err != nil {
+		t.Fatalf("Install(%s) error = %v", rel.Tag, err)
+	}
+	if got := readFile(t, u.Executable); got != "1ctl v1.2.0" {
+		t.Errorf("binary = %q, want v1.2.0", got)
+	}
+	if info, err := os.Stat(u.Executable); err != nil || info.Mode().Perm()&0100 == 0 {
+		t.Errorf("binary is not executable: %v, %v", info, err)
+	}
+	if got := readFile(t, u.Executable+".old"); got != "1ctl v1.0.0" {
+		t.Errorf("kept binary = %q, want v1.0.0", got)
+	}
+
+	if err := u.Rollback(); err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+	if got := readFile(t, u.Executable); got != "1ctl v1.0.0" {
+		t.Errorf("binary after rollback = %q, want v1.0.0", got)
+	}
+	if err := u.Rollback(); err != nil {
+		t.Fatalf("second Rollback() error = %v", err)
+	}
+	if got := readFile(t, u.Executable); got != "1ctl v1.2.0" {
+		t.Errorf("binary after undoing the rollback = %q, want v1.2.0", got)
+	}
+}
+
+func TestInstallSpecificVersionOnWindows(t *testing.T) {
+	s := newReleaseServer(t)
+	s.publish(t, "v1.1.0")
+	s.publish(t, "v1.2.0")
+	u := s.updater(t, "windows")
+
+	rel, err := u.Release("1.1.0")
+	if err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if err := u.Install(rel); err != nil {
+		t.Fatalf("Install(%s) error = %v", rel.Tag, err)
+	}
+	if got := readFile(t, u.Executable); got != "1ctl v1.1.0" {
+		t.Errorf("binary = %q, want v1.1.0", got)
+	}
+}
+
+func TestInstallRejectsUnverifiedReleases(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(s *releaseServer, u *Updater, files map[string][]byte)
+		want   string
+	}{
+		{
+			name: "signed by another key",
+			tamper: func(s *releaseServer, u *Updater, files map[string][]byte) {
+				other, _, _ := ed25519.GenerateKey(rand.Reader) //nolint:errcheck
+				u.PublicKey = other
+			},
+			want: "not signed",
+		},
+		{
+			name: "archive replaced",
+			tamper: func(s *releaseServer, u *Updater, files map[string][]byte) {
+				files["1ctl-1.2.0-linux-amd64.tar.gz"] = tarGz(t, "1ctl", "evil")
+			},
+			want: "does not match",
+		},
+		{
+			name: "checksums changed after signing",
+			tamper: func(s *releaseServer, u *Updater, files map[string][]byte) {
+				sum := sha256.Sum256(files["1ctl-1.2.0-linux-amd64.tar.gz"])
+				files["1ctl-1.2.0-checksums.sha256"] = []byte(hex.EncodeToString(sum[:]) + "  1ctl-1.2.0-linux-amd64.tar.gz\n")
+			},
+			want: "not signed",
+		},
+		{
+			name: "no signature",
+			tamper: func(s *releaseServer, u *Updater, files map[string][]byte) {
+				delete(files, "1ctl-1.2.0-checksums.sha256.sig")
+			},
+			want: "has no 1ctl-1.2.0-checksums.sha256.sig",
+		},
+		{
+			name: "build without a release key",
+			tamper: func(s *releaseServer, u *Updater, files map[string][]byte) {
+				u.PublicKey = nil
+			},
+			want: "no release signing key",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newReleaseServer(t)
+			files := s.publish(t, "v1.2.0")
+			u := s.updater(t, "linux")
+			tt.tamper(s, u, files)
+
+			rel, err := u.Latest()
+			if err != nil {
+				t.Fatalf("Latest() error = %v", err)
+			}
+			err = u.Install(rel)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Install() error = %v, want %q", err, tt.want)
+			}
+			if got := readFile(t, u.Executable); got != "1ctl v1.0.0" {
+				t.Errorf("binary = %q after a rejected update", got)
+			}
+			if _, err := os.Stat(u.Executable + ".old"); !os.IsNotExist(err) {
+				t.Errorf("a rejected update left %s.old", u.Executable)
+			}
+		})
+	}
+}
+
+func TestIsHomebrew(t *testing.T) {
+	for path, want := range map[string]bool{
+		"/opt/homebrew/Cellar/satuctl/1.2.0/bin/1ctl":              true,
+		"/usr/local/Cellar/satuctl/1.2.0/bin/1ctl":                 true,
+		"/home/linuxbrew/.linuxbrew/Cellar/satuctl/1.2.0/bin/1ctl": true,
+		"/usr/local/bin/1ctl":                                      false,
+		"/home/dev/go/bin/1ctl":                                    false,
+	} {
+		if got := (&Updater{Executable: path}).IsHomebrew(); got != want {
+			t.Errorf("IsHomebrew(%s) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestNoticeChecksOnceADay(t *testing.T) {
+	s := newReleaseServer(t)
+	s.publish(t, "v1.2.0")
+	u := s.updater(t, "linux")
+	state := filepath.Join(t.TempDir(), "update-check.json")
+	now := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+
+	if got := u.Notice(state, "v1.0.0", now); !strings.Contains(got, "1ctl v1.2.0 is available") || !strings.Contains(got, "1ctl update") {
+		t.Errorf("Notice() = %q, want v1.2.0 announced", got)
+	}
+	if got := u.Notice(state, "v1.0.0", now.Add(time.Hour)); got != "" || s.requests.Load() != 1 {
+		t.Errorf("second Notice() on the same day = %q after %d requests, want nothing and no request", got, s.requests.Load())
+	}
+	if got := u.Notice(state, "v1.2.0", now.Add(25*time.Hour)); got != "" || s.requests.Load() != 2 {
+		t.Errorf("Notice() when up to date = %q after %d requests, want nothing after a new check", got, s.requests.Load())
+	}
+
+	s.Close()
+	if got := u.Notice(state, "v1.0.0", now.Add(50*time.Hour)); got != "" {
+		t.Errorf("Notice() with the feed down = %q, want nothing", got)
+	}
+}
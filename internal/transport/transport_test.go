@@ -0,0 +1,153 @@
+package transport
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"1ctl/internal/utils"
+)
+
+func writePEM(t *testing.T, name, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// clientCert writes a self-signed client certificate and its key.
+func clientCert(t *testing.T) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "1ctl-test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return writePEM(t, "client.crt", "CERTIFICATE", der), writePEM(t, "client.key", "EC PRIVATE KEY", keyDER)
+}
+
+func configure(t *testing.T, cfg Config) {
+	t.Helper()
+	if err := Configure(cfg); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	t.Cleanup(func() { _ = Configure(Config{}) })
+}
+
+func get200(t *testing.T, url string) error {
+	t.Helper()
+	resp, err := (&http.Client{Transport: Shared()}).Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200", resp.StatusCode)
+	}
+	return nil
+}
+
+func TestCAFileIsTrusted(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	if err := get200(t, server.URL); err == nil {
+		t.Fatal("request to a server with an unknown CA succeeded")
+	}
+
+	caFile := writePEM(t, "ca.pem", "CERTIFICATE", server.Certificate().Raw)
+	configure(t, Config{CAFiles: []string{"", caFile}})
+	if err := get200(t, server.URL); err != nil {
+		t.Fatalf("request with --ca-file: %v", err)
+	}
+	if d := WebSocketDialer(); d.TLSClientConfig == nil || d.TLSClientConfig.RootCAs == nil {
+		t.Error("WebSocket dialer does not use the CA file")
+	}
+}
+
+func TestClientCertificate(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) == 0 {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+	caFile := writePEM(t, "ca.pem", "CERTIFICATE", server.Certificate().Raw)
+
+	configure(t, Config{CAFiles: []string{caFile}})
+	if err := get200(t, server.URL); err == nil {
+		t.Fatal("request without a client certificate succeeded")
+	}
+
+	certFile, keyFile := clientCert(t)
+	configure(t, Config{CAFiles: []string{caFile}, ClientCert: certFile, ClientKey: keyFile})
+	if err := get200(t, server.URL); err != nil {
+		t.Fatalf("request with a client certificate: %v", err)
+	}
+}
+
+func TestConfigureRejectsBadFiles(t *testing.T) {
+	certFile, _ := clientCert(t)
+	notPEM := filepath.Join(t.TempDir(), "ca.txt")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, cfg := range map[string]Config{
+		"missing CA file":  {CAFiles: []string{filepath.Join(t.TempDir(), "missing.pem")}},
+		"CA file not PEM":  {CAFiles: []string{notPEM}},
+		"cert without key": {ClientCert: certFile},
+		"key is the cert":  {ClientCert: certFile, ClientKey: certFile},
+	} {
+		t.Run(name, func(t *testing.T) {
+			before := get()
+			err := Configure(cfg)
+			if got := utils.KindOf(err); got != utils.ErrUsage {
+				t.Errorf("Configure() error = %v, kind %q, want %q", err, got, utils.ErrUsage)
+			}
+			if get() != before {
+				t.Error("failed Configure replaced the transport")
+			}
+		})
+	}
+}
+
+func TestProxyFromEnvironment(t *testing.T) {
+	certFile, keyFile := clientCert(t)
+	configure(t, Config{ClientCert: certFile, ClientKey: keyFile})
+	if get().Proxy == nil {
+		t.Error("transport ignores HTTPS_PROXY")
+	}
+	if WebSocketDialer().Proxy == nil {
+		t.Error("WebSocket dialer ignores HTTPS_PROXY")
+	}
+}
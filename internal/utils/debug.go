@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// DebugEnv turns on debug output when set to a non-empty value other than
+// "0" or "false".
+const DebugEnv = "SATUSKY_DEBUG"
+
+var (
+	debugMu  sync.RWMutex
+	debugOn            = debugFromEnv()
+	debugOut io.Writer = os.Stderr
+)
+
+func debugFromEnv() bool {
+	v := os.Getenv(DebugEnv)
+	return v != "" && v != "0" && v != "false"
+}
+
+// SetDebug turns debug output on or off for this process.
+func SetDebug(on bool) {
+	debugMu.Lock()
+	defer debugMu.Unlock()
+	debugOn = on
+}
+
+// IsDebug reports whether debug output is on.
+func IsDebug() bool {
+	debugMu.RLock()
+	defer debugMu.RUnlock()
+	return debugOn
+}
+
+// Debugf prints a redacted debug line to stderr when debug output is on.
+func Debugf(format string, a ...interface{}) {
+	debugMu.RLock()
+	on, out := debugOn, debugOut
+	debugMu.RUnlock()
+	if !on {
+		return
+	}
+	_, _ = fmt.Fprintf(out, "[debug] %s\n", Redact(fmt.Sprintf(format, a...))) //nolint:errcheck
+}
+
+// SetDebugOutput sends debug output to w instead of stderr.
+func SetDebugOutput(w io.Writer) {
+	debugMu.Lock()
+	defer debugMu.Unlock()
+	debugOut = w
+}
@@ -1,11 +1,66 @@
 package utils
 
-import "fmt"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+// ErrorKind is the category of a failure. It sets the exit code and is the
+// code in the JSON error printed with --output json, so scripts can branch
+// on it.
+type ErrorKind string
+
+const (
+	ErrGeneral     ErrorKind = "error"
+	ErrUsage       ErrorKind = "usage"
+	ErrAuth        ErrorKind = "unauthenticated"
+	ErrPermission  ErrorKind = "permission_denied"
+	ErrNotFound    ErrorKind = "not_found"
+	ErrValidation  ErrorKind = "validation_failed"
+	ErrConflict    ErrorKind = "conflict"
+	ErrQuota       ErrorKind = "resource_exhausted"
+	ErrRateLimited ErrorKind = "rate_limited"
+	ErrNetwork     ErrorKind = "network_error"
+	ErrServer      ErrorKind = "server_error"
+	// ErrUpgradeRequired means the API no longer supports this version of
+	// the CLI.
+	ErrUpgradeRequired ErrorKind = "upgrade_required"
+)
+
+// exitCodes are the documented exit codes for each kind of error. They are
+// part of the CLI's interface: do not renumber them.
+var exitCodes = map[ErrorKind]int{
+	ErrGeneral:         1,
+	ErrUsage:           2,
+	ErrAuth:            3,
+	ErrPermission:      4,
+	ErrNotFound:        5,
+	ErrValidation:      6,
+	ErrConflict:        7,
+	ErrQuota:           8,
+	ErrRateLimited:     9,
+	ErrNetwork:         10,
+	ErrServer:          11,
+	ErrUpgradeRequired: 12,
+}
 
 // CLIError represents an error that can be nicely formatted for CLI output
 type CLIError struct {
 	Message string
 	Err     error
+
+	// Kind is the category of the error; empty means ErrGeneral, or the
+	// kind of Err.
+	Kind ErrorKind
+	// Status, Code and RequestID describe the API response that failed:
+	// its HTTP status, the API's error code and its X-Request-Id.
+	Status    int
+	Code      string
+	RequestID string
 }
 
 func (e *CLIError) Error() string {
@@ -15,6 +70,10 @@ func (e *CLIError) Error() string {
 	return e.Message
 }
 
+func (e *CLIError) Unwrap() error {
+	return e.Err
+}
+
 // NewError creates a new CLIError
 func NewError(message string, err error) error {
 	return &CLIError{
@@ -23,16 +82,159 @@ func NewError(message string, err error) error {
 	}
 }
 
-// HandleError prints the error and returns it
-func HandleError(err error) error {
+// NewKindError creates a new CLIError of kind.
+func NewKindError(kind ErrorKind, message string, err error) error {
+	return &CLIError{
+		Message: message,
+		Err:     err,
+		Kind:    kind,
+	}
+}
+
+// NewAPIError creates the CLIError for an API response with status, the
+// API's error code and the response's request ID.
+func NewAPIError(status int, code, requestID, message string) error {
+	return &CLIError{
+		Message:   message,
+		Kind:      KindForStatus(status),
+		Status:    status,
+		Code:      code,
+		RequestID: requestID,
+	}
+}
+
+// KindForStatus returns the kind of error an HTTP status means.
+func KindForStatus(status int) ErrorKind {
+	switch {
+	case status == http.StatusUnauthorized:
+		return ErrAuth
+	case status == http.StatusForbidden:
+		return ErrPermission
+	case status == http.StatusNotFound:
+		return ErrNotFound
+	case status == http.StatusConflict:
+		return ErrConflict
+	case status == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case status == http.StatusUpgradeRequired:
+		return ErrUpgradeRequired
+	case status == http.StatusBadRequest || status == http.StatusUnprocessableEntity:
+		return ErrValidation
+	case status >= 500:
+		return ErrServer
+	default:
+		return ErrGeneral
+	}
+}
+
+// KindOf returns the kind of err: the first kind set along its chain,
+// ErrQuota for a ResourceExhaustedCLIError, or ErrGeneral.
+func KindOf(err error) ErrorKind {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		switch t := e.(type) {
+		case *CLIError:
+			if t.Kind != "" {
+				return t.Kind
+			}
+		case *ResourceExhaustedCLIError:
+			return ErrQuota
+		}
+	}
+	return ErrGeneral
+}
+
+// ExitStatusError reports that a command 1ctl ran for the user, such as the
+// child of "1ctl run", exited with Code. 1ctl exits with the same code and
+// prints nothing more: the command has reported its own failure.
+type ExitStatusError struct {
+	Code int
+}
+
+func (e *ExitStatusError) Error() string {
+	return fmt.Sprintf("command exited with status %d", e.Code)
+}
+
+// NewExitStatusError returns the error for a command that exited with code.
+func NewExitStatusError(code int) error {
+	return &ExitStatusError{Code: code}
+}
+
+// ExitCode returns the exit code for err, 0 for nil.
+func ExitCode(err error) int {
 	if err == nil {
-		return nil
+		return 0
+	}
+	var status *ExitStatusError
+	if errors.As(err, &status) {
+		return status.Code
+	}
+	return exitCodes[KindOf(err)]
+}
+
+// ErrorEnvelope is the JSON printed to stderr for a failed command with
+// --output json.
+type ErrorEnvelope struct {
+	Error ErrorBody `json:"error"`
+}
+
+// ErrorBody describes the error in an ErrorEnvelope.
+type ErrorBody struct {
+	Code      ErrorKind `json:"code"`
+	Message   string    `json:"message"`
+	Status    int       `json:"status,omitempty"`
+	APICode   string    `json:"api_code,omitempty"`
+	RequestID string    `json:"request_id,omitempty"`
+}
+
+// NewErrorEnvelope describes err for JSON output, with secrets masked.
+func NewErrorEnvelope(err error) ErrorEnvelope {
+	body := ErrorBody{Code: KindOf(err), Message: Redact(err.Error())}
+	if cliErr := apiCLIError(err); cliErr != nil {
+		body.Status, body.APICode, body.RequestID = cliErr.Status, cliErr.Code, cliErr.RequestID
+	}
+	var resourceErr *ResourceExhaustedCLIError
+	if errors.As(err, &resourceErr) {
+		body.Status, body.RequestID = http.StatusUnprocessableEntity, resourceErr.RequestID
+		if resourceErr.ResourceError != nil {
+			body.APICode = resourceErr.ResourceError.Code
+		}
+	}
+	return ErrorEnvelope{Error: body}
+}
+
+// apiCLIError returns the CLIError along err's chain that describes an API
+// response, if any.
+func apiCLIError(err error) *CLIError {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if cliErr, ok := e.(*CLIError); ok && (cliErr.Status != 0 || cliErr.RequestID != "") {
+			return cliErr
+		}
+	}
+	return nil
+}
+
+// HandleError prints the error and returns it. With --output json it
+// prints an ErrorEnvelope to stderr instead. An ExitStatusError is not
+// printed.
+func HandleError(err error) error {
+	var status *ExitStatusError
+	if err == nil || errors.As(err, &status) {
+		return err
+	}
+	if IsJSONOutput() {
+		writeErrorJSON(os.Stderr, err)
+		return err
 	}
 
-	if cliErr, ok := err.(*CLIError); ok {
-		PrintError("%s", cliErr.Error())
-	} else {
-		PrintError("%s", err.Error())
+	// PrintError masks registered secrets, which API errors can echo back.
+	message := err.Error()
+	if cliErr := apiCLIError(err); cliErr != nil && cliErr.RequestID != "" {
+		message += fmt.Sprintf(" (request ID: %s)", cliErr.RequestID)
 	}
+	PrintError("%s", message)
 	return err
 }
+
+func writeErrorJSON(w io.Writer, err error) {
+	_ = json.NewEncoder(w).Encode(NewErrorEnvelope(err)) //nolint:errcheck
+}
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, 0},
+		{"plain error", errors.New("boom"), 1},
+		{"CLIError", NewError("boom", nil), 1},
+		{"usage", NewKindError(ErrUsage, "bad flag", nil), 2},
+		{"401", NewAPIError(401, "", "", "invalid API key"), 3},
+		{"403", NewAPIError(403, "", "", "forbidden"), 4},
+		{"404", NewAPIError(404, "", "", "not found"), 5},
+		{"400", NewAPIError(400, "", "", "bad port"), 6},
+		{"409", NewAPIError(409, "", "", "exists"), 7},
+		{"quota", NewResourceExhaustedCLIError(&ResourceExhaustedError{Type: QuotaCPU}), 8},
+		{"429", NewAPIError(429, "", "", "slow down"), 9},
+		{"network", NewKindError(ErrNetwork, "failed to make request", nil), 10},
+		{"502", NewAPIError(502, "", "", "bad gateway"), 11},
+		{"426", NewAPIError(426, "", "", "upgrade 1ctl"), 12},
+		{"kind of a wrapped error", NewError("deploy failed", NewAPIError(404, "", "", "not found")), 5},
+		{"kind of an fmt-wrapped error", fmt.Errorf("step 2: %w", NewKindError(ErrAuth, "not authenticated", nil)), 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorJSONEnvelope(t *testing.T) {
+	original := defaultRedactor
+	t.Cleanup(func() { defaultRedactor = original })
+	defaultRedactor = NewRedactor(nil)
+	RegisterSecret("hunter2-secret")
+
+	err := NewError("deploy failed", NewAPIError(404, "APP_NOT_FOUND", "req-1", "app hunter2-secret not found"))
+	var out bytes.Buffer
+	writeErrorJSON(&out, err)
+	want := `{"error":{"code":"not_found","message":"deploy failed: app ******** not found","status":404,"api_code":"APP_NOT_FOUND","request_id":"req-1"}}` + "\n"
+	if out.String() != want {
+		t.Errorf("got %s\nwant %s", out.String(), want)
+	}
+
+	out.Reset()
+	writeErrorJSON(&out, errors.New("boom"))
+	if want := `{"error":{"code":"error","message":"boom"}}` + "\n"; out.String() != want {
+		t.Errorf("got %s\nwant %s", out.String(), want)
+	}
+}
@@ -13,22 +13,33 @@ func SetOutputFormat(format string) {
 	outputFormat = format
 }
 
-// IsJSONOutput returns true when --output json was requested.
+// IsJSONOutput returns true when --output json or ndjson was requested.
 func IsJSONOutput() bool {
-	return outputFormat == "json"
+	return outputFormat == "json" || IsNDJSONOutput()
+}
+
+// IsNDJSONOutput returns true when --output ndjson was requested: JSON on
+// one line, one line per list item.
+func IsNDJSONOutput() bool {
+	return outputFormat == "ndjson"
 }
 
 // TryPrintJSON marshals data to indented JSON and prints it if JSON output is enabled.
+// Registered secret values are masked unless --reveal was passed.
 // Returns true so callers can do: if utils.TryPrintJSON(data) { return nil }
 func TryPrintJSON(data interface{}) bool {
 	if !IsJSONOutput() {
 		return false
 	}
+	if IsNDJSONOutput() {
+		printJSONLine(data)
+		return true
+	}
 	b, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
-		fmt.Printf("{\"error\": %q}\n", err.Error())
+		fmt.Printf("{\"error\": %q}\n", Redact(err.Error()))
 	} else {
-		fmt.Println(string(b))
+		fmt.Println(Redact(string(b)))
 	}
 	return true
 }
@@ -47,13 +58,87 @@ func TryPrintJSON(data interface{}) bool {
 //	}
 //	utils.PrintTable(headers, rows)
 func PrintListOrJSON(items interface{}, emptyMsg string) bool {
+	v := reflect.ValueOf(items)
+	if IsNDJSONOutput() && v.Kind() == reflect.Slice {
+		for i := 0; i < v.Len(); i++ {
+			printJSONLine(v.Index(i).Interface())
+		}
+		return true
+	}
 	if TryPrintJSON(items) {
 		return true
 	}
-	v := reflect.ValueOf(items)
 	if v.Kind() == reflect.Slice && v.Len() == 0 && emptyMsg != "" {
 		fmt.Println(emptyMsg)
 		return true
 	}
 	return false
 }
+
+// printJSONLine prints data as one line of JSON, with secrets masked.
+func printJSONLine(data interface{}) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		fmt.Printf("{\"error\": %q}\n", Redact(err.Error()))
+		return
+	}
+	fmt.Println(Redact(string(b)))
+}
+
+// ListStream prints a list that arrives a page at a time, as it arrives:
+// one line of JSON per item with --output ndjson, or in table mode each
+// page through the printPage function it was made with. --output json
+// needs the whole list for its array, which is printed by Close.
+//
+//	stream := utils.NewListStream("No things found", printThings)
+//	if err := api.ListThings(opts, stream.Add); err != nil {
+//	    return err
+//	}
+//	stream.Close()
+type ListStream[T any] struct {
+	emptyMsg  string
+	printPage func(page []T, first bool)
+	items     []T
+	count     int
+}
+
+// NewListStream returns a ListStream that prints emptyMsg for an empty list
+// in table mode and pages with printPage, which is told whether the page is
+// the first, e.g. to print a header.
+func NewListStream[T any](emptyMsg string, printPage func(page []T, first bool)) *ListStream[T] {
+	return &ListStream[T]{emptyMsg: emptyMsg, printPage: printPage}
+}
+
+// Add prints a page of items, or keeps it for Close with --output json. It
+// never fails; the error lets it be passed as a page callback.
+func (s *ListStream[T]) Add(page []T) error {
+	switch {
+	case IsNDJSONOutput():
+		for _, item := range page {
+			printJSONLine(item)
+		}
+	case IsJSONOutput():
+		s.items = append(s.items, page...)
+	default:
+		s.printPage(page, s.count == 0)
+	}
+	s.count += len(page)
+	return nil
+}
+
+// Close finishes the list: the JSON array with --output json, or emptyMsg
+// in table mode when no items arrived.
+func (s *ListStream[T]) Close() {
+	switch {
+	case IsNDJSONOutput():
+	case IsJSONOutput():
+		if s.items == nil {
+			s.items = []T{}
+		}
+		TryPrintJSON(s.items)
+	default:
+		if s.count == 0 && s.emptyMsg != "" {
+			fmt.Println(s.emptyMsg)
+		}
+	}
+}
@@ -43,28 +43,54 @@ var defaultPrinter = NewPrinter(os.Stdout)
 
 // Success prints a success message
 func (p *Printer) Success(format string, a ...interface{}) {
-	message := fmt.Sprintf(format, a...)
+	message := Redact(fmt.Sprintf(format, a...))
 	_, _ = fmt.Fprintf(p.out, "%s\n", SuccessColor("✅ "+message)) //nolint:errcheck
 }
 
 // Error prints an error message
 func (p *Printer) Error(format string, a ...interface{}) {
-	message := fmt.Sprintf(format, a...)
+	message := Redact(fmt.Sprintf(format, a...))
 	_, _ = fmt.Fprintf(p.out, "%s\n", ErrorColor("❌ "+message)) //nolint:errcheck
 }
 
 // Warning prints a warning message
 func (p *Printer) Warning(format string, a ...interface{}) {
-	message := fmt.Sprintf(format, a...)
+	message := Redact(fmt.Sprintf(format, a...))
 	_, _ = fmt.Fprintf(p.out, "%s\n", WarnColor("❗️ "+message)) //nolint:errcheck
 }
 
 // Info prints an info message
 func (p *Printer) Info(format string, a ...interface{}) {
-	message := fmt.Sprintf(format, a...)
+	message := Redact(fmt.Sprintf(format, a...))
 	_, _ = fmt.Fprintf(p.out, "%s\n", InfoColor("💡 "+message)) //nolint:errcheck
 }
 
+// StatusLine prints a status line with label and value
+func (p *Printer) StatusLine(label, value string) {
+	_, _ = fmt.Fprintf(p.out, "%s: %s\n", BoldColor(label), Redact(value)) //nolint:errcheck
+}
+
+// LoadingStep prints a step in the deployment process with a loading
+// animation. Until done, the line ends with a carriage return instead of a
+// newline so the next call overwrites it.
+func (p *Printer) LoadingStep(step, total int, message, resource string, done bool) {
+	resource = Redact(resource)
+	if done {
+		_, _ = fmt.Fprintf(p.out, "\r%s: %s %s %s\n", //nolint:errcheck
+			InfoColor(fmt.Sprintf("Step %d/%d", step, total)),
+			SuccessColor(message),
+			WarnColor(resource),
+			SuccessColor("✓"))
+	} else {
+		spinner := loadingChars[step%len(loadingChars)]
+		_, _ = fmt.Fprintf(p.out, "\r%s: %s %s %s", //nolint:errcheck
+			InfoColor(fmt.Sprintf("Step %d/%d", step, total)),
+			SuccessColor(message),
+			WarnColor(resource),
+			InfoColor(spinner))
+	}
+}
+
 // Global functions that use the default printer
 func PrintSuccess(format string, a ...interface{}) {
 	defaultPrinter.Success(format, a...)
@@ -87,12 +113,12 @@ func PrintStep(step, total int, message, resource string) {
 	fmt.Printf("%s: %s %s\n",
 		InfoColor(fmt.Sprintf("Step %d/%d", step, total)),
 		SuccessColor(message),
-		WarnColor(resource))
+		WarnColor(Redact(resource)))
 }
 
 // PrintHeader prints a header with bold text and underline
 func PrintHeader(format string, a ...interface{}) {
-	text := fmt.Sprintf(format, a...)
+	text := Redact(fmt.Sprintf(format, a...))
 	fmt.Println(BoldColor(text))
 	underline := strings.Repeat("─", len(text))
 	fmt.Println(DividerColor(underline))
@@ -100,7 +126,7 @@ func PrintHeader(format string, a ...interface{}) {
 
 // PrintStatusLine prints a status line with label and value
 func PrintStatusLine(label, value string) {
-	fmt.Printf("%s: %s\n", BoldColor(label), value)
+	defaultPrinter.StatusLine(label, value)
 }
 
 // PrintDivider prints a divider line
@@ -110,20 +136,7 @@ func PrintDivider() {
 
 // PrintLoadingStep prints a step in the deployment process with a loading animation
 func PrintLoadingStep(step, total int, message, resource string, done bool) {
-	if done {
-		fmt.Printf("\r%s: %s %s %s\n",
-			InfoColor(fmt.Sprintf("Step %d/%d", step, total)),
-			SuccessColor(message),
-			WarnColor(resource),
-			SuccessColor("✓"))
-	} else {
-		spinner := loadingChars[step%len(loadingChars)]
-		fmt.Printf("\r%s: %s %s %s",
-			InfoColor(fmt.Sprintf("Step %d/%d", step, total)),
-			SuccessColor(message),
-			WarnColor(resource),
-			InfoColor(spinner))
-	}
+	defaultPrinter.LoadingStep(step, total, message, resource, done)
 }
 
 // ============================================================================
@@ -155,14 +168,10 @@ func PrintTable(headers []string, rows [][]string) {
 	PrintTableWithConfig(rows, config)
 }
 
-// PrintTableWithConfig prints data in a formatted table with custom config
-func PrintTableWithConfig(rows [][]string, config TableConfig) {
-	if len(config.Headers) == 0 && len(rows) == 0 {
-		return
-	}
-
-	// Calculate column widths
-	numCols := len(config.Headers)
+// ColumnWidths returns the width of each column of a table: its widest
+// cell or header. Pass it as MinWidths to line up tables printed in parts.
+func ColumnWidths(headers []string, rows [][]string) []int {
+	numCols := len(headers)
 	if numCols == 0 && len(rows) > 0 {
 		numCols = len(rows[0])
 	}
@@ -170,7 +179,7 @@ func PrintTableWithConfig(rows [][]string, config TableConfig) {
 	colWidths := make([]int, numCols)
 
 	// Consider header widths
-	for i, h := range config.Headers {
+	for i, h := range headers {
 		if i < numCols && len(h) > colWidths[i] {
 			colWidths[i] = len(h)
 		}
@@ -184,6 +193,18 @@ func PrintTableWithConfig(rows [][]string, config TableConfig) {
 			}
 		}
 	}
+	return colWidths
+}
+
+// PrintTableWithConfig prints data in a formatted table with custom config
+func PrintTableWithConfig(rows [][]string, config TableConfig) {
+	if len(config.Headers) == 0 && len(rows) == 0 {
+		return
+	}
+
+	// Calculate column widths
+	colWidths := ColumnWidths(config.Headers, rows)
+	numCols := len(colWidths)
 
 	// Apply minimum widths
 	for i, minW := range config.MinWidths {
@@ -220,7 +241,7 @@ func PrintTableWithConfig(rows [][]string, config TableConfig) {
 		for i := 0; i < numCols; i++ {
 			cell := ""
 			if i < len(row) {
-				cell = row[i]
+				cell = Redact(row[i])
 			}
 			rowParts[i] = padRight(cell, colWidths[i])
 		}
@@ -305,7 +326,7 @@ func PrintJSON(data interface{}) error {
 	if err != nil {
 		return err
 	}
-	fmt.Println(string(output))
+	fmt.Println(Redact(string(output)))
 	return nil
 }
 
@@ -315,7 +336,7 @@ func PrintJSONCompact(data interface{}) error {
 	if err != nil {
 		return err
 	}
-	fmt.Println(string(output))
+	fmt.Println(Redact(string(output)))
 	return nil
 }
 
@@ -353,7 +374,7 @@ func PrintKeyValueWithIndent(pairs []KeyValue, indent int) {
 		fmt.Printf("%s%s: %s\n",
 			indentStr,
 			BoldColor(padRight(kv.Key, maxKeyWidth)),
-			kv.Value)
+			Redact(kv.Value))
 	}
 }
 
@@ -529,14 +550,14 @@ func PrintSection(title string) {
 // PrintList prints a list of items with bullets
 func PrintList(items []string) {
 	for _, item := range items {
-		fmt.Printf("  • %s\n", item)
+		fmt.Printf("  • %s\n", Redact(item))
 	}
 }
 
 // PrintNumberedList prints a numbered list of items
 func PrintNumberedList(items []string) {
 	for i, item := range items {
-		fmt.Printf("  %d. %s\n", i+1, item)
+		fmt.Printf("  %d. %s\n", i+1, Redact(item))
 	}
 }
 
@@ -0,0 +1,169 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// RedactedValue replaces registered secret values in output.
+const RedactedValue = "********"
+
+// minRedactLength is the shortest value that is masked in output. Shorter
+// values ("true", "8080") are too likely to match ordinary output, so
+// commands that print secrets leave their values out instead. GitHub
+// Actions is still asked to mask them.
+const minRedactLength = 6
+
+// Redactor masks known sensitive values in everything the CLI prints.
+// Values are registered as they are fetched (secrets, tokens, database
+// passwords), so output and errors stay safe to paste into CI logs.
+type Redactor struct {
+	mu      sync.RWMutex
+	values  map[string]struct{}
+	sorted  []string // longest first, so a value is masked before its substrings
+	reveal  bool
+	ciMask  bool
+	ciOut   io.Writer
+	ciMasks map[string]struct{}
+}
+
+// NewRedactor returns a redactor that writes GitHub Actions mask commands
+// to ciOut when running under GitHub Actions.
+func NewRedactor(ciOut io.Writer) *Redactor {
+	return &Redactor{
+		values:  map[string]struct{}{},
+		ciMask:  os.Getenv("GITHUB_ACTIONS") == "true",
+		ciOut:   ciOut,
+		ciMasks: map[string]struct{}{},
+	}
+}
+
+// Mask commands go to stderr so stdout stays clean for "-o json" and export.
+var defaultRedactor = NewRedactor(os.Stderr)
+
+// SetReveal disables masking for this process (the --reveal flag).
+func SetReveal(reveal bool) {
+	defaultRedactor.SetReveal(reveal)
+}
+
+// IsRevealed reports whether --reveal was passed.
+func IsRevealed() bool {
+	return defaultRedactor.IsRevealed()
+}
+
+// RegisterSecret marks values as sensitive so they are masked in output.
+func RegisterSecret(values ...string) {
+	defaultRedactor.Register(values...)
+}
+
+// MaskInCI asks GitHub Actions to mask values in its logs without masking
+// them in CLI output, for values that are shown once on purpose (a newly
+// created token).
+func MaskInCI(values ...string) {
+	defaultRedactor.MaskInCI(values...)
+}
+
+// Redact returns s with every registered value masked.
+func Redact(s string) string {
+	return defaultRedactor.Redact(s)
+}
+
+// SetReveal disables masking.
+func (r *Redactor) SetReveal(reveal bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reveal = reveal
+}
+
+// IsRevealed reports whether masking is disabled.
+func (r *Redactor) IsRevealed() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.reveal
+}
+
+// Register marks values as sensitive. Each value is also registered in its
+// JSON-escaped form so it is masked inside JSON output.
+func (r *Redactor) Register(values ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	changed := false
+	for _, v := range values {
+		r.addMask(v)
+		if len(v) < minRedactLength {
+			continue
+		}
+		for _, form := range []string{v, jsonEscaped(v)} {
+			if _, ok := r.values[form]; !ok {
+				r.values[form] = struct{}{}
+				changed = true
+			}
+		}
+	}
+	if changed {
+		r.sorted = r.sorted[:0]
+		for v := range r.values {
+			r.sorted = append(r.sorted, v)
+		}
+		sort.Slice(r.sorted, func(i, j int) bool { return len(r.sorted[i]) > len(r.sorted[j]) })
+	}
+}
+
+// MaskInCI emits GitHub Actions mask commands for values without masking
+// them in output.
+func (r *Redactor) MaskInCI(values ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, v := range values {
+		r.addMask(v)
+	}
+}
+
+// Redact returns s with every registered value replaced by RedactedValue.
+func (r *Redactor) Redact(s string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.reveal || len(r.sorted) == 0 {
+		return s
+	}
+	for _, v := range r.sorted {
+		if strings.Contains(s, v) {
+			s = strings.ReplaceAll(s, v, RedactedValue)
+		}
+	}
+	return s
+}
+
+// addMask writes "::add-mask::" once per value. The runner matches masks
+// line by line, so multi-line values are masked one line at a time. It is
+// emitted even with --reveal: revealing is for the terminal, not CI logs.
+func (r *Redactor) addMask(v string) {
+	if !r.ciMask || r.ciOut == nil {
+		return
+	}
+	for _, line := range strings.Split(v, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if _, ok := r.ciMasks[line]; ok {
+			continue
+		}
+		r.ciMasks[line] = struct{}{}
+		_, _ = fmt.Fprintf(r.ciOut, "::add-mask::%s\n", strings.ReplaceAll(line, "%", "%25")) //nolint:errcheck
+	}
+}
+
+// jsonEscaped returns v as it appears inside a JSON string.
+func jsonEscaped(v string) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	return string(b[1 : len(b)-1])
+}
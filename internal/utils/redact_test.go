@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRedactorMasksRegisteredValues(t *testing.T) {
+	r := NewRedactor(nil)
+	r.Register("hunter2-secret", "short", `p"ss\word`)
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "password is hunter2-secret.", "password is ********."},
+		{"in uri", "postgres://app:hunter2-secret@db:5432/app", "postgres://app:********@db:5432/app"},
+		{"short values are not masked", "short", "short"},
+		{"unrelated", "nothing here", "nothing here"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.Redact(tt.in); got != tt.want {
+				t.Errorf("Redact(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+
+	b, err := json.Marshal(map[string]string{"value": `p"ss\word`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := r.Redact(string(b)); strings.Contains(got, "word") {
+		t.Errorf("JSON-escaped value not masked: %s", got)
+	}
+
+	r.SetReveal(true)
+	if got := r.Redact("hunter2-secret"); got != "hunter2-secret" {
+		t.Errorf("Redact with reveal = %q, want value unchanged", got)
+	}
+}
+
+func TestRedactorLongestValueFirst(t *testing.T) {
+	r := NewRedactor(nil)
+	r.Register("secret", "secret-and-more")
+	if got := r.Redact("secret-and-more"); got != RedactedValue {
+		t.Errorf("Redact() = %q, want %q", got, RedactedValue)
+	}
+}
+
+func TestRedactorGitHubActionsMask(t *testing.T) {
+	t.Setenv("GITHUB_ACTIONS", "true")
+	var out bytes.Buffer
+	r := NewRedactor(&out)
+	r.Register("first-line\nsecond-line", "100%-secret")
+	r.Register("first-line") // already masked
+	r.MaskInCI("one-time-token")
+
+	want := "::add-mask::first-line\n::add-mask::second-line\n::add-mask::100%25-secret\n::add-mask::one-time-token\n"
+	if out.String() != want {
+		t.Errorf("mask commands = %q, want %q", out.String(), want)
+	}
+	if got := r.Redact("one-time-token"); got != "one-time-token" {
+		t.Errorf("MaskInCI value was redacted in output: %q", got)
+	}
+}
+
+func TestRedactorMasksShortValuesInCI(t *testing.T) {
+	t.Setenv("GITHUB_ACTIONS", "true")
+	var out bytes.Buffer
+	r := NewRedactor(&out)
+	r.Register("pw12", "")
+	r.MaskInCI("abc")
+
+	want := "::add-mask::pw12\n::add-mask::abc\n"
+	if out.String() != want {
+		t.Errorf("mask commands = %q, want %q", out.String(), want)
+	}
+	if got := r.Redact("pw12"); got != "pw12" {
+		t.Errorf("Redact(short value) = %q; short values are left to the commands that print them", got)
+	}
+}
@@ -211,6 +211,8 @@ func PrintResourceExhaustedError(err *ResourceExhaustedError) {
 // ResourceExhaustedCLIError wraps ResourceExhaustedError as a CLIError
 type ResourceExhaustedCLIError struct {
 	ResourceError *ResourceExhaustedError
+	// RequestID is the X-Request-Id of the response that reported it.
+	RequestID string
 }
 
 func (e *ResourceExhaustedCLIError) Error() string {
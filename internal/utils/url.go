@@ -1,6 +1,12 @@
 package utils
 
-import "strings"
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/browser"
+)
 
 // IsLocalhostURL returns true if the URL points to localhost or 127.0.0.1.
 func IsLocalhostURL(rawURL string) bool {
@@ -8,3 +14,20 @@ func IsLocalhostURL(rawURL string) bool {
 		strings.HasPrefix(rawURL, "http://127.0.0.1") ||
 		strings.HasPrefix(rawURL, "http://[::1]")
 }
+
+// OpenBrowser opens the given URL in the user's default browser.
+func OpenBrowser(targetURL string) error {
+	if targetURL == "" {
+		return fmt.Errorf("no URL to open")
+	}
+	parsed, err := url.ParseRequestURI(targetURL)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+	switch parsed.Scheme {
+	case "http", "https":
+		return browser.OpenURL(parsed.String())
+	default:
+		return fmt.Errorf("unsupported URL scheme: %s", parsed.Scheme)
+	}
+}
@@ -2,6 +2,8 @@ package version
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 )
 
 var (
@@ -26,3 +28,69 @@ func GetVersionInfo() string {
 	}
 	return version
 }
+
+// IsRelease reports whether v is a released version like v1.4.2 rather
+// than a development build.
+func IsRelease(v string) bool {
+	_, ok := parse(v)
+	return ok
+}
+
+// Compare compares two versions like v1.4.2 or 1.5.0-rc.1 and returns -1,
+// 0 or +1. A pre-release sorts before its release. ok is false when either
+// is not a version, e.g. "dev".
+func Compare(a, b string) (result int, ok bool) {
+	va, okA := parse(a)
+	vb, okB := parse(b)
+	if !okA || !okB {
+		return 0, false
+	}
+	for i := range va.nums {
+		if va.nums[i] != vb.nums[i] {
+			if va.nums[i] < vb.nums[i] {
+				return -1, true
+			}
+			return 1, true
+		}
+	}
+	switch {
+	case va.pre == vb.pre:
+		return 0, true
+	case va.pre == "":
+		return 1, true
+	case vb.pre == "":
+		return -1, true
+	case va.pre < vb.pre:
+		return -1, true
+	default:
+		return 1, true
+	}
+}
+
+type semver struct {
+	nums [3]int
+	pre  string
+}
+
+func parse(v string) (semver, bool) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexByte(v, '+'); i >= 0 {
+		v = v[:i]
+	}
+	var s semver
+	if i := strings.IndexByte(v, '-'); i >= 0 {
+		v, s.pre = v[:i], v[i+1:]
+	}
+	parts := strings.Split(v, ".")
+	if len(parts) < 1 || len(parts) > 3 {
+		return semver{}, false
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return semver{}, false
+		}
+		s.nums[i] = n
+	}
+	return s, true
+}
@@ -59,3 +59,27 @@ func TestGetVersionInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b   string
+		want   int
+		wantOK bool
+	}{
+		{"v1.2.3", "1.2.3", 0, true},
+		{"v1.2.3", "v1.10.0", -1, true},
+		{"v2.0.0", "v1.99.99", 1, true},
+		{"v1.2", "v1.2.0", 0, true},
+		{"v1.5.0-rc.1", "v1.5.0", -1, true},
+		{"v1.5.0-rc.2", "v1.5.0-rc.1", 1, true},
+		{"v1.5.0+abc", "v1.5.0", 0, true},
+		{"dev", "v1.0.0", 0, false},
+		{"v1.0.0", "", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := Compare(tt.a, tt.b)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("Compare(%q, %q) = %d, %v, want %d, %v", tt.a, tt.b, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}